@@ -0,0 +1,86 @@
+/*
+ * Copyright 2014 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package fakevcd
+
+import (
+	"fmt"
+	"net/http"
+
+	types "github.com/vmware/go-vcloud-director/types/v56"
+)
+
+// serveControlAccess serves the access control of a catalog or a vApp of
+// org, stored in access. rest is either "/controlAccess/" or
+// "/action/controlAccess". Catalogs can only be shared with everyone read
+// only.
+func serveControlAccess(w http.ResponseWriter, r *http.Request, rest string, org *Org, access **types.ControlAccessParams, catalog bool) {
+	switch {
+	case (rest == "/controlAccess" || rest == "/controlAccess/") && r.Method == http.MethodGet:
+		params := *access
+		if params == nil {
+			params = &types.ControlAccessParams{}
+		}
+		writeXML(w, http.StatusOK, "ControlAccessParams", types.MimeControlAccess, params)
+	case rest == "/action/controlAccess" && r.Method == http.MethodPost:
+		params := &types.ControlAccessParams{}
+		if err := readXML(r, params); err != nil {
+			writeError(w, http.StatusBadRequest, "", err.Error())
+			return
+		}
+		if params.IsSharedToEveryone {
+			if !validAccessLevel(params.EveryoneAccessLevel) || catalog && params.EveryoneAccessLevel != types.ControlAccessReadOnly {
+				writeError(w, http.StatusBadRequest, "", fmt.Sprintf("Invalid access level %s for everyone.", params.EveryoneAccessLevel))
+				return
+			}
+		} else {
+			params.EveryoneAccessLevel = ""
+		}
+		if params.AccessSettings != nil {
+			for _, setting := range params.AccessSettings.AccessSetting {
+				if !validAccessLevel(setting.AccessLevel) {
+					writeError(w, http.StatusBadRequest, "", fmt.Sprintf("Invalid access level %s.", setting.AccessLevel))
+					return
+				}
+				subject := org.subject(setting.Subject)
+				if subject == nil {
+					writeError(w, http.StatusBadRequest, "", "The access setting subject is not a user or a group of the organization.")
+					return
+				}
+				setting.Subject = subject
+			}
+		}
+		*access = params
+		writeXML(w, http.StatusOK, "ControlAccessParams", types.MimeControlAccess, params)
+	default:
+		writeMethodNotAllowed(w, r)
+	}
+}
+
+// subject returns the reference to the user or the group of the
+// organization referenced by ref, or nil.
+func (o *Org) subject(ref *types.Reference) *types.Reference {
+	if ref == nil {
+		return nil
+	}
+	for _, user := range o.users {
+		if user.User.HREF == ref.HREF {
+			return &types.Reference{HREF: user.User.HREF, Type: types.MimeAdminUser, Name: user.User.Name}
+		}
+	}
+	for _, group := range o.groups {
+		if group.Group.HREF == ref.HREF {
+			return &types.Reference{HREF: group.Group.HREF, Type: types.MimeAdminGroup, Name: group.Group.Name}
+		}
+	}
+	return nil
+}
+
+func validAccessLevel(accessLevel string) bool {
+	switch accessLevel {
+	case types.ControlAccessReadOnly, types.ControlAccessChange, types.ControlAccessFullControl:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,206 @@
+/*
+ * Copyright 2014 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package fakevcd
+
+import (
+	"net/http"
+
+	types "github.com/vmware/go-vcloud-director/types/v56"
+)
+
+// renderAdmin returns the admin view of the VDC.
+func (v *Vdc) renderAdmin() *types.AdminVdc {
+	vdc := v.render()
+	adminVdc := v.admin
+	adminVdc.HREF = v.server.href("/admin/vdc/" + v.id)
+	adminVdc.Type = types.MimeAdminVdc
+	adminVdc.ID = vdc.ID
+	adminVdc.Name = vdc.Name
+	adminVdc.Status = vdc.Status
+	adminVdc.Link = types.LinkList{
+		link(types.RelUp, "application/vnd.vmware.admin.organization+xml", v.server.href("/admin/org/"+v.org.id), ""),
+		link(types.RelAlternate, types.MimeVDC, vdc.HREF, ""),
+	}
+	adminVdc.Description = vdc.Description
+	adminVdc.Tasks = vdc.Tasks
+	adminVdc.AllocationModel = vdc.AllocationModel
+	adminVdc.ComputeCapacity = vdc.ComputeCapacity
+	adminVdc.ResourceEntities = vdc.ResourceEntities
+	adminVdc.AvailableNetworks = vdc.AvailableNetworks
+	adminVdc.Capabilities = vdc.Capabilities
+	adminVdc.NicQuota = vdc.NicQuota
+	adminVdc.NetworkQuota = vdc.NetworkQuota
+	adminVdc.UsedNetworkCount = len(v.networks)
+	adminVdc.VMQuota = vdc.VMQuota
+	adminVdc.IsEnabled = vdc.IsEnabled
+	profiles := &types.VdcStorageProfiles{}
+	for _, profile := range v.storageProfiles {
+		profiles.VdcStorageProfile = append(profiles.VdcStorageProfile, profile.adminReference())
+	}
+	adminVdc.VdcStorageProfiles = []*types.VdcStorageProfiles{profiles}
+	return &adminVdc
+}
+
+// createVdc creates a VDC from CreateVdcParams. The storage profiles must
+// be profiles of the provider VDC.
+func (o *Org) createVdc(w http.ResponseWriter, r *http.Request) {
+	s := o.server
+	params := &types.CreateVdcParams{}
+	if err := readXML(r, params); err != nil {
+		writeError(w, http.StatusBadRequest, "", err.Error())
+		return
+	}
+	if params.Name == "" || !validAllocationModel(params.AllocationModel) ||
+		len(params.ComputeCapacity) == 0 || params.ComputeCapacity[0].CPU == nil || params.ComputeCapacity[0].Memory == nil {
+		writeError(w, http.StatusBadRequest, "", "The VDC needs a name, an allocation model and a compute capacity.")
+		return
+	}
+	for _, vdc := range o.vdcs {
+		if vdc.Vdc.Name == params.Name {
+			writeError(w, http.StatusBadRequest, "DUPLICATE_NAME", "A VDC named "+params.Name+" already exists.")
+			return
+		}
+	}
+	var providerVdc *ProviderVdc
+	for _, candidate := range s.providerVdcs {
+		if params.ProviderVdcReference != nil && candidate.ProviderVdc.HREF == params.ProviderVdcReference.HREF {
+			providerVdc = candidate
+		}
+	}
+	if providerVdc == nil {
+		writeError(w, http.StatusBadRequest, "", "The provider VDC does not exist.")
+		return
+	}
+	for _, profile := range params.VdcStorageProfile {
+		if providerVdc.storageProfile(profile.ProviderVdcStorageProfile) == nil {
+			writeError(w, http.StatusBadRequest, "", "The storage profile is not a storage profile of the provider VDC.")
+			return
+		}
+	}
+	if len(params.VdcStorageProfile) == 0 {
+		writeError(w, http.StatusBadRequest, "", "The VDC needs a storage profile.")
+		return
+	}
+
+	vdc := o.addVdc(params.Name)
+	vdc.providerVdc = providerVdc
+	vdc.Vdc.Description = params.Description
+	vdc.Vdc.AllocationModel = params.AllocationModel
+	vdc.Vdc.ComputeCapacity = params.ComputeCapacity
+	vdc.Vdc.NicQuota = params.NicQuota
+	vdc.Vdc.NetworkQuota = params.NetworkQuota
+	vdc.Vdc.VMQuota = params.VMQuota
+	vdc.Vdc.IsEnabled = params.IsEnabled
+	var hasDefault bool
+	for _, profileParams := range params.VdcStorageProfile {
+		profile := vdc.addStorageProfile("", providerVdc.storageProfile(profileParams.ProviderVdcStorageProfile),
+			profileParams.Enabled, limitMB(profileParams.Limit, profileParams.Units))
+		profile.isDefault = profileParams.Default && !hasDefault
+		hasDefault = hasDefault || profileParams.Default
+	}
+	if !hasDefault {
+		vdc.storageProfiles[0].isDefault = true
+	}
+	vdc.admin = types.AdminVdc{
+		ResourceGuaranteedMemory: params.ResourceGuaranteedMemory,
+		ResourceGuaranteedCPU:    params.ResourceGuaranteedCPU,
+		VCPUInMhz:                params.VCPUInMhz,
+		IsThinProvision:          params.IsThinProvision,
+		NetworkPoolReference:     params.NetworkPoolReference,
+		ProviderVdcReference:     &types.Reference{HREF: providerVdc.ProviderVdc.HREF, Type: types.MimeProviderVdc, Name: providerVdc.ProviderVdc.Name},
+		UsesFastProvisioning:     params.UsesFastProvisioning,
+	}
+
+	owner := &types.Reference{HREF: vdc.Vdc.HREF, Name: vdc.Vdc.Name, Type: types.MimeVDC}
+	created := vdc.renderAdmin()
+	created.Tasks = &types.TasksInProgress{Task: []*types.Task{s.newTask(o, "vdcCreateVdc", owner, nil)}}
+	writeXML(w, http.StatusCreated, "AdminVdc", types.MimeAdminVdc, created)
+}
+
+// update changes the description, compute capacity, quotas and resource
+// guarantees of the VDC. The allocation model cannot be changed.
+func (v *Vdc) update(w http.ResponseWriter, r *http.Request) {
+	params := &types.AdminVdc{}
+	if err := readXML(r, params); err != nil {
+		writeError(w, http.StatusBadRequest, "", err.Error())
+		return
+	}
+	if params.AllocationModel != v.Vdc.AllocationModel {
+		writeError(w, http.StatusBadRequest, "", "The allocation model of a VDC cannot be changed.")
+		return
+	}
+	if len(params.ComputeCapacity) == 0 || params.ComputeCapacity[0].CPU == nil || params.ComputeCapacity[0].Memory == nil {
+		writeError(w, http.StatusBadRequest, "", "The VDC needs a compute capacity.")
+		return
+	}
+	if params.Name != "" {
+		v.Vdc.Name = params.Name
+	}
+	v.Vdc.Description = params.Description
+	v.Vdc.ComputeCapacity = params.ComputeCapacity
+	v.Vdc.NicQuota = params.NicQuota
+	v.Vdc.NetworkQuota = params.NetworkQuota
+	v.Vdc.VMQuota = params.VMQuota
+	v.Vdc.IsEnabled = params.IsEnabled
+	v.admin.ResourceGuaranteedMemory = params.ResourceGuaranteedMemory
+	v.admin.ResourceGuaranteedCPU = params.ResourceGuaranteedCPU
+	v.admin.VCPUInMhz = params.VCPUInMhz
+	v.admin.IsThinProvision = params.IsThinProvision
+	v.admin.UsesFastProvisioning = params.UsesFastProvisioning
+
+	owner := &types.Reference{HREF: v.Vdc.HREF, Name: v.Vdc.Name, Type: types.MimeVDC}
+	updated := v.renderAdmin()
+	updated.Tasks = &types.TasksInProgress{Task: []*types.Task{v.server.newTask(v.org, "vdcUpdateVdc", owner, nil)}}
+	writeXML(w, http.StatusAccepted, "AdminVdc", types.MimeAdminVdc, updated)
+}
+
+// delete deletes the disabled VDC. Without recursive, the VDC must have
+// no vApp and no network.
+func (v *Vdc) delete(w http.ResponseWriter, r *http.Request) {
+	if v.Vdc.IsEnabled {
+		writeError(w, http.StatusBadRequest, "", "The VDC "+v.Vdc.Name+" must be disabled before it is deleted.")
+		return
+	}
+	if r.URL.Query().Get("recursive") != "true" && (len(v.vapps) > 0 || len(v.networks) > 0) {
+		writeError(w, http.StatusBadRequest, "", "The VDC "+v.Vdc.Name+" is not empty.")
+		return
+	}
+	owner := &types.Reference{HREF: v.Vdc.HREF, Name: v.Vdc.Name, Type: types.MimeVDC}
+	writeTask(w, v.server.newTask(v.org, "vdcDeleteVdc", owner, v.remove))
+}
+
+// remove deletes the VDC and all its children from the organization.
+func (v *Vdc) remove() {
+	s := v.server
+	for i, vdc := range v.org.vdcs {
+		if vdc == v {
+			v.org.vdcs = append(v.org.vdcs[:i], v.org.vdcs[i+1:]...)
+			break
+		}
+	}
+	for len(v.vapps) > 0 {
+		v.vapps[0].remove()
+	}
+	for _, network := range v.networks {
+		s.unhandle("/network/" + network.id)
+		s.unhandle("/admin/network/" + network.id)
+	}
+	for _, edge := range v.edgeGateways {
+		s.unhandle("/admin/edgeGateway/" + edge.id)
+	}
+	for _, profile := range v.storageProfiles {
+		s.unhandle("/admin/vdcStorageProfile/" + profile.id)
+	}
+	s.unhandle("/vdc/" + v.id)
+	s.unhandle("/admin/vdc/" + v.id)
+}
+
+func validAllocationModel(allocationModel string) bool {
+	switch allocationModel {
+	case types.AllocationVApp, types.AllocationPool, types.ReservationPool:
+		return true
+	}
+	return false
+}
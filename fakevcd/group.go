@@ -0,0 +1,217 @@
+/*
+ * Copyright 2014 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package fakevcd
+
+import (
+	"fmt"
+	"net/http"
+
+	types "github.com/vmware/go-vcloud-director/types/v56"
+)
+
+// Group is a group of an organization, imported from its LDAP directory.
+type Group struct {
+	Group *types.Group
+
+	org *Org
+	id  string
+}
+
+// AddLdapGroup adds a group with the given members to the LDAP directory
+// of the organization, to be imported with the groups API. Importing the
+// group imports its members as users, instead of waiting for their first
+// login as vCD does.
+func (o *Org) AddLdapGroup(name string, members ...string) {
+	s := o.server
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if o.directory == nil {
+		o.directory = make(map[string][]string)
+	}
+	o.directory[name] = members
+}
+
+func (o *Org) ldap() *types.OrgLdapSettingsType {
+	if o.ldapSettings == nil {
+		o.ldapSettings = &types.OrgLdapSettingsType{OrgLdapMode: types.OrgLdapModeNone}
+	}
+	settings := *o.ldapSettings
+	settings.HREF = o.server.href("/admin/org/" + o.id + "/settings/ldap")
+	settings.Type = types.MimeOrgLdapSettings
+	return &settings
+}
+
+// serveLdapSettings serves the LDAP settings of the organization.
+func (o *Org) serveLdapSettings(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		writeXML(w, http.StatusOK, "OrgLdapSettings", types.MimeOrgLdapSettings, o.ldap())
+	case http.MethodPut:
+		params := &types.OrgLdapSettingsType{}
+		if err := readXML(r, params); err != nil {
+			writeError(w, http.StatusBadRequest, "", err.Error())
+			return
+		}
+		switch params.OrgLdapMode {
+		case types.OrgLdapModeNone, types.OrgLdapModeSystem:
+			params.CustomOrgLdapSettings = nil
+		case types.OrgLdapModeCustom:
+			if params.CustomOrgLdapSettings == nil || params.CustomOrgLdapSettings.HostName == "" {
+				writeError(w, http.StatusBadRequest, "", "The custom LDAP settings require a host name.")
+				return
+			}
+			params.CustomOrgLdapSettings.Password = ""
+		default:
+			writeError(w, http.StatusBadRequest, "", fmt.Sprintf("Invalid LDAP mode %s.", params.OrgLdapMode))
+			return
+		}
+		o.ldapSettings = params
+		writeXML(w, http.StatusOK, "OrgLdapSettings", types.MimeOrgLdapSettings, o.ldap())
+	default:
+		writeMethodNotAllowed(w, r)
+	}
+}
+
+// importGroup serves the import of an LDAP group into the organization.
+func (o *Org) importGroup(w http.ResponseWriter, r *http.Request) {
+	s := o.server
+	params := &types.Group{}
+	if err := readXML(r, params); err != nil {
+		writeError(w, http.StatusBadRequest, "", err.Error())
+		return
+	}
+	if o.ldap().OrgLdapMode == types.OrgLdapModeNone {
+		writeError(w, http.StatusBadRequest, "", "The organization has no LDAP service.")
+		return
+	}
+	if !s.isRole(params.Role) {
+		writeError(w, http.StatusBadRequest, "", "The group role is missing or invalid.")
+		return
+	}
+	nameInSource := params.NameInSource
+	if nameInSource == "" {
+		nameInSource = params.Name
+	}
+	members, ok := o.directory[nameInSource]
+	if !ok {
+		writeError(w, http.StatusBadRequest, "", fmt.Sprintf("The group %s was not found in LDAP.", nameInSource))
+		return
+	}
+	for _, group := range o.groups {
+		if group.Group.Name == params.Name {
+			writeError(w, http.StatusBadRequest, "DUPLICATE_NAME", fmt.Sprintf("The group %s already exists.", params.Name))
+			return
+		}
+	}
+
+	id := s.newID()
+	group := &Group{org: o, id: id}
+	group.Group = &types.Group{
+		HREF:         s.href("/admin/group/" + id),
+		Type:         types.MimeAdminGroup,
+		ID:           "urn:vcloud:group:" + id,
+		Name:         params.Name,
+		Description:  params.Description,
+		NameInSource: nameInSource,
+		Role:         params.Role,
+		ProviderType: "INTEGRATED",
+	}
+	o.groups = append(o.groups, group)
+	s.handle("/admin/group/"+id, group.serve)
+
+	ref := &types.Reference{HREF: group.Group.HREF, Type: types.MimeAdminGroup, Name: group.Group.Name}
+	for _, member := range members {
+		user := o.userByName(member)
+		if user == nil {
+			user = o.addUser(&types.User{Name: member, IsEnabled: true, Role: params.Role})
+			user.User.IsExternal = true
+			user.User.IsGroupRole = true
+		}
+		if user.User.GroupReferences == nil {
+			user.User.GroupReferences = &types.GroupReferences{}
+		}
+		user.User.GroupReferences.GroupReference = append(user.User.GroupReferences.GroupReference, ref)
+	}
+	writeXML(w, http.StatusCreated, "Group", types.MimeAdminGroup, group.render())
+}
+
+func (o *Org) userByName(name string) *User {
+	for _, user := range o.users {
+		if user.User.Name == name {
+			return user
+		}
+	}
+	return nil
+}
+
+// render returns the group along with the references to its users.
+func (g *Group) render() *types.Group {
+	group := *g.Group
+	group.UsersList = &types.UsersList{}
+	for _, user := range g.org.users {
+		if user.memberOf(g) {
+			group.UsersList.UserReference = append(group.UsersList.UserReference, &types.Reference{
+				HREF: user.User.HREF, Type: types.MimeAdminUser, Name: user.User.Name,
+			})
+		}
+	}
+	return &group
+}
+
+func (u *User) memberOf(g *Group) bool {
+	if u.User.GroupReferences == nil {
+		return false
+	}
+	for _, ref := range u.User.GroupReferences.GroupReference {
+		if ref.HREF == g.Group.HREF {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *Group) serve(w http.ResponseWriter, r *http.Request, rest string) {
+	switch {
+	case rest == "" && r.Method == http.MethodGet:
+		writeXML(w, http.StatusOK, "Group", types.MimeAdminGroup, g.render())
+	case rest == "" && r.Method == http.MethodPut:
+		params := &types.Group{}
+		if err := readXML(r, params); err != nil {
+			writeError(w, http.StatusBadRequest, "", err.Error())
+			return
+		}
+		if !g.org.server.isRole(params.Role) {
+			writeError(w, http.StatusBadRequest, "", "The group role is missing or invalid.")
+			return
+		}
+		g.Group.Description = params.Description
+		g.Group.Role = params.Role
+		writeXML(w, http.StatusOK, "Group", types.MimeAdminGroup, g.render())
+	case rest == "" && r.Method == http.MethodDelete:
+		for _, user := range g.org.users {
+			if user.User.GroupReferences == nil {
+				continue
+			}
+			refs := user.User.GroupReferences.GroupReference[:0]
+			for _, ref := range user.User.GroupReferences.GroupReference {
+				if ref.HREF != g.Group.HREF {
+					refs = append(refs, ref)
+				}
+			}
+			user.User.GroupReferences.GroupReference = refs
+		}
+		for i, group := range g.org.groups {
+			if group == g {
+				g.org.groups = append(g.org.groups[:i], g.org.groups[i+1:]...)
+				break
+			}
+		}
+		g.org.server.unhandle("/admin/group/" + g.id)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeMethodNotAllowed(w, r)
+	}
+}
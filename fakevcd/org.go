@@ -0,0 +1,313 @@
+/*
+ * Copyright 2014 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package fakevcd
+
+import (
+	"net/http"
+	"strings"
+
+	types "github.com/vmware/go-vcloud-director/types/v56"
+)
+
+// Org is an organization of the fake server. The Org field holds the
+// static part of the organization, links to its children are added when
+// it is served.
+type Org struct {
+	Org *types.Org
+
+	server   *Server
+	id       string
+	vdcs     []*Vdc
+	catalogs []*Catalog
+	users    []*User
+	groups   []*Group
+
+	ldapSettings *types.OrgLdapSettingsType
+	directory    map[string][]string
+	settings     map[string]interface{}
+}
+
+// Catalog is a catalog of an organization.
+type Catalog struct {
+	Catalog *types.Catalog
+
+	server *Server
+	org    *Org
+	id     string
+	items  []*CatalogItem
+	access *types.ControlAccessParams
+}
+
+// CatalogItem is a catalog item referencing a vApp template.
+type CatalogItem struct {
+	CatalogItem  *types.CatalogItem
+	VAppTemplate *types.VAppTemplate
+}
+
+// AddOrg adds an enabled organization to the server.
+func (s *Server) AddOrg(name string) *Org {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := s.newID()
+	org := &Org{
+		server: s,
+		id:     id,
+		Org: &types.Org{
+			HREF:      s.href("/org/" + id),
+			Type:      types.MimeOrg,
+			ID:        "urn:vcloud:org:" + id,
+			Name:      name,
+			FullName:  name,
+			IsEnabled: true,
+		},
+	}
+	s.orgs = append(s.orgs, org)
+	s.handle("/org/"+id, org.serve)
+	s.handle("/admin/org/"+id, org.serveAdmin)
+	s.handle("/tasksList/"+id, org.serveTasksList)
+	return org
+}
+
+func (o *Org) render() *types.Org {
+	org := *o.Org
+	org.Link = nil
+	for _, vdc := range o.vdcs {
+		org.Link = append(org.Link, link(types.RelDown, types.MimeVDC, vdc.Vdc.HREF, vdc.Vdc.Name))
+	}
+	for _, catalog := range o.catalogs {
+		org.Link = append(org.Link, link(types.RelDown, types.MimeCatalog, catalog.Catalog.HREF, catalog.Catalog.Name))
+	}
+	org.Link = append(org.Link, link(types.RelDown, types.MimeTasksList, o.server.href("/tasksList/"+o.id), ""))
+	org.Link = append(org.Link, o.Org.Link...)
+	return &org
+}
+
+func (o *Org) serve(w http.ResponseWriter, r *http.Request, rest string) {
+	if rest != "" || r.Method != http.MethodGet {
+		writeMethodNotAllowed(w, r)
+		return
+	}
+	writeXML(w, http.StatusOK, "Org", types.MimeOrg, o.render())
+}
+
+func (o *Org) serveAdmin(w http.ResponseWriter, r *http.Request, rest string) {
+	switch {
+	case rest == "/users" && r.Method == http.MethodPost:
+		o.createUser(w, r)
+		return
+	case rest == "/groups" && r.Method == http.MethodPost:
+		o.importGroup(w, r)
+		return
+	case rest == "/roles" && r.Method == http.MethodPost:
+		o.server.createRole(w, r, o)
+		return
+	case rest == "/vdcsparams" && r.Method == http.MethodPost:
+		o.createVdc(w, r)
+		return
+	case rest == "/settings/ldap":
+		o.serveLdapSettings(w, r)
+		return
+	case rest == "/settings" || strings.HasPrefix(rest, "/settings/"):
+		o.serveSettings(w, r, rest)
+		return
+	}
+	if rest == "" && r.Method == http.MethodPut {
+		o.update(w, r)
+		return
+	}
+	if rest != "" || r.Method != http.MethodGet {
+		writeMethodNotAllowed(w, r)
+		return
+	}
+	org := &types.AdminOrg{
+		HREF:        o.server.href("/admin/org/" + o.id),
+		Type:        "application/vnd.vmware.admin.organization+xml",
+		ID:          o.Org.ID,
+		Name:        o.Org.Name,
+		Description: o.Org.Description,
+		FullName:    o.Org.FullName,
+		IsEnabled:   o.Org.IsEnabled,
+		Vdcs:        &types.VDCList{},
+		Networks:    &types.NetworksList{},
+		Catalogs:    &types.CatalogsList{},
+		Users:       &types.OrgUserList{},
+		Groups:      &types.OrgGroupList{},
+
+		OrgSettings:     o.orgSettings(),
+		RightReferences: o.server.rightReferences(),
+		RoleReferences:  o.server.roleReferences(o),
+	}
+	for _, user := range o.users {
+		org.Users.User = append(org.Users.User, &types.Reference{
+			HREF: user.User.HREF, Type: types.MimeAdminUser, Name: user.User.Name,
+		})
+	}
+	for _, group := range o.groups {
+		org.Groups.Group = append(org.Groups.Group, &types.Reference{
+			HREF: group.Group.HREF, Type: types.MimeAdminGroup, Name: group.Group.Name,
+		})
+	}
+	for _, vdc := range o.vdcs {
+		org.Vdcs.Vdcs = append(org.Vdcs.Vdcs, &types.Reference{
+			HREF: o.server.href("/admin/vdc/" + vdc.id), Type: "application/vnd.vmware.admin.vdc+xml", Name: vdc.Vdc.Name,
+		})
+		for _, network := range vdc.networks {
+			org.Networks.Networks = append(org.Networks.Networks, &types.Reference{
+				HREF: o.server.href("/admin/network/" + network.id), Type: "application/vnd.vmware.admin.network+xml", Name: network.Network.Name,
+			})
+		}
+	}
+	for _, catalog := range o.catalogs {
+		org.Catalogs.Catalog = append(org.Catalogs.Catalog, &types.Reference{
+			HREF: o.server.href("/admin/catalog/" + catalog.id), Type: "application/vnd.vmware.admin.catalog+xml", Name: catalog.Catalog.Name,
+		})
+	}
+	writeXML(w, http.StatusOK, "AdminOrg", "application/vnd.vmware.admin.organization+xml", org)
+}
+
+// update changes the names, the enabled flag and the settings sent with
+// the organization, like vCD does.
+func (o *Org) update(w http.ResponseWriter, r *http.Request) {
+	params := &types.AdminOrg{}
+	if err := readXML(r, params); err != nil {
+		writeError(w, http.StatusBadRequest, "", err.Error())
+		return
+	}
+	o.Org.Name = params.Name
+	o.Org.FullName = params.FullName
+	o.Org.Description = params.Description
+	o.Org.IsEnabled = params.IsEnabled
+	if params.OrgSettings != nil {
+		o.updateSettings(params.OrgSettings)
+	}
+	owner := &types.Reference{HREF: o.server.href("/admin/org/" + o.id), Name: o.Org.Name, Type: "application/vnd.vmware.admin.organization+xml"}
+	writeTask(w, o.server.newTask(o, "orgUpdateOrg", owner, nil))
+}
+
+func (o *Org) serveTasksList(w http.ResponseWriter, r *http.Request, rest string) {
+	if rest != "" || r.Method != http.MethodGet {
+		writeMethodNotAllowed(w, r)
+		return
+	}
+	list := &types.TasksList{
+		HREF: o.server.href("/tasksList/" + o.id),
+		Type: types.MimeTasksList,
+		Name: "Tasks Lists",
+	}
+	for _, t := range o.server.tasks {
+		if t.org == o {
+			list.Task = append(list.Task, t.task)
+		}
+	}
+	writeXML(w, http.StatusOK, "TasksList", types.MimeTasksList, list)
+}
+
+// AddCatalog adds an empty catalog to the organization.
+func (o *Org) AddCatalog(name string) *Catalog {
+	s := o.server
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := s.newID()
+	catalog := &Catalog{
+		server: s,
+		org:    o,
+		id:     id,
+		Catalog: &types.Catalog{
+			HREF:        s.href("/catalog/" + id),
+			Type:        types.MimeCatalog,
+			ID:          "urn:vcloud:catalog:" + id,
+			Name:        name,
+			DateCreated: "2018-01-01T00:00:00.000Z",
+		},
+	}
+	o.catalogs = append(o.catalogs, catalog)
+	s.handle("/catalog/"+id, catalog.serve)
+	s.handle("/admin/catalog/"+id, catalog.serve)
+	s.handle("/org/"+o.id+"/catalog/"+id, func(w http.ResponseWriter, r *http.Request, rest string) {
+		serveControlAccess(w, r, rest, o, &catalog.access, true)
+	})
+	return catalog
+}
+
+func (c *Catalog) serve(w http.ResponseWriter, r *http.Request, rest string) {
+	if rest != "" || r.Method != http.MethodGet {
+		writeMethodNotAllowed(w, r)
+		return
+	}
+	catalog := *c.Catalog
+	items := &types.CatalogItems{}
+	for _, item := range c.items {
+		items.CatalogItem = append(items.CatalogItem, &types.Reference{
+			HREF: item.CatalogItem.HREF, Type: types.MimeCatalogItem, Name: item.CatalogItem.Name, ID: item.CatalogItem.ID,
+		})
+	}
+	catalog.CatalogItems = []*types.CatalogItems{items}
+	catalog.Link = append(types.LinkList{link(types.RelUp, types.MimeOrg, c.org.Org.HREF, "")}, c.Catalog.Link...)
+	writeXML(w, http.StatusOK, "Catalog", types.MimeCatalog, &catalog)
+}
+
+// AddItem adds a catalog item referencing a new vApp template with a
+// single VM.
+func (c *Catalog) AddItem(name string) *CatalogItem {
+	s := c.server
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	itemID := s.newID()
+	templateID := s.newID()
+	vmID := s.newID()
+	item := &CatalogItem{
+		CatalogItem: &types.CatalogItem{
+			HREF: s.href("/catalogItem/" + itemID),
+			Type: types.MimeCatalogItem,
+			ID:   "urn:vcloud:catalogitem:" + itemID,
+			Name: name,
+			Entity: &types.Entity{
+				HREF: s.href("/vAppTemplate/vappTemplate-" + templateID),
+				Type: types.MimeVAppTemplate,
+				Name: name,
+			},
+		},
+		VAppTemplate: &types.VAppTemplate{
+			HREF:   s.href("/vAppTemplate/vappTemplate-" + templateID),
+			Type:   types.MimeVAppTemplate,
+			ID:     "urn:vcloud:vapptemplate:" + templateID,
+			Name:   name,
+			Status: 8,
+			Children: &types.VAppTemplateChildren{
+				VM: []*types.VAppTemplate{{
+					HREF:              s.href("/vAppTemplate/vm-" + vmID),
+					Type:              "application/vnd.vmware.vcloud.vm+xml",
+					ID:                "urn:vcloud:vm:" + vmID,
+					Name:              name + "-vm",
+					VAppScopedLocalID: name + "-vm",
+					NetworkConnectionSection: &types.NetworkConnectionSection{
+						HREF: s.href("/vAppTemplate/vm-" + vmID + "/networkConnectionSection/"),
+						Type: "application/vnd.vmware.vcloud.networkConnectionSection+xml",
+						Info: "Specifies the available VM network connections",
+					},
+				}},
+			},
+		},
+	}
+	c.items = append(c.items, item)
+	s.handle("/catalogItem/"+itemID, func(w http.ResponseWriter, r *http.Request, rest string) {
+		if rest != "" || r.Method != http.MethodGet {
+			writeMethodNotAllowed(w, r)
+			return
+		}
+		writeXML(w, http.StatusOK, "CatalogItem", types.MimeCatalogItem, item.CatalogItem)
+	})
+	s.handle("/vAppTemplate/vappTemplate-"+templateID, func(w http.ResponseWriter, r *http.Request, rest string) {
+		if rest != "" || r.Method != http.MethodGet {
+			writeMethodNotAllowed(w, r)
+			return
+		}
+		writeXML(w, http.StatusOK, "VAppTemplate", types.MimeVAppTemplate, item.VAppTemplate)
+	})
+	return item
+}
@@ -0,0 +1,196 @@
+/*
+ * Copyright 2014 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package fakevcd
+
+import (
+	"net/http"
+	"reflect"
+
+	types "github.com/vmware/go-vcloud-director/types/v56"
+)
+
+// orgSettingsSection describes a section of the settings of an
+// organization, served under /admin/org/<id>/settings/<path>.
+type orgSettingsSection struct {
+	path string
+	root string
+	mime string
+	// defaults returns the section of a new organization.
+	defaults func() interface{}
+	// check returns why an update of the section is invalid, if it is,
+	// and clears the passwords it holds.
+	check func(v interface{}) string
+}
+
+var orgSettingsSections = []orgSettingsSection{
+	{
+		path: "general", root: "OrgGeneralSettings", mime: types.MimeOrgGeneralSettings,
+		defaults: func() interface{} { return &types.OrgGeneralSettings{CanPublishCatalogs: true} },
+	},
+	{
+		path: "vAppLeaseSettings", root: "VAppLeaseSettings", mime: types.MimeVAppLeaseSettings,
+		defaults: func() interface{} {
+			return &types.VAppLeaseSettings{DeploymentLeaseSeconds: 604800, StorageLeaseSeconds: 2592000}
+		},
+	},
+	{
+		path: "vAppTemplateLeaseSettings", root: "VAppTemplateLeaseSettings", mime: types.MimeVAppTemplateLeaseSettings,
+		defaults: func() interface{} { return &types.VAppTemplateLeaseSettings{StorageLeaseSeconds: 7776000} },
+	},
+	{
+		path: "email", root: "OrgEmailSettings", mime: types.MimeOrgEmailSettings,
+		defaults: func() interface{} {
+			return &types.OrgEmailSettings{IsDefaultSmtpServer: true, IsDefaultOrgEmail: true, IsAlertEmailToAllAdmins: true}
+		},
+		check: func(v interface{}) string {
+			settings := v.(*types.OrgEmailSettings)
+			if !settings.IsDefaultSmtpServer && (settings.SmtpServerSettings == nil || settings.SmtpServerSettings.Host == "") {
+				return "The SMTP server host is required."
+			}
+			if settings.SmtpServerSettings != nil {
+				settings.SmtpServerSettings.Password = ""
+			}
+			return ""
+		},
+	},
+	{
+		path: "passwordPolicy", root: "OrgPasswordPolicySettings", mime: types.MimeOrgPasswordPolicySettings,
+		defaults: func() interface{} {
+			return &types.OrgPasswordPolicySettings{InvalidLoginsBeforeLockout: 5, AccountLockoutIntervalMinutes: 10}
+		},
+		check: func(v interface{}) string {
+			settings := v.(*types.OrgPasswordPolicySettings)
+			if settings.AccountLockoutEnabled && (settings.InvalidLoginsBeforeLockout < 1 || settings.InvalidLoginsBeforeLockout > 15) {
+				return "The number of invalid logins before lockout must be between 1 and 15."
+			}
+			return ""
+		},
+	},
+	{
+		path: "guestPersonalizationSettings", root: "GuestPersonalizationSettings", mime: types.MimeGuestPersonalizationSettings,
+		defaults: func() interface{} { return &types.GuestPersonalizationSettings{} },
+		check: func(v interface{}) string {
+			settings := v.(*types.GuestPersonalizationSettings)
+			if settings.AllowDomainSettings && settings.DomainName == "" {
+				return "The domain name is required."
+			}
+			settings.AccountPassword = ""
+			return ""
+		},
+	},
+	{
+		path: "federation", root: "OrgFederationSettings", mime: types.MimeOrgFederationSettings,
+		defaults: func() interface{} { return &types.OrgFederationSettings{} },
+		check: func(v interface{}) string {
+			if settings := v.(*types.OrgFederationSettings); settings.Enabled && settings.SAMLMetadata == "" {
+				return "The SAML metadata is required."
+			}
+			return ""
+		},
+	},
+}
+
+// settingsSection returns the stored section of the settings of the
+// organization served at path.
+func (o *Org) settingsSection(path string) interface{} {
+	if o.settings == nil {
+		o.settings = make(map[string]interface{})
+	}
+	if _, ok := o.settings[path]; !ok {
+		for _, section := range orgSettingsSections {
+			if section.path == path {
+				o.storeSettings(section, section.defaults())
+			}
+		}
+	}
+	return o.settings[path]
+}
+
+func (o *Org) storeSettings(section orgSettingsSection, v interface{}) {
+	value := reflect.ValueOf(v).Elem()
+	value.FieldByName("HREF").SetString(o.server.href("/admin/org/" + o.id + "/settings/" + section.path))
+	value.FieldByName("Type").SetString(section.mime)
+	o.settings[section.path] = v
+}
+
+// orgSettings returns every section of the settings of the organization.
+func (o *Org) orgSettings() *types.OrgSettings {
+	return &types.OrgSettings{
+		HREF:                      o.server.href("/admin/org/" + o.id + "/settings"),
+		Type:                      types.MimeOrgSettings,
+		OrgGeneralSettings:        o.settingsSection("general").(*types.OrgGeneralSettings),
+		OrgVAppLeaseSettings:      o.settingsSection("vAppLeaseSettings").(*types.VAppLeaseSettings),
+		OrgVAppTemplateSettings:   o.settingsSection("vAppTemplateLeaseSettings").(*types.VAppTemplateLeaseSettings),
+		OrgLdapSettings:           o.ldap(),
+		OrgEmailSettings:          o.settingsSection("email").(*types.OrgEmailSettings),
+		OrgPasswordPolicySettings: o.settingsSection("passwordPolicy").(*types.OrgPasswordPolicySettings),
+		OrgFederationSettings:     o.settingsSection("federation").(*types.OrgFederationSettings),
+	}
+}
+
+// updateSettings stores the sections of settings that are set, as a PUT of
+// the whole organization does. The email section loses its SMTP password.
+func (o *Org) updateSettings(settings *types.OrgSettings) {
+	sent := map[string]interface{}{
+		"general":                   settings.OrgGeneralSettings,
+		"vAppLeaseSettings":         settings.OrgVAppLeaseSettings,
+		"vAppTemplateLeaseSettings": settings.OrgVAppTemplateSettings,
+		"email":                     settings.OrgEmailSettings,
+		"passwordPolicy":            settings.OrgPasswordPolicySettings,
+		"federation":                settings.OrgFederationSettings,
+	}
+	for _, section := range orgSettingsSections {
+		v, ok := sent[section.path]
+		if !ok || reflect.ValueOf(v).IsNil() {
+			continue
+		}
+		if section.check != nil {
+			section.check(v)
+		}
+		o.settingsSection(section.path)
+		o.storeSettings(section, v)
+	}
+}
+
+// serveSettings serves the settings of the organization, as a whole and
+// by section. The LDAP settings are served by serveLdapSettings.
+func (o *Org) serveSettings(w http.ResponseWriter, r *http.Request, rest string) {
+	if rest == "/settings" {
+		if r.Method != http.MethodGet {
+			writeMethodNotAllowed(w, r)
+			return
+		}
+		writeXML(w, http.StatusOK, "OrgSettings", types.MimeOrgSettings, o.orgSettings())
+		return
+	}
+	for _, section := range orgSettingsSections {
+		if rest != "/settings/"+section.path {
+			continue
+		}
+		switch r.Method {
+		case http.MethodGet:
+			writeXML(w, http.StatusOK, section.root, section.mime, o.settingsSection(section.path))
+		case http.MethodPut:
+			params := reflect.New(reflect.TypeOf(section.defaults()).Elem()).Interface()
+			if err := readXML(r, params); err != nil {
+				writeError(w, http.StatusBadRequest, "", err.Error())
+				return
+			}
+			if section.check != nil {
+				if message := section.check(params); message != "" {
+					writeError(w, http.StatusBadRequest, "", message)
+					return
+				}
+			}
+			o.settingsSection(section.path)
+			o.storeSettings(section, params)
+			writeXML(w, http.StatusOK, section.root, section.mime, params)
+		default:
+			writeMethodNotAllowed(w, r)
+		}
+		return
+	}
+	writeNotFound(w, r.URL.Path)
+}
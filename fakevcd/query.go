@@ -0,0 +1,512 @@
+/*
+ * Copyright 2014 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package fakevcd
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	types "github.com/vmware/go-vcloud-director/types/v56"
+)
+
+const defaultPageSize = 25
+
+// queryType describes how the results of a query type are returned.
+type queryType struct {
+	// records is the types.QueryResultRecordsType field holding the
+	// records.
+	records string
+	// reference is the name of the elements holding the references.
+	reference string
+	// mime is the MIME type of the entities.
+	mime string
+}
+
+// queryTypes lists the query types served by the fake server.
+var queryTypes = map[string]queryType{
+	"vApp":                 {"VAppRecord", "VAppReference", types.MimeVApp},
+	"adminVApp":            {"VAppRecord", "AdminVAppReference", types.MimeVApp},
+	"vm":                   {"VMRecord", "VMReference", "application/vnd.vmware.vcloud.vm+xml"},
+	"adminVM":              {"VMRecord", "AdminVMReference", "application/vnd.vmware.vcloud.vm+xml"},
+	"edgeGateway":          {"EdgeGatewayRecord", "EdgeGatewayReference", "application/vnd.vmware.admin.edgeGateway+xml"},
+	"orgVdcStorageProfile": {"OrgVdcStorageProfileRecord", "OrgVdcStorageProfileReference", "application/vnd.vmware.vcloud.vdcStorageProfile+xml"},
+	"orgVdc":               {"OrgVdcRecord", "OrgVdcReference", types.MimeVDC},
+	"adminOrgVdc":          {"AdminVdcRecord", "AdminVdcReference", "application/vnd.vmware.admin.vdc+xml"},
+	"orgVdcNetwork":        {"OrgVdcNetworkRecord", "OrgVdcNetworkReference", "application/vnd.vmware.vcloud.orgVdcNetwork+xml"},
+	"catalog":              {"CatalogRecord", "CatalogReference", types.MimeCatalog},
+	"catalogItem":          {"CatalogItemRecord", "CatalogItemReference", types.MimeCatalogItem},
+	"vAppTemplate":         {"VAppTemplateRecord", "VAppTemplateReference", types.MimeVAppTemplate},
+	"task":                 {"TaskRecord", "TaskReference", types.MimeTask},
+	"user":                 {"UserRecord", "UserReference", types.MimeAdminUser},
+	"group":                {"GroupRecord", "GroupReference", types.MimeAdminGroup},
+}
+
+// queryFormats maps the query formats to the MIME type of their results.
+var queryFormats = map[string]string{
+	"records":    types.MimeQueryResultRecords,
+	"references": types.MimeQueryResultReferences,
+	"idrecords":  types.MimeQueryResultIdRecords,
+}
+
+// record is a query result record along with the attributes the query
+// filters can match.
+type record struct {
+	attributes map[string]string
+	value      interface{}
+}
+
+// queryReference is a result of a query in references format, named after
+// the query type, e.g. VAppReference.
+type queryReference struct {
+	XMLName xml.Name
+	types.Reference
+}
+
+// queryResultReferences is the container of the results of a query in
+// references format.
+type queryResultReferences struct {
+	HREF       string        `xml:"href,attr,omitempty"`
+	Type       string        `xml:"type,attr,omitempty"`
+	Name       string        `xml:"name,attr,omitempty"`
+	Page       int           `xml:"page,attr,omitempty"`
+	PageSize   int           `xml:"pageSize,attr,omitempty"`
+	Total      float64       `xml:"total,attr,omitempty"`
+	Link       []*types.Link `xml:"Link,omitempty"`
+	References []*queryReference
+}
+
+// serveQuery serves the query list, and the typed queries listed in
+// queryTypes. Filters support the comparison
+// operators on record attributes, joined with ';' and ',' and grouped with
+// parentheses. The fields parameter is ignored, records are always
+// complete.
+func (s *Server) serveQuery(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeMethodNotAllowed(w, r)
+		return
+	}
+	params := r.URL.Query()
+	typeName := params.Get("type")
+	if typeName == "" {
+		s.serveQueryList(w)
+		return
+	}
+	queryType, ok := queryTypes[typeName]
+	if !ok {
+		writeError(w, http.StatusBadRequest, "", "Unsupported query type "+typeName+".")
+		return
+	}
+	records := s.queryRecords(typeName)
+	format := params.Get("format")
+	if format == "" {
+		format = "records"
+	}
+	mime, ok := queryFormats[format]
+	if !ok {
+		writeError(w, http.StatusBadRequest, "", "Unsupported query format "+format+".")
+		return
+	}
+	match, err := parseFilter(params.Get("filter"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "", "Invalid filter: "+err.Error())
+		return
+	}
+
+	var filtered []record
+	for _, rec := range records {
+		if match(rec.attributes) {
+			filtered = append(filtered, rec)
+		}
+	}
+	if attribute, desc := params.Get("sortAsc"), params.Get("sortDesc"); attribute != "" || desc != "" {
+		if desc != "" {
+			attribute = desc
+		}
+		sort.SliceStable(filtered, func(i, j int) bool {
+			c := compareValues(filtered[i].attributes[attribute], filtered[j].attributes[attribute])
+			if desc != "" {
+				return c > 0
+			}
+			return c < 0
+		})
+	}
+
+	page, _ := strconv.Atoi(params.Get("page"))
+	if page < 1 {
+		page = 1
+	}
+	pageSize, _ := strconv.Atoi(params.Get("pageSize"))
+	if pageSize < 1 {
+		pageSize = defaultPageSize
+	}
+	var pageRecords []record
+	for i := (page - 1) * pageSize; i < len(filtered) && i < page*pageSize; i++ {
+		pageRecords = append(pageRecords, filtered[i])
+	}
+	var links []*types.Link
+	if page*pageSize < len(filtered) {
+		next := r.URL.Query()
+		next.Set("page", strconv.Itoa(page+1))
+		next.Set("pageSize", strconv.Itoa(pageSize))
+		links = append(links, &types.Link{Rel: "nextPage", Type: mime, HREF: s.href("/query?" + next.Encode())})
+	}
+
+	if format == "references" {
+		results := &queryResultReferences{
+			HREF:     s.URL + r.URL.RequestURI(),
+			Type:     mime,
+			Name:     typeName,
+			Page:     page,
+			PageSize: pageSize,
+			Total:    float64(len(filtered)),
+			Link:     links,
+		}
+		for _, rec := range pageRecords {
+			value := reflect.Indirect(reflect.ValueOf(rec.value))
+			results.References = append(results.References, &queryReference{
+				XMLName:   xml.Name{Local: queryType.reference},
+				Reference: types.Reference{HREF: value.FieldByName("HREF").String(), Type: queryType.mime, Name: value.FieldByName("Name").String()},
+			})
+		}
+		writeXML(w, http.StatusOK, "QueryResultReferences", mime, results)
+		return
+	}
+
+	results := &types.QueryResultRecordsType{
+		HREF:     s.URL + r.URL.RequestURI(),
+		Type:     mime,
+		Name:     typeName,
+		Page:     page,
+		PageSize: pageSize,
+		Total:    float64(len(filtered)),
+		Link:     links,
+	}
+	field := reflect.ValueOf(results).Elem().FieldByName(queryType.records)
+	for _, rec := range pageRecords {
+		field.Set(reflect.Append(field, reflect.ValueOf(rec.value)))
+	}
+	writeXML(w, http.StatusOK, "QueryResultRecords", mime, results)
+}
+
+// serveQueryList serves the links to the queries of every type and
+// format.
+func (s *Server) serveQueryList(w http.ResponseWriter) {
+	queryList := &types.QueryList{HREF: s.href("/query"), Type: types.MimeQueryList}
+	var names []string
+	for name := range queryTypes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, queryType := range names {
+		for _, format := range []string{"records", "references", "idrecords"} {
+			queryList.Link = append(queryList.Link, &types.Link{
+				Rel:  "down",
+				Type: queryFormats[format],
+				Name: queryType,
+				HREF: s.href("/query?type=" + queryType + "&format=" + format),
+			})
+		}
+	}
+	writeXML(w, http.StatusOK, "QueryList", types.MimeQueryList, queryList)
+}
+
+// queryRecords returns all the records of a query type.
+func (s *Server) queryRecords(queryType string) []record {
+	var records []record
+	add := func(attributes map[string]string, value interface{}) {
+		records = append(records, record{attributes: attributes, value: value})
+	}
+	if queryType == "task" {
+		for _, t := range s.tasks {
+			rec := &types.QueryResultTaskRecordType{
+				HREF: t.task.HREF, Name: t.task.OperationName, Status: t.task.Status,
+				StartDate: t.task.StartTime, EndDate: t.task.EndTime, Details: t.failReason,
+			}
+			if t.task.Owner != nil {
+				rec.Object, rec.ObjectName = t.task.Owner.HREF, t.task.Owner.Name
+			}
+			if t.org != nil {
+				rec.Org, rec.OrgName = t.org.Org.HREF, t.org.Org.Name
+			}
+			add(map[string]string{"name": rec.Name, "status": rec.Status, "objectName": rec.ObjectName, "orgName": rec.OrgName}, rec)
+		}
+		return records
+	}
+	for _, org := range s.orgs {
+		if queryType == "user" {
+			for _, user := range org.users {
+				add(map[string]string{"name": user.User.Name, "fullName": user.User.FullName, "isEnabled": fmt.Sprint(user.User.IsEnabled)},
+					&types.QueryResultUserRecordType{
+						HREF: user.User.HREF, ID: user.User.ID, Name: user.User.Name, FullName: user.User.FullName,
+						Email: user.User.EmailAddress, Telephone: user.User.Telephone, IsEnabled: user.User.IsEnabled,
+						DeployedVMQuota: user.User.DeployedVmQuota, StoredVMQuota: user.User.StoredVmQuota, IsLdapUser: user.User.IsExternal,
+					})
+			}
+		}
+		if queryType == "group" {
+			for _, group := range org.groups {
+				add(map[string]string{"name": group.Group.Name, "roleName": group.Group.Role.Name},
+					&types.QueryResultGroupRecordType{
+						HREF: group.Group.HREF, ID: group.Group.ID, Name: group.Group.Name,
+						RoleName: group.Group.Role.Name, IdentityProviderType: group.Group.ProviderType,
+					})
+			}
+		}
+		for _, catalog := range org.catalogs {
+			switch queryType {
+			case "catalog":
+				add(map[string]string{"name": catalog.Catalog.Name, "orgName": org.Org.Name, "numberOfVAppTemplates": strconv.Itoa(len(catalog.items))},
+					&types.QueryResultCatalogRecordType{
+						HREF: catalog.Catalog.HREF, ID: catalog.Catalog.ID, Name: catalog.Catalog.Name,
+						Description: catalog.Catalog.Description, IsPublished: catalog.Catalog.IsPublished,
+						CreationDate: catalog.Catalog.DateCreated, OrgName: org.Org.Name, NumberOfVAppTemplates: len(catalog.items),
+					})
+			case "catalogItem":
+				for _, item := range catalog.items {
+					add(map[string]string{"name": item.CatalogItem.Name, "catalog": catalog.Catalog.HREF, "catalogName": catalog.Catalog.Name},
+						&types.QueryResultCatalogItemRecordType{
+							HREF: item.CatalogItem.HREF, ID: item.CatalogItem.ID, Name: item.CatalogItem.Name,
+							Entity: item.CatalogItem.Entity.HREF, EntityName: item.CatalogItem.Entity.Name, EntityType: "vapptemplate",
+							Catalog: catalog.Catalog.HREF, CatalogName: catalog.Catalog.Name, IsPublished: catalog.Catalog.IsPublished,
+						})
+				}
+			case "vAppTemplate":
+				for _, item := range catalog.items {
+					add(map[string]string{"name": item.VAppTemplate.Name, "catalogName": catalog.Catalog.Name},
+						&types.QueryResultVAppTemplateRecordType{
+							HREF: item.VAppTemplate.HREF, ID: item.VAppTemplate.ID, Name: item.VAppTemplate.Name,
+							Org: org.Org.HREF, CatalogName: catalog.Catalog.Name, Status: types.VAppStatuses[item.VAppTemplate.Status],
+							IsEnabled: true, IsGoldMaster: item.VAppTemplate.GoldMaster, IsPublished: catalog.Catalog.IsPublished,
+						})
+				}
+			}
+		}
+		for _, vdc := range org.vdcs {
+			switch queryType {
+			case "vApp", "adminVApp":
+				for _, vapp := range vdc.vapps {
+					add(map[string]string{"name": vapp.VApp.Name, "vdc": vdc.Vdc.HREF, "vdcName": vdc.Vdc.Name, "numberOfVMs": strconv.Itoa(len(vapp.vms))},
+						&types.QueryResultVAppRecordType{
+							HREF: vapp.VApp.HREF, Name: vapp.VApp.Name, Deployed: vapp.VApp.Deployed,
+							Status: types.VAppStatuses[vapp.VApp.Status], VdcHREF: vdc.Vdc.HREF, VdcName: vdc.Vdc.Name,
+							NumberOfVMs: len(vapp.vms), VdcEnabled: vdc.Vdc.IsEnabled,
+						})
+				}
+			case "vm", "adminVM":
+				for _, vapp := range vdc.vapps {
+					for _, vm := range vapp.vms {
+						add(map[string]string{"name": vm.VM.Name, "vdc": vdc.Vdc.HREF, "container": vapp.VApp.HREF, "containerName": vapp.VApp.Name, "isVAppTemplate": "false"},
+							&types.QueryResultVMRecordType{
+								HREF: vm.VM.HREF, Name: vm.VM.Name, Deployed: vm.VM.Deployed,
+								Status: types.VAppStatuses[vm.VM.Status], VdcHREF: vdc.Vdc.HREF,
+								VAppParentHREF: vapp.VApp.HREF, VAppParentName: vapp.VApp.Name,
+							})
+					}
+				}
+			case "edgeGateway":
+				for _, edge := range vdc.edgeGateways {
+					add(map[string]string{"name": edge.EdgeGateway.Name, "vdc": vdc.Vdc.HREF},
+						&types.QueryResultEdgeGatewayRecordType{
+							HREF: edge.EdgeGateway.HREF, Name: edge.EdgeGateway.Name, Vdc: vdc.Vdc.HREF, GatewayStatus: "READY",
+						})
+				}
+			case "orgVdcStorageProfile":
+				for _, profile := range vdc.storageProfiles {
+					add(map[string]string{"name": profile.name, "vdc": vdc.Vdc.HREF, "vdcName": vdc.Vdc.Name,
+						"isDefaultStorageProfile": fmt.Sprint(profile.isDefault), "isEnabled": fmt.Sprint(profile.enabled)},
+						&types.QueryResultOrgVdcStorageProfileRecordType{
+							HREF: profile.reference().HREF, Name: profile.name, VdcHREF: vdc.Vdc.HREF, VdcName: vdc.Vdc.Name,
+							IsDefaultStorageProfile: profile.isDefault, IsEnabled: profile.enabled,
+							StorageUsedMB: profile.usedMB, StorageLimitMB: profile.limitMB,
+						})
+				}
+			case "orgVdc", "adminOrgVdc":
+				add(map[string]string{"name": vdc.Vdc.Name, "orgName": org.Org.Name, "numberOfVApps": strconv.Itoa(len(vdc.vapps))},
+					&types.QueryResultVdcRecordType{
+						HREF: vdc.Vdc.HREF, ID: vdc.Vdc.ID, Name: vdc.Vdc.Name, Description: vdc.Vdc.Description,
+						Org: org.Org.HREF, OrgName: org.Org.Name, AllocationModel: vdc.Vdc.AllocationModel,
+						IsEnabled: vdc.Vdc.IsEnabled, NumberOfVApps: len(vdc.vapps),
+					})
+			case "orgVdcNetwork":
+				for _, network := range vdc.networks {
+					rec := &types.QueryResultOrgVdcNetworkRecordType{
+						HREF: network.Network.HREF, ID: network.Network.ID, Name: network.Network.Name,
+						LinkType: 2, Vdc: vdc.Vdc.HREF, VdcName: vdc.Vdc.Name, IsShared: network.Network.IsShared,
+					}
+					if network.Network.EdgeGateway != nil {
+						rec.LinkType, rec.ConnectedTo = 1, network.Network.EdgeGateway.Name
+					}
+					if config := network.Network.Configuration; config != nil && config.IPScopes != nil {
+						scope := config.IPScopes.IPScope
+						rec.DefaultGateway, rec.Netmask, rec.Dns1, rec.Dns2, rec.DnsSuffix = scope.Gateway, scope.Netmask, scope.DNS1, scope.DNS2, scope.DNSSuffix
+					}
+					add(map[string]string{"name": rec.Name, "vdc": vdc.Vdc.HREF, "vdcName": vdc.Vdc.Name, "linkType": strconv.Itoa(rec.LinkType)}, rec)
+				}
+			}
+		}
+	}
+	return records
+}
+
+// parseFilter parses a filter expression, e.g. "name==web*;(status==8,numberOfVMs=gt=2)",
+// into a function matching record attributes. ';' binds tighter than ','
+// and '\' escapes the special characters of values.
+func parseFilter(filter string) (func(map[string]string) bool, error) {
+	if filter == "" {
+		return func(map[string]string) bool { return true }, nil
+	}
+	p := &filterParser{input: filter}
+	match, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.input) {
+		return nil, fmt.Errorf("unexpected %q at %d", p.input[p.pos], p.pos)
+	}
+	return match, nil
+}
+
+type filterParser struct {
+	input string
+	pos   int
+}
+
+func (p *filterParser) parseOr() (func(map[string]string) bool, error) {
+	var terms []func(map[string]string) bool
+	for {
+		term, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, term)
+		if p.pos >= len(p.input) || p.input[p.pos] != ',' {
+			break
+		}
+		p.pos++
+	}
+	return func(attributes map[string]string) bool {
+		for _, term := range terms {
+			if term(attributes) {
+				return true
+			}
+		}
+		return false
+	}, nil
+}
+
+func (p *filterParser) parseAnd() (func(map[string]string) bool, error) {
+	var terms []func(map[string]string) bool
+	for {
+		term, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, term)
+		if p.pos >= len(p.input) || p.input[p.pos] != ';' {
+			break
+		}
+		p.pos++
+	}
+	return func(attributes map[string]string) bool {
+		for _, term := range terms {
+			if !term(attributes) {
+				return false
+			}
+		}
+		return true
+	}, nil
+}
+
+func (p *filterParser) parseTerm() (func(map[string]string) bool, error) {
+	if p.pos < len(p.input) && p.input[p.pos] == '(' {
+		p.pos++
+		match, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.pos >= len(p.input) || p.input[p.pos] != ')' {
+			return nil, fmt.Errorf("missing ')' at %d", p.pos)
+		}
+		p.pos++
+		return match, nil
+	}
+
+	start := p.pos
+	for p.pos < len(p.input) && strings.IndexByte("=!", p.input[p.pos]) < 0 {
+		p.pos++
+	}
+	attribute := p.input[start:p.pos]
+	var operator string
+	for _, op := range []string{"==", "!=", "=lt=", "=le=", "=gt=", "=ge="} {
+		if strings.HasPrefix(p.input[p.pos:], op) {
+			operator = op
+			break
+		}
+	}
+	if attribute == "" || operator == "" {
+		return nil, fmt.Errorf("invalid condition at %d", start)
+	}
+	p.pos += len(operator)
+
+	var value strings.Builder
+	for p.pos < len(p.input) && strings.IndexByte(";,()", p.input[p.pos]) < 0 {
+		if p.input[p.pos] == '\\' && p.pos+1 < len(p.input) {
+			p.pos++
+		}
+		value.WriteByte(p.input[p.pos])
+		p.pos++
+	}
+	expected := value.String()
+
+	return func(attributes map[string]string) bool {
+		actual := attributes[attribute]
+		switch operator {
+		case "==":
+			return matchWildcard(expected, actual)
+		case "!=":
+			return !matchWildcard(expected, actual)
+		case "=lt=":
+			return compareValues(actual, expected) < 0
+		case "=le=":
+			return compareValues(actual, expected) <= 0
+		case "=gt=":
+			return compareValues(actual, expected) > 0
+		}
+		return compareValues(actual, expected) >= 0
+	}, nil
+}
+
+// matchWildcard reports whether value matches pattern, in which '*'
+// matches any sequence of characters.
+func matchWildcard(pattern, value string) bool {
+	if !strings.Contains(pattern, "*") {
+		return pattern == value
+	}
+	expression := strings.Replace(regexp.QuoteMeta(pattern), `\*`, ".*", -1)
+	return regexp.MustCompile("^" + expression + "$").MatchString(value)
+}
+
+// compareValues compares two attribute values, as numbers when they both
+// are.
+func compareValues(a, b string) int {
+	x, errA := strconv.ParseFloat(a, 64)
+	y, errB := strconv.ParseFloat(b, 64)
+	if errA == nil && errB == nil {
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+		return 0
+	}
+	return strings.Compare(a, b)
+}
@@ -0,0 +1,312 @@
+/*
+ * Copyright 2014 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package fakevcd
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	types "github.com/vmware/go-vcloud-director/types/v56"
+)
+
+// DefaultRights lists the rights of a new server.
+var DefaultRights = []string{
+	"General: Administrator View",
+	"Organization: View",
+	"Organization vDC: View",
+	"Role: Create, Edit, Delete, or Copy",
+	"Catalog: View Private and Shared Catalogs",
+	"Catalog: Create / Delete a Catalog",
+	"vApp: Create / Reconfigure a vApp",
+	"vApp: Delete",
+	"vApp: Power Operations",
+	"vApp: Use Console",
+}
+
+// DefaultRoles lists the global roles of a new server. The Organization
+// Administrator has every right, the others a subset of them.
+var DefaultRoles = []string{
+	"Organization Administrator",
+	"Catalog Author",
+	"vApp Author",
+	"vApp User",
+	"Console Access Only",
+}
+
+var defaultRoleRights = map[string][]string{
+	"Catalog Author": {
+		"Organization: View", "Organization vDC: View", "Catalog: View Private and Shared Catalogs",
+		"Catalog: Create / Delete a Catalog", "vApp: Create / Reconfigure a vApp", "vApp: Delete", "vApp: Power Operations",
+	},
+	"vApp Author": {
+		"Organization: View", "Organization vDC: View", "Catalog: View Private and Shared Catalogs",
+		"vApp: Create / Reconfigure a vApp", "vApp: Delete", "vApp: Power Operations", "vApp: Use Console",
+	},
+	"vApp User": {
+		"Organization: View", "Catalog: View Private and Shared Catalogs", "vApp: Power Operations", "vApp: Use Console",
+	},
+	"Console Access Only": {"vApp: Use Console"},
+}
+
+// Role is a global role, or a role local to an organization.
+type Role struct {
+	Role *types.Role
+
+	server *Server
+	org    *Org
+	id     string
+}
+
+func (s *Server) addDefaultRoles() {
+	s.handle("/admin/roles", func(w http.ResponseWriter, r *http.Request, rest string) {
+		if rest != "" || r.Method != http.MethodPost {
+			writeMethodNotAllowed(w, r)
+			return
+		}
+		s.createRole(w, r, nil)
+	})
+	for _, name := range DefaultRights {
+		id := s.newID()
+		category := name[:strings.Index(name, ":")]
+		s.rights = append(s.rights, &types.Right{
+			HREF:     s.href("/admin/right/" + id),
+			Type:     types.MimeAdminRight,
+			ID:       "urn:vcloud:right:" + id,
+			Name:     name,
+			Category: category,
+		})
+		right := s.rights[len(s.rights)-1]
+		s.handle("/admin/right/"+id, func(w http.ResponseWriter, r *http.Request, rest string) {
+			if rest != "" || r.Method != http.MethodGet {
+				writeMethodNotAllowed(w, r)
+				return
+			}
+			writeXML(w, http.StatusOK, "Right", types.MimeAdminRight, right)
+		})
+	}
+	for _, name := range DefaultRoles {
+		rights := &types.RightReferences{}
+		for _, right := range s.rights {
+			if rightNames, ok := defaultRoleRights[name]; !ok || contains(rightNames, right.Name) {
+				rights.RightReference = append(rights.RightReference, rightReference(right))
+			}
+		}
+		s.addRole(nil, &types.Role{Name: name, RightReferences: rights})
+	}
+}
+
+func (s *Server) addRole(org *Org, params *types.Role) *Role {
+	id := s.newID()
+	role := &Role{server: s, org: org, id: id}
+	role.Role = &types.Role{
+		HREF:            s.href("/admin/role/" + id),
+		Type:            types.MimeAdminRole,
+		ID:              "urn:vcloud:role:" + id,
+		Name:            params.Name,
+		Description:     params.Description,
+		RightReferences: params.RightReferences,
+	}
+	s.roles = append(s.roles, role)
+	s.handle("/admin/role/"+id, role.serve)
+	return role
+}
+
+// createRole serves the creation of a global role, or of a role local to
+// org.
+func (s *Server) createRole(w http.ResponseWriter, r *http.Request, org *Org) {
+	params := &types.Role{}
+	if err := readXML(r, params); err != nil {
+		writeError(w, http.StatusBadRequest, "", err.Error())
+		return
+	}
+	if !s.validRole(w, params, org, nil) {
+		return
+	}
+	role := s.addRole(org, params)
+	writeXML(w, http.StatusCreated, "Role", types.MimeAdminRole, role.Role)
+}
+
+// validRole checks the name and the rights of params, a new role or the
+// update of existing, and writes the error when they are invalid.
+func (s *Server) validRole(w http.ResponseWriter, params *types.Role, org *Org, existing *Role) bool {
+	if params.Name == "" {
+		writeError(w, http.StatusBadRequest, "", "The role name is required.")
+		return false
+	}
+	for _, role := range s.roles {
+		if role != existing && role.org == org && role.Role.Name == params.Name {
+			writeError(w, http.StatusBadRequest, "DUPLICATE_NAME", fmt.Sprintf("The role %s already exists.", params.Name))
+			return false
+		}
+	}
+	if params.RightReferences == nil {
+		params.RightReferences = &types.RightReferences{}
+	}
+	for i, ref := range params.RightReferences.RightReference {
+		right := s.rightByHREF(ref.HREF)
+		if right == nil {
+			writeError(w, http.StatusBadRequest, "", fmt.Sprintf("The right %s does not exist.", ref.HREF))
+			return false
+		}
+		params.RightReferences.RightReference[i] = rightReference(right)
+	}
+	return true
+}
+
+func (role *Role) serve(w http.ResponseWriter, r *http.Request, rest string) {
+	s := role.server
+	switch {
+	case rest == "" && r.Method == http.MethodGet:
+		writeXML(w, http.StatusOK, "Role", types.MimeAdminRole, role.Role)
+	case rest == "" && r.Method == http.MethodPut:
+		params := &types.Role{}
+		if err := readXML(r, params); err != nil {
+			writeError(w, http.StatusBadRequest, "", err.Error())
+			return
+		}
+		if !s.validRole(w, params, role.org, role) {
+			return
+		}
+		role.Role.Name = params.Name
+		role.Role.Description = params.Description
+		role.Role.RightReferences = params.RightReferences
+		writeXML(w, http.StatusOK, "Role", types.MimeAdminRole, role.Role)
+	case rest == "" && r.Method == http.MethodDelete:
+		if s.roleInUse(role) {
+			writeError(w, http.StatusBadRequest, "", fmt.Sprintf("The role %s is in use.", role.Role.Name))
+			return
+		}
+		for i, existing := range s.roles {
+			if existing == role {
+				s.roles = append(s.roles[:i], s.roles[i+1:]...)
+				break
+			}
+		}
+		s.unhandle("/admin/role/" + role.id)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeMethodNotAllowed(w, r)
+	}
+}
+
+// roleInUse tells whether role is given to a user or a group.
+func (s *Server) roleInUse(role *Role) bool {
+	for _, org := range s.orgs {
+		for _, user := range org.users {
+			if user.User.Role != nil && user.User.Role.HREF == role.Role.HREF {
+				return true
+			}
+		}
+		for _, group := range org.groups {
+			if group.Group.Role != nil && group.Group.Role.HREF == role.Role.HREF {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// roleReference returns the reference to the global role having the given
+// name, or nil.
+func (s *Server) roleReference(name string) *types.Reference {
+	for _, role := range s.roles {
+		if role.org == nil && role.Role.Name == name {
+			return roleReference(role)
+		}
+	}
+	return nil
+}
+
+// roleReferences returns the references to the roles local to org, or to
+// the global roles when org is nil.
+func (s *Server) roleReferences(org *Org) *types.RoleReferences {
+	refs := &types.RoleReferences{}
+	for _, role := range s.roles {
+		if role.org == org {
+			refs.RoleReference = append(refs.RoleReference, roleReference(role))
+		}
+	}
+	return refs
+}
+
+// rightReferences returns the references to all the rights.
+func (s *Server) rightReferences() *types.RightReferences {
+	refs := &types.RightReferences{}
+	for _, right := range s.rights {
+		refs.RightReference = append(refs.RightReference, rightReference(right))
+	}
+	return refs
+}
+
+// isRole tells whether ref references a role of the server.
+func (s *Server) isRole(ref *types.Reference) bool {
+	if ref == nil {
+		return false
+	}
+	for _, role := range s.roles {
+		if role.Role.HREF == ref.HREF {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) rightByHREF(href string) *types.Right {
+	for _, right := range s.rights {
+		if right.HREF == href {
+			return right
+		}
+	}
+	return nil
+}
+
+func roleReference(role *Role) *types.Reference {
+	return &types.Reference{HREF: role.Role.HREF, Type: types.MimeAdminRole, ID: role.Role.ID, Name: role.Role.Name}
+}
+
+func rightReference(right *types.Right) *types.Reference {
+	return &types.Reference{HREF: right.HREF, Type: types.MimeAdminRight, ID: right.ID, Name: right.Name}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// serveAdmin serves the admin view of the cloud, /api/admin, listing the
+// global roles and the rights.
+func (s *Server) serveAdmin(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeMethodNotAllowed(w, r)
+		return
+	}
+	vcloud := &types.VCloud{
+		HREF:                   s.href("/admin"),
+		Type:                   types.MimeAdminVCloud,
+		Name:                   "vCloud",
+		OrganizationReferences: &types.OrganizationReferences{},
+		ProviderVdcReferences:  &types.ProviderVdcReferences{},
+		RightReferences:        s.rightReferences(),
+		RoleReferences:         s.roleReferences(nil),
+		Networks:               &types.Networks{},
+	}
+	for _, org := range s.orgs {
+		vcloud.OrganizationReferences.OrganizationReference = append(vcloud.OrganizationReferences.OrganizationReference,
+			&types.Reference{HREF: s.href("/admin/org/" + org.id), Type: "application/vnd.vmware.admin.organization+xml", Name: org.Org.Name})
+	}
+	for _, providerVdc := range s.providerVdcs {
+		vcloud.ProviderVdcReferences.ProviderVdcReference = append(vcloud.ProviderVdcReferences.ProviderVdcReference,
+			&types.Reference{HREF: providerVdc.ProviderVdc.HREF, Type: types.MimeProviderVdc, Name: providerVdc.ProviderVdc.Name})
+	}
+	for _, network := range s.externalNetworks {
+		vcloud.Networks.Network = append(vcloud.Networks.Network, network.reference())
+	}
+	writeXML(w, http.StatusOK, "VCloud", types.MimeAdminVCloud, vcloud)
+}
@@ -0,0 +1,397 @@
+/*
+ * Copyright 2014 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+// Package fakevcd provides an in-process fake vCloud Director endpoint,
+// built on net/http/httptest, for tests that cannot reach a real vCD.
+//
+// The server speaks the subset of the vCloud API used by govcd: versions
+// and sessions, roles and rights, organizations with their users, LDAP
+// groups and settings, VDCs, vApps, VMs, catalogs, networks, edge gateways
+// and asynchronous tasks, and the provider VDCs, external networks and
+// network pools of the system administrator. The inventory is built with
+// AddOrg and the Add methods of the returned handles:
+//
+//	server := fakevcd.NewServer()
+//	defer server.Close()
+//	org := server.AddOrg("my-org")
+//	vdc := org.AddVdc("my-vdc")
+//	vdc.AddVApp("my-vapp").AddVM("my-vm")
+//
+//	client := govcd.NewVCDClient(server.APIURL(), true)
+//	err := client.Authenticate(fakevcd.DefaultUser, fakevcd.DefaultPassword, "my-org")
+//
+// The package does not import govcd, so govcd itself can use it in its
+// tests.
+package fakevcd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync"
+
+	types "github.com/vmware/go-vcloud-director/types/v56"
+)
+
+const (
+	// DefaultUser is the user accepted by a new server.
+	DefaultUser = "administrator"
+	// DefaultPassword is the password accepted by a new server.
+	DefaultPassword = "password"
+	// AuthHeader is the header carrying the session token.
+	AuthHeader = "x-vcloud-authorization"
+)
+
+// Server is a fake vCloud Director endpoint. Its exported fields can be
+// changed before the first request is sent.
+type Server struct {
+	*httptest.Server
+
+	// User and Password are the credentials accepted on login. Any
+	// organization name is accepted.
+	User     string
+	Password string
+	// Versions lists the API versions returned by /versions.
+	Versions []string
+	// TaskPolls is the number of times a task is reported as running
+	// before it completes. Zero completes tasks on the first refresh.
+	TaskPolls int
+
+	mu       sync.Mutex
+	nextID   int
+	tokens   map[string]bool
+	orgs     []*Org
+	tasks    []*task
+	failures []*injectedFailure
+	routes   map[string]route
+	failNext string
+	roles    []*Role
+	rights   []*types.Right
+
+	requests []Request
+
+	providerVdcs     []*ProviderVdc
+	externalNetworks []*ExternalNetwork
+	networkPools     []*NetworkPool
+}
+
+// Request is a request received by the server, recorded so that tests can
+// check the method, path and body a client sends against the vCloud API.
+type Request struct {
+	Method string
+	// Path is the request path without the /api prefix, as in
+	// InjectError.
+	Path        string
+	Query       url.Values
+	ContentType string
+	Body        string
+}
+
+// route serves the requests for an entity path. rest holds the path that
+// follows the entity, e.g. "/power/action/powerOn".
+type route func(w http.ResponseWriter, r *http.Request, rest string)
+
+// injectedFailure is an error response returned instead of the normal
+// handling of a request.
+type injectedFailure struct {
+	method string
+	path   string
+	times  int
+	status int
+	err    types.Error
+}
+
+// NewServer starts a fake vCD with no organizations.
+func NewServer() *Server {
+	s := &Server{
+		User:     DefaultUser,
+		Password: DefaultPassword,
+		Versions: []string{"5.1", "5.5", "5.6"},
+		tokens:   make(map[string]bool),
+		routes:   make(map[string]route),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	s.addDefaultRoles()
+	s.handle("/admin/extension", s.serveExtension)
+	return s
+}
+
+// APIURL returns the URL of the API endpoint, to be given to
+// govcd.NewVCDClient.
+func (s *Server) APIURL() url.URL {
+	u, _ := url.Parse(s.URL + "/api")
+	return *u
+}
+
+// ExpireSessions invalidates every session token issued so far.
+func (s *Server) ExpireSessions() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tokens = make(map[string]bool)
+}
+
+// AddToken makes the server accept an existing session token.
+func (s *Server) AddToken(token string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tokens[token] = true
+}
+
+// InjectError makes the next times requests matching method and path fail
+// with the given HTTP status and error body. path is matched against the
+// end of the request path, so "/power/action/powerOn" matches the power on
+// of any vApp.
+func (s *Server) InjectError(method, path string, times, status int, vcdErr types.Error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = append(s.failures, &injectedFailure{method: method, path: path, times: times, status: status, err: vcdErr})
+}
+
+// Requests returns the requests received by the server, oldest first.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// ResetRequests forgets the requests received so far, so that Requests
+// only returns the requests of the operation under test.
+func (s *Server) ResetRequests() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = nil
+}
+
+// FailNextTask makes the next task created by the server end in error
+// with the given message.
+func (s *Server) FailNextTask(message string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failNext = message
+}
+
+// newID returns a new unique identifier formatted as a UUID. Identifiers
+// are deterministic, the same inventory always gets the same HREFs.
+func (s *Server) newID() string {
+	s.nextID++
+	return fmt.Sprintf("%08x-0000-4000-8000-%012x", s.nextID, s.nextID)
+}
+
+// href returns the absolute HREF of an API path.
+func (s *Server) href(path string) string {
+	return s.URL + "/api" + path
+}
+
+// handle registers the route serving path and what follows it.
+func (s *Server) handle(path string, r route) {
+	s.routes[path] = r
+}
+
+// unhandle removes the route of a deleted entity.
+func (s *Server) unhandle(path string) {
+	delete(s.routes, path)
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path := strings.TrimPrefix(r.URL.Path, "/api")
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "", err.Error())
+		return
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	s.requests = append(s.requests, Request{
+		Method:      r.Method,
+		Path:        path,
+		Query:       r.URL.Query(),
+		ContentType: r.Header.Get("Content-Type"),
+		Body:        string(body),
+	})
+
+	if s.injectFailure(w, r) {
+		return
+	}
+
+	switch {
+	case path == "/versions":
+		s.serveVersions(w, r)
+		return
+	case path == "/sessions" && r.Method == http.MethodPost:
+		s.login(w, r)
+		return
+	}
+
+	if !s.tokens[r.Header.Get(AuthHeader)] {
+		writeError(w, http.StatusUnauthorized, "", "This operation is denied.")
+		return
+	}
+
+	switch {
+	case path == "/session" || path == "/sessions":
+		s.serveSession(w, r)
+		return
+	case path == "/org":
+		s.serveOrgList(w, r)
+		return
+	case path == "/query":
+		s.serveQuery(w, r)
+		return
+	case path == "/admin":
+		s.serveAdmin(w, r)
+		return
+	}
+
+	// Entity routes are registered by path, the longest matching prefix
+	// serves the request.
+	for prefix := path; prefix != ""; prefix = prefix[:strings.LastIndex(prefix, "/")] {
+		if route, ok := s.routes[prefix]; ok {
+			route(w, r, strings.TrimPrefix(path, prefix))
+			return
+		}
+	}
+	writeNotFound(w, path)
+}
+
+// injectFailure writes the injected error matching r, if any.
+func (s *Server) injectFailure(w http.ResponseWriter, r *http.Request) bool {
+	for i, f := range s.failures {
+		if f.method != r.Method || !strings.HasSuffix(r.URL.Path, f.path) {
+			continue
+		}
+		f.times--
+		if f.times <= 0 {
+			s.failures = append(s.failures[:i], s.failures[i+1:]...)
+		}
+		vcdErr := f.err
+		writeXML(w, f.status, "Error", types.MimeError, &vcdErr)
+		return true
+	}
+	return false
+}
+
+type supportedVersions struct {
+	VersionInfo []versionInfo `xml:"VersionInfo"`
+}
+
+type versionInfo struct {
+	Deprecated bool   `xml:"deprecated,attr"`
+	Version    string `xml:"Version"`
+	LoginURL   string `xml:"LoginUrl"`
+}
+
+func (s *Server) serveVersions(w http.ResponseWriter, r *http.Request) {
+	versions := &supportedVersions{}
+	for _, version := range s.Versions {
+		versions.VersionInfo = append(versions.VersionInfo, versionInfo{Version: version, LoginURL: s.href("/sessions")})
+	}
+	writeXML(w, http.StatusOK, "SupportedVersions", "application/vnd.vmware.vcloud.supportedVersions+xml", versions)
+}
+
+type session struct {
+	HREF string         `xml:"href,attr"`
+	Type string         `xml:"type,attr"`
+	User string         `xml:"user,attr"`
+	Org  string         `xml:"org,attr"`
+	Link types.LinkList `xml:"Link"`
+}
+
+func (s *Server) login(w http.ResponseWriter, r *http.Request) {
+	user, password, ok := r.BasicAuth()
+	at := strings.LastIndex(user, "@")
+	if !ok || at < 0 || user[:at] != s.User || password != s.Password {
+		writeError(w, http.StatusUnauthorized, "", "Invalid credentials.")
+		return
+	}
+	token := fmt.Sprintf("fake-token-%s", s.newID())
+	s.tokens[token] = true
+	w.Header().Set(AuthHeader, token)
+	s.writeSession(w, user[:at], user[at+1:])
+}
+
+func (s *Server) writeSession(w http.ResponseWriter, user, org string) {
+	sess := &session{HREF: s.href("/session"), Type: types.MimeSession, User: user, Org: org}
+	sess.Link = append(sess.Link, &types.Link{Rel: "down", Type: types.MimeOrgList, HREF: s.href("/org")})
+	writeXML(w, http.StatusOK, "Session", types.MimeSession, sess)
+}
+
+func (s *Server) serveSession(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		s.writeSession(w, s.User, "")
+	case http.MethodDelete:
+		delete(s.tokens, r.Header.Get(AuthHeader))
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeMethodNotAllowed(w, r)
+	}
+}
+
+func (s *Server) serveOrgList(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeMethodNotAllowed(w, r)
+		return
+	}
+	list := &types.OrgList{}
+	for _, org := range s.orgs {
+		list.Org = append(list.Org, &types.Org{HREF: org.Org.HREF, Type: types.MimeOrg, Name: org.Org.Name})
+	}
+	writeXML(w, http.StatusOK, "OrgList", types.MimeOrgList, list)
+}
+
+// writeXML writes v as the body of the response, using name as the root
+// element in the vCloud namespace.
+func writeXML(w http.ResponseWriter, status int, name, mime string, v interface{}) {
+	start := xml.StartElement{Name: xml.Name{Local: name}}
+	// Types carrying their own xmlns attribute get the namespace set,
+	// the others get it added to the root element.
+	if field := reflect.Indirect(reflect.ValueOf(v)).FieldByName("Xmlns"); field.IsValid() && field.Kind() == reflect.String {
+		if field.String() == "" && field.CanSet() {
+			field.SetString(types.NsVCloud)
+		}
+	} else {
+		start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: "xmlns"}, Value: types.NsVCloud})
+	}
+
+	w.Header().Set("Content-Type", mime+";version=5.5")
+	w.WriteHeader(status)
+	_, _ = io.WriteString(w, xml.Header)
+	_ = xml.NewEncoder(w).EncodeElement(v, start)
+}
+
+// writeError writes a vCD error body with the given status.
+func writeError(w http.ResponseWriter, status int, minorCode, message string) {
+	if minorCode == "" {
+		minorCode = strings.ToUpper(strings.Replace(http.StatusText(status), " ", "_", -1))
+	}
+	vcdErr := &types.Error{MajorErrorCode: status, MinorErrorCode: minorCode, Message: message}
+	writeXML(w, status, "Error", types.MimeError, vcdErr)
+}
+
+func writeNotFound(w http.ResponseWriter, path string) {
+	writeError(w, http.StatusNotFound, "RESOURCE_NOT_FOUND", fmt.Sprintf("No resource found for %s.", path))
+}
+
+func writeMethodNotAllowed(w http.ResponseWriter, r *http.Request) {
+	writeError(w, http.StatusMethodNotAllowed, "", fmt.Sprintf("Method %s is not supported for %s.", r.Method, r.URL.Path))
+}
+
+// readXML decodes the request body into v.
+func readXML(r *http.Request, v interface{}) error {
+	return xml.NewDecoder(r.Body).Decode(v)
+}
+
+// link returns a Link to an entity.
+func link(rel, mime, href, name string) *types.Link {
+	return &types.Link{Rel: rel, Type: mime, HREF: href, Name: name}
+}
@@ -0,0 +1,238 @@
+/*
+ * Copyright 2014 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package fakevcd
+
+import (
+	"encoding/xml"
+	"net/http"
+	"strings"
+	"testing"
+
+	types "github.com/vmware/go-vcloud-director/types/v56"
+)
+
+// do sends a request to the server and decodes the XML response into v,
+// when v is not nil.
+func do(t *testing.T, method, href, token string, v interface{}) *http.Response {
+	req, err := http.NewRequest(method, href, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token != "" {
+		req.Header.Set(AuthHeader, token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if v != nil {
+		if err := xml.NewDecoder(resp.Body).Decode(v); err != nil {
+			t.Fatalf("error decoding %s %s: %s", method, href, err)
+		}
+	}
+	return resp
+}
+
+func login(t *testing.T, s *Server) string {
+	req, _ := http.NewRequest(http.MethodPost, s.href("/sessions"), nil)
+	req.SetBasicAuth(DefaultUser+"@org", DefaultPassword)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("login failed with status %d", resp.StatusCode)
+	}
+	return resp.Header.Get(AuthHeader)
+}
+
+func TestServer_Sessions(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	versions := &supportedVersions{}
+	do(t, http.MethodGet, s.href("/versions"), "", versions)
+	if len(versions.VersionInfo) != 3 || versions.VersionInfo[1].LoginURL != s.href("/sessions") {
+		t.Fatalf("unexpected versions: %+v", versions)
+	}
+
+	req, _ := http.NewRequest(http.MethodPost, s.href("/sessions"), nil)
+	req.SetBasicAuth(DefaultUser+"@org", "wrong")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected 401 on bad credentials, got %d", resp.StatusCode)
+	}
+
+	if resp := do(t, http.MethodGet, s.href("/org"), "", nil); resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without token, got %d", resp.StatusCode)
+	}
+
+	token := login(t, s)
+	if resp := do(t, http.MethodGet, s.href("/session"), token, nil); resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200 with token, got %d", resp.StatusCode)
+	}
+	s.ExpireSessions()
+	if resp := do(t, http.MethodGet, s.href("/session"), token, nil); resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected 401 after expiry, got %d", resp.StatusCode)
+	}
+}
+
+func TestServer_Inventory(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	org := s.AddOrg("org")
+	vdc := org.AddVdc("vdc")
+	vapp := vdc.AddVApp("vapp")
+	vapp.AddVM("vm")
+	token := login(t, s)
+
+	orgList := &types.OrgList{}
+	do(t, http.MethodGet, s.href("/org"), token, orgList)
+	if len(orgList.Org) != 1 || orgList.Org[0].Name != "org" {
+		t.Fatalf("unexpected org list: %+v", orgList.Org)
+	}
+
+	gotOrg := &types.Org{}
+	do(t, http.MethodGet, org.Org.HREF, token, gotOrg)
+	if gotOrg.Link.ForType(types.MimeVDC, types.RelDown) == nil {
+		t.Fatalf("org has no link to its VDC")
+	}
+
+	gotVApp := &types.VApp{}
+	do(t, http.MethodGet, vapp.VApp.HREF, token, gotVApp)
+	if gotVApp.Children == nil || len(gotVApp.Children.VM) != 1 || gotVApp.Children.VM[0].Name != "vm" {
+		t.Fatalf("unexpected vApp children: %+v", gotVApp.Children)
+	}
+
+	records := &types.QueryResultRecordsType{}
+	do(t, http.MethodGet, s.href("/query?type=vm&format=records&filter=name==vm"), token, records)
+	if len(records.VMRecord) != 1 || records.VMRecord[0].VAppParentName != "vapp" {
+		t.Fatalf("unexpected query result: %+v", records.VMRecord)
+	}
+
+	if resp := do(t, http.MethodGet, s.href("/vApp/vapp-unknown"), token, nil); resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected 404 for an unknown vApp, got %d", resp.StatusCode)
+	}
+}
+
+func TestServer_Tasks(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	s.TaskPolls = 1
+	vapp := s.AddOrg("org").AddVdc("vdc").AddVApp("vapp")
+	token := login(t, s)
+
+	task := &types.Task{}
+	resp := do(t, http.MethodPost, vapp.VApp.HREF+"/power/action/powerOn", token, task)
+	if resp.StatusCode != http.StatusAccepted || task.Status != "running" {
+		t.Fatalf("unexpected power on response: %d %s", resp.StatusCode, task.Status)
+	}
+	for _, expected := range []string{"running", "success"} {
+		do(t, http.MethodGet, task.HREF, token, task)
+		if task.Status != expected {
+			t.Fatalf("expected task status %s, got %s", expected, task.Status)
+		}
+	}
+	if vapp.VApp.Status != statusPoweredOn || !vapp.VApp.Deployed {
+		t.Fatalf("vApp not powered on after the task completed")
+	}
+
+	s.TaskPolls = 0
+	s.FailNextTask("no capacity")
+	do(t, http.MethodPost, vapp.VApp.HREF+"/power/action/powerOff", token, task)
+	do(t, http.MethodGet, task.HREF, token, task)
+	if task.Status != "error" || task.Error == nil || !strings.Contains(task.Error.Message, "no capacity") {
+		t.Fatalf("expected failed task, got %s %+v", task.Status, task.Error)
+	}
+	if vapp.VApp.Status != statusPoweredOn {
+		t.Fatalf("failed task changed the vApp status")
+	}
+}
+
+func TestServer_InjectError(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	vapp := s.AddOrg("org").AddVdc("vdc").AddVApp("vapp")
+	token := login(t, s)
+
+	s.InjectError(http.MethodPost, "/power/action/powerOn", 2, http.StatusServiceUnavailable, types.Error{
+		MajorErrorCode: http.StatusServiceUnavailable, MinorErrorCode: "SERVICE_UNAVAILABLE", Message: "try again",
+	})
+	for i, expected := range []int{http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusAccepted} {
+		vcdErr := &types.Error{}
+		var v interface{}
+		if expected != http.StatusAccepted {
+			v = vcdErr
+		}
+		resp := do(t, http.MethodPost, vapp.VApp.HREF+"/power/action/powerOn", token, v)
+		if resp.StatusCode != expected {
+			t.Fatalf("request %d: expected status %d, got %d", i, expected, resp.StatusCode)
+		}
+		if v != nil && vcdErr.Message != "try again" {
+			t.Fatalf("request %d: unexpected error body %+v", i, vcdErr)
+		}
+	}
+}
+
+func TestServer_Requests(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	vapp := s.AddOrg("org").AddVdc("vdc").AddVApp("vapp")
+	token := login(t, s)
+
+	s.ResetRequests()
+	req, _ := http.NewRequest(http.MethodPost, vapp.VApp.HREF+"/power/action/powerOn?force=true", strings.NewReader("<Body/>"))
+	req.Header.Set(AuthHeader, token)
+	req.Header.Set("Content-Type", "application/xml")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	requests := s.Requests()
+	if len(requests) != 1 {
+		t.Fatalf("expected 1 request, got %+v", requests)
+	}
+	got := requests[0]
+	if got.Method != http.MethodPost || got.Path != "/vApp/vapp-"+vapp.id+"/power/action/powerOn" ||
+		got.Query.Get("force") != "true" || got.ContentType != "application/xml" || got.Body != "<Body/>" {
+		t.Fatalf("unexpected recorded request: %+v", got)
+	}
+}
+
+func TestParseFilter(t *testing.T) {
+	attributes := map[string]string{"name": "web;01", "numberOfVMs": "10", "status": "POWERED_ON"}
+	for filter, expected := range map[string]bool{
+		"":                               true,
+		`name==web\;01`:                  true,
+		"name==web*":                     true,
+		"name!=web*":                     false,
+		"numberOfVMs=gt=9":               true,
+		"numberOfVMs=lt=9":               false,
+		"numberOfVMs=ge=10;status==POW*": true,
+		"numberOfVMs=lt=9,status==POW*":  true,
+		"name==db;(numberOfVMs==10,status==POWERED_ON)": false,
+		"(name==db,numberOfVMs==10);status==POWERED_ON": true,
+	} {
+		match, err := parseFilter(filter)
+		if err != nil {
+			t.Fatalf("error parsing %q: %s", filter, err)
+		}
+		if match(attributes) != expected {
+			t.Errorf("expected %q to match: %t", filter, expected)
+		}
+	}
+	for _, filter := range []string{"name", "name==web;", "(name==web", "name==web)"} {
+		if _, err := parseFilter(filter); err == nil {
+			t.Errorf("expected an error parsing %q", filter)
+		}
+	}
+}
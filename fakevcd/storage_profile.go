@@ -0,0 +1,228 @@
+/*
+ * Copyright 2014 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package fakevcd
+
+import (
+	"net/http"
+	"strings"
+
+	types "github.com/vmware/go-vcloud-director/types/v56"
+)
+
+// vdcStorageProfile is a storage profile of a VDC with its limit and the
+// storage used by the VDC, in MB. A limit of 0 is unlimited.
+type vdcStorageProfile struct {
+	vdc             *Vdc
+	id              string
+	name            string
+	providerProfile *types.Reference
+	enabled         bool
+	isDefault       bool
+	limitMB         int
+	usedMB          int
+}
+
+// SetStorageUsed sets the storage used by the VDC on the storage profile
+// having the given name.
+func (v *Vdc) SetStorageUsed(profileName string, usedMB int) {
+	v.server.mu.Lock()
+	defer v.server.mu.Unlock()
+	for _, profile := range v.storageProfiles {
+		if profile.name == profileName {
+			profile.usedMB = usedMB
+		}
+	}
+}
+
+// AddStorageProfile adds a storage profile to the provider VDC.
+func (p *ProviderVdc) AddStorageProfile(name string) {
+	p.server.mu.Lock()
+	defer p.server.mu.Unlock()
+	p.ProviderVdc.StorageProfiles.ProviderVdcStorageProfile = append(p.ProviderVdc.StorageProfiles.ProviderVdcStorageProfile, &types.Reference{
+		HREF: p.server.href("/admin/pvdcStorageProfile/" + p.server.newID()),
+		Type: "application/vnd.vmware.admin.pvdcStorageProfile+xml",
+		Name: name,
+	})
+}
+
+// addStorageProfile adds a storage profile to the VDC. Profiles added from
+// a provider VDC profile take its name.
+func (v *Vdc) addStorageProfile(name string, providerProfile *types.Reference, enabled bool, limitMB int) *vdcStorageProfile {
+	if providerProfile != nil {
+		name = providerProfile.Name
+	}
+	profile := &vdcStorageProfile{
+		vdc:             v,
+		id:              v.server.newID(),
+		name:            name,
+		providerProfile: providerProfile,
+		enabled:         enabled,
+		limitMB:         limitMB,
+	}
+	v.storageProfiles = append(v.storageProfiles, profile)
+	v.server.handle("/admin/vdcStorageProfile/"+profile.id, profile.serve)
+	return profile
+}
+
+func (p *vdcStorageProfile) reference() *types.Reference {
+	return &types.Reference{HREF: p.vdc.server.href("/vdcStorageProfile/" + p.id), Type: types.MimeVdcStorageProfile, Name: p.name}
+}
+
+func (p *vdcStorageProfile) adminReference() *types.Reference {
+	return &types.Reference{HREF: p.vdc.server.href("/admin/vdcStorageProfile/" + p.id), Type: types.MimeAdminVdcStorageProfile, Name: p.name}
+}
+
+func (p *vdcStorageProfile) render() *types.AdminVdcStorageProfile {
+	return &types.AdminVdcStorageProfile{
+		HREF: p.adminReference().HREF,
+		Type: types.MimeAdminVdcStorageProfile,
+		ID:   "urn:vcloud:vdcstorageProfile:" + p.id,
+		Name: p.name,
+		Link: types.LinkList{
+			link(types.RelUp, types.MimeAdminVdc, p.vdc.server.href("/admin/vdc/"+p.vdc.id), ""),
+		},
+		Enabled:                   p.enabled,
+		Units:                     "MB",
+		Limit:                     int64(p.limitMB),
+		Default:                   p.isDefault,
+		ProviderVdcStorageProfile: p.providerProfile,
+	}
+}
+
+func (p *vdcStorageProfile) serve(w http.ResponseWriter, r *http.Request, rest string) {
+	switch {
+	case rest == "" && r.Method == http.MethodGet:
+		writeXML(w, http.StatusOK, "AdminVdcStorageProfile", types.MimeAdminVdcStorageProfile, p.render())
+	case rest == "" && r.Method == http.MethodPut:
+		p.update(w, r)
+	default:
+		writeMethodNotAllowed(w, r)
+	}
+}
+
+// update changes the limit and the enabled and default flags of the
+// profile. The default profile must stay enabled and can only stop being
+// the default when another profile becomes the default.
+func (p *vdcStorageProfile) update(w http.ResponseWriter, r *http.Request) {
+	params := &types.AdminVdcStorageProfile{}
+	if err := readXML(r, params); err != nil {
+		writeError(w, http.StatusBadRequest, "", err.Error())
+		return
+	}
+	if p.isDefault && !params.Default {
+		writeError(w, http.StatusBadRequest, "", "The VDC "+p.vdc.Vdc.Name+" needs a default storage profile.")
+		return
+	}
+	if params.Default && !params.Enabled {
+		writeError(w, http.StatusBadRequest, "", "The default storage profile must be enabled.")
+		return
+	}
+	if params.Units != "MB" && params.Units != "GB" {
+		writeError(w, http.StatusBadRequest, "", "Unsupported storage units "+params.Units+".")
+		return
+	}
+	if params.Default {
+		for _, profile := range p.vdc.storageProfiles {
+			profile.isDefault = false
+		}
+	}
+	p.enabled = params.Enabled
+	p.isDefault = params.Default
+	p.limitMB = limitMB(params.Limit, params.Units)
+	writeXML(w, http.StatusOK, "AdminVdcStorageProfile", types.MimeAdminVdcStorageProfile, p.render())
+}
+
+// updateStorageProfiles adds storage profiles of the provider VDC to the
+// VDC and removes disabled storage profiles no disk uses.
+func (v *Vdc) updateStorageProfiles(w http.ResponseWriter, r *http.Request) {
+	params := &types.UpdateVdcStorageProfiles{}
+	if err := readXML(r, params); err != nil {
+		writeError(w, http.StatusBadRequest, "", err.Error())
+		return
+	}
+	for _, add := range params.AddStorageProfile {
+		if v.providerVdc == nil || v.providerVdc.storageProfile(add.ProviderVdcStorageProfile) == nil {
+			writeError(w, http.StatusBadRequest, "", "The storage profile is not a storage profile of the provider VDC.")
+			return
+		}
+		if v.findStorageProfile(add.ProviderVdcStorageProfile.HREF) != nil {
+			writeError(w, http.StatusBadRequest, "DUPLICATE_NAME", "The storage profile is already a storage profile of the VDC.")
+			return
+		}
+	}
+	var removed []*vdcStorageProfile
+	for _, remove := range params.RemoveStorageProfile {
+		profile := v.findStorageProfile(remove.HREF)
+		switch {
+		case profile == nil:
+			writeError(w, http.StatusBadRequest, "", "The storage profile "+remove.HREF+" is not a storage profile of the VDC.")
+			return
+		case profile.enabled || profile.isDefault:
+			writeError(w, http.StatusBadRequest, "", "The storage profile "+profile.name+" must be disabled before it is removed.")
+			return
+		case profile.inUse():
+			writeError(w, http.StatusBadRequest, "", "The storage profile "+profile.name+" is in use.")
+			return
+		}
+		removed = append(removed, profile)
+	}
+
+	for _, add := range params.AddStorageProfile {
+		profile := v.addStorageProfile("", v.providerVdc.storageProfile(add.ProviderVdcStorageProfile), add.Enabled, limitMB(add.Limit, add.Units))
+		if add.Default {
+			for _, other := range v.storageProfiles {
+				other.isDefault = false
+			}
+			profile.isDefault, profile.enabled = true, true
+		}
+	}
+	for _, profile := range removed {
+		for i, candidate := range v.storageProfiles {
+			if candidate == profile {
+				v.storageProfiles = append(v.storageProfiles[:i], v.storageProfiles[i+1:]...)
+				break
+			}
+		}
+		v.server.unhandle("/admin/vdcStorageProfile/" + profile.id)
+	}
+	owner := &types.Reference{HREF: v.Vdc.HREF, Name: v.Vdc.Name, Type: types.MimeVDC}
+	writeTask(w, v.server.newTask(v.org, "vdcUpdateVdcStorageProfiles", owner, nil))
+}
+
+// findStorageProfile returns the storage profile of the VDC having either
+// of its HREFs, or made of the provider VDC profile having href, or nil.
+func (v *Vdc) findStorageProfile(href string) *vdcStorageProfile {
+	for _, profile := range v.storageProfiles {
+		if href == profile.reference().HREF || href == profile.adminReference().HREF ||
+			(profile.providerProfile != nil && href == profile.providerProfile.HREF) {
+			return profile
+		}
+	}
+	return nil
+}
+
+// inUse tells whether a disk of a VM of the VDC is on the profile.
+func (p *vdcStorageProfile) inUse() bool {
+	for _, vapp := range p.vdc.vapps {
+		for _, vm := range vapp.vms {
+			for _, disk := range vm.disks {
+				for _, hostResource := range disk.HostResource {
+					if p.vdc.findStorageProfile(hostResource.StorageProfile) == p {
+						return true
+					}
+				}
+			}
+		}
+	}
+	return false
+}
+
+// limitMB converts a storage limit in units, "MB" or "GB", to MB.
+func limitMB(limit int64, units string) int {
+	if strings.EqualFold(units, "GB") {
+		return int(limit) * 1024
+	}
+	return int(limit)
+}
@@ -0,0 +1,291 @@
+/*
+ * Copyright 2014 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package fakevcd
+
+import (
+	"net/http"
+
+	types "github.com/vmware/go-vcloud-director/types/v56"
+)
+
+// ProviderVdc is a provider VDC. All the provider VDCs of the server share
+// its external networks and network pools.
+type ProviderVdc struct {
+	ProviderVdc *types.ProviderVdc
+
+	server *Server
+	id     string
+}
+
+// ExternalNetwork is an external network of the server.
+type ExternalNetwork struct {
+	ExternalNetwork *types.ExternalNetwork
+
+	server *Server
+	id     string
+}
+
+// NetworkPool is a network pool of the server.
+type NetworkPool struct {
+	NetworkPool *types.NetworkPool
+
+	server *Server
+	id     string
+}
+
+// AddProviderVdc adds an enabled provider VDC, with 20 GHz of CPU, 64 GB
+// of memory and a single storage profile, to the server.
+func (s *Server) AddProviderVdc(name string) *ProviderVdc {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := s.newID()
+	providerVdc := &ProviderVdc{
+		server: s,
+		id:     id,
+		ProviderVdc: &types.ProviderVdc{
+			HREF: s.href("/admin/providervdc/" + id),
+			Type: types.MimeProviderVdc,
+			ID:   "urn:vcloud:providervdc:" + id,
+			Name: name,
+			ComputeCapacity: &types.RootComputeCapacity{
+				CPU:    &types.ProviderVdcCapacity{Units: "MHz", Total: 20000},
+				Memory: &types.ProviderVdcCapacity{Units: "MB", Total: 65536},
+			},
+			StorageProfiles: &types.ProviderStorageProfiles{
+				ProviderVdcStorageProfile: []*types.Reference{{
+					HREF: s.href("/admin/pvdcStorageProfile/" + s.newID()),
+					Type: "application/vnd.vmware.admin.pvdcStorageProfile+xml",
+					Name: "*",
+				}},
+			},
+			IsEnabled: true,
+		},
+	}
+	s.providerVdcs = append(s.providerVdcs, providerVdc)
+	s.handle("/admin/providervdc/"+id, providerVdc.serve)
+	return providerVdc
+}
+
+func (p *ProviderVdc) serve(w http.ResponseWriter, r *http.Request, rest string) {
+	if rest != "" || r.Method != http.MethodGet {
+		writeMethodNotAllowed(w, r)
+		return
+	}
+	s := p.server
+	providerVdc := *p.ProviderVdc
+	providerVdc.AvailableNetworks = &types.AvailableNetworks{}
+	for _, network := range s.externalNetworks {
+		providerVdc.AvailableNetworks.Network = append(providerVdc.AvailableNetworks.Network, network.reference())
+	}
+	providerVdc.NetworkPoolReferences = &types.NetworkPoolReferences{}
+	for _, pool := range s.networkPools {
+		providerVdc.NetworkPoolReferences.NetworkPoolReference = append(providerVdc.NetworkPoolReferences.NetworkPoolReference, pool.reference())
+	}
+	// The capacity allocated to the org VDCs is the sum of their
+	// allocations.
+	cpu, memory := *p.ProviderVdc.ComputeCapacity.CPU, *p.ProviderVdc.ComputeCapacity.Memory
+	providerVdc.ComputeCapacity = &types.RootComputeCapacity{CPU: &cpu, Memory: &memory}
+	providerVdc.Vdcs = &types.VDCList{}
+	for _, org := range s.orgs {
+		for _, vdc := range org.vdcs {
+			if vdc.providerVdc != p {
+				continue
+			}
+			providerVdc.Vdcs.Vdcs = append(providerVdc.Vdcs.Vdcs, &types.Reference{HREF: vdc.Vdc.HREF, Type: types.MimeVDC, Name: vdc.Vdc.Name})
+			for _, capacity := range vdc.Vdc.ComputeCapacity {
+				cpu.Allocation += capacity.CPU.Allocated
+				memory.Allocation += capacity.Memory.Allocated
+			}
+		}
+	}
+	writeXML(w, http.StatusOK, "ProviderVdc", types.MimeProviderVdc, &providerVdc)
+}
+
+// SetProviderVdc makes the VDC, added with AddVdc, a VDC of the provider
+// VDC. Its storage profiles are backed by the provider VDC profiles of the
+// same name.
+func (v *Vdc) SetProviderVdc(providerVdc *ProviderVdc) {
+	v.server.mu.Lock()
+	defer v.server.mu.Unlock()
+	v.providerVdc = providerVdc
+	v.admin.ProviderVdcReference = &types.Reference{HREF: providerVdc.ProviderVdc.HREF, Type: types.MimeProviderVdc, Name: providerVdc.ProviderVdc.Name}
+	for _, profile := range v.storageProfiles {
+		for _, providerProfile := range providerVdc.ProviderVdc.StorageProfiles.ProviderVdcStorageProfile {
+			if providerProfile.Name == profile.name {
+				profile.providerProfile = providerProfile
+			}
+		}
+	}
+}
+
+// storageProfile returns the storage profile of the provider VDC
+// referenced by ref, or nil.
+func (p *ProviderVdc) storageProfile(ref *types.Reference) *types.Reference {
+	if ref == nil {
+		return nil
+	}
+	for _, profile := range p.ProviderVdc.StorageProfiles.ProviderVdcStorageProfile {
+		if profile.HREF == ref.HREF {
+			return profile
+		}
+	}
+	return nil
+}
+
+// AddExternalNetwork adds an external network, backed by a distributed
+// port group, to the server.
+func (s *Server) AddExternalNetwork(name string) *ExternalNetwork {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.addExternalNetwork(&types.ExternalNetwork{
+		Name: name,
+		Configuration: &types.NetworkConfiguration{
+			IPScopes: &types.IPScopes{IPScope: types.IPScope{
+				Gateway:   "192.168.1.1",
+				Netmask:   "255.255.255.0",
+				IsEnabled: true,
+			}},
+			FenceMode: "isolated",
+		},
+		VimPortGroupRef: &types.VimObjectRef{
+			VimServerRef:  &types.Reference{HREF: s.href("/admin/extension/vimServer/" + s.newID()), Name: "vc"},
+			MoRef:         "dvportgroup-" + s.newID()[:8],
+			VimObjectType: "DV_PORTGROUP",
+		},
+	})
+}
+
+func (s *Server) addExternalNetwork(params *types.ExternalNetwork) *ExternalNetwork {
+	id := s.newID()
+	params.HREF = s.href("/admin/extension/externalnet/" + id)
+	params.Type = types.MimeExternalNetwork
+	params.ID = "urn:vcloud:network:" + id
+	params.Tasks = nil
+	params.Link = nil
+	network := &ExternalNetwork{ExternalNetwork: params, server: s, id: id}
+	s.externalNetworks = append(s.externalNetworks, network)
+	s.handle("/admin/extension/externalnet/"+id, network.serve)
+	return network
+}
+
+func (n *ExternalNetwork) reference() *types.Reference {
+	return &types.Reference{HREF: n.ExternalNetwork.HREF, Type: types.MimeExternalNetwork, Name: n.ExternalNetwork.Name}
+}
+
+func (n *ExternalNetwork) serve(w http.ResponseWriter, r *http.Request, rest string) {
+	s := n.server
+	switch {
+	case rest == "" && r.Method == http.MethodGet:
+		writeXML(w, http.StatusOK, "VMWExternalNetwork", types.MimeExternalNetwork, n.ExternalNetwork)
+	case rest == "" && r.Method == http.MethodDelete:
+		if n.inUse() {
+			writeError(w, http.StatusBadRequest, "", "The external network "+n.ExternalNetwork.Name+" is in use.")
+			return
+		}
+		writeTask(w, s.newTask(nil, "networkDelete", n.reference(), func() {
+			for i, network := range s.externalNetworks {
+				if network == n {
+					s.externalNetworks = append(s.externalNetworks[:i], s.externalNetworks[i+1:]...)
+					break
+				}
+			}
+			s.unhandle("/admin/extension/externalnet/" + n.id)
+		}))
+	default:
+		writeMethodNotAllowed(w, r)
+	}
+}
+
+// inUse tells whether an org VDC network is connected to the external
+// network.
+func (n *ExternalNetwork) inUse() bool {
+	for _, org := range n.server.orgs {
+		for _, vdc := range org.vdcs {
+			for _, network := range vdc.networks {
+				configuration := network.Network.Configuration
+				if configuration != nil && configuration.ParentNetwork != nil && configuration.ParentNetwork.HREF == n.ExternalNetwork.HREF {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// AddNetworkPool adds a VLAN backed network pool to the server.
+func (s *Server) AddNetworkPool(name string) *NetworkPool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := s.newID()
+	pool := &NetworkPool{
+		server: s,
+		id:     id,
+		NetworkPool: &types.NetworkPool{
+			HREF:     s.href("/admin/extension/networkPool/" + id),
+			Type:     types.MimeNetworkPool,
+			ID:       "urn:vcloud:networkpool:" + id,
+			Name:     name,
+			PoolType: "vmext:VlanPoolType",
+		},
+	}
+	s.networkPools = append(s.networkPools, pool)
+	s.handle("/admin/extension/networkPool/"+id, pool.serve)
+	return pool
+}
+
+func (p *NetworkPool) reference() *types.Reference {
+	return &types.Reference{HREF: p.NetworkPool.HREF, Type: types.MimeNetworkPool, Name: p.NetworkPool.Name}
+}
+
+func (p *NetworkPool) serve(w http.ResponseWriter, r *http.Request, rest string) {
+	if rest != "" || r.Method != http.MethodGet {
+		writeMethodNotAllowed(w, r)
+		return
+	}
+	writeXML(w, http.StatusOK, "VMWNetworkPool", types.MimeNetworkPool, p.NetworkPool)
+}
+
+// serveExtension serves the lists of the extension API and the creation
+// of external networks.
+func (s *Server) serveExtension(w http.ResponseWriter, r *http.Request, rest string) {
+	switch {
+	case rest == "/externalNetworkReferences" && r.Method == http.MethodGet:
+		references := &types.ExternalNetworkReferences{}
+		for _, network := range s.externalNetworks {
+			references.ExternalNetworkReference = append(references.ExternalNetworkReference, network.reference())
+		}
+		writeXML(w, http.StatusOK, "VMWExternalNetworkReferences", types.MimeExternalNetworkReferences, references)
+	case rest == "/networkPoolReferences" && r.Method == http.MethodGet:
+		references := &types.NetworkPoolReferences{}
+		for _, pool := range s.networkPools {
+			references.NetworkPoolReference = append(references.NetworkPoolReference, pool.reference())
+		}
+		writeXML(w, http.StatusOK, "VMWNetworkPoolReferences", types.MimeNetworkPoolReferences, references)
+	case rest == "/externalnets" && r.Method == http.MethodPost:
+		params := &types.ExternalNetwork{}
+		if err := readXML(r, params); err != nil {
+			writeError(w, http.StatusBadRequest, "", err.Error())
+			return
+		}
+		if params.Name == "" || params.VimPortGroupRef == nil || params.Configuration == nil || params.Configuration.IPScopes == nil {
+			writeError(w, http.StatusBadRequest, "", "The external network needs a name, an IP scope and a port group.")
+			return
+		}
+		for _, network := range s.externalNetworks {
+			if network.ExternalNetwork.Name == params.Name {
+				writeError(w, http.StatusBadRequest, "DUPLICATE_NAME", "An external network named "+params.Name+" already exists.")
+				return
+			}
+		}
+		network := s.addExternalNetwork(params)
+		created := *network.ExternalNetwork
+		created.Tasks = &types.TasksInProgress{Task: []*types.Task{s.newTask(nil, "networkCreateExternalNetwork", network.reference(), nil)}}
+		writeXML(w, http.StatusCreated, "VMWExternalNetwork", types.MimeExternalNetwork, &created)
+	default:
+		writeMethodNotAllowed(w, r)
+	}
+}
@@ -0,0 +1,119 @@
+/*
+ * Copyright 2014 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package fakevcd
+
+import (
+	"net/http"
+	"time"
+
+	types "github.com/vmware/go-vcloud-director/types/v56"
+)
+
+// task is an asynchronous operation. It is reported as running when it is
+// created and for Server.TaskPolls refreshes, then it completes and applies
+// its effect on the inventory.
+type task struct {
+	task       *types.Task
+	org        *Org
+	remaining  int
+	onSuccess  func()
+	failReason string
+}
+
+// newTask registers a running task on owner and returns it. onSuccess is
+// called, with the server lock held, when the task completes.
+func (s *Server) newTask(org *Org, operation string, owner *types.Reference, onSuccess func()) *types.Task {
+	id := s.newID()
+	path := "/task/" + id
+	t := &task{
+		task: &types.Task{
+			HREF:          s.href(path),
+			Type:          types.MimeTask,
+			ID:            "urn:vcloud:task:" + id,
+			Name:          "task",
+			Status:        "running",
+			Operation:     operation,
+			OperationName: operation,
+			StartTime:     time.Now().Format(time.RFC3339),
+			ExpiryTime:    time.Now().Add(24 * time.Hour).Format(time.RFC3339),
+			Owner:         owner,
+		},
+		org:        org,
+		remaining:  s.TaskPolls,
+		onSuccess:  onSuccess,
+		failReason: s.failNext,
+	}
+	s.failNext = ""
+	t.task.Link = link(types.RelTaskCancel, "", s.href(path+"/action/cancel"), "")
+	if org != nil {
+		t.task.Organization = &types.Reference{HREF: org.Org.HREF, Name: org.Org.Name, Type: types.MimeOrg}
+	}
+	s.tasks = append(s.tasks, t)
+	s.handle(path, func(w http.ResponseWriter, r *http.Request, rest string) {
+		s.serveTask(w, r, t, rest)
+	})
+	return t.task
+}
+
+// advance moves the task one step towards completion.
+func (t *task) advance() {
+	if t.task.Status != "running" {
+		return
+	}
+	if t.remaining > 0 {
+		t.remaining--
+		t.task.Progress = 50
+		return
+	}
+	t.task.EndTime = time.Now().Format(time.RFC3339)
+	t.task.Progress = 100
+	if t.failReason != "" {
+		t.task.Status = "error"
+		t.task.Error = &types.Error{MajorErrorCode: 500, MinorErrorCode: "INTERNAL_SERVER_ERROR", Message: t.failReason}
+		return
+	}
+	t.task.Status = "success"
+	if t.onSuccess != nil {
+		t.onSuccess()
+	}
+}
+
+func (s *Server) serveTask(w http.ResponseWriter, r *http.Request, t *task, rest string) {
+	switch {
+	case rest == "" && r.Method == http.MethodGet:
+		t.advance()
+		writeXML(w, http.StatusOK, "Task", types.MimeTask, t.task)
+	case rest == "/action/cancel" && r.Method == http.MethodPost:
+		if t.task.Status == "running" {
+			t.task.CancelRequested = true
+			t.task.Status = "aborted"
+			t.task.EndTime = time.Now().Format(time.RFC3339)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeMethodNotAllowed(w, r)
+	}
+}
+
+// runningTasks returns the tasks in progress whose owner is href, to be
+// listed in the Tasks element of the owner.
+func (s *Server) runningTasks(href string) *types.TasksInProgress {
+	var inProgress *types.TasksInProgress
+	for _, t := range s.tasks {
+		if t.task.Status != "running" || t.task.Owner == nil || t.task.Owner.HREF != href {
+			continue
+		}
+		if inProgress == nil {
+			inProgress = &types.TasksInProgress{}
+		}
+		inProgress.Task = append(inProgress.Task, t.task)
+	}
+	return inProgress
+}
+
+// writeTask answers a request that started a task.
+func writeTask(w http.ResponseWriter, t *types.Task) {
+	writeXML(w, http.StatusAccepted, "Task", types.MimeTask, t)
+}
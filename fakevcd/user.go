@@ -0,0 +1,124 @@
+/*
+ * Copyright 2014 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package fakevcd
+
+import (
+	"fmt"
+	"net/http"
+
+	types "github.com/vmware/go-vcloud-director/types/v56"
+)
+
+// User is a local user of an organization. The password of the user is
+// kept, it is never served back.
+type User struct {
+	User     *types.User
+	Password string
+
+	org *Org
+	id  string
+}
+
+// AddUser adds an enabled user with the given role to the organization.
+func (o *Org) AddUser(name, roleName string) *User {
+	s := o.server
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return o.addUser(&types.User{Name: name, IsEnabled: true, Role: s.roleReference(roleName)})
+}
+
+func (o *Org) addUser(params *types.User) *User {
+	s := o.server
+	id := s.newID()
+	user := &User{org: o, id: id, Password: params.Password}
+	user.User = &types.User{
+		HREF:            s.href("/admin/user/" + id),
+		Type:            types.MimeAdminUser,
+		ID:              "urn:vcloud:user:" + id,
+		Name:            params.Name,
+		Description:     params.Description,
+		FullName:        params.FullName,
+		EmailAddress:    params.EmailAddress,
+		Telephone:       params.Telephone,
+		IsEnabled:       params.IsEnabled,
+		NameInSource:    params.Name,
+		StoredVmQuota:   params.StoredVmQuota,
+		DeployedVmQuota: params.DeployedVmQuota,
+		Role:            params.Role,
+	}
+	o.users = append(o.users, user)
+	s.handle("/admin/user/"+id, user.serve)
+	return user
+}
+
+// createUser serves the creation of a user in the organization.
+func (o *Org) createUser(w http.ResponseWriter, r *http.Request) {
+	params := &types.User{}
+	if err := readXML(r, params); err != nil {
+		writeError(w, http.StatusBadRequest, "", err.Error())
+		return
+	}
+	if params.Name == "" || params.Password == "" {
+		writeError(w, http.StatusBadRequest, "", "The user name and password are required.")
+		return
+	}
+	if !o.server.isRole(params.Role) {
+		writeError(w, http.StatusBadRequest, "", "The user role is missing or invalid.")
+		return
+	}
+	for _, user := range o.users {
+		if user.User.Name == params.Name {
+			writeError(w, http.StatusBadRequest, "DUPLICATE_NAME", fmt.Sprintf("The user %s already exists.", params.Name))
+			return
+		}
+	}
+	user := o.addUser(params)
+	writeXML(w, http.StatusCreated, "User", types.MimeAdminUser, user.User)
+}
+
+func (u *User) serve(w http.ResponseWriter, r *http.Request, rest string) {
+	switch {
+	case rest == "" && r.Method == http.MethodGet:
+		writeXML(w, http.StatusOK, "User", types.MimeAdminUser, u.User)
+	case rest == "" && r.Method == http.MethodPut:
+		params := &types.User{}
+		if err := readXML(r, params); err != nil {
+			writeError(w, http.StatusBadRequest, "", err.Error())
+			return
+		}
+		if !u.org.server.isRole(params.Role) {
+			writeError(w, http.StatusBadRequest, "", "The user role is missing or invalid.")
+			return
+		}
+		if params.Password != "" {
+			u.Password = params.Password
+		}
+		u.User.Description = params.Description
+		u.User.FullName = params.FullName
+		u.User.EmailAddress = params.EmailAddress
+		u.User.Telephone = params.Telephone
+		u.User.IsEnabled = params.IsEnabled
+		// An administrator can unlock a user, only vCD locks them.
+		if !params.IsLocked {
+			u.User.IsLocked = false
+		}
+		u.User.StoredVmQuota = params.StoredVmQuota
+		u.User.DeployedVmQuota = params.DeployedVmQuota
+		u.User.Role = params.Role
+		writeXML(w, http.StatusOK, "User", types.MimeAdminUser, u.User)
+	case rest == "" && r.Method == http.MethodDelete:
+		for i, user := range u.org.users {
+			if user == u {
+				u.org.users = append(u.org.users[:i], u.org.users[i+1:]...)
+				break
+			}
+		}
+		u.org.server.unhandle("/admin/user/" + u.id)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeMethodNotAllowed(w, r)
+	}
+}
@@ -0,0 +1,289 @@
+/*
+ * Copyright 2014 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package fakevcd
+
+import (
+	"net/http"
+	"strings"
+
+	types "github.com/vmware/go-vcloud-director/types/v56"
+)
+
+// vApp and VM status values, see types.VAppStatuses.
+const (
+	statusSuspended  = 3
+	statusPoweredOn  = 4
+	statusPoweredOff = 8
+)
+
+// VApp is a vApp of a VDC. Power operations and deployments change the
+// status of the vApp and of its VMs once their task completes.
+type VApp struct {
+	VApp *types.VApp
+
+	vdc    *Vdc
+	id     string
+	vms    []*VM
+	access *types.ControlAccessParams
+}
+
+// VM is a virtual machine of a vApp.
+type VM struct {
+	VM *types.VM
+
+	vapp  *VApp
+	id    string
+	disks []*types.VirtualHardwareItem
+}
+
+// AddVApp adds a powered off vApp without VMs to the VDC.
+func (v *Vdc) AddVApp(name string) *VApp {
+	v.server.mu.Lock()
+	defer v.server.mu.Unlock()
+	return v.addVApp(name)
+}
+
+func (v *Vdc) addVApp(name string) *VApp {
+	s := v.server
+	id := s.newID()
+	vapp := &VApp{
+		vdc: v,
+		id:  id,
+		VApp: &types.VApp{
+			HREF:   s.href("/vApp/vapp-" + id),
+			Type:   types.MimeVApp,
+			ID:     "urn:vcloud:vapp:" + id,
+			Name:   name,
+			Status: statusPoweredOff,
+		},
+	}
+	v.vapps = append(v.vapps, vapp)
+	s.handle("/vApp/vapp-"+id, vapp.serve)
+	return vapp
+}
+
+func (a *VApp) render() *types.VApp {
+	vapp := *a.VApp
+	vapp.Link = append(types.LinkList{
+		link(types.RelUp, types.MimeVDC, a.vdc.Vdc.HREF, ""),
+		link(types.RelDown, types.MimeControlAccess, a.VApp.HREF+"/controlAccess/", ""),
+		link(types.RelControlAccess, types.MimeControlAccess, a.VApp.HREF+"/action/controlAccess", ""),
+	}, a.VApp.Link...)
+	vapp.Tasks = a.vdc.server.runningTasks(a.VApp.HREF)
+	if len(a.vms) > 0 {
+		vapp.Children = &types.VAppChildren{}
+		for _, vm := range a.vms {
+			vapp.Children.VM = append(vapp.Children.VM, vm.render())
+		}
+	}
+	return &vapp
+}
+
+func (a *VApp) serve(w http.ResponseWriter, r *http.Request, rest string) {
+	s := a.vdc.server
+	owner := &types.Reference{HREF: a.VApp.HREF, Name: a.VApp.Name, Type: types.MimeVApp}
+	switch {
+	case rest == "" && r.Method == http.MethodGet:
+		writeXML(w, http.StatusOK, "VApp", types.MimeVApp, a.render())
+	case rest == "" && r.Method == http.MethodDelete:
+		if a.VApp.Deployed {
+			writeError(w, http.StatusBadRequest, "BAD_REQUEST", "The requested operation could not be executed since vApp \""+a.VApp.Name+"\" is not stopped.")
+			return
+		}
+		writeTask(w, s.newTask(a.vdc.org, "vdcDeleteVapp", owner, a.remove))
+	case strings.HasPrefix(rest, "/controlAccess") || rest == "/action/controlAccess":
+		serveControlAccess(w, r, rest, a.vdc.org, &a.access, false)
+	case strings.HasPrefix(rest, "/power/action/") && r.Method == http.MethodPost:
+		action := strings.TrimPrefix(rest, "/power/action/")
+		status, ok := powerActions[action]
+		if !ok {
+			writeNotFound(w, r.URL.Path)
+			return
+		}
+		writeTask(w, s.newTask(a.vdc.org, "vapp"+strings.ToUpper(action[:1])+action[1:], owner, func() {
+			a.setStatus(status, status != statusPoweredOff)
+		}))
+	case rest == "/action/deploy" && r.Method == http.MethodPost:
+		params := &types.DeployVAppParams{}
+		if err := readXML(r, params); err != nil {
+			writeError(w, http.StatusBadRequest, "", err.Error())
+			return
+		}
+		writeTask(w, s.newTask(a.vdc.org, "vappDeploy", owner, func() {
+			status := a.VApp.Status
+			if params.PowerOn {
+				status = statusPoweredOn
+			}
+			a.setStatus(status, true)
+		}))
+	case rest == "/action/undeploy" && r.Method == http.MethodPost:
+		writeTask(w, s.newTask(a.vdc.org, "vappUndeployPowerOff", owner, func() {
+			a.setStatus(statusPoweredOff, false)
+		}))
+	default:
+		writeMethodNotAllowed(w, r)
+	}
+}
+
+// powerActions maps the power actions to the status they lead to.
+var powerActions = map[string]int{
+	"powerOn":  statusPoweredOn,
+	"powerOff": statusPoweredOff,
+	"reboot":   statusPoweredOn,
+	"reset":    statusPoweredOn,
+	"suspend":  statusSuspended,
+	"shutdown": statusPoweredOff,
+}
+
+// setStatus changes the status of the vApp and of all its VMs.
+func (a *VApp) setStatus(status int, deployed bool) {
+	a.VApp.Status = status
+	a.VApp.Deployed = deployed
+	for _, vm := range a.vms {
+		vm.VM.Status = status
+		vm.VM.Deployed = deployed
+	}
+}
+
+// remove deletes the vApp and its VMs from the VDC.
+func (a *VApp) remove() {
+	s := a.vdc.server
+	for i, vapp := range a.vdc.vapps {
+		if vapp == a {
+			a.vdc.vapps = append(a.vdc.vapps[:i], a.vdc.vapps[i+1:]...)
+			break
+		}
+	}
+	for _, vm := range a.vms {
+		s.unhandle("/vApp/vm-" + vm.id)
+	}
+	s.unhandle("/vApp/vapp-" + a.id)
+}
+
+// AddVM adds a VM, with the status of the vApp, to the vApp.
+func (a *VApp) AddVM(name string) *VM {
+	a.vdc.server.mu.Lock()
+	defer a.vdc.server.mu.Unlock()
+	return a.addVM(name)
+}
+
+func (a *VApp) addVM(name string) *VM {
+	s := a.vdc.server
+	id := s.newID()
+	vm := &VM{
+		vapp: a,
+		id:   id,
+		VM: &types.VM{
+			HREF:     s.href("/vApp/vm-" + id),
+			Type:     "application/vnd.vmware.vcloud.vm+xml",
+			ID:       "urn:vcloud:vm:" + id,
+			Name:     name,
+			Status:   a.VApp.Status,
+			Deployed: a.VApp.Deployed,
+			NetworkConnectionSection: &types.NetworkConnectionSection{
+				HREF: s.href("/vApp/vm-" + id + "/networkConnectionSection/"),
+				Type: "application/vnd.vmware.vcloud.networkConnectionSection+xml",
+				Info: "Specifies the available VM network connections",
+			},
+			VAppScopedLocalID: name,
+		},
+	}
+	vm.disks = a.vdc.newDisks()
+	a.vms = append(a.vms, vm)
+	s.handle("/vApp/vm-"+id, vm.serve)
+	return vm
+}
+
+func (m *VM) render() *types.VM {
+	vm := *m.VM
+	vm.Link = append(types.LinkList{link(types.RelUp, types.MimeVApp, m.vapp.VApp.HREF, "")}, m.VM.Link...)
+	vm.Tasks = m.vapp.vdc.server.runningTasks(m.VM.HREF)
+	return &vm
+}
+
+func (m *VM) serve(w http.ResponseWriter, r *http.Request, rest string) {
+	s := m.vapp.vdc.server
+	owner := &types.Reference{HREF: m.VM.HREF, Name: m.VM.Name, Type: m.VM.Type}
+	switch {
+	case rest == "" && r.Method == http.MethodGet:
+		writeXML(w, http.StatusOK, "Vm", m.VM.Type, m.render())
+	case rest == "/networkConnectionSection" || rest == "/networkConnectionSection/":
+		if r.Method != http.MethodGet {
+			writeMethodNotAllowed(w, r)
+			return
+		}
+		writeXML(w, http.StatusOK, "NetworkConnectionSection", "application/vnd.vmware.vcloud.networkConnectionSection+xml", m.VM.NetworkConnectionSection)
+	case rest == "/virtualHardwareSection/disks" && r.Method == http.MethodGet:
+		writeXML(w, http.StatusOK, "RasdItemsList", types.MimeRasdItemsList, &types.RasdItemsList{
+			HREF: m.VM.HREF + rest,
+			Type: types.MimeRasdItemsList,
+			Item: m.disks,
+		})
+	case rest == "/virtualHardwareSection/disks" && r.Method == http.MethodPut:
+		m.updateDisks(w, r, owner)
+	case strings.HasPrefix(rest, "/power/action/") && r.Method == http.MethodPost:
+		action := strings.TrimPrefix(rest, "/power/action/")
+		status, ok := powerActions[action]
+		if !ok {
+			writeNotFound(w, r.URL.Path)
+			return
+		}
+		writeTask(w, s.newTask(m.vapp.vdc.org, "vapp"+strings.ToUpper(action[:1])+action[1:], owner, func() {
+			m.VM.Status = status
+			m.VM.Deployed = status != statusPoweredOff
+		}))
+	case rest == "/action/undeploy" && r.Method == http.MethodPost:
+		writeTask(w, s.newTask(m.vapp.vdc.org, "vappUndeployPowerOff", owner, func() {
+			m.VM.Status = statusPoweredOff
+			m.VM.Deployed = false
+		}))
+	default:
+		writeMethodNotAllowed(w, r)
+	}
+}
+
+// newDisks returns the disks of a new VM: a SCSI controller and a 16 GB
+// disk on the default storage profile of the VDC.
+func (v *Vdc) newDisks() []*types.VirtualHardwareItem {
+	var profileHREF string
+	for _, profile := range v.storageProfiles {
+		if profile.isDefault {
+			profileHREF = profile.reference().HREF
+		}
+	}
+	return []*types.VirtualHardwareItem{
+		{ElementName: "SCSI Controller 0", Description: "SCSI Controller", InstanceID: 2, Address: "0", ResourceType: 6, ResourceSubType: "lsilogicsas"},
+		{
+			ElementName: "Hard disk 1", Description: "Hard disk", InstanceID: 2000, Parent: 2, ResourceType: 17,
+			HostResource: []*types.VirtualHardwareHostResource{{BusType: 6, BusSubType: "lsilogicsas", Capacity: 16384, StorageProfile: profileHREF}},
+		},
+	}
+}
+
+// updateDisks replaces the disks of the VM. Every disk must be on an
+// enabled storage profile of the VDC.
+func (m *VM) updateDisks(w http.ResponseWriter, r *http.Request, owner *types.Reference) {
+	params := &types.RasdItemsList{}
+	if err := readXML(r, params); err != nil {
+		writeError(w, http.StatusBadRequest, "", err.Error())
+		return
+	}
+	for _, item := range params.Item {
+		for _, hostResource := range item.HostResource {
+			if hostResource.StorageProfile == "" {
+				continue
+			}
+			profile := m.vapp.vdc.findStorageProfile(hostResource.StorageProfile)
+			if profile == nil || !profile.enabled {
+				writeError(w, http.StatusBadRequest, "", "The storage profile of "+item.ElementName+" is not an enabled storage profile of the VDC.")
+				return
+			}
+			hostResource.StorageProfile = profile.reference().HREF
+		}
+	}
+	writeTask(w, m.vapp.vdc.server.newTask(m.vapp.vdc.org, "vappUpdateVm", owner, func() {
+		m.disks = params.Item
+	}))
+}
@@ -0,0 +1,363 @@
+/*
+ * Copyright 2014 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package fakevcd
+
+import (
+	"fmt"
+	"net/http"
+
+	types "github.com/vmware/go-vcloud-director/types/v56"
+)
+
+// Vdc is a VDC of an organization. The Vdc field holds the static part of
+// the VDC, its resource entities and networks are added when it is served.
+type Vdc struct {
+	Vdc *types.Vdc
+
+	server       *Server
+	org          *Org
+	id           string
+	vapps        []*VApp
+	networks     []*Network
+	edgeGateways []*EdgeGateway
+
+	// admin holds the fields of the admin view missing from Vdc, such as
+	// the resource guarantees and the network pool.
+	admin       types.AdminVdc
+	providerVdc *ProviderVdc
+
+	storageProfiles []*vdcStorageProfile
+}
+
+// Network is an organization VDC network.
+type Network struct {
+	Network *types.OrgVDCNetwork
+
+	vdc *Vdc
+	id  string
+}
+
+// EdgeGateway is an edge gateway of a VDC. The services configured through
+// govcd are stored in EdgeGateway.Configuration.EdgeGatewayServiceConfiguration.
+type EdgeGateway struct {
+	EdgeGateway *types.EdgeGateway
+
+	vdc *Vdc
+	id  string
+}
+
+// Org returns the organization the VDC belongs to.
+func (v *Vdc) Org() *Org {
+	return v.org
+}
+
+// AddVdc adds an enabled pay-as-you-go VDC, with a single default storage
+// profile, to the organization.
+func (o *Org) AddVdc(name string) *Vdc {
+	o.server.mu.Lock()
+	defer o.server.mu.Unlock()
+	vdc := o.addVdc(name)
+	vdc.addStorageProfile("*", nil, true, 0).isDefault = true
+	return vdc
+}
+
+func (o *Org) addVdc(name string) *Vdc {
+	s := o.server
+	id := s.newID()
+	vdc := &Vdc{
+		server: s,
+		org:    o,
+		id:     id,
+		Vdc: &types.Vdc{
+			HREF:            s.href("/vdc/" + id),
+			Type:            types.MimeVDC,
+			ID:              "urn:vcloud:vdc:" + id,
+			Name:            name,
+			Status:          "1",
+			AllocationModel: "AllocationVApp",
+			ComputeCapacity: []*types.ComputeCapacity{{
+				CPU:    &types.CapacityWithUsage{Units: "MHz"},
+				Memory: &types.CapacityWithUsage{Units: "MB"},
+			}},
+			IsEnabled:    true,
+			NetworkQuota: 20,
+			NicQuota:     0,
+		},
+	}
+	o.vdcs = append(o.vdcs, vdc)
+	s.handle("/vdc/"+id, vdc.serve)
+	s.handle("/admin/vdc/"+id, vdc.serveAdmin)
+	return vdc
+}
+
+func (v *Vdc) render() *types.Vdc {
+	vdc := *v.Vdc
+	vdc.Link = types.LinkList{
+		link(types.RelUp, types.MimeOrg, v.org.Org.HREF, ""),
+		link("edgeGateways", "application/vnd.vmware.vcloud.query.records+xml", v.server.href("/admin/vdc/"+v.id+"/edgeGateways"), ""),
+		link(types.RelAdd, "application/vnd.vmware.vcloud.orgVdcNetwork+xml", v.server.href("/admin/vdc/"+v.id+"/networks"), ""),
+		link(types.RelAdd, "application/vnd.vmware.vcloud.composeVAppParams+xml", v.Vdc.HREF+"/action/composeVApp", ""),
+		link(types.RelAdd, types.MimeInstantiateVAppTemplate, v.Vdc.HREF+"/action/instantiateVAppTemplate", ""),
+	}
+	vdc.Link = append(vdc.Link, v.Vdc.Link...)
+
+	entities := &types.ResourceEntities{}
+	for _, vapp := range v.vapps {
+		entities.ResourceEntity = append(entities.ResourceEntity, &types.ResourceReference{
+			HREF: vapp.VApp.HREF, Type: types.MimeVApp, Name: vapp.VApp.Name,
+		})
+	}
+	vdc.ResourceEntities = []*types.ResourceEntities{entities}
+
+	networks := &types.AvailableNetworks{}
+	for _, network := range v.networks {
+		networks.Network = append(networks.Network, &types.Reference{
+			HREF: network.Network.HREF, Type: types.MimeNetwork, Name: network.Network.Name,
+		})
+	}
+	vdc.AvailableNetworks = []*types.AvailableNetworks{networks}
+	vdc.UsedNetworkCount = len(v.networks)
+
+	profiles := &types.VdcStorageProfiles{}
+	for _, profile := range v.storageProfiles {
+		profiles.VdcStorageProfile = append(profiles.VdcStorageProfile, profile.reference())
+	}
+	vdc.VdcStorageProfiles = []*types.VdcStorageProfiles{profiles}
+	vdc.Tasks = v.server.runningTasks(v.Vdc.HREF)
+	return &vdc
+}
+
+func (v *Vdc) serve(w http.ResponseWriter, r *http.Request, rest string) {
+	switch {
+	case rest == "" && r.Method == http.MethodGet:
+		writeXML(w, http.StatusOK, "Vdc", types.MimeVDC, v.render())
+	case rest == "/action/composeVApp" && r.Method == http.MethodPost:
+		params := &types.ComposeVAppParams{}
+		if err := readXML(r, params); err != nil {
+			writeError(w, http.StatusBadRequest, "", err.Error())
+			return
+		}
+		vmName := params.Name + "-vm"
+		if params.SourcedItem != nil && params.SourcedItem.Source != nil && params.SourcedItem.Source.Name != "" {
+			vmName = params.SourcedItem.Source.Name
+		}
+		v.createVApp(w, params.Name, params.Description, vmName, "vdcComposeVapp")
+	case rest == "/action/instantiateVAppTemplate" && r.Method == http.MethodPost:
+		params := &types.InstantiateVAppTemplateParams{}
+		if err := readXML(r, params); err != nil {
+			writeError(w, http.StatusBadRequest, "", err.Error())
+			return
+		}
+		v.createVApp(w, params.Name, params.Description, params.Name+"-vm", "vdcInstantiateVapp")
+	default:
+		writeMethodNotAllowed(w, r)
+	}
+}
+
+// createVApp answers a compose or instantiate request with the new vApp,
+// carrying the task that creates it.
+func (v *Vdc) createVApp(w http.ResponseWriter, name, description, vmName, operation string) {
+	if name == "" {
+		writeError(w, http.StatusBadRequest, "", "The vApp name is missing.")
+		return
+	}
+	vapp := v.addVApp(name)
+	vapp.VApp.Description = description
+	vapp.VApp.Status = 0
+	vapp.addVM(vmName)
+	owner := &types.Reference{HREF: vapp.VApp.HREF, Name: name, Type: types.MimeVApp}
+	task := v.server.newTask(v.org, operation, owner, func() {
+		vapp.VApp.Status = 8
+	})
+	rendered := vapp.render()
+	rendered.Tasks = &types.TasksInProgress{Task: []*types.Task{task}}
+	writeXML(w, http.StatusCreated, "VApp", types.MimeVApp, rendered)
+}
+
+func (v *Vdc) serveAdmin(w http.ResponseWriter, r *http.Request, rest string) {
+	switch {
+	case rest == "" && r.Method == http.MethodGet:
+		writeXML(w, http.StatusOK, "AdminVdc", types.MimeAdminVdc, v.renderAdmin())
+	case rest == "" && r.Method == http.MethodPut:
+		v.update(w, r)
+	case rest == "" && r.Method == http.MethodDelete:
+		v.delete(w, r)
+	case (rest == "/action/enable" || rest == "/action/disable") && r.Method == http.MethodPost:
+		v.Vdc.IsEnabled = rest == "/action/enable"
+		w.WriteHeader(http.StatusNoContent)
+	case rest == "/vdcStorageProfiles" && r.Method == http.MethodPost:
+		v.updateStorageProfiles(w, r)
+	case rest == "/edgeGateways" && r.Method == http.MethodGet:
+		records := &types.QueryResultEdgeGatewayRecordsType{
+			HREF:     v.server.href("/admin/vdc/" + v.id + "/edgeGateways"),
+			Type:     "application/vnd.vmware.vcloud.query.records+xml",
+			Name:     "edgeGateway",
+			Page:     1,
+			PageSize: 25,
+			Total:    float64(len(v.edgeGateways)),
+		}
+		for _, edge := range v.edgeGateways {
+			records.EdgeGatewayRecord = append(records.EdgeGatewayRecord, &types.QueryResultEdgeGatewayRecordType{
+				HREF:          edge.EdgeGateway.HREF,
+				Name:          edge.EdgeGateway.Name,
+				Vdc:           v.Vdc.HREF,
+				GatewayStatus: "READY",
+				HaStatus:      "DISABLED",
+			})
+		}
+		writeXML(w, http.StatusOK, "QueryResultRecords", "application/vnd.vmware.vcloud.query.records+xml", records)
+	case rest == "/networks" && r.Method == http.MethodPost:
+		config := &types.OrgVDCNetwork{}
+		if err := readXML(r, config); err != nil {
+			writeError(w, http.StatusBadRequest, "", err.Error())
+			return
+		}
+		network := v.addNetwork(config.Name)
+		network.Network.Description = config.Description
+		network.Network.Configuration = config.Configuration
+		network.Network.EdgeGateway = config.EdgeGateway
+		network.Network.IsShared = config.IsShared
+		owner := &types.Reference{HREF: network.Network.HREF, Name: config.Name, Type: types.MimeNetwork}
+		task := v.server.newTask(v.org, "networkCreateOrgVdcNetwork", owner, nil)
+		rendered := *network.Network
+		rendered.Tasks = &types.TasksInProgress{Task: []*types.Task{task}}
+		writeXML(w, http.StatusCreated, "OrgVdcNetwork", "application/vnd.vmware.vcloud.orgVdcNetwork+xml", &rendered)
+	default:
+		writeMethodNotAllowed(w, r)
+	}
+}
+
+// AddNetwork adds a routed organization VDC network to the VDC.
+func (v *Vdc) AddNetwork(name string) *Network {
+	v.server.mu.Lock()
+	defer v.server.mu.Unlock()
+	return v.addNetwork(name)
+}
+
+func (v *Vdc) addNetwork(name string) *Network {
+	s := v.server
+	id := s.newID()
+	network := &Network{
+		vdc: v,
+		id:  id,
+		Network: &types.OrgVDCNetwork{
+			HREF:   s.href("/network/" + id),
+			Type:   "application/vnd.vmware.vcloud.orgVdcNetwork+xml",
+			ID:     "urn:vcloud:network:" + id,
+			Name:   name,
+			Status: "1",
+			Configuration: &types.NetworkConfiguration{
+				FenceMode: "natRouted",
+			},
+		},
+	}
+	network.Network.Link = []types.Link{{Rel: types.RelUp, Type: types.MimeVDC, HREF: v.Vdc.HREF}}
+	v.networks = append(v.networks, network)
+	s.handle("/network/"+id, network.serve)
+	s.handle("/admin/network/"+id, network.serve)
+	return network
+}
+
+func (n *Network) serve(w http.ResponseWriter, r *http.Request, rest string) {
+	s := n.vdc.server
+	switch {
+	case rest == "" && r.Method == http.MethodGet:
+		writeXML(w, http.StatusOK, "OrgVdcNetwork", "application/vnd.vmware.vcloud.orgVdcNetwork+xml", n.Network)
+	case rest == "" && r.Method == http.MethodDelete:
+		owner := &types.Reference{HREF: n.Network.HREF, Name: n.Network.Name, Type: types.MimeNetwork}
+		writeTask(w, s.newTask(n.vdc.org, "networkDelete", owner, func() {
+			for i, network := range n.vdc.networks {
+				if network == n {
+					n.vdc.networks = append(n.vdc.networks[:i], n.vdc.networks[i+1:]...)
+					break
+				}
+			}
+			s.unhandle("/network/" + n.id)
+			s.unhandle("/admin/network/" + n.id)
+		}))
+	default:
+		writeMethodNotAllowed(w, r)
+	}
+}
+
+// AddEdgeGateway adds an edge gateway, with a single uplink interface, to
+// the VDC.
+func (v *Vdc) AddEdgeGateway(name string) *EdgeGateway {
+	s := v.server
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := s.newID()
+	edge := &EdgeGateway{
+		vdc: v,
+		id:  id,
+		EdgeGateway: &types.EdgeGateway{
+			HREF:   s.href("/admin/edgeGateway/" + id),
+			Type:   "application/vnd.vmware.admin.edgeGateway+xml",
+			ID:     "urn:vcloud:gateway:" + id,
+			Name:   name,
+			Status: 1,
+			Configuration: &types.GatewayConfiguration{
+				GatewayBackingConfig: "compact",
+				GatewayInterfaces: &types.GatewayInterfaces{
+					GatewayInterface: []*types.GatewayInterface{{
+						Name:          "uplink",
+						DisplayName:   "uplink",
+						InterfaceType: "uplink",
+						Network: &types.Reference{
+							HREF: s.href("/admin/network/" + s.newID()),
+							Type: "application/vnd.vmware.admin.network+xml",
+							Name: "uplink",
+						},
+						UseForDefaultRoute: true,
+					}},
+				},
+				EdgeGatewayServiceConfiguration: &types.GatewayFeatures{},
+			},
+		},
+	}
+	v.edgeGateways = append(v.edgeGateways, edge)
+	s.handle("/admin/edgeGateway/"+id, edge.serve)
+	return edge
+}
+
+func (e *EdgeGateway) serve(w http.ResponseWriter, r *http.Request, rest string) {
+	s := e.vdc.server
+	switch {
+	case rest == "" && r.Method == http.MethodGet:
+		edge := *e.EdgeGateway
+		edge.Link = append(types.LinkList{link(types.RelUp, types.MimeVDC, e.vdc.Vdc.HREF, "")}, e.EdgeGateway.Link...)
+		edge.Tasks = s.runningTasks(e.EdgeGateway.HREF)
+		writeXML(w, http.StatusOK, "EdgeGateway", "application/vnd.vmware.admin.edgeGateway+xml", &edge)
+	case rest == "/action/configureServices" && r.Method == http.MethodPost:
+		if s.runningTasks(e.EdgeGateway.HREF) != nil {
+			writeError(w, http.StatusBadRequest, "BUSY_ENTITY", fmt.Sprintf("The entity %s is busy completing an operation.", e.EdgeGateway.Name))
+			return
+		}
+		config := &types.EdgeGatewayServiceConfiguration{}
+		if err := readXML(r, config); err != nil {
+			writeError(w, http.StatusBadRequest, "", err.Error())
+			return
+		}
+		owner := &types.Reference{HREF: e.EdgeGateway.HREF, Name: e.EdgeGateway.Name, Type: e.EdgeGateway.Type}
+		writeTask(w, s.newTask(e.vdc.org, "networkEdgeGatewayConfigureServices", owner, func() {
+			services := e.EdgeGateway.Configuration.EdgeGatewayServiceConfiguration
+			if config.FirewallService != nil {
+				services.FirewallService = config.FirewallService
+			}
+			if config.NatService != nil {
+				services.NatService = config.NatService
+			}
+			if config.GatewayDhcpService != nil {
+				services.GatewayDhcpService = config.GatewayDhcpService
+			}
+			if config.GatewayIpsecVpnService != nil {
+				services.GatewayIpsecVpnService = config.GatewayIpsecVpnService
+			}
+		}))
+	default:
+		writeMethodNotAllowed(w, r)
+	}
+}
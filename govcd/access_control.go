@@ -0,0 +1,251 @@
+/*
+ * Copyright 2014 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"strings"
+
+	types "github.com/vmware/go-vcloud-director/types/v56"
+)
+
+// accessControl reads and changes the access control of a catalog or a
+// vApp.
+type accessControl struct {
+	c       *Client
+	name    string
+	getHREF string
+	setHREF string
+	// everyoneReadOnly is set for catalogs, which vCD only shares with
+	// everyone read only.
+	everyoneReadOnly bool
+}
+
+// GetAccessControl returns who the catalog is shared with.
+func (cat *Catalog) GetAccessControl() (*types.ControlAccessParams, error) {
+	return cat.accessControl().get()
+}
+
+// SetAccessControl replaces the access control of the catalog.
+func (cat *Catalog) SetAccessControl(params *types.ControlAccessParams) error {
+	return cat.accessControl().set(params)
+}
+
+// ShareWithEveryone shares the catalog with every user of the org. vCD
+// only allows types.ControlAccessReadOnly for catalogs, other levels are
+// rejected without contacting vCD.
+func (cat *Catalog) ShareWithEveryone(accessLevel string) error {
+	return cat.accessControl().shareWithEveryone(accessLevel)
+}
+
+// ShareWith gives the users and groups referenced by subjects the access
+// level to the catalog, in addition to the existing access settings.
+func (cat *Catalog) ShareWith(accessLevel string, subjects ...*types.Reference) error {
+	return cat.accessControl().shareWith(accessLevel, subjects)
+}
+
+// Unshare removes the access settings of the users and groups referenced
+// by subjects from the catalog.
+func (cat *Catalog) Unshare(subjects ...*types.Reference) error {
+	return cat.accessControl().unshare(subjects)
+}
+
+// RemoveAccessControl stops sharing the catalog with anyone.
+func (cat *Catalog) RemoveAccessControl() error {
+	return cat.accessControl().set(&types.ControlAccessParams{})
+}
+
+func (cat *Catalog) accessControl() *accessControl {
+	ac := &accessControl{c: cat.c, name: "catalog " + cat.Catalog.Name, everyoneReadOnly: true}
+	if link := cat.Catalog.Link.ForType(types.MimeControlAccess, types.RelDown); link != nil {
+		ac.getHREF = link.HREF
+	}
+	if link := cat.Catalog.Link.ForType(types.MimeControlAccess, types.RelControlAccess); link != nil {
+		ac.setHREF = link.HREF
+	}
+	// The access control of a catalog lives under its org, e.g.
+	// /api/org/<org id>/catalog/<catalog id>/controlAccess/
+	if org := cat.Catalog.Link.ForType(types.MimeOrg, types.RelUp); org != nil && (ac.getHREF == "" || ac.setHREF == "") {
+		catalogHREF := org.HREF + "/catalog/" + cat.Catalog.HREF[strings.LastIndex(cat.Catalog.HREF, "/")+1:]
+		if ac.getHREF == "" {
+			ac.getHREF = catalogHREF + "/controlAccess/"
+		}
+		if ac.setHREF == "" {
+			ac.setHREF = catalogHREF + "/action/controlAccess"
+		}
+	}
+	return ac
+}
+
+// GetAccessControl returns who the vApp is shared with.
+func (vapp *VApp) GetAccessControl() (*types.ControlAccessParams, error) {
+	return vapp.accessControl().get()
+}
+
+// SetAccessControl replaces the access control of the vApp.
+func (vapp *VApp) SetAccessControl(params *types.ControlAccessParams) error {
+	return vapp.accessControl().set(params)
+}
+
+// ShareWithEveryone shares the vApp with every user of the org.
+func (vapp *VApp) ShareWithEveryone(accessLevel string) error {
+	return vapp.accessControl().shareWithEveryone(accessLevel)
+}
+
+// ShareWith gives the users and groups referenced by subjects the access
+// level to the vApp, in addition to the existing access settings.
+func (vapp *VApp) ShareWith(accessLevel string, subjects ...*types.Reference) error {
+	return vapp.accessControl().shareWith(accessLevel, subjects)
+}
+
+// Unshare removes the access settings of the users and groups referenced
+// by subjects from the vApp.
+func (vapp *VApp) Unshare(subjects ...*types.Reference) error {
+	return vapp.accessControl().unshare(subjects)
+}
+
+// RemoveAccessControl stops sharing the vApp with anyone but its owner.
+func (vapp *VApp) RemoveAccessControl() error {
+	return vapp.accessControl().set(&types.ControlAccessParams{})
+}
+
+func (vapp *VApp) accessControl() *accessControl {
+	ac := &accessControl{
+		c:       vapp.c,
+		name:    "vApp " + vapp.VApp.Name,
+		getHREF: vapp.VApp.HREF + "/controlAccess/",
+		setHREF: vapp.VApp.HREF + "/action/controlAccess",
+	}
+	if link := vapp.VApp.Link.ForType(types.MimeControlAccess, types.RelDown); link != nil {
+		ac.getHREF = link.HREF
+	}
+	if link := vapp.VApp.Link.ForType(types.MimeControlAccess, types.RelControlAccess); link != nil {
+		ac.setHREF = link.HREF
+	}
+	return ac
+}
+
+func (ac *accessControl) get() (*types.ControlAccessParams, error) {
+	getHREF, err := url.ParseRequestURI(ac.getHREF)
+	if err != nil {
+		return nil, fmt.Errorf("error getting the access control HREF of %s: %w", ac.name, err)
+	}
+	req := ac.c.NewRequest(map[string]string{}, "GET", *getHREF, nil)
+	resp, err := ac.c.doRequest(req)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving the access control of %s: %w", ac.name, err)
+	}
+	params := new(types.ControlAccessParams)
+	if err = decodeBody(resp, params); err != nil {
+		return nil, fmt.Errorf("error decoding the access control of %s: %w", ac.name, err)
+	}
+	return params, nil
+}
+
+func (ac *accessControl) set(params *types.ControlAccessParams) error {
+	if params.IsSharedToEveryone {
+		if err := validateAccessLevel(params.EveryoneAccessLevel); err != nil {
+			return err
+		}
+		if ac.everyoneReadOnly && params.EveryoneAccessLevel != types.ControlAccessReadOnly {
+			return fmt.Errorf("%s can only be shared with everyone with access level %s", ac.name, types.ControlAccessReadOnly)
+		}
+	} else {
+		params.EveryoneAccessLevel = ""
+	}
+	if params.AccessSettings != nil {
+		for _, setting := range params.AccessSettings.AccessSetting {
+			if err := validateAccessLevel(setting.AccessLevel); err != nil {
+				return err
+			}
+		}
+	}
+	setHREF, err := url.ParseRequestURI(ac.setHREF)
+	if err != nil {
+		return fmt.Errorf("error getting the access control HREF of %s: %w", ac.name, err)
+	}
+
+	params.Xmlns = "http://www.vmware.com/vcloud/v1.5"
+	output, _ := xml.MarshalIndent(params, "  ", "    ")
+	xmlData := bytes.NewBufferString(xml.Header + string(output))
+
+	req := ac.c.NewRequest(map[string]string{}, "POST", *setHREF, xmlData)
+	req.Header.Add("Content-Type", types.MimeControlAccess)
+	resp, err := ac.c.doRequest(req)
+	if err != nil {
+		return fmt.Errorf("error changing the access control of %s: %w", ac.name, err)
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (ac *accessControl) shareWithEveryone(accessLevel string) error {
+	params, err := ac.get()
+	if err != nil {
+		return err
+	}
+	params.IsSharedToEveryone = true
+	params.EveryoneAccessLevel = accessLevel
+	return ac.set(params)
+}
+
+func (ac *accessControl) shareWith(accessLevel string, subjects []*types.Reference) error {
+	params, err := ac.get()
+	if err != nil {
+		return err
+	}
+	if params.AccessSettings == nil {
+		params.AccessSettings = &types.AccessSettingList{}
+	}
+	for _, subject := range subjects {
+		var found bool
+		for _, setting := range params.AccessSettings.AccessSetting {
+			if setting.Subject.HREF == subject.HREF {
+				setting.AccessLevel, found = accessLevel, true
+			}
+		}
+		if !found {
+			params.AccessSettings.AccessSetting = append(params.AccessSettings.AccessSetting,
+				&types.AccessSetting{Subject: subject, AccessLevel: accessLevel})
+		}
+	}
+	return ac.set(params)
+}
+
+func (ac *accessControl) unshare(subjects []*types.Reference) error {
+	params, err := ac.get()
+	if err != nil {
+		return err
+	}
+	if params.AccessSettings == nil {
+		return nil
+	}
+	removed := make(map[string]bool)
+	for _, subject := range subjects {
+		removed[subject.HREF] = true
+	}
+	var settings []*types.AccessSetting
+	for _, setting := range params.AccessSettings.AccessSetting {
+		if !removed[setting.Subject.HREF] {
+			settings = append(settings, setting)
+		}
+	}
+	params.AccessSettings.AccessSetting = settings
+	if len(settings) == 0 {
+		params.AccessSettings = nil
+	}
+	return ac.set(params)
+}
+
+func validateAccessLevel(accessLevel string) error {
+	switch accessLevel {
+	case types.ControlAccessReadOnly, types.ControlAccessChange, types.ControlAccessFullControl:
+		return nil
+	}
+	return fmt.Errorf("invalid access level %q", accessLevel)
+}
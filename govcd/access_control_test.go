@@ -0,0 +1,150 @@
+/*
+ * Copyright 2014 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"testing"
+
+	types "github.com/vmware/go-vcloud-director/types/v56"
+	. "gopkg.in/check.v1"
+)
+
+// Shares the test catalog with everyone, then restores its access
+// control.
+func (vcd *TestVCD) Test_CatalogAccessControl(check *C) {
+	cat, err := vcd.org.FindCatalog(vcd.config.VCD.Catalog.Name)
+	check.Assert(err, IsNil)
+	initial, err := cat.GetAccessControl()
+	check.Assert(err, IsNil)
+	defer cat.SetAccessControl(initial)
+
+	check.Assert(cat.ShareWithEveryone(types.ControlAccessReadOnly), IsNil)
+	params, err := cat.GetAccessControl()
+	check.Assert(err, IsNil)
+	check.Assert(params.IsSharedToEveryone, Equals, true)
+	check.Assert(params.EveryoneAccessLevel, Equals, types.ControlAccessReadOnly)
+}
+
+func TestAccessControlFakeVCD(t *testing.T) {
+	server, fakeVdc, client := newFakeVCD(t)
+	defer server.Close()
+	fakeVdc.AddVApp("web")
+	fakeVdc.Org().AddCatalog("shared")
+
+	org, vdc := fakeOrgVdc(t, client)
+	adminOrg, err := GetAdminOrgByName(client, "org")
+	if err != nil {
+		t.Fatalf("error getting admin org: %s", err)
+	}
+	role, err := adminOrg.GetRoleReference("vApp User")
+	if err != nil {
+		t.Fatalf("error getting role: %s", err)
+	}
+	var subjects []*types.Reference
+	for _, name := range []string{"alice", "bob"} {
+		user, err := adminOrg.CreateUser(&types.User{Name: name, Password: "secret", Role: role})
+		if err != nil {
+			t.Fatalf("error creating user: %s", err)
+		}
+		subjects = append(subjects, &types.Reference{HREF: user.User.HREF, Type: user.User.Type})
+	}
+	alice, bob := subjects[0], subjects[1]
+
+	cat, err := org.FindCatalog("shared")
+	if err != nil {
+		t.Fatalf("error finding catalog: %s", err)
+	}
+	vapp, err := vdc.FindVAppByName("web")
+	if err != nil {
+		t.Fatalf("error finding vApp: %s", err)
+	}
+
+	for _, shared := range []struct {
+		name string
+		sharer
+		// path of the controlAccess action of the object
+		path string
+	}{
+		{"catalog", &cat, "/org/[^/]+/catalog/[^/]+/action/controlAccess"},
+		{"vApp", &vapp, "/vApp/vapp-[^/]+/action/controlAccess"},
+	} {
+		params, err := shared.GetAccessControl()
+		if err != nil || params.IsSharedToEveryone || params.AccessSettings != nil {
+			t.Fatalf("expected the %s not to be shared: %+v, %v", shared.name, params, err)
+		}
+		if err = shared.ShareWithEveryone("Everything"); err == nil {
+			t.Fatalf("expected an error sharing the %s with an invalid access level", shared.name)
+		}
+		server.ResetRequests()
+		if err = shared.ShareWithEveryone(types.ControlAccessReadOnly); err != nil {
+			t.Fatalf("error sharing the %s with everyone: %s", shared.name, err)
+		}
+		if err = shared.ShareWith(types.ControlAccessReadOnly, alice, bob); err != nil {
+			t.Fatalf("error sharing the %s: %s", shared.name, err)
+		}
+		checkRequests(t, server, apiRequest{
+			Method: "POST", Path: shared.path, ContentType: "application/vnd.vmware.vcloud.controlAccess+xml",
+			Body: []string{"<ControlAccessParams ", "<IsSharedToEveryone>true</IsSharedToEveryone>",
+				"<EveryoneAccessLevel>ReadOnly</EveryoneAccessLevel>"},
+		}, apiRequest{
+			Method: "POST", Path: shared.path, ContentType: "application/vnd.vmware.vcloud.controlAccess+xml",
+			Body: []string{"<AccessSettings>", "<AccessSetting>", `<Subject href="` + alice.HREF, `<Subject href="` + bob.HREF,
+				"<AccessLevel>ReadOnly</AccessLevel>"},
+		})
+		if err = shared.ShareWith(types.ControlAccessFullControl, bob); err != nil {
+			t.Fatalf("error changing the access level of the %s: %s", shared.name, err)
+		}
+		params, err = shared.GetAccessControl()
+		if err != nil || !params.IsSharedToEveryone || params.EveryoneAccessLevel != types.ControlAccessReadOnly ||
+			params.AccessSettings == nil || len(params.AccessSettings.AccessSetting) != 2 {
+			t.Fatalf("unexpected access control of the %s: %+v, %v", shared.name, params, err)
+		}
+		for _, setting := range params.AccessSettings.AccessSetting {
+			expected := map[string]string{"alice": types.ControlAccessReadOnly, "bob": types.ControlAccessFullControl}[setting.Subject.Name]
+			if setting.AccessLevel != expected {
+				t.Fatalf("expected %s to have access %s to the %s, got %s", setting.Subject.Name, expected, shared.name, setting.AccessLevel)
+			}
+		}
+
+		if err = shared.Unshare(alice); err != nil {
+			t.Fatalf("error unsharing the %s: %s", shared.name, err)
+		}
+		params, err = shared.GetAccessControl()
+		if err != nil || len(params.AccessSettings.AccessSetting) != 1 || params.AccessSettings.AccessSetting[0].Subject.Name != "bob" {
+			t.Fatalf("expected the %s to be shared with bob only: %+v, %v", shared.name, params, err)
+		}
+		if err = shared.RemoveAccessControl(); err != nil {
+			t.Fatalf("error removing the access control of the %s: %s", shared.name, err)
+		}
+		params, err = shared.GetAccessControl()
+		if err != nil || params.IsSharedToEveryone || params.AccessSettings != nil {
+			t.Fatalf("expected the %s not to be shared anymore: %+v, %v", shared.name, params, err)
+		}
+	}
+
+	// Catalogs can only be shared with everyone read only, which is
+	// checked before sending the change
+	server.ResetRequests()
+	if err = cat.ShareWithEveryone(types.ControlAccessChange); err == nil {
+		t.Fatal("expected an error sharing a catalog with everyone with change access")
+	}
+	for _, request := range server.Requests() {
+		if request.Method == "POST" {
+			t.Fatalf("unexpected request sharing a catalog with everyone with change access:\n%s", formatRequests(server.Requests()))
+		}
+	}
+	if err = vapp.ShareWithEveryone(types.ControlAccessChange); err != nil {
+		t.Fatalf("error sharing the vApp with everyone with change access: %s", err)
+	}
+}
+
+// sharer is implemented by Catalog and VApp.
+type sharer interface {
+	GetAccessControl() (*types.ControlAccessParams, error)
+	ShareWithEveryone(accessLevel string) error
+	ShareWith(accessLevel string, subjects ...*types.Reference) error
+	Unshare(subjects ...*types.Reference) error
+	RemoveAccessControl() error
+}
@@ -0,0 +1,264 @@
+/*
+ * Copyright 2014 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	types "github.com/vmware/go-vcloud-director/types/v56"
+)
+
+// AdminVdc is the admin view of an org VDC, used by system
+// administrators to change its allocation, enable, disable or delete it.
+// GetVdc returns the user view, used by the rest of govcd.
+type AdminVdc struct {
+	AdminVdc *types.AdminVdc
+	AdminOrg *AdminOrg
+	c        *Client
+}
+
+// NewAdminVdc returns an empty VDC of adminOrg.
+func NewAdminVdc(c *Client, adminOrg *AdminOrg) *AdminVdc {
+	return &AdminVdc{
+		AdminVdc: new(types.AdminVdc),
+		AdminOrg: adminOrg,
+		c:        c,
+	}
+}
+
+// CreateVdc creates a VDC in the org, waits for its creation to complete
+// and returns it. params.AllocationModel is one of types.AllocationVApp,
+// types.AllocationPool and types.ReservationPool.
+func (adminOrg *AdminOrg) CreateVdc(params *types.CreateVdcParams) (*Vdc, error) {
+	if err := validateCreateVdcParams(params); err != nil {
+		return nil, err
+	}
+	params.Xmlns = "http://www.vmware.com/vcloud/v1.5"
+	output, err := xml.MarshalIndent(params, "  ", "    ")
+	if err != nil {
+		return nil, fmt.Errorf("error encoding VDC %s: %w", params.Name, err)
+	}
+	xmlData := bytes.NewBufferString(xml.Header + string(output))
+
+	createHREF, err := url.ParseRequestURI(adminOrg.AdminOrg.HREF)
+	if err != nil {
+		return nil, fmt.Errorf("error getting AdminOrg HREF %s : %w", adminOrg.AdminOrg.HREF, err)
+	}
+	createHREF.Path += "/vdcsparams"
+	req := adminOrg.c.NewRequest(map[string]string{}, "POST", *createHREF, xmlData)
+	req.Header.Add("Content-Type", types.MimeCreateVdcParams)
+	resp, err := adminOrg.c.doRequest(req)
+	if err != nil {
+		return nil, fmt.Errorf("error creating VDC %s: %w", params.Name, err)
+	}
+	adminVdc := NewAdminVdc(adminOrg.c, adminOrg)
+	if err = decodeBody(resp, adminVdc.AdminVdc); err != nil {
+		return nil, fmt.Errorf("error decoding VDC response: %w", err)
+	}
+	if err = adminVdc.waitTasks(); err != nil {
+		return nil, fmt.Errorf("error creating VDC %s: %w", params.Name, err)
+	}
+	if err = adminOrg.Refresh(); err != nil {
+		return nil, err
+	}
+	return adminVdc.GetVdc()
+}
+
+func validateCreateVdcParams(params *types.CreateVdcParams) error {
+	if params.Name == "" {
+		return fmt.Errorf("VDC name is required")
+	}
+	switch params.AllocationModel {
+	case types.AllocationVApp, types.AllocationPool, types.ReservationPool:
+	default:
+		return fmt.Errorf("invalid allocation model %q for VDC %s", params.AllocationModel, params.Name)
+	}
+	if len(params.ComputeCapacity) == 0 || params.ComputeCapacity[0].CPU == nil || params.ComputeCapacity[0].Memory == nil {
+		return fmt.Errorf("VDC %s needs a CPU and memory capacity", params.Name)
+	}
+	if params.ProviderVdcReference == nil {
+		return fmt.Errorf("VDC %s needs a provider VDC", params.Name)
+	}
+	if len(params.VdcStorageProfile) == 0 {
+		return fmt.Errorf("VDC %s needs at least one storage profile", params.Name)
+	}
+	for _, profile := range params.VdcStorageProfile {
+		if profile.ProviderVdcStorageProfile == nil {
+			return fmt.Errorf("storage profile of VDC %s needs a provider VDC storage profile", params.Name)
+		}
+	}
+	return nil
+}
+
+// GetAdminVdcByName returns the admin view of the VDC of the org having
+// the given name. The returned error matches ErrNotFound when there is no
+// such VDC.
+func (adminOrg *AdminOrg) GetAdminVdcByName(name string) (*AdminVdc, error) {
+	if err := adminOrg.Refresh(); err != nil {
+		return nil, err
+	}
+	if adminOrg.AdminOrg.Vdcs != nil {
+		for _, reference := range adminOrg.AdminOrg.Vdcs.Vdcs {
+			if reference.Name == name {
+				adminVdc := NewAdminVdc(adminOrg.c, adminOrg)
+				adminVdc.AdminVdc.HREF = reference.HREF
+				if err := adminVdc.Refresh(); err != nil {
+					return nil, err
+				}
+				return adminVdc, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("VDC %s: %w", name, ErrNotFound)
+}
+
+// Refresh fetches the VDC again.
+func (adminVdc *AdminVdc) Refresh() error {
+	if adminVdc.AdminVdc.HREF == "" {
+		return fmt.Errorf("cannot refresh, Object is empty")
+	}
+	refreshed := new(types.AdminVdc)
+	if err := adminVdc.c.getByHREF(adminVdc.AdminVdc.HREF, "VDC", refreshed); err != nil {
+		return err
+	}
+	adminVdc.AdminVdc = refreshed
+	return nil
+}
+
+// GetVdc returns the user view of the VDC.
+func (adminVdc *AdminVdc) GetVdc() (*Vdc, error) {
+	vdc := NewVdc(adminVdc.c)
+	vdcHREF := strings.Replace(adminVdc.AdminVdc.HREF, "/api/admin/vdc/", "/api/vdc/", 1)
+	if err := adminVdc.c.getByHREF(vdcHREF, "VDC", vdc.Vdc); err != nil {
+		return nil, err
+	}
+	return vdc, nil
+}
+
+// Update sends the description, compute capacity, quotas and resource
+// guarantees of AdminVdc to vCD and waits for the update to complete. The
+// storage profiles are not changed.
+func (adminVdc *AdminVdc) Update() error {
+	current := adminVdc.AdminVdc
+	vdcConfiguration := &types.AdminVdc{
+		Xmlns:                    "http://www.vmware.com/vcloud/v1.5",
+		HREF:                     current.HREF,
+		Name:                     current.Name,
+		Description:              current.Description,
+		AllocationModel:          current.AllocationModel,
+		ComputeCapacity:          current.ComputeCapacity,
+		NicQuota:                 current.NicQuota,
+		NetworkQuota:             current.NetworkQuota,
+		VMQuota:                  current.VMQuota,
+		IsEnabled:                current.IsEnabled,
+		ResourceGuaranteedMemory: current.ResourceGuaranteedMemory,
+		ResourceGuaranteedCPU:    current.ResourceGuaranteedCPU,
+		VCPUInMhz:                current.VCPUInMhz,
+		IsThinProvision:          current.IsThinProvision,
+		NetworkPoolReference:     current.NetworkPoolReference,
+		ProviderVdcReference:     current.ProviderVdcReference,
+		UsesFastProvisioning:     current.UsesFastProvisioning,
+	}
+	output, err := xml.MarshalIndent(vdcConfiguration, "  ", "    ")
+	if err != nil {
+		return fmt.Errorf("error encoding VDC %s: %w", current.Name, err)
+	}
+	xmlData := bytes.NewBufferString(xml.Header + string(output))
+
+	vdcHREF, err := url.ParseRequestURI(current.HREF)
+	if err != nil {
+		return fmt.Errorf("error getting VDC HREF %s : %w", current.HREF, err)
+	}
+	req := adminVdc.c.NewRequest(map[string]string{}, "PUT", *vdcHREF, xmlData)
+	req.Header.Add("Content-Type", types.MimeAdminVdc)
+	resp, err := adminVdc.c.doRequest(req)
+	if err != nil {
+		return fmt.Errorf("error updating VDC %s: %w", current.Name, err)
+	}
+	updated := new(types.AdminVdc)
+	if err = decodeBody(resp, updated); err != nil {
+		return fmt.Errorf("error decoding VDC response: %w", err)
+	}
+	adminVdc.AdminVdc = updated
+	if err = adminVdc.waitTasks(); err != nil {
+		return fmt.Errorf("error updating VDC %s: %w", current.Name, err)
+	}
+	return adminVdc.Refresh()
+}
+
+// Enable enables the VDC, new vApps can be created in it again.
+func (adminVdc *AdminVdc) Enable() error {
+	return adminVdc.action("enable")
+}
+
+// Disable disables the VDC, no vApp can be created in it. A VDC must be
+// disabled before it is deleted.
+func (adminVdc *AdminVdc) Disable() error {
+	return adminVdc.action("disable")
+}
+
+func (adminVdc *AdminVdc) action(action string) error {
+	actionHREF, err := url.ParseRequestURI(adminVdc.AdminVdc.HREF)
+	if err != nil {
+		return fmt.Errorf("error getting VDC HREF %s : %w", adminVdc.AdminVdc.HREF, err)
+	}
+	actionHREF.Path += "/action/" + action
+	req := adminVdc.c.NewRequest(map[string]string{}, "POST", *actionHREF, nil)
+	resp, err := adminVdc.c.doRequest(req)
+	if err != nil {
+		return fmt.Errorf("error trying to %s VDC %s: %w", action, adminVdc.AdminVdc.Name, err)
+	}
+	resp.Body.Close()
+	return adminVdc.Refresh()
+}
+
+// Delete deletes the disabled VDC and waits for the deletion to complete.
+// recursive deletes its vApps and networks too, force deletes them even
+// when they are running.
+func (adminVdc *AdminVdc) Delete(force bool, recursive bool) error {
+	vdcHREF, err := url.ParseRequestURI(adminVdc.AdminVdc.HREF)
+	if err != nil {
+		return fmt.Errorf("error getting VDC HREF %s : %w", adminVdc.AdminVdc.HREF, err)
+	}
+	req := adminVdc.c.NewRequest(map[string]string{
+		"force":     strconv.FormatBool(force),
+		"recursive": strconv.FormatBool(recursive),
+	}, "DELETE", *vdcHREF, nil)
+	resp, err := adminVdc.c.doRequest(req)
+	if err != nil {
+		return fmt.Errorf("error deleting VDC %s: %w", adminVdc.AdminVdc.Name, err)
+	}
+	task := NewTask(adminVdc.c)
+	if err = decodeBody(resp, task.Task); err != nil {
+		return fmt.Errorf("error decoding task response: %w", err)
+	}
+	if err = task.WaitTaskCompletion(); err != nil {
+		return err
+	}
+	if adminVdc.AdminOrg != nil {
+		return adminVdc.AdminOrg.Refresh()
+	}
+	return nil
+}
+
+// waitTasks waits for the tasks running on the VDC to complete.
+func (adminVdc *AdminVdc) waitTasks() error {
+	if adminVdc.AdminVdc.Tasks == nil {
+		return nil
+	}
+	task := NewTask(adminVdc.c)
+	for _, t := range adminVdc.AdminVdc.Tasks.Task {
+		task.Task = t
+		if err := task.WaitTaskCompletion(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
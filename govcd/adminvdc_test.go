@@ -0,0 +1,136 @@
+/*
+ * Copyright 2014 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"errors"
+	"testing"
+
+	types "github.com/vmware/go-vcloud-director/types/v56"
+	. "gopkg.in/check.v1"
+)
+
+// Checks that the admin view of the test VDC can be read and leads back
+// to the VDC.
+func (vcd *TestVCD) Test_AdminVdc(check *C) {
+	adminOrg, err := GetAdminOrgByName(vcd.client, vcd.config.VCD.Org)
+	check.Assert(err, IsNil)
+	adminVdc, err := adminOrg.GetAdminVdcByName(vcd.vdc.Vdc.Name)
+	check.Assert(err, IsNil)
+	check.Assert(adminVdc.AdminVdc.ProviderVdcReference, NotNil)
+	vdc, err := adminVdc.GetVdc()
+	check.Assert(err, IsNil)
+	check.Assert(vdc.Vdc.HREF, Equals, vcd.vdc.Vdc.HREF)
+}
+
+func TestAdminOrg_VdcFakeVCD(t *testing.T) {
+	server, _, client := newFakeVCD(t)
+	defer server.Close()
+	server.AddProviderVdc("pvdc")
+	server.AddNetworkPool("vlan-pool")
+
+	adminOrg, err := GetAdminOrgByName(client, "org")
+	if err != nil {
+		t.Fatalf("error getting admin org: %s", err)
+	}
+	providerVdc, err := client.GetProviderVdcByName("pvdc")
+	if err != nil {
+		t.Fatalf("error getting provider VDC: %s", err)
+	}
+	params := &types.CreateVdcParams{
+		Name:            "pool",
+		Description:     "allocation pool",
+		AllocationModel: "Reservation",
+		ComputeCapacity: []*types.ComputeCapacity{{
+			CPU:    &types.CapacityWithUsage{Units: "MHz", Allocated: 2000, Limit: 2000},
+			Memory: &types.CapacityWithUsage{Units: "MB", Allocated: 4096, Limit: 4096},
+		}},
+		VMQuota:   10,
+		IsEnabled: true,
+		VdcStorageProfile: []*types.VdcStorageProfileParams{{
+			Enabled:                   true,
+			Units:                     "MB",
+			Limit:                     102400,
+			Default:                   true,
+			ProviderVdcStorageProfile: providerVdc.ProviderVdc.StorageProfiles.ProviderVdcStorageProfile[0],
+		}},
+		ResourceGuaranteedMemory: 0.5,
+		NetworkPoolReference:     providerVdc.ProviderVdc.NetworkPoolReferences.NetworkPoolReference[0],
+		ProviderVdcReference:     &types.Reference{HREF: providerVdc.ProviderVdc.HREF},
+	}
+	if _, err = adminOrg.CreateVdc(params); err == nil {
+		t.Fatal("expected an error creating a VDC with an invalid allocation model")
+	}
+	params.AllocationModel = types.AllocationPool
+	server.ResetRequests()
+	vdc, err := adminOrg.CreateVdc(params)
+	if err != nil {
+		t.Fatalf("error creating VDC: %s", err)
+	}
+	checkRequests(t, server, apiRequest{
+		Method: "POST", Path: "/admin/org/[^/]+/vdcsparams", ContentType: "application/vnd.vmware.admin.createVdcParams+xml",
+		Body: []string{"<CreateVdcParams ", `name="pool"`, "<AllocationModel>AllocationPool</AllocationModel>",
+			"<ComputeCapacity>", "<VdcStorageProfile>", "<NetworkPoolReference href=", "<ProviderVdcReference href="},
+	})
+	if vdc.Vdc.Name != "pool" || vdc.Vdc.AllocationModel != types.AllocationPool || vdc.Vdc.VMQuota != 10 {
+		t.Fatalf("unexpected VDC: %+v", vdc.Vdc)
+	}
+	if _, err = vdc.FindStorageProfileReference("*"); err != nil {
+		t.Fatalf("error finding the storage profile of the new VDC: %s", err)
+	}
+	if _, err = adminOrg.CreateVdc(params); err == nil {
+		t.Fatal("expected an error creating a VDC with a duplicate name")
+	}
+	if err = providerVdc.Refresh(); err != nil || providerVdc.ProviderVdc.ComputeCapacity.CPU.Allocation != 2000 {
+		t.Fatalf("expected the VDC allocation in the provider VDC: %+v, %v", providerVdc.ProviderVdc.ComputeCapacity.CPU, err)
+	}
+
+	adminVdc, err := adminOrg.GetAdminVdcByName("pool")
+	if err != nil {
+		t.Fatalf("error getting admin VDC: %s", err)
+	}
+	if adminVdc.AdminVdc.ResourceGuaranteedMemory != 0.5 || adminVdc.AdminVdc.NetworkPoolReference == nil ||
+		adminVdc.AdminVdc.ProviderVdcReference.Name != "pvdc" {
+		t.Fatalf("unexpected admin VDC: %+v", adminVdc.AdminVdc)
+	}
+	adminVdc.AdminVdc.ComputeCapacity[0].Memory.Allocated = 8192
+	adminVdc.AdminVdc.ComputeCapacity[0].Memory.Limit = 8192
+	adminVdc.AdminVdc.VMQuota = 20
+	server.ResetRequests()
+	if err = adminVdc.Update(); err != nil {
+		t.Fatalf("error updating VDC: %s", err)
+	}
+	checkRequests(t, server, apiRequest{
+		Method: "PUT", Path: "/admin/vdc/[^/]+", ContentType: "application/vnd.vmware.admin.vdc+xml",
+		Body: []string{"<AdminVdc ", "<VmQuota>20</VmQuota>", "<Allocated>8192</Allocated>"},
+	})
+	if err = vdc.Refresh(); err != nil || vdc.Vdc.ComputeCapacity[0].Memory.Allocated != 8192 || vdc.Vdc.VMQuota != 20 {
+		t.Fatalf("unexpected updated VDC: %+v, %v", vdc.Vdc, err)
+	}
+
+	if err = adminVdc.Delete(true, true); err == nil {
+		t.Fatal("expected an error deleting an enabled VDC")
+	}
+	if err = adminVdc.Disable(); err != nil || adminVdc.AdminVdc.IsEnabled {
+		t.Fatalf("error disabling VDC: %v", err)
+	}
+	if err = adminVdc.Enable(); err != nil || !adminVdc.AdminVdc.IsEnabled {
+		t.Fatalf("error enabling VDC: %v", err)
+	}
+	server.ResetRequests()
+	if err = adminVdc.Disable(); err != nil {
+		t.Fatalf("error disabling VDC: %s", err)
+	}
+	if err = adminVdc.Delete(true, true); err != nil {
+		t.Fatalf("error deleting VDC: %s", err)
+	}
+	checkRequests(t, server,
+		apiRequest{Method: "POST", Path: "/admin/vdc/[^/]+/action/disable"},
+		apiRequest{Method: "DELETE", Path: "/admin/vdc/[^/]+", Query: map[string]string{"force": "true", "recursive": "true"}},
+	)
+	if _, err = adminOrg.GetAdminVdcByName("pool"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound after deletion, got %v", err)
+	}
+}
@@ -6,28 +6,75 @@
 package govcd
 
 import (
+	"context"
 	"encoding/xml"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"sync"
+	"time"
 
 	types "github.com/vmware/go-vcloud-director/types/v56"
 )
 
 // Client provides a client to vCloud Director, values can be populated automatically using the Authenticate method.
 type Client struct {
-	APIVersion    string      // The API version required
-	VCDToken      string      // Access Token (authorization header)
-	VCDAuthHeader string      // Authorization header
-	VCDHREF       url.URL     // VCD API ENDPOINT
-	Http          http.Client // HttpClient is the client to use. Default will be used if not provided.
+	APIVersion    string       // The API version required
+	VCDToken      string       // Access Token (authorization header)
+	VCDAuthHeader string       // Authorization header
+	VCDHREF       url.URL      // VCD API ENDPOINT
+	Http          http.Client  // HttpClient is the client to use. Default will be used if not provided.
+	RetryPolicy   *RetryPolicy // Retry policy for transient failures. No retries when nil.
+	Logger        Logger       // Logger for the requests. Warnings go to the standard logger when nil.
+	LogBodies     bool         // Log the redacted headers and bodies of requests and responses at trace level.
+	Middlewares   []Middleware // Middlewares wrapping every request, the first one being the outermost.
+
+	// metrics, when set, collects the metrics registered with
+	// RegisterMetrics.
+	metrics *clientMetrics
+
+	// apiLimiter and uploadLimiter, when set, limit the API calls and the
+	// file uploads of the client.
+	apiLimiter    *limiter
+	uploadLimiter *limiter
+
+	// reauthenticate, when set, logs in again after a request is rejected
+	// because the session token it carried has expired.
+	reauthenticate func(ctx context.Context, rejectedToken string) error
+
+	// tokenMutex guards VCDAuthHeader and VCDToken, which a new login
+	// replaces while other requests are in flight.
+	tokenMutex sync.RWMutex
+}
+
+// authorization returns the authorization header and the session token
+// the requests carry.
+func (c *Client) authorization() (header, token string) {
+	c.tokenMutex.RLock()
+	defer c.tokenMutex.RUnlock()
+	return c.VCDAuthHeader, c.VCDToken
+}
+
+// setAuthorization replaces the authorization header and the session token
+// the following requests carry.
+func (c *Client) setAuthorization(header, token string) {
+	c.tokenMutex.Lock()
+	defer c.tokenMutex.Unlock()
+	c.VCDAuthHeader, c.VCDToken = header, token
 }
 
 // NewRequest creates a new HTTP request and applies necessary auth headers if
 // set.
 func (c *Client) NewRequest(params map[string]string, method string, u url.URL, body io.Reader) *http.Request {
+	return c.NewRequestWithContext(context.Background(), params, method, u, body)
+}
+
+// NewRequestWithContext creates a new HTTP request bound to ctx and applies
+// necessary auth headers if set. Cancelling ctx, or reaching its deadline,
+// aborts the request while it is in flight.
+func (c *Client) NewRequestWithContext(ctx context.Context, params map[string]string, method string, u url.URL, body io.Reader) *http.Request {
 
 	p := url.Values{}
 
@@ -42,11 +89,11 @@ func (c *Client) NewRequest(params map[string]string, method string, u url.URL,
 	// Build the request, no point in checking for errors here as we're just
 	// passing a string version of an url.URL struct and http.NewRequest returns
 	// error only if can't process an url.ParseRequestURI().
-	req, _ := http.NewRequest(method, u.String(), body)
+	req, _ := http.NewRequestWithContext(ctx, method, u.String(), body)
 
-	if c.VCDAuthHeader != "" && c.VCDToken != "" {
+	if header, token := c.authorization(); header != "" && token != "" {
 		// Add the authorization header
-		req.Header.Add(c.VCDAuthHeader, c.VCDToken)
+		req.Header.Add(header, token)
 		// Add the Accept header for VCD
 		req.Header.Add("Accept", "application/*+xml;version="+c.APIVersion)
 	}
@@ -55,17 +102,19 @@ func (c *Client) NewRequest(params map[string]string, method string, u url.URL,
 
 }
 
-// parseErr takes an error XML resp and returns a single string for use in error messages.
+// parseErr takes an error XML resp and returns a *VCDError describing it.
 func parseErr(resp *http.Response) error {
 
 	errBody := new(types.Error)
 
-	// if there was an error decoding the body, just return that
+	// if there was an error decoding the body, keep at least the status code
 	if err := decodeBody(resp, errBody); err != nil {
-		return fmt.Errorf("error parsing error body for non-200 request: %s", err)
+		vcdErr := newVCDError(resp.StatusCode, nil)
+		vcdErr.Message = fmt.Sprintf("error parsing error body for non-200 request: %s", err)
+		return vcdErr
 	}
 
-	return fmt.Errorf("API Error: %d: %s", errBody.MajorErrorCode, errBody.Message)
+	return newVCDError(resp.StatusCode, errBody)
 }
 
 // decodeBody is used to XML decode a response body
@@ -102,6 +151,21 @@ func checkResp(resp *http.Response, err error) (*http.Response, error) {
 		return nil, parseErr(resp)
 	// Unhandled response.
 	default:
-		return nil, fmt.Errorf("unhandled API response, please report this issue, status code: %s", resp.Status)
+		vcdErr := newVCDError(resp.StatusCode, nil)
+		vcdErr.Message = fmt.Sprintf("unhandled API response, please report this issue, status code: %s", resp.Status)
+		return nil, vcdErr
+	}
+}
+
+// sleepWithContext pauses the current goroutine for d. It returns early with
+// the context error if ctx is cancelled or its deadline expires first.
+func sleepWithContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
 	}
 }
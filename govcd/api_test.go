@@ -1,3 +1,43 @@
 package govcd
 
-import ()
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// Tests that requests built with NewRequestWithContext carry the context
+// and that NewRequest falls back to a background context.
+func TestClient_NewRequestWithContext(t *testing.T) {
+	u, _ := url.ParseRequestURI("https://vcd.example.com/api/org")
+	client := &Client{APIVersion: "5.5"}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req := client.NewRequestWithContext(ctx, map[string]string{}, "GET", *u, nil)
+	if req.Context() != ctx {
+		t.Fatalf("request is not bound to the given context")
+	}
+	req = client.NewRequest(map[string]string{}, "GET", *u, nil)
+	if req.Context() == nil || req.Context().Done() != nil {
+		t.Fatalf("NewRequest should use a background context")
+	}
+}
+
+// Tests that sleepWithContext returns early when the context is cancelled.
+func TestSleepWithContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	start := time.Now()
+	err := sleepWithContext(ctx, time.Minute)
+	if err != context.Canceled {
+		t.Fatalf("expected context.Canceled, got: %v", err)
+	}
+	if time.Since(start) > time.Second {
+		t.Fatalf("sleepWithContext did not return early")
+	}
+	if err = sleepWithContext(context.Background(), time.Millisecond); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -1,7 +1,9 @@
 package govcd
 
 import (
+	"context"
 	"crypto/tls"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -15,21 +17,120 @@ type VCDClient struct {
 	sessionHREF url.URL // HREF for the session API
 	QueryHREF   url.URL // HREF for the query API
 	Mutex       sync.Mutex
+
+	// Credentials stored by Authenticate, used to log in again when the
+	// session expires and automatic reauthentication is enabled.
+	user, password, org string
+	reauthenticate      bool
+	reauthMutex         sync.Mutex
+
+	pinnedAPIVersion  string   // API version requested with WithAPIVersion
+	serverAPIVersions []string // API versions returned by /versions
+}
+
+// VCDClientOption configures a VCDClient created by NewVCDClient.
+type VCDClientOption func(*VCDClient)
+
+// WithToken makes the client reuse an existing session token, instead of
+// logging in with Authenticate.
+func WithToken(token string) VCDClientOption {
+	return func(c *VCDClient) {
+		c.SetToken(token)
+	}
+}
+
+// WithRetryPolicy replaces the default retry policy of the client.
+func WithRetryPolicy(policy RetryPolicy) VCDClientOption {
+	return func(c *VCDClient) {
+		c.SetRetryPolicy(policy)
+	}
+}
+
+// WithAutoReauthentication makes the client log in again with the
+// credentials given to Authenticate when a request fails because the
+// session has expired. The failed request is then sent again once.
+func WithAutoReauthentication() VCDClientOption {
+	return func(c *VCDClient) {
+		c.reauthenticate = true
+	}
+}
+
+// WithAPIVersion pins the API version used by the client. Authenticate
+// fails when vCD does not support it.
+func WithAPIVersion(version string) VCDClientOption {
+	return func(c *VCDClient) {
+		c.pinnedAPIVersion = version
+		c.Client.APIVersion = version
+	}
+}
+
+// WithLogger makes the client send its log messages to logger.
+func WithLogger(logger Logger) VCDClientOption {
+	return func(c *VCDClient) {
+		c.SetLogger(logger)
+	}
+}
+
+// WithBodyLogging makes the client log the headers and bodies of requests
+// and responses, at trace level. Tokens, credentials and passwords are
+// redacted. A client without a logger then sends every message, up to
+// trace level, to the standard logger; a logger set with WithLogger must
+// accept trace messages to receive the bodies.
+func WithBodyLogging() VCDClientOption {
+	return func(c *VCDClient) {
+		c.Client.LogBodies = true
+		if c.Client.Logger == nil {
+			c.Client.Logger = NewStdLogger(nil, LogTrace)
+		}
+	}
+}
+
+// WithMiddleware adds middlewares to the chain sending the requests of the
+// client. The first middleware given is the outermost one.
+func WithMiddleware(middlewares ...Middleware) VCDClientOption {
+	return func(c *VCDClient) {
+		c.Use(middlewares...)
+	}
+}
+
+// WithMetrics makes the client collect its metrics in registry, see
+// RegisterMetrics.
+func WithMetrics(registry MetricsRegistry) VCDClientOption {
+	return func(c *VCDClient) {
+		c.RegisterMetrics(registry)
+	}
+}
+
+// WithRateLimit limits the API calls of the client, see SetRateLimit.
+func WithRateLimit(limit RateLimit) VCDClientOption {
+	return func(c *VCDClient) {
+		c.SetRateLimit(limit)
+	}
+}
+
+// WithUploadRateLimit limits the file uploads of the client, see
+// SetUploadRateLimit.
+func WithUploadRateLimit(limit RateLimit) VCDClientOption {
+	return func(c *VCDClient) {
+		c.SetUploadRateLimit(limit)
+	}
 }
 
 type supportedVersions struct {
-	VersionInfo struct {
+	VersionInfo []struct {
 		Version  string `xml:"Version"`
 		LoginUrl string `xml:"LoginUrl"`
 	} `xml:"VersionInfo"`
 }
 
-func (c *VCDClient) vcdloginurl() error {
+// vcdloginurl reads the API versions supported by vCD, negotiates the one
+// used by the client and stores the matching LoginUrl.
+func (c *VCDClient) vcdloginurl(ctx context.Context) error {
 	s := c.Client.VCDHREF
 	s.Path += "/versions"
 	// No point in checking for errors here
-	req := c.Client.NewRequest(map[string]string{}, "GET", s, nil)
-	resp, err := checkResp(c.Client.Http.Do(req))
+	req := c.Client.NewRequestWithContext(ctx, map[string]string{}, "GET", s, nil)
+	resp, err := c.Client.doRequest(req)
 	if err != nil {
 		return err
 	}
@@ -38,17 +139,59 @@ func (c *VCDClient) vcdloginurl() error {
 	supportedVersions := new(supportedVersions)
 	err = decodeBody(resp, supportedVersions)
 	if err != nil {
-		return fmt.Errorf("error decoding versions response: %s", err)
+		return fmt.Errorf("error decoding versions response: %w", err)
 	}
-	u, err := url.Parse(supportedVersions.VersionInfo.LoginUrl)
+
+	offered := make([]string, 0, len(supportedVersions.VersionInfo))
+	for _, versionInfo := range supportedVersions.VersionInfo {
+		offered = append(offered, versionInfo.Version)
+	}
+	version, err := negotiateAPIVersion(offered, c.pinnedAPIVersion)
 	if err != nil {
-		return fmt.Errorf("couldn't find a LoginUrl in versions")
+		return err
+	}
+
+	for _, versionInfo := range supportedVersions.VersionInfo {
+		if versionInfo.Version != version {
+			continue
+		}
+		u, err := url.Parse(versionInfo.LoginUrl)
+		if err != nil || versionInfo.LoginUrl == "" {
+			return fmt.Errorf("couldn't find a LoginUrl in versions")
+		}
+		c.sessionHREF = *u
 	}
-	c.sessionHREF = *u
+	c.serverAPIVersions = offered
+	c.Client.APIVersion = version
 	return nil
 }
 
-func (c *VCDClient) vcdauthorize(user, pass, org string) error {
+// NegotiateAPIVersion queries /versions and selects the API version used by
+// the client, as Authenticate does. Clients created WithToken call it to
+// avoid staying on the default version.
+func (c *VCDClient) NegotiateAPIVersion() error {
+	return c.NegotiateAPIVersionWithContext(context.Background())
+}
+
+// NegotiateAPIVersionWithContext behaves like NegotiateAPIVersion, aborting
+// if ctx is cancelled first.
+func (c *VCDClient) NegotiateAPIVersionWithContext(ctx context.Context) error {
+	return c.vcdloginurl(ctx)
+}
+
+// APIVersion returns the API version used by the client, which is the
+// negotiated version once Authenticate or NegotiateAPIVersion succeeded.
+func (c *VCDClient) APIVersion() string {
+	return c.Client.APIVersion
+}
+
+// ServerAPIVersions returns the API versions vCD reported as supported, or
+// nil before the versions have been negotiated.
+func (c *VCDClient) ServerAPIVersions() []string {
+	return c.serverAPIVersions
+}
+
+func (c *VCDClient) vcdauthorize(ctx context.Context, user, pass, org string) error {
 	if user == "" {
 		user = os.Getenv("VCLOUD_USERNAME")
 	}
@@ -59,31 +202,86 @@ func (c *VCDClient) vcdauthorize(user, pass, org string) error {
 		org = os.Getenv("VCLOUD_ORG")
 	}
 	// No point in checking for errors here
-	req := c.Client.NewRequest(map[string]string{}, "POST", c.sessionHREF, nil)
+	req := c.Client.NewRequestWithContext(ctx, map[string]string{}, "POST", c.sessionHREF, nil)
+	// Drop the token of an expired session, if any
+	if header, _ := c.Client.authorization(); header != "" {
+		req.Header.Del(header)
+	}
 	// Set Basic Authentication Header
 	req.SetBasicAuth(user+"@"+org, pass)
 	// Add the Accept header for vCA
-	req.Header.Add("Accept", "application/*+xml;version=5.5")
-	resp, err := checkResp(c.Client.Http.Do(req))
+	req.Header.Add("Accept", "application/*+xml;version="+c.Client.APIVersion)
+	resp, err := c.Client.doRequest(req)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 	// Store the authentication header
-	c.Client.VCDToken = resp.Header.Get("x-vcloud-authorization")
-	c.Client.VCDAuthHeader = "x-vcloud-authorization"
+	c.SetToken(resp.Header.Get("x-vcloud-authorization"))
+	return nil
+}
+
+// SetToken makes the client use an existing session token for the
+// following requests.
+func (c *VCDClient) SetToken(token string) {
+	c.Client.setAuthorization("x-vcloud-authorization", token)
 	// Get query href
 	c.QueryHREF = c.Client.VCDHREF
 	c.QueryHREF.Path += "/query"
-	return nil
+	if c.sessionHREF.Host == "" {
+		c.sessionHREF = c.Client.VCDHREF
+		c.sessionHREF.Path += "/sessions"
+	}
+}
+
+// SessionIsValid checks the current session against the session endpoint.
+// It returns false without an error when vCD rejects the session token.
+func (c *VCDClient) SessionIsValid() (bool, error) {
+	return c.SessionIsValidWithContext(context.Background())
+}
+
+// SessionIsValidWithContext behaves like SessionIsValid, aborting if ctx is
+// cancelled first.
+func (c *VCDClient) SessionIsValidWithContext(ctx context.Context) (bool, error) {
+	if _, token := c.Client.authorization(); token == "" {
+		return false, nil
+	}
+	s := c.Client.VCDHREF
+	s.Path += "/session"
+	req := c.Client.NewRequestWithContext(ctx, map[string]string{}, "GET", s, nil)
+	// Bypass the retries, an expired session must not trigger a new login
+	resp, err := c.Client.send(req)
+	if err != nil {
+		if errors.Is(err, ErrUnauthorized) {
+			return false, nil
+		}
+		return false, fmt.Errorf("error checking session: %w", err)
+	}
+	resp.Body.Close()
+	return true, nil
+}
+
+// reauthorize logs in again with the stored credentials, unless the token
+// that was rejected has already been replaced by another request.
+func (c *VCDClient) reauthorize(ctx context.Context, rejectedToken string) error {
+	c.reauthMutex.Lock()
+	defer c.reauthMutex.Unlock()
+	if _, token := c.Client.authorization(); token != rejectedToken {
+		return nil
+	}
+	return c.vcdauthorize(ctx, c.user, c.password, c.org)
 }
 
-func NewVCDClient(vcdEndpoint url.URL, insecure bool) *VCDClient {
+// NewVCDClient creates a client for the vCD API endpoint. The options are
+// applied in order once the default client is built.
+func NewVCDClient(vcdEndpoint url.URL, insecure bool, options ...VCDClientOption) *VCDClient {
 
-	return &VCDClient{
+	retryPolicy := DefaultRetryPolicy()
+	vcdClient := &VCDClient{
 		Client: Client{
-			APIVersion: "5.5",
-			VCDHREF:    vcdEndpoint,
+			APIVersion:  "5.5",
+			VCDHREF:     vcdEndpoint,
+			RetryPolicy: &retryPolicy,
 			Http: http.Client{
 				Transport: &http.Transport{
 					TLSClientConfig: &tls.Config{
@@ -95,35 +293,77 @@ func NewVCDClient(vcdEndpoint url.URL, insecure bool) *VCDClient {
 			},
 		},
 	}
+	for _, option := range options {
+		option(vcdClient)
+	}
+	if vcdClient.reauthenticate {
+		vcdClient.Client.reauthenticate = vcdClient.reauthorize
+	}
+	return vcdClient
+}
+
+// SetRetryPolicy replaces the retry policy used for every request made
+// through this client. Passing a policy with MaxAttempts set to 1 disables
+// retries.
+func (c *VCDClient) SetRetryPolicy(policy RetryPolicy) {
+	c.Client.RetryPolicy = &policy
+}
+
+// SetLogger replaces the logger used by the client. A nil logger sends the
+// errors and warnings to the standard logger and drops the other messages.
+func (c *VCDClient) SetLogger(logger Logger) {
+	c.Client.Logger = logger
+}
+
+// Use appends middlewares to the chain sending the requests of the client.
+// Middlewares added first wrap the ones added later.
+func (c *VCDClient) Use(middlewares ...Middleware) {
+	c.Client.Middlewares = append(c.Client.Middlewares, middlewares...)
 }
 
 // Authenticate is an helper function that performs a login in vCloud Director.
 func (c *VCDClient) Authenticate(username, password, org string) error {
+	return c.AuthenticateWithContext(context.Background(), username, password, org)
+}
+
+// AuthenticateWithContext performs a login in vCloud Director, aborting
+// if ctx is cancelled before the login completes.
+func (c *VCDClient) AuthenticateWithContext(ctx context.Context, username, password, org string) error {
 	// LoginUrl
-	err := c.vcdloginurl()
+	err := c.vcdloginurl(ctx)
 	if err != nil {
-		return fmt.Errorf("error finding LoginUrl: %s", err)
+		return fmt.Errorf("error finding LoginUrl: %w", err)
 	}
 	// Authorize
-	err = c.vcdauthorize(username, password, org)
+	err = c.vcdauthorize(ctx, username, password, org)
 	if err != nil {
-		return fmt.Errorf("error authorizing: %s", err)
+		return fmt.Errorf("error authorizing: %w", err)
 	}
+	c.user, c.password, c.org = username, password, org
 	return nil
 }
 
 // Disconnect performs a disconnection from the vCloud Director API endpoint.
 func (c *VCDClient) Disconnect() error {
-	if c.Client.VCDToken == "" && c.Client.VCDAuthHeader == "" {
+	return c.DisconnectWithContext(context.Background())
+}
+
+// DisconnectWithContext performs a disconnection from the vCloud Director
+// API endpoint, aborting if ctx is cancelled first.
+func (c *VCDClient) DisconnectWithContext(ctx context.Context) error {
+	header, token := c.Client.authorization()
+	if token == "" && header == "" {
 		return fmt.Errorf("cannot disconnect, client is not authenticated")
 	}
-	req := c.Client.NewRequest(map[string]string{}, "DELETE", c.sessionHREF, nil)
+	req := c.Client.NewRequestWithContext(ctx, map[string]string{}, "DELETE", c.sessionHREF, nil)
 	// Add the Accept header for vCA
-	req.Header.Add("Accept", "application/xml;version=5.5")
+	req.Header.Add("Accept", "application/xml;version="+c.Client.APIVersion)
 	// Set Authorization Header
-	req.Header.Add(c.Client.VCDAuthHeader, c.Client.VCDToken)
-	if _, err := checkResp(c.Client.Http.Do(req)); err != nil {
-		return fmt.Errorf("error processing session delete for vCloud Director: %s", err)
+	req.Header.Add(header, token)
+	resp, err := c.Client.doRequest(req)
+	if err != nil {
+		return fmt.Errorf("error processing session delete for vCloud Director: %w", err)
 	}
+	resp.Body.Close()
 	return nil
 }
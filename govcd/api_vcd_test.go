@@ -1,13 +1,25 @@
 package govcd
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
+	"github.com/vmware/go-vcloud-director/fakevcd"
+	"github.com/vmware/go-vcloud-director/recorder"
 	. "gopkg.in/check.v1"
 	"gopkg.in/yaml.v2"
 	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 )
 
 // Struct to get info from a config yaml file that the user
@@ -50,17 +62,22 @@ type TestVCD struct {
 	vapp          VApp
 	config        TestConfig
 	skipVappTests bool
+	stopRecording func() error
 }
 
 var _ = Suite(&TestVCD{})
 
 // Users use the environmental variable VCLOUD_CONFIG as
 // a config file for testing. Otherwise the default is config.yaml
-// in the home directory. Throws an error if it cannot find your
+// in the home directory, or the config saved with the cassettes
+// in replay mode. Throws an error if it cannot find your
 // yaml file or if it cannot read it.
 func GetConfigStruct() (TestConfig, error) {
 	config := os.Getenv("VCLOUD_CONFIG")
 	config_struct := TestConfig{}
+	if mode, ok := recorderMode(); config == "" && ok && mode == recorder.ModeReplay {
+		config = filepath.Join(cassetteDir(), "config.yaml")
+	}
 	if config == "" {
 		config = os.Getenv("HOME") + "/config.yaml"
 	}
@@ -87,6 +104,62 @@ func GetTestVCDFromYaml(g TestConfig) (*VCDClient, error) {
 	return vcdClient, nil
 }
 
+// recorderMode returns the mode selected with the VCLOUD_RECORD
+// environment variable, "record" or "replay". The second result is false
+// when the tests talk to vCD directly.
+func recorderMode() (recorder.Mode, bool) {
+	switch os.Getenv("VCLOUD_RECORD") {
+	case "record":
+		return recorder.ModeRecord, true
+	case "replay":
+		return recorder.ModeReplay, true
+	}
+	return 0, false
+}
+
+// cassetteDir returns the directory holding the cassettes, set with
+// VCLOUD_CASSETTES and defaulting to testdata/cassettes.
+func cassetteDir() string {
+	if dir := os.Getenv("VCLOUD_CASSETTES"); dir != "" {
+		return dir
+	}
+	return filepath.Join("testdata", "cassettes")
+}
+
+// startRecording records or replays the traffic of client in the cassette
+// name, as selected by VCLOUD_RECORD. The returned function writes the
+// cassette and restores the transport of the client.
+func startRecording(client *VCDClient, name string) (func() error, error) {
+	mode, ok := recorderMode()
+	if !ok {
+		return func() error { return nil }, nil
+	}
+	transport := client.Client.Http.Transport
+	rec, err := recorder.New(filepath.Join(cassetteDir(), name+".json"), mode, transport)
+	if err != nil {
+		return nil, err
+	}
+	client.Client.Http.Transport = rec
+	return func() error {
+		client.Client.Http.Transport = transport
+		return rec.Stop()
+	}, nil
+}
+
+// saveReplayConfig writes config, without the password, next to the
+// cassettes so that the suite can be replayed without a config file.
+func saveReplayConfig(config TestConfig) error {
+	config.Provider.Password = recorder.Redacted
+	data, err := yaml.Marshal(config)
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(cassetteDir(), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(cassetteDir(), "config.yaml"), data, 0644)
+}
+
 // Neccessary to enable the suite tests with TestVCD
 func Test(t *testing.T) { TestingT(t) }
 
@@ -107,6 +180,20 @@ func (vcd *TestVCD) SetUpSuite(check *C) {
 		panic(err)
 	}
 	vcd.client = vcdClient
+	if mode, ok := recorderMode(); ok && mode == recorder.ModeRecord {
+		if err = saveReplayConfig(config); err != nil {
+			panic(err)
+		}
+	}
+	stopRecording, err := startRecording(vcd.client, "SetUpSuite")
+	if err != nil {
+		panic(err)
+	}
+	defer func() {
+		if err := stopRecording(); err != nil {
+			panic(err)
+		}
+	}()
 	// org and vdc are the test org and vdc that is used in all other test cases
 	err = vcd.client.Authenticate(config.Provider.User, config.Provider.Password, config.Provider.Org)
 	if err != nil {
@@ -137,11 +224,38 @@ func (vcd *TestVCD) SetUpSuite(check *C) {
 
 }
 
+// Records or replays the traffic of each test in its own cassette.
+func (vcd *TestVCD) SetUpTest(check *C) {
+	var err error
+	vcd.stopRecording, err = startRecording(vcd.client, check.TestName())
+	if err != nil {
+		check.Skip(fmt.Sprintf("no cassette for %s: %s", check.TestName(), err))
+	}
+}
+
+func (vcd *TestVCD) TearDownTest(check *C) {
+	if vcd.stopRecording == nil {
+		return
+	}
+	err := vcd.stopRecording()
+	vcd.stopRecording = nil
+	check.Assert(err, IsNil)
+}
+
 func (vcd *TestVCD) TearDownSuite(check *C) {
 	if vcd.skipVappTests {
 		check.Skip("Vapp tests skipped, no vapp to be deleted")
 	}
-	err := vcd.vapp.Refresh()
+	stopRecording, err := startRecording(vcd.client, "TearDownSuite")
+	if err != nil {
+		panic(err)
+	}
+	defer func() {
+		if err := stopRecording(); err != nil {
+			panic(err)
+		}
+	}()
+	err = vcd.vapp.Refresh()
 	if err != nil {
 		panic(err)
 	}
@@ -168,8 +282,17 @@ func TestClient_getloginurl(t *testing.T) {
 	if err != nil {
 		t.Fatalf("err: %v", err)
 	}
+	stopRecording, err := startRecording(client, t.Name())
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	defer func() {
+		if err := stopRecording(); err != nil {
+			t.Errorf("error writing cassette: %v", err)
+		}
+	}()
 
-	err = client.vcdloginurl()
+	err = client.vcdloginurl(context.Background())
 	if err != nil {
 		t.Fatalf("err: %v", err)
 	}
@@ -189,8 +312,314 @@ func TestVCDClient_Authenticate(t *testing.T) {
 	if err != nil {
 		t.Fatalf("err: %v", err)
 	}
+	stopRecording, err := startRecording(client, t.Name())
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	defer func() {
+		if err := stopRecording(); err != nil {
+			t.Errorf("error writing cassette: %v", err)
+		}
+	}()
 	err = client.Authenticate(config.Provider.User, config.Provider.Password, config.Provider.Org)
 	if err != nil {
 		t.Fatalf("Error authenticating: %v", err)
 	}
 }
+
+// sessionServer is a minimal vCD endpoint issuing a new token on every
+// login. Expiring the session makes it reject the current token.
+type sessionServer struct {
+	*httptest.Server
+	mu     sync.Mutex
+	token  string
+	logins int
+}
+
+func newSessionServer() *sessionServer {
+	s := &sessionServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+func (s *sessionServer) expire() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.token = ""
+}
+
+func (s *sessionServer) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch {
+	case r.URL.Path == "/api/versions":
+		fmt.Fprintf(w, `<SupportedVersions><VersionInfo><Version>5.5</Version><LoginUrl>%s/api/sessions</LoginUrl></VersionInfo></SupportedVersions>`, s.URL)
+	case r.URL.Path == "/api/sessions" && r.Method == "POST":
+		if user, pass, _ := r.BasicAuth(); user != "user@org" || pass != "pass" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		s.logins++
+		s.token = fmt.Sprintf("token-%d", s.logins)
+		w.Header().Set("x-vcloud-authorization", s.token)
+	case r.Header.Get("x-vcloud-authorization") == "" || r.Header.Get("x-vcloud-authorization") != s.token:
+		w.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprint(w, `<Error majorErrorCode="401" message="session expired"></Error>`)
+	}
+}
+
+func TestVCDClient_WithToken(t *testing.T) {
+	server := newSessionServer()
+	defer server.Close()
+	server.token = "existing"
+
+	u, _ := url.ParseRequestURI(server.URL + "/api")
+	client := NewVCDClient(*u, true, WithToken("existing"))
+	if client.QueryHREF.Path != "/api/query" {
+		t.Fatalf("unexpected query HREF: %s", client.QueryHREF.Path)
+	}
+
+	valid, err := client.SessionIsValid()
+	if err != nil || !valid {
+		t.Fatalf("expected a valid session, got %t, %v", valid, err)
+	}
+
+	server.expire()
+	valid, err = client.SessionIsValid()
+	if err != nil || valid {
+		t.Fatalf("expected an expired session, got %t, %v", valid, err)
+	}
+}
+
+func TestVCDClient_AutoReauthentication(t *testing.T) {
+	server := newSessionServer()
+	defer server.Close()
+
+	u, _ := url.ParseRequestURI(server.URL + "/api")
+	client := NewVCDClient(*u, true, WithAutoReauthentication())
+	if err := client.Authenticate("user", "pass", "org"); err != nil {
+		t.Fatalf("error authenticating: %s", err)
+	}
+
+	server.expire()
+	req := client.Client.NewRequest(map[string]string{}, "GET", client.QueryHREF, nil)
+	if _, err := client.Client.doRequest(req); err != nil {
+		t.Fatalf("expected the request to succeed after logging in again: %s", err)
+	}
+	if _, token := client.Client.authorization(); server.logins != 2 || token != "token-2" {
+		t.Fatalf("expected a second login, got %d logins and token %q", server.logins, token)
+	}
+
+	// Without the option the expired session is reported to the caller
+	client = NewVCDClient(*u, true)
+	if err := client.Authenticate("user", "pass", "org"); err != nil {
+		t.Fatalf("error authenticating: %s", err)
+	}
+	server.expire()
+	req = client.Client.NewRequest(map[string]string{}, "GET", client.QueryHREF, nil)
+	if _, err := client.Client.doRequest(req); !errors.Is(err, ErrUnauthorized) {
+		t.Fatalf("expected ErrUnauthorized, got %v", err)
+	}
+}
+
+// Checks that requests sent while the session expires log in again once,
+// and that the token is not raced on (go test -race).
+func TestVCDClient_ConcurrentReauthentication(t *testing.T) {
+	server := newSessionServer()
+	defer server.Close()
+
+	u, _ := url.ParseRequestURI(server.URL + "/api")
+	client := NewVCDClient(*u, true, WithAutoReauthentication())
+	if err := client.Authenticate("user", "pass", "org"); err != nil {
+		t.Fatalf("error authenticating: %s", err)
+	}
+
+	server.expire()
+	var wg sync.WaitGroup
+	errs := make(chan error, 8)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req := client.Client.NewRequest(map[string]string{}, "GET", client.QueryHREF, nil)
+			_, err := client.Client.doRequest(req)
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("expected the requests to succeed after logging in again: %s", err)
+		}
+	}
+	if server.logins != 2 {
+		t.Fatalf("expected a single new login, got %d logins", server.logins)
+	}
+}
+
+// newFakeVCD starts a fake vCD holding an org and a VDC and returns it
+// along with a client authenticated to it. Retries use a short backoff so
+// that tests exercising them stay fast.
+func newFakeVCD(t *testing.T) (*fakevcd.Server, *fakevcd.Vdc, *VCDClient) {
+	server := fakevcd.NewServer()
+	vdc := server.AddOrg("org").AddVdc("vdc")
+
+	client := NewVCDClient(server.APIURL(), true, WithRetryPolicy(RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+		RetryBusy:      true,
+	}))
+	if err := client.Authenticate(fakevcd.DefaultUser, fakevcd.DefaultPassword, "org"); err != nil {
+		server.Close()
+		t.Fatalf("error authenticating to the fake vCD: %s", err)
+	}
+	return server, vdc, client
+}
+
+// apiRequest is a vCloud API call a test expects the client to make, as
+// documented in the vCloud API reference. Path is a regular expression
+// matched against the whole path without the /api prefix. The query must
+// hold the parameters of Query and the body must contain every fragment of
+// Body and none of NotBody.
+type apiRequest struct {
+	Method      string
+	Path        string
+	Query       map[string]string
+	ContentType string
+	Body        []string
+	NotBody     []string
+}
+
+// checkRequests checks that the requests the fake vCD received since its
+// last ResetRequests include the expected ones, in order. Other requests,
+// such as refreshes, may come in between.
+func checkRequests(t *testing.T, server *fakevcd.Server, expected ...apiRequest) {
+	t.Helper()
+	requests := server.Requests()
+	next := 0
+	for _, want := range expected {
+		path := regexp.MustCompile("^" + want.Path + "$")
+		found := false
+		for ; next < len(requests) && !found; next++ {
+			got := requests[next]
+			if got.Method != want.Method || !path.MatchString(got.Path) || !matchQuery(got.Query, want.Query) {
+				continue
+			}
+			found = true
+			if want.ContentType != "" && !strings.HasPrefix(got.ContentType, want.ContentType) {
+				t.Errorf("%s %s: expected content type %s, got %s", got.Method, got.Path, want.ContentType, got.ContentType)
+			}
+			for _, fragment := range want.Body {
+				if !strings.Contains(got.Body, fragment) {
+					t.Errorf("%s %s: expected %q in body:\n%s", got.Method, got.Path, fragment, got.Body)
+				}
+			}
+			for _, fragment := range want.NotBody {
+				if strings.Contains(got.Body, fragment) {
+					t.Errorf("%s %s: unexpected %q in body:\n%s", got.Method, got.Path, fragment, got.Body)
+				}
+			}
+		}
+		if !found {
+			t.Fatalf("expected a request %s %s, got:\n%s", want.Method, want.Path, formatRequests(requests))
+		}
+	}
+}
+
+func matchQuery(got url.Values, want map[string]string) bool {
+	for key, value := range want {
+		if got.Get(key) != value {
+			return false
+		}
+	}
+	return true
+}
+
+func formatRequests(requests []fakevcd.Request) string {
+	var lines []string
+	for _, request := range requests {
+		lines = append(lines, request.Method+" "+request.Path)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// fakeOrgVdc returns the org and VDC created by newFakeVCD.
+func fakeOrgVdc(t *testing.T, client *VCDClient) (Org, Vdc) {
+	org, err := GetOrgByName(client, "org")
+	if err != nil || org.Org == nil || org.Org.HREF == "" {
+		t.Fatalf("error getting org: %v", err)
+	}
+	vdc, err := org.GetVdcByName("vdc")
+	if err != nil {
+		t.Fatalf("error getting vdc: %s", err)
+	}
+	return org, vdc
+}
+
+func TestVCDClient_FakeVCD(t *testing.T) {
+	server, _, client := newFakeVCD(t)
+	defer server.Close()
+
+	if client.APIVersion() != "5.6" {
+		t.Fatalf("expected API version 5.6, got %s", client.APIVersion())
+	}
+	_, vdc := fakeOrgVdc(t, client)
+	if vdc.Vdc.Name != "vdc" {
+		t.Fatalf("expected vdc, got %s", vdc.Vdc.Name)
+	}
+
+	server.ExpireSessions()
+	if valid, err := client.SessionIsValid(); err != nil || valid {
+		t.Fatalf("expected an expired session, got %t, %v", valid, err)
+	}
+	if err := client.Disconnect(); err == nil {
+		t.Fatalf("expected an error disconnecting an expired session")
+	}
+}
+
+func TestVCDClient_RecordReplayFakeVCD(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cassettes")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	cassette := filepath.Join(dir, "session.json")
+
+	server, fakeVdc, _ := newFakeVCD(t)
+	fakeVdc.AddVApp("vapp")
+	apiURL := server.APIURL()
+
+	run := func(mode recorder.Mode) error {
+		client := NewVCDClient(apiURL, true)
+		rec, err := recorder.New(cassette, mode, client.Client.Http.Transport)
+		if err != nil {
+			return err
+		}
+		client.Client.Http.Transport = rec
+		if err = client.Authenticate(fakevcd.DefaultUser, fakevcd.DefaultPassword, "org"); err != nil {
+			return err
+		}
+		_, vdc := fakeOrgVdc(t, client)
+		if _, err = vdc.FindVAppByName("vapp"); err != nil {
+			return err
+		}
+		return rec.Stop()
+	}
+
+	if err = run(recorder.ModeRecord); err != nil {
+		t.Fatalf("error recording: %s", err)
+	}
+	server.Close()
+
+	data, err := ioutil.ReadFile(cassette)
+	if err != nil {
+		t.Fatalf("error reading cassette: %s", err)
+	}
+	if bytes.Contains(data, []byte("fake-token-")) {
+		t.Fatalf("cassette contains the session token")
+	}
+	if err = run(recorder.ModeReplay); err != nil {
+		t.Fatalf("error replaying: %s", err)
+	}
+}
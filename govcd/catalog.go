@@ -6,6 +6,7 @@ package govcd
 
 import (
 	"bytes"
+	"context"
 	"encoding/xml"
 	"errors"
 	"fmt"
@@ -13,7 +14,6 @@ import (
 	"github.com/vmware/go-vcloud-director/util"
 	"io"
 	"io/ioutil"
-	"log"
 	"math"
 	"net/http"
 	"net/url"
@@ -49,6 +49,12 @@ type Envelope struct {
 }
 
 func (c *Catalog) FindCatalogItem(catalogitem string) (CatalogItem, error) {
+	return c.FindCatalogItemWithContext(context.Background(), catalogitem)
+}
+
+// FindCatalogItemWithContext behaves like FindCatalogItem, aborting if ctx
+// is cancelled before vCD answers.
+func (c *Catalog) FindCatalogItemWithContext(ctx context.Context, catalogitem string) (CatalogItem, error) {
 
 	for _, cis := range c.Catalog.CatalogItems {
 		for _, ci := range cis.CatalogItem {
@@ -56,20 +62,20 @@ func (c *Catalog) FindCatalogItem(catalogitem string) (CatalogItem, error) {
 				u, err := url.ParseRequestURI(ci.HREF)
 
 				if err != nil {
-					return CatalogItem{}, fmt.Errorf("error decoding catalog response: %s", err)
+					return CatalogItem{}, fmt.Errorf("error decoding catalog response: %w", err)
 				}
 
-				req := c.c.NewRequest(map[string]string{}, "GET", *u, nil)
+				req := c.c.NewRequestWithContext(ctx, map[string]string{}, "GET", *u, nil)
 
-				resp, err := checkResp(c.c.Http.Do(req))
+				resp, err := c.c.doRequest(req)
 				if err != nil {
-					return CatalogItem{}, fmt.Errorf("error retreiving catalog: %s", err)
+					return CatalogItem{}, fmt.Errorf("error retreiving catalog: %w", err)
 				}
 
 				cat := NewCatalogItem(c.c)
 
 				if err = decodeBody(resp, cat.CatalogItem); err != nil {
-					return CatalogItem{}, fmt.Errorf("error decoding catalog response: %s", err)
+					return CatalogItem{}, fmt.Errorf("error decoding catalog response: %w", err)
 				}
 
 				// The request was successful
@@ -84,6 +90,12 @@ func (c *Catalog) FindCatalogItem(catalogitem string) (CatalogItem, error) {
 // uploads an ova file to a catalog. This method only uploads bits to vCD spool area.
 // Returns errors if any occur during upload from vCD or upload process.
 func (c *Catalog) UploadOvf(ovaFileName, itemName, description string, chunkSize int) (Task, error) {
+	return c.UploadOvfWithContext(context.Background(), ovaFileName, itemName, description, chunkSize)
+}
+
+// UploadOvfWithContext behaves like UploadOvf. Cancelling ctx aborts the
+// upload, including any file transfer that is in progress.
+func (c *Catalog) UploadOvfWithContext(ctx context.Context, ovaFileName, itemName, description string, chunkSize int) (Task, error) {
 
 	//	On a very high level the flow is as follows
 	//	1. Makes a POST call to vCD to create the catalog item (also creates a transfer folder in the spool area and as result will give a sparse catalog item resource XML).
@@ -96,12 +108,12 @@ func (c *Catalog) UploadOvf(ovaFileName, itemName, description string, chunkSize
 		return Task{}, err
 	}
 
-	vappTemplateUrl, err := createItemForUpload(c.c, catalogItemUploadURL, itemName, description)
+	vappTemplateUrl, err := createItemForUpload(ctx, c.c, catalogItemUploadURL, itemName, description)
 	if err != nil {
 		return Task{}, err
 	}
 
-	vappTemplate, err := queryVappTemplate(c.c, vappTemplateUrl)
+	vappTemplate, err := queryVappTemplate(ctx, c.c, vappTemplateUrl)
 	if err != nil {
 		return Task{}, err
 	}
@@ -121,7 +133,7 @@ func (c *Catalog) UploadOvf(ovaFileName, itemName, description string, chunkSize
 
 	for _, filePath := range filesAbsPaths {
 		if filepath.Ext(filePath) == ".ovf" {
-			ovfFileDesc, err = uploadOvfDescription(c.c, filePath, ovfUploadHref)
+			ovfFileDesc, err = uploadOvfDescription(ctx, c.c, filePath, ovfUploadHref)
 			tempPath, _ = filepath.Split(filePath)
 			if err != nil {
 				return Task{}, err
@@ -130,33 +142,33 @@ func (c *Catalog) UploadOvf(ovaFileName, itemName, description string, chunkSize
 		}
 	}
 
-	vappTemplate, err = waitForTempUploadLinks(c.c, vappTemplateUrl)
+	vappTemplate, err = waitForTempUploadLinks(ctx, c.c, vappTemplateUrl)
 
-	err = uploadFiles(c.c, vappTemplate, &ovfFileDesc, tempPath, filesAbsPaths)
+	err = uploadFiles(ctx, c.c, vappTemplate, &ovfFileDesc, tempPath, filesAbsPaths)
 	if err != nil {
 		return Task{}, err
 	}
 
 	var task Task
 	for _, item := range vappTemplate.Tasks.Task {
-		task, err = createTaskForVcdImport(c.c, item.HREF)
+		task, err = createTaskForVcdImport(ctx, c.c, item.HREF)
 	}
 
-	log.Printf("[TRACE] Upload finished and task for vcd import created. \n")
+	c.c.log(LogDebug, "upload finished, vCD import task created", "item", itemName)
 	return task, nil
 }
 
-func uploadFiles(client *Client, vappTemplate *types.VAppTemplate, ovfFileDesc *Envelope, tempPath string, filesAbsPaths []string) error {
+func uploadFiles(ctx context.Context, client *Client, vappTemplate *types.VAppTemplate, ovfFileDesc *Envelope, tempPath string, filesAbsPaths []string) error {
 	for _, item := range vappTemplate.Files.File {
 		if item.BytesTransferred == 0 {
 			if ovfFileDesc.File[0].ChunkSize != 0 {
 				chunkFilePaths := getChunkedFilePaths(tempPath, ovfFileDesc.File[0].HREF, ovfFileDesc.File[0].Size, ovfFileDesc.File[0].ChunkSize)
-				err := uploadMultiPartFile(client, chunkFilePaths, item.Link[0].HREF, int64(ovfFileDesc.File[0].Size))
+				err := uploadMultiPartFile(ctx, client, chunkFilePaths, item.Link[0].HREF, int64(ovfFileDesc.File[0].Size))
 				if err != nil {
 					return err
 				}
 			} else {
-				_, err := uploadFile(client, item.Link[0].HREF, findFilePath(filesAbsPaths, item.Name), 0, item.Size)
+				_, err := uploadFile(ctx, client, item.Link[0].HREF, findFilePath(filesAbsPaths, item.Name), 0, item.Size)
 				if err != nil {
 					return err
 				}
@@ -166,14 +178,14 @@ func uploadFiles(client *Client, vappTemplate *types.VAppTemplate, ovfFileDesc *
 	return nil
 }
 
-func uploadMultiPartFile(client *Client, filePaths []string, uploadHREF string, totalBytesToUpload int64) error {
-	log.Printf("[TRACE] Upload multi part file: %v\n, href: %s, size: %v", filePaths, uploadHREF, totalBytesToUpload)
+func uploadMultiPartFile(ctx context.Context, client *Client, filePaths []string, uploadHREF string, totalBytesToUpload int64) error {
+	client.log(LogTrace, "uploading multi part file", "files", filePaths, "url", uploadHREF, "size", totalBytesToUpload)
 
 	var uploadedBytes int64
 
 	for i, filePath := range filePaths {
-		log.Printf("[TRACE] Uploading file: %v\n", i+1)
-		tempVar, err := uploadFile(client, uploadHREF, filePath, uploadedBytes, totalBytesToUpload)
+		client.log(LogTrace, "uploading file part", "part", i+1)
+		tempVar, err := uploadFile(ctx, client, uploadHREF, filePath, uploadedBytes, totalBytesToUpload)
 		if err != nil {
 			return err
 		}
@@ -183,34 +195,35 @@ func uploadMultiPartFile(client *Client, filePaths []string, uploadHREF string,
 }
 
 // Function waits until vCD provides temporary file upload links.
-func waitForTempUploadLinks(client *Client, vappTemplateUrl *url.URL) (*types.VAppTemplate, error) {
+func waitForTempUploadLinks(ctx context.Context, client *Client, vappTemplateUrl *url.URL) (*types.VAppTemplate, error) {
 	var vAppTemplate *types.VAppTemplate
 	var err error
 	for {
-		log.Printf("[TRACE] Sleep... for 5 seconds.\n")
-		time.Sleep(time.Second * 5)
-		vAppTemplate, err = queryVappTemplate(client, vappTemplateUrl)
+		if err = sleepWithContext(ctx, time.Second*5); err != nil {
+			return nil, err
+		}
+		vAppTemplate, err = queryVappTemplate(ctx, client, vappTemplateUrl)
 		if err != nil {
 			return nil, err
 		}
 		if len(vAppTemplate.Files.File) > 1 {
-			log.Printf("[TRACE] upload link prepared.\n")
+			client.log(LogTrace, "upload links prepared", "url", vappTemplateUrl.String())
 			break
 		}
 	}
 	return vAppTemplate, nil
 }
 
-func createTaskForVcdImport(client *Client, taskHREF string) (Task, error) {
-	log.Printf("[TRACE] Create task for vcd with HREF: %s\n", taskHREF)
+func createTaskForVcdImport(ctx context.Context, client *Client, taskHREF string) (Task, error) {
+	client.log(LogTrace, "creating task for vCD import", "url", taskHREF)
 
 	taskURL, err := url.ParseRequestURI(taskHREF)
 	if err != nil {
 		return Task{}, err
 	}
 
-	request := client.NewRequest(map[string]string{}, "GET", *taskURL, nil)
-	response, err := checkResp(client.Http.Do(request))
+	request := client.NewRequestWithContext(ctx, map[string]string{}, "GET", *taskURL, nil)
+	response, err := client.doRequest(request)
 	if err != nil {
 		return Task{}, err
 	}
@@ -218,7 +231,7 @@ func createTaskForVcdImport(client *Client, taskHREF string) (Task, error) {
 	task := NewTask(client)
 
 	if err = decodeBody(response, task.Task); err != nil {
-		return Task{}, fmt.Errorf("error decoding Task response: %s", err)
+		return Task{}, fmt.Errorf("error decoding Task response: %w", err)
 	}
 
 	// The request was successful
@@ -226,8 +239,6 @@ func createTaskForVcdImport(client *Client, taskHREF string) (Task, error) {
 }
 
 func getOvfUploadLink(vappTemplate *types.VAppTemplate) (*url.URL, error) {
-	log.Printf("[TRACE] Parsing ofv upload link: %#v\n", vappTemplate)
-
 	ovfUploadHref, err := url.ParseRequestURI(vappTemplate.Files.File[0].Link[0].HREF)
 	if err != nil {
 		return nil, err
@@ -236,10 +247,10 @@ func getOvfUploadLink(vappTemplate *types.VAppTemplate) (*url.URL, error) {
 	return ovfUploadHref, nil
 }
 
-func queryVappTemplate(client *Client, vappTemplateUrl *url.URL) (*types.VAppTemplate, error) {
-	log.Printf("[TRACE] Qeurying vapp template: %s\n", vappTemplateUrl)
-	request := client.NewRequest(map[string]string{}, "GET", *vappTemplateUrl, nil)
-	response, err := checkResp(client.Http.Do(request))
+func queryVappTemplate(ctx context.Context, client *Client, vappTemplateUrl *url.URL) (*types.VAppTemplate, error) {
+	client.log(LogTrace, "querying vApp template", "url", vappTemplateUrl.String())
+	request := client.NewRequestWithContext(ctx, map[string]string{}, "GET", *vappTemplateUrl, nil)
+	response, err := client.doRequest(request)
 	if err != nil {
 		return nil, err
 	}
@@ -250,16 +261,13 @@ func queryVappTemplate(client *Client, vappTemplateUrl *url.URL) (*types.VAppTem
 	}
 
 	defer response.Body.Close()
-
-	log.Printf("[TRACE] Response: %v\n", response)
-	log.Printf("[TRACE] Response body: %v\n", vappTemplateParsed)
 	return vappTemplateParsed, nil
 }
 
 // Uploads ovf description file from unarchived provided ova file. As result vCD will generate temporary upload links which has to be queried later.
 // Function will return parsed part for upload files from description xml.
-func uploadOvfDescription(client *Client, ovfFile string, ovfUploadUrl *url.URL) (Envelope, error) {
-	log.Printf("[TRACE] Uploding ovf description with file: %s and url: %s\n", ovfFile, ovfUploadUrl)
+func uploadOvfDescription(ctx context.Context, client *Client, ovfFile string, ovfUploadUrl *url.URL) (Envelope, error) {
+	client.log(LogTrace, "uploading OVF description", "file", ovfFile, "url", ovfUploadUrl.String())
 	openedFile, err := os.Open(ovfFile)
 	if err != nil {
 		return Envelope{}, err
@@ -268,10 +276,10 @@ func uploadOvfDescription(client *Client, ovfFile string, ovfUploadUrl *url.URL)
 	var buf bytes.Buffer
 	ovfReader := io.TeeReader(openedFile, &buf)
 
-	request := client.NewRequest(map[string]string{}, "PUT", *ovfUploadUrl, ovfReader)
+	request := client.NewRequestWithContext(ctx, map[string]string{}, "PUT", *ovfUploadUrl, ovfReader)
 	request.Header.Add("Content-Type", "text/xml")
 
-	response, err := checkResp(client.Http.Do(request))
+	response, err := client.doRequest(request)
 	if err != nil {
 		return Envelope{}, err
 	}
@@ -289,11 +297,6 @@ func uploadOvfDescription(client *Client, ovfFile string, ovfUploadUrl *url.URL)
 
 	openedFile.Close()
 
-	body, err := ioutil.ReadAll(response.Body)
-	log.Printf("[TRACE] Response: %#v\n", response)
-	log.Printf("[TRACE] Response body: %s\n", string(body[:]))
-	log.Printf("[TRACE] Ovf file description file: %#v\n", ovfFileDesc)
-
 	response.Body.Close()
 
 	return ovfFileDesc, nil
@@ -302,7 +305,7 @@ func uploadOvfDescription(client *Client, ovfFile string, ovfUploadUrl *url.URL)
 func findCatalogItemUploadLink(catalog *Catalog) (*url.URL, error) {
 	for _, item := range catalog.Catalog.Link {
 		if item.Type == "application/vnd.vmware.vcloud.uploadVAppTemplateParams+xml" && item.Rel == "add" {
-			log.Printf("[TRACE] Found Catalong link for uplaod: %s\n", item.HREF)
+			catalog.c.log(LogTrace, "found catalog upload link", "url", item.HREF)
 
 			uploadURL, err := url.ParseRequestURI(item.HREF)
 			if err != nil {
@@ -315,8 +318,8 @@ func findCatalogItemUploadLink(catalog *Catalog) (*url.URL, error) {
 	return nil, errors.New("catalog upload url isn't found")
 }
 
-func uploadFile(client *Client, uploadLink, filePath string, offset, fileSizeToUpload int64) (int64, error) {
-	log.Printf("[TRACE] Starting uploading: %s, offset: %v, fileze: %v, toLink: %s \n", filePath, offset, fileSizeToUpload, uploadLink)
+func uploadFile(ctx context.Context, client *Client, uploadLink, filePath string, offset, fileSizeToUpload int64) (int64, error) {
+	client.log(LogTrace, "uploading file", "file", filePath, "offset", offset, "size", fileSizeToUpload, "url", uploadLink)
 
 	file, err := os.Open(filePath)
 	if err != nil {
@@ -330,23 +333,20 @@ func uploadFile(client *Client, uploadLink, filePath string, offset, fileSizeToU
 
 	defer file.Close()
 
-	request, err := newFileUploadRequest(uploadLink, file, offset, fileInfo.Size(), fileSizeToUpload)
+	request, err := newFileUploadRequest(ctx, uploadLink, file, offset, fileInfo.Size(), fileSizeToUpload)
 	if err != nil {
 		return 0, err
 	}
 
-	response, err := checkResp(client.Http.Do(request))
+	response, err := client.doRequest(request)
 	if err != nil {
-		return 0, fmt.Errorf("File "+filePath+" upload failed. Err: %s \n", err)
+		return 0, fmt.Errorf("File "+filePath+" upload failed. Err: %w \n", err)
 	}
 	defer response.Body.Close()
 
-	body, err := ioutil.ReadAll(response.Body)
-	if err != nil {
+	if _, err = ioutil.ReadAll(response.Body); err != nil {
 		return 0, err
 	}
-	log.Printf("[TRACE] Response: %#v\n", response)
-	log.Printf("[TRACE] Response body: %s\n", string(body[:]))
 
 	return fileInfo.Size(), nil
 }
@@ -362,17 +362,17 @@ func findFilePath(filesAbsPaths []string, fileName string) string {
 }
 
 // Initiates creation of item and returns ovf upload url for created item.
-func createItemForUpload(client *Client, createHREF *url.URL, catalogItemName string, itemDescription string) (*url.URL, error) {
+func createItemForUpload(ctx context.Context, client *Client, createHREF *url.URL, catalogItemName string, itemDescription string) (*url.URL, error) {
 
 	reqBody := bytes.NewBufferString(
 		"<UploadVAppTemplateParams xmlns=\"http://www.vmware.com/vcloud/v1.5\" name=\"" + catalogItemName + "\" >" +
 			"<Description>" + itemDescription + "</Description>" +
 			"</UploadVAppTemplateParams>")
 
-	request := client.NewRequest(map[string]string{}, "POST", *createHREF, reqBody)
+	request := client.NewRequestWithContext(ctx, map[string]string{}, "POST", *createHREF, reqBody)
 	request.Header.Add("Content-Type", "application/vnd.vmware.vcloud.uploadVAppTemplateParams+xml")
 
-	response, err := checkResp(client.Http.Do(request))
+	response, err := client.doRequest(request)
 	if err != nil {
 		return nil, err
 	}
@@ -383,9 +383,6 @@ func createItemForUpload(client *Client, createHREF *url.URL, catalogItemName st
 		return nil, err
 	}
 
-	log.Printf("[TRACE] Response: %#v \n", response)
-	log.Printf("[TRACE] Catalog item parsed: %#v\n", catalogItemParsed)
-
 	ovfUploadUrl, err := url.ParseRequestURI(catalogItemParsed.Entity.HREF)
 	if err != nil {
 		return nil, err
@@ -395,10 +392,8 @@ func createItemForUpload(client *Client, createHREF *url.URL, catalogItemName st
 }
 
 // Create Request with right headers and range settings. Support multi part file upload.
-func newFileUploadRequest(requestUrl string, file io.Reader, offset, fileSize, fileSizeToUpload int64) (*http.Request, error) {
-	log.Printf("[TRACE] Creating file upload request: %s, %v, %v, %v \n", requestUrl, offset, fileSize, fileSizeToUpload)
-
-	uploadReq, err := http.NewRequest("PUT", requestUrl, file)
+func newFileUploadRequest(ctx context.Context, requestUrl string, file io.Reader, offset, fileSize, fileSizeToUpload int64) (*http.Request, error) {
+	uploadReq, err := http.NewRequestWithContext(withUpload(ctx), "PUT", requestUrl, file)
 	if err != nil {
 		return nil, err
 	}
@@ -409,10 +404,6 @@ func newFileUploadRequest(requestUrl string, file io.Reader, offset, fileSize, f
 	rangeExpression := "bytes " + strconv.FormatInt(int64(offset), 10) + "-" + strconv.FormatInt(int64(offset+fileSize-1), 10) + "/" + strconv.FormatInt(int64(fileSizeToUpload), 10)
 	uploadReq.Header.Set("Content-Range", rangeExpression)
 
-	for key, value := range uploadReq.Header {
-		log.Printf("[TRACE] Header: %s :%s \n", key, value)
-	}
-
 	return uploadReq, nil
 }
 
@@ -434,6 +425,5 @@ func getChunkedFilePaths(baseDir, baseFileName string, totalFileSize, partSize i
 		filePaths = append(filePaths, filePath)
 	}
 
-	log.Printf("[TRACE] Chunked files file paths: %s \n", filePaths)
 	return filePaths
 }
@@ -6,6 +6,7 @@ package govcd
 
 import (
 	. "gopkg.in/check.v1"
+	"testing"
 )
 
 func (vcd *TestVCD) Test_FindCatalogItem(check *C) {
@@ -24,3 +25,29 @@ func (vcd *TestVCD) Test_FindCatalogItem(check *C) {
 	catitem, err = cat.FindCatalogItem("INVALID")
 	check.Assert(err, NotNil)
 }
+
+func TestCatalog_FindCatalogItemFakeVCD(t *testing.T) {
+	server, fakeVdc, client := newFakeVCD(t)
+	defer server.Close()
+	fakeVdc.Org().AddCatalog("catalog").AddItem("template")
+	org, _ := fakeOrgVdc(t, client)
+
+	catalog, err := org.FindCatalog("catalog")
+	if err != nil {
+		t.Fatalf("error finding catalog: %s", err)
+	}
+	item, err := catalog.FindCatalogItem("template")
+	if err != nil {
+		t.Fatalf("error finding catalog item: %s", err)
+	}
+	template, err := item.GetVAppTemplate()
+	if err != nil {
+		t.Fatalf("error getting vapp template: %s", err)
+	}
+	if template.VAppTemplate.Children == nil || len(template.VAppTemplate.Children.VM) != 1 {
+		t.Fatalf("expected a template with one VM")
+	}
+	if _, err = catalog.FindCatalogItem("missing"); err == nil {
+		t.Fatalf("expected an error finding a missing item")
+	}
+}
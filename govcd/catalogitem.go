@@ -5,6 +5,7 @@
 package govcd
 
 import (
+	"context"
 	"fmt"
 	"net/url"
 
@@ -24,23 +25,29 @@ func NewCatalogItem(c *Client) *CatalogItem {
 }
 
 func (ci *CatalogItem) GetVAppTemplate() (VAppTemplate, error) {
+	return ci.GetVAppTemplateWithContext(context.Background())
+}
+
+// GetVAppTemplateWithContext behaves like GetVAppTemplate, aborting if ctx
+// is cancelled before vCD answers.
+func (ci *CatalogItem) GetVAppTemplateWithContext(ctx context.Context) (VAppTemplate, error) {
 	url, err := url.ParseRequestURI(ci.CatalogItem.Entity.HREF)
 
 	if err != nil {
-		return VAppTemplate{}, fmt.Errorf("error decoding catalogitem response: %s", err)
+		return VAppTemplate{}, fmt.Errorf("error decoding catalogitem response: %w", err)
 	}
 
-	req := ci.c.NewRequest(map[string]string{}, "GET", *url, nil)
+	req := ci.c.NewRequestWithContext(ctx, map[string]string{}, "GET", *url, nil)
 
-	resp, err := checkResp(ci.c.Http.Do(req))
+	resp, err := ci.c.doRequest(req)
 	if err != nil {
-		return VAppTemplate{}, fmt.Errorf("error retreiving vapptemplate: %s", err)
+		return VAppTemplate{}, fmt.Errorf("error retreiving vapptemplate: %w", err)
 	}
 
 	cat := NewVAppTemplate(ci.c)
 
 	if err = decodeBody(resp, cat.VAppTemplate); err != nil {
-		return VAppTemplate{}, fmt.Errorf("error decoding vapptemplate response: %s", err)
+		return VAppTemplate{}, fmt.Errorf("error decoding vapptemplate response: %w", err)
 	}
 
 	// The request was successful
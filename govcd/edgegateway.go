@@ -6,14 +6,10 @@ package govcd
 
 import (
 	"bytes"
+	"context"
 	"encoding/xml"
 	"fmt"
-	"log"
-	"net/http"
 	"net/url"
-	"os"
-	"regexp"
-	"time"
 
 	types "github.com/vmware/go-vcloud-director/types/v56"
 )
@@ -31,9 +27,14 @@ func NewEdgeGateway(c *Client) *EdgeGateway {
 }
 
 func (e *EdgeGateway) AddDhcpPool(network *types.OrgVDCNetwork, dhcppool []interface{}) (Task, error) {
+	return e.AddDhcpPoolWithContext(context.Background(), network, dhcppool)
+}
+
+// AddDhcpPoolWithContext behaves like AddDhcpPool. Both the request and
+// the wait while the edge gateway is busy stop when ctx is cancelled.
+func (e *EdgeGateway) AddDhcpPoolWithContext(ctx context.Context, network *types.OrgVDCNetwork, dhcppool []interface{}) (Task, error) {
 	newedgeconfig := e.EdgeGateway.Configuration.EdgeGatewayServiceConfiguration
-	log.Printf("[DEBUG] EDGE GATEWAY: %#v", newedgeconfig)
-	log.Printf("[DEBUG] EDGE GATEWAY SERVICE: %#v", newedgeconfig.GatewayDhcpService)
+	e.c.log(LogDebug, "adding DHCP pools", "edge_gateway", e.EdgeGateway.Name, "network", network.Name)
 	newdchpservice := &types.GatewayDhcpService{}
 	if newedgeconfig.GatewayDhcpService == nil {
 		newdchpservice.IsEnabled = true
@@ -84,37 +85,29 @@ func (e *EdgeGateway) AddDhcpPool(network *types.OrgVDCNetwork, dhcppool []inter
 
 	output, err := xml.MarshalIndent(newRules, "  ", "    ")
 	if err != nil {
-		return Task{}, fmt.Errorf("error reconfiguring Edge Gateway: %s", err)
+		return Task{}, fmt.Errorf("error reconfiguring Edge Gateway: %w", err)
 	}
 
-	var resp *http.Response
-	for {
-		b := bytes.NewBufferString(xml.Header + string(output))
+	b := bytes.NewBufferString(xml.Header + string(output))
 
-		s, _ := url.ParseRequestURI(e.EdgeGateway.HREF)
-		s.Path += "/action/configureServices"
+	s, _ := url.ParseRequestURI(e.EdgeGateway.HREF)
+	s.Path += "/action/configureServices"
 
-		req := e.c.NewRequest(map[string]string{}, "POST", *s, b)
-		log.Printf("[DEBUG] POSTING TO URL: %s", s.Path)
-		log.Printf("[DEBUG] XML TO SEND:\n%s", b)
+	req := e.c.NewRequestWithContext(ctx, map[string]string{}, "POST", *s, b)
 
-		req.Header.Add("Content-Type", "application/vnd.vmware.admin.edgeGatewayServiceConfiguration+xml")
+	req.Header.Add("Content-Type", "application/vnd.vmware.admin.edgeGatewayServiceConfiguration+xml")
 
-		resp, err = checkResp(e.c.Http.Do(req))
-		if err != nil {
-			if v, _ := regexp.MatchString("is busy completing an operation.$", err.Error()); v {
-				time.Sleep(3 * time.Second)
-				continue
-			}
-			return Task{}, fmt.Errorf("error reconfiguring Edge Gateway: %s", err)
-		}
-		break
+	// The request layer retries while the edge gateway is busy completing
+	// an operation, according to the client retry policy.
+	resp, err := e.c.doRequest(req)
+	if err != nil {
+		return Task{}, fmt.Errorf("error reconfiguring Edge Gateway: %w", err)
 	}
 
 	task := NewTask(e.c)
 
 	if err = decodeBody(resp, task.Task); err != nil {
-		return Task{}, fmt.Errorf("error decoding Task response: %s", err)
+		return Task{}, fmt.Errorf("error decoding Task response: %w", err)
 	}
 
 	// The request was successful
@@ -123,10 +116,22 @@ func (e *EdgeGateway) AddDhcpPool(network *types.OrgVDCNetwork, dhcppool []inter
 }
 
 func (e *EdgeGateway) RemoveNATMapping(nattype, externalIP, internalIP, port string) (Task, error) {
-	return e.RemoveNATPortMapping(nattype, externalIP, port, internalIP, port)
+	return e.RemoveNATMappingWithContext(context.Background(), nattype, externalIP, internalIP, port)
+}
+
+// RemoveNATMappingWithContext behaves like RemoveNATMapping, aborting
+// the request if ctx is cancelled before vCD accepts it.
+func (e *EdgeGateway) RemoveNATMappingWithContext(ctx context.Context, nattype, externalIP, internalIP, port string) (Task, error) {
+	return e.RemoveNATPortMappingWithContext(ctx, nattype, externalIP, port, internalIP, port)
 }
 
 func (e *EdgeGateway) RemoveNATPortMapping(nattype, externalIP, externalPort string, internalIP, internalPort string) (Task, error) {
+	return e.RemoveNATPortMappingWithContext(context.Background(), nattype, externalIP, externalPort, internalIP, internalPort)
+}
+
+// RemoveNATPortMappingWithContext behaves like RemoveNATPortMapping,
+// aborting the request if ctx is cancelled before vCD accepts it.
+func (e *EdgeGateway) RemoveNATPortMappingWithContext(ctx context.Context, nattype, externalIP, externalPort string, internalIP, internalPort string) (Task, error) {
 	// Find uplink interface
 	var uplink types.Reference
 	for _, gi := range e.EdgeGateway.Configuration.GatewayInterfaces.GatewayInterface {
@@ -154,10 +159,10 @@ func (e *EdgeGateway) RemoveNATPortMapping(nattype, externalIP, externalPort str
 			v.GatewayNatRule.OriginalIP == externalIP &&
 			v.GatewayNatRule.OriginalPort == externalPort &&
 			v.GatewayNatRule.Interface.HREF == uplink.HREF {
-			log.Printf("[DEBUG] REMOVING %s Rule: %#v", v.RuleType, v.GatewayNatRule)
+			e.c.log(LogDebug, "removing NAT rule", "type", v.RuleType, "original_ip", v.GatewayNatRule.OriginalIP, "original_port", v.GatewayNatRule.OriginalPort)
 			continue
 		}
-		log.Printf("[DEBUG] KEEPING %s Rule: %#v", v.RuleType, v.GatewayNatRule)
+		e.c.log(LogTrace, "keeping NAT rule", "type", v.RuleType, "original_ip", v.GatewayNatRule.OriginalIP, "original_port", v.GatewayNatRule.OriginalPort)
 		newnatservice.NatRule = append(newnatservice.NatRule, v)
 	}
 
@@ -170,7 +175,7 @@ func (e *EdgeGateway) RemoveNATPortMapping(nattype, externalIP, externalPort str
 
 	output, err := xml.MarshalIndent(newRules, "  ", "    ")
 	if err != nil {
-		return Task{}, fmt.Errorf("error reconfiguring Edge Gateway: %s", err)
+		return Task{}, fmt.Errorf("error reconfiguring Edge Gateway: %w", err)
 	}
 
 	b := bytes.NewBufferString(xml.Header + string(output))
@@ -178,22 +183,19 @@ func (e *EdgeGateway) RemoveNATPortMapping(nattype, externalIP, externalPort str
 	s, _ := url.ParseRequestURI(e.EdgeGateway.HREF)
 	s.Path += "/action/configureServices"
 
-	req := e.c.NewRequest(map[string]string{}, "POST", *s, b)
-	log.Printf("[DEBUG] POSTING TO URL: %s", s.Path)
-	log.Printf("[DEBUG] XML TO SEND:\n%s", b)
+	req := e.c.NewRequestWithContext(ctx, map[string]string{}, "POST", *s, b)
 
 	req.Header.Add("Content-Type", "application/vnd.vmware.admin.edgeGatewayServiceConfiguration+xml")
 
-	resp, err := checkResp(e.c.Http.Do(req))
+	resp, err := e.c.doRequest(req)
 	if err != nil {
-		log.Printf("[DEBUG] Error is: %#v", err)
-		return Task{}, fmt.Errorf("error reconfiguring Edge Gateway: %s", err)
+		return Task{}, fmt.Errorf("error reconfiguring Edge Gateway: %w", err)
 	}
 
 	task := NewTask(e.c)
 
 	if err = decodeBody(resp, task.Task); err != nil {
-		return Task{}, fmt.Errorf("error decoding Task response: %s", err)
+		return Task{}, fmt.Errorf("error decoding Task response: %w", err)
 	}
 
 	// The request was successful
@@ -202,11 +204,23 @@ func (e *EdgeGateway) RemoveNATPortMapping(nattype, externalIP, externalPort str
 }
 
 func (e *EdgeGateway) AddNATMapping(nattype, externalIP, internalIP, port string) (Task, error) {
-	return e.AddNATPortMapping(nattype, externalIP, port, internalIP, port)
+	return e.AddNATMappingWithContext(context.Background(), nattype, externalIP, internalIP, port)
+}
+
+// AddNATMappingWithContext behaves like AddNATMapping, aborting the
+// request if ctx is cancelled before vCD accepts it.
+func (e *EdgeGateway) AddNATMappingWithContext(ctx context.Context, nattype, externalIP, internalIP, port string) (Task, error) {
+	return e.AddNATPortMappingWithContext(ctx, nattype, externalIP, port, internalIP, port)
 }
 
 func (e *EdgeGateway) AddNATPortMapping(nattype, externalIP, externalPort string, internalIP, internalPort string) (Task, error) {
-	return e.AddNATPortMappingWithUplink(nil, nattype, externalIP, externalPort, internalIP, internalPort)
+	return e.AddNATPortMappingWithContext(context.Background(), nattype, externalIP, externalPort, internalIP, internalPort)
+}
+
+// AddNATPortMappingWithContext behaves like AddNATPortMapping, aborting
+// the request if ctx is cancelled before vCD accepts it.
+func (e *EdgeGateway) AddNATPortMappingWithContext(ctx context.Context, nattype, externalIP, externalPort string, internalIP, internalPort string) (Task, error) {
+	return e.AddNATPortMappingWithUplinkWithContext(ctx, nil, nattype, externalIP, externalPort, internalIP, internalPort)
 }
 
 func (e *EdgeGateway) getFirstUplink() types.Reference {
@@ -221,6 +235,13 @@ func (e *EdgeGateway) getFirstUplink() types.Reference {
 }
 
 func (e *EdgeGateway) AddNATPortMappingWithUplink(network *types.OrgVDCNetwork, nattype, externalIP, externalPort string, internalIP, internalPort string) (Task, error) {
+	return e.AddNATPortMappingWithUplinkWithContext(context.Background(), network, nattype, externalIP, externalPort, internalIP, internalPort)
+}
+
+// AddNATPortMappingWithUplinkWithContext behaves like
+// AddNATPortMappingWithUplink, aborting the request if ctx is cancelled
+// before vCD accepts it.
+func (e *EdgeGateway) AddNATPortMappingWithUplinkWithContext(ctx context.Context, network *types.OrgVDCNetwork, nattype, externalIP, externalPort string, internalIP, internalPort string) (Task, error) {
 	// if a network is provided take it, otherwise find first uplink on the edgegateway
 	var uplinkRef string
 
@@ -286,7 +307,7 @@ func (e *EdgeGateway) AddNATPortMappingWithUplink(network *types.OrgVDCNetwork,
 
 	output, err := xml.MarshalIndent(newRules, "  ", "    ")
 	if err != nil {
-		return Task{}, fmt.Errorf("error reconfiguring Edge Gateway: %s", err)
+		return Task{}, fmt.Errorf("error reconfiguring Edge Gateway: %w", err)
 	}
 
 	b := bytes.NewBufferString(xml.Header + string(output))
@@ -294,22 +315,19 @@ func (e *EdgeGateway) AddNATPortMappingWithUplink(network *types.OrgVDCNetwork,
 	s, _ := url.ParseRequestURI(e.EdgeGateway.HREF)
 	s.Path += "/action/configureServices"
 
-	req := e.c.NewRequest(map[string]string{}, "POST", *s, b)
-	log.Printf("[DEBUG] POSTING TO URL: %s", s.Path)
-	log.Printf("[DEBUG] XML TO SEND:\n%s", b)
+	req := e.c.NewRequestWithContext(ctx, map[string]string{}, "POST", *s, b)
 
 	req.Header.Add("Content-Type", "application/vnd.vmware.admin.edgeGatewayServiceConfiguration+xml")
 
-	resp, err := checkResp(e.c.Http.Do(req))
+	resp, err := e.c.doRequest(req)
 	if err != nil {
-		log.Printf("[DEBUG] Error is: %#v", err)
-		return Task{}, fmt.Errorf("error reconfiguring Edge Gateway: %s", err)
+		return Task{}, fmt.Errorf("error reconfiguring Edge Gateway: %w", err)
 	}
 
 	task := NewTask(e.c)
 
 	if err = decodeBody(resp, task.Task); err != nil {
-		return Task{}, fmt.Errorf("error decoding Task response: %s", err)
+		return Task{}, fmt.Errorf("error decoding Task response: %w", err)
 	}
 
 	// The request was successful
@@ -318,9 +336,16 @@ func (e *EdgeGateway) AddNATPortMappingWithUplink(network *types.OrgVDCNetwork,
 }
 
 func (e *EdgeGateway) CreateFirewallRules(defaultAction string, rules []*types.FirewallRule) (Task, error) {
-	err := e.Refresh()
+	return e.CreateFirewallRulesWithContext(context.Background(), defaultAction, rules)
+}
+
+// CreateFirewallRulesWithContext behaves like CreateFirewallRules.
+// Both the request and the wait while the edge gateway is busy stop when
+// ctx is cancelled.
+func (e *EdgeGateway) CreateFirewallRulesWithContext(ctx context.Context, defaultAction string, rules []*types.FirewallRule) (Task, error) {
+	err := e.RefreshWithContext(ctx)
 	if err != nil {
-		return Task{}, fmt.Errorf("error: %v\n", err)
+		return Task{}, fmt.Errorf("error: %w\n", err)
 	}
 
 	newRules := &types.EdgeGatewayServiceConfiguration{
@@ -335,37 +360,29 @@ func (e *EdgeGateway) CreateFirewallRules(defaultAction string, rules []*types.F
 
 	output, err := xml.MarshalIndent(newRules, "  ", "    ")
 	if err != nil {
-		return Task{}, fmt.Errorf("error: %v\n", err)
+		return Task{}, fmt.Errorf("error: %w\n", err)
 	}
 
-	var resp *http.Response
-	for {
-		b := bytes.NewBufferString(xml.Header + string(output))
+	b := bytes.NewBufferString(xml.Header + string(output))
 
-		s, _ := url.ParseRequestURI(e.EdgeGateway.HREF)
-		s.Path += "/action/configureServices"
+	s, _ := url.ParseRequestURI(e.EdgeGateway.HREF)
+	s.Path += "/action/configureServices"
 
-		req := e.c.NewRequest(map[string]string{}, "POST", *s, b)
-		log.Printf("[DEBUG] POSTING TO URL: %s", s.Path)
-		log.Printf("[DEBUG] XML TO SEND:\n%s", b)
+	req := e.c.NewRequestWithContext(ctx, map[string]string{}, "POST", *s, b)
 
-		req.Header.Add("Content-Type", "application/vnd.vmware.admin.edgeGatewayServiceConfiguration+xml")
+	req.Header.Add("Content-Type", "application/vnd.vmware.admin.edgeGatewayServiceConfiguration+xml")
 
-		resp, err = checkResp(e.c.Http.Do(req))
-		if err != nil {
-			if v, _ := regexp.MatchString("is busy completing an operation.$", err.Error()); v {
-				time.Sleep(3 * time.Second)
-				continue
-			}
-			return Task{}, fmt.Errorf("error reconfiguring Edge Gateway: %s", err)
-		}
-		break
+	// The request layer retries while the edge gateway is busy completing
+	// an operation, according to the client retry policy.
+	resp, err := e.c.doRequest(req)
+	if err != nil {
+		return Task{}, fmt.Errorf("error reconfiguring Edge Gateway: %w", err)
 	}
 
 	task := NewTask(e.c)
 
 	if err = decodeBody(resp, task.Task); err != nil {
-		return Task{}, fmt.Errorf("error decoding Task response: %s", err)
+		return Task{}, fmt.Errorf("error decoding Task response: %w", err)
 	}
 
 	// The request was successful
@@ -373,6 +390,12 @@ func (e *EdgeGateway) CreateFirewallRules(defaultAction string, rules []*types.F
 }
 
 func (e *EdgeGateway) Refresh() error {
+	return e.RefreshWithContext(context.Background())
+}
+
+// RefreshWithContext retrieves the current edge gateway
+// configuration, aborting if ctx is cancelled before vCD answers.
+func (e *EdgeGateway) RefreshWithContext(ctx context.Context) error {
 
 	if e.EdgeGateway == nil {
 		return fmt.Errorf("cannot refresh, Object is empty")
@@ -380,11 +403,11 @@ func (e *EdgeGateway) Refresh() error {
 
 	u, _ := url.ParseRequestURI(e.EdgeGateway.HREF)
 
-	req := e.c.NewRequest(map[string]string{}, "GET", *u, nil)
+	req := e.c.NewRequestWithContext(ctx, map[string]string{}, "GET", *u, nil)
 
-	resp, err := checkResp(e.c.Http.Do(req))
+	resp, err := e.c.doRequest(req)
 	if err != nil {
-		return fmt.Errorf("error retreiving Edge Gateway: %s", err)
+		return fmt.Errorf("error retreiving Edge Gateway: %w", err)
 	}
 
 	// Empty struct before a new unmarshal, otherwise we end up with duplicate
@@ -392,7 +415,7 @@ func (e *EdgeGateway) Refresh() error {
 	e.EdgeGateway = &types.EdgeGateway{}
 
 	if err = decodeBody(resp, e.EdgeGateway); err != nil {
-		return fmt.Errorf("error decoding Edge Gateway response: %s", err)
+		return fmt.Errorf("error decoding Edge Gateway response: %w", err)
 	}
 
 	// The request was successful
@@ -400,11 +423,17 @@ func (e *EdgeGateway) Refresh() error {
 }
 
 func (e *EdgeGateway) Remove1to1Mapping(internal, external string) (Task, error) {
+	return e.Remove1to1MappingWithContext(context.Background(), internal, external)
+}
+
+// Remove1to1MappingWithContext behaves like Remove1to1Mapping, aborting
+// the request if ctx is cancelled before vCD accepts it.
+func (e *EdgeGateway) Remove1to1MappingWithContext(ctx context.Context, internal, external string) (Task, error) {
 
 	// Refresh EdgeGateway rules
-	err := e.Refresh()
+	err := e.RefreshWithContext(ctx)
 	if err != nil {
-		fmt.Printf("error: %v\n", err)
+		e.c.log(LogError, "error refreshing edge gateway", "error", err)
 	}
 
 	var uplinkif string
@@ -503,13 +532,7 @@ func (e *EdgeGateway) Remove1to1Mapping(internal, external string) (Task, error)
 
 	output, err := xml.MarshalIndent(newedgeconfig, "  ", "    ")
 	if err != nil {
-		fmt.Printf("error: %v\n", err)
-	}
-
-	debug := os.Getenv("GOVCLOUDAIR_DEBUG")
-
-	if debug == "true" {
-		fmt.Printf("\n\nXML DEBUG: %s\n\n", string(output))
+		e.c.log(LogError, "error marshaling request body", "error", err)
 	}
 
 	b := bytes.NewBufferString(xml.Header + string(output))
@@ -517,19 +540,19 @@ func (e *EdgeGateway) Remove1to1Mapping(internal, external string) (Task, error)
 	s, _ := url.ParseRequestURI(e.EdgeGateway.HREF)
 	s.Path += "/action/configureServices"
 
-	req := e.c.NewRequest(map[string]string{}, "POST", *s, b)
+	req := e.c.NewRequestWithContext(ctx, map[string]string{}, "POST", *s, b)
 
 	req.Header.Add("Content-Type", "application/vnd.vmware.admin.edgeGatewayServiceConfiguration+xml")
 
-	resp, err := checkResp(e.c.Http.Do(req))
+	resp, err := e.c.doRequest(req)
 	if err != nil {
-		return Task{}, fmt.Errorf("error reconfiguring Edge Gateway: %s", err)
+		return Task{}, fmt.Errorf("error reconfiguring Edge Gateway: %w", err)
 	}
 
 	task := NewTask(e.c)
 
 	if err = decodeBody(resp, task.Task); err != nil {
-		return Task{}, fmt.Errorf("error decoding Task response: %s", err)
+		return Task{}, fmt.Errorf("error decoding Task response: %w", err)
 	}
 
 	// The request was successful
@@ -538,11 +561,17 @@ func (e *EdgeGateway) Remove1to1Mapping(internal, external string) (Task, error)
 }
 
 func (e *EdgeGateway) Create1to1Mapping(internal, external, description string) (Task, error) {
+	return e.Create1to1MappingWithContext(context.Background(), internal, external, description)
+}
+
+// Create1to1MappingWithContext behaves like Create1to1Mapping, aborting
+// the request if ctx is cancelled before vCD accepts it.
+func (e *EdgeGateway) Create1to1MappingWithContext(ctx context.Context, internal, external, description string) (Task, error) {
 
 	// Refresh EdgeGateway rules
-	err := e.Refresh()
+	err := e.RefreshWithContext(ctx)
 	if err != nil {
-		fmt.Printf("error: %v\n", err)
+		e.c.log(LogError, "error refreshing edge gateway", "error", err)
 	}
 
 	var uplinkif string
@@ -622,13 +651,7 @@ func (e *EdgeGateway) Create1to1Mapping(internal, external, description string)
 
 	output, err := xml.MarshalIndent(newedgeconfig, "  ", "    ")
 	if err != nil {
-		fmt.Printf("error: %v\n", err)
-	}
-
-	debug := os.Getenv("GOVCLOUDAIR_DEBUG")
-
-	if debug == "true" {
-		fmt.Printf("\n\nXML DEBUG: %s\n\n", string(output))
+		e.c.log(LogError, "error marshaling request body", "error", err)
 	}
 
 	b := bytes.NewBufferString(xml.Header + string(output))
@@ -636,19 +659,19 @@ func (e *EdgeGateway) Create1to1Mapping(internal, external, description string)
 	s, _ := url.ParseRequestURI(e.EdgeGateway.HREF)
 	s.Path += "/action/configureServices"
 
-	req := e.c.NewRequest(map[string]string{}, "POST", *s, b)
+	req := e.c.NewRequestWithContext(ctx, map[string]string{}, "POST", *s, b)
 
 	req.Header.Add("Content-Type", "application/vnd.vmware.admin.edgeGatewayServiceConfiguration+xml")
 
-	resp, err := checkResp(e.c.Http.Do(req))
+	resp, err := e.c.doRequest(req)
 	if err != nil {
-		return Task{}, fmt.Errorf("error reconfiguring Edge Gateway: %s", err)
+		return Task{}, fmt.Errorf("error reconfiguring Edge Gateway: %w", err)
 	}
 
 	task := NewTask(e.c)
 
 	if err = decodeBody(resp, task.Task); err != nil {
-		return Task{}, fmt.Errorf("error decoding Task response: %s", err)
+		return Task{}, fmt.Errorf("error decoding Task response: %w", err)
 	}
 
 	// The request was successful
@@ -657,42 +680,41 @@ func (e *EdgeGateway) Create1to1Mapping(internal, external, description string)
 }
 
 func (e *EdgeGateway) AddIpsecVPN(ipsecVPNConfig *types.EdgeGatewayServiceConfiguration) (Task, error) {
+	return e.AddIpsecVPNWithContext(context.Background(), ipsecVPNConfig)
+}
+
+// AddIpsecVPNWithContext behaves like AddIpsecVPN, aborting the request
+// if ctx is cancelled before vCD accepts it.
+func (e *EdgeGateway) AddIpsecVPNWithContext(ctx context.Context, ipsecVPNConfig *types.EdgeGatewayServiceConfiguration) (Task, error) {
 
-	err := e.Refresh()
+	err := e.RefreshWithContext(ctx)
 	if err != nil {
-		fmt.Printf("error: %v\n", err)
+		e.c.log(LogError, "error refreshing edge gateway", "error", err)
 	}
 
 	output, err := xml.MarshalIndent(ipsecVPNConfig, "  ", "    ")
 	if err != nil {
-		return Task{}, fmt.Errorf("error marshaling ipsecVPNConfig compose: %s", err)
-	}
-
-	debug := os.Getenv("GOVCLOUDAIR_DEBUG")
-
-	if debug == "true" {
-		fmt.Printf("\n\nXML DEBUG: %s\n\n", string(output))
+		return Task{}, fmt.Errorf("error marshaling ipsecVPNConfig compose: %w", err)
 	}
 
 	b := bytes.NewBufferString(xml.Header + string(output))
-	log.Printf("[DEBUG] ipsecVPN configuration: %s", b)
 
 	s, _ := url.ParseRequestURI(e.EdgeGateway.HREF)
 	s.Path += "/action/configureServices"
 
-	req := e.c.NewRequest(map[string]string{}, "POST", *s, b)
+	req := e.c.NewRequestWithContext(ctx, map[string]string{}, "POST", *s, b)
 
 	req.Header.Add("Content-Type", "application/vnd.vmware.admin.edgeGatewayServiceConfiguration+xml")
 
-	resp, err := checkResp(e.c.Http.Do(req))
+	resp, err := e.c.doRequest(req)
 	if err != nil {
-		return Task{}, fmt.Errorf("error reconfiguring Edge Gateway: %s", err)
+		return Task{}, fmt.Errorf("error reconfiguring Edge Gateway: %w", err)
 	}
 
 	task := NewTask(e.c)
 
 	if err = decodeBody(resp, task.Task); err != nil {
-		return Task{}, fmt.Errorf("error decoding Task response: %s", err)
+		return Task{}, fmt.Errorf("error decoding Task response: %w", err)
 	}
 
 	// The request was successful
@@ -7,6 +7,8 @@ package govcd
 import (
 	types "github.com/vmware/go-vcloud-director/types/v56"
 	. "gopkg.in/check.v1"
+	"net/http"
+	"testing"
 )
 
 func (vcd *TestVCD) Test_Refresh(check *C) {
@@ -122,3 +124,36 @@ func (vcd *TestVCD) Test_AddIpsecVPN(check *C) {
 	_, err = edge.AddIpsecVPN(ipsecVPNConfig)
 	check.Assert(err, IsNil)
 }
+
+func TestEdgeGateway_CreateFirewallRulesBusyFakeVCD(t *testing.T) {
+	server, fakeVdc, client := newFakeVCD(t)
+	defer server.Close()
+	fakeVdc.AddEdgeGateway("edge")
+	_, vdc := fakeOrgVdc(t, client)
+
+	edge, err := vdc.FindEdgeGateway("edge")
+	if err != nil {
+		t.Fatalf("error finding edge gateway: %s", err)
+	}
+	// The first attempt is rejected as busy, the retry policy resends it
+	server.InjectError(http.MethodPost, "/action/configureServices", 1, http.StatusBadRequest, types.Error{
+		MajorErrorCode: http.StatusBadRequest,
+		MinorErrorCode: "BUSY_ENTITY",
+		Message:        "The entity edge is busy completing an operation.",
+	})
+	rules := []*types.FirewallRule{{IsEnabled: true, Description: "allow all", Protocols: &types.FirewallRuleProtocols{Any: true}}}
+	task, err := edge.CreateFirewallRules("allow", rules)
+	if err != nil {
+		t.Fatalf("error creating firewall rules: %s", err)
+	}
+	if err = task.WaitTaskCompletion(); err != nil {
+		t.Fatalf("error waiting for firewall rules: %s", err)
+	}
+	if err = edge.Refresh(); err != nil {
+		t.Fatalf("error refreshing edge gateway: %s", err)
+	}
+	firewall := edge.EdgeGateway.Configuration.EdgeGatewayServiceConfiguration.FirewallService
+	if firewall == nil || len(firewall.FirewallRule) != 1 || firewall.DefaultAction != "allow" {
+		t.Fatalf("firewall rules not applied: %+v", firewall)
+	}
+}
@@ -0,0 +1,96 @@
+/*
+ * Copyright 2014 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	types "github.com/vmware/go-vcloud-director/types/v56"
+)
+
+// Sentinel errors that can be matched against any error returned by govcd
+// using errors.Is.
+var (
+	// ErrNotFound is matched when vCD reports that the referenced entity
+	// does not exist.
+	ErrNotFound = errors.New("entity not found")
+	// ErrBusy is matched when vCD refuses an operation because the entity
+	// is busy completing another one. These operations can be retried.
+	ErrBusy = errors.New("entity is busy")
+	// ErrUnauthorized is matched when the session is missing, expired or
+	// the credentials are not valid.
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrConflict is matched when the request conflicts with the current
+	// state of the entity.
+	ErrConflict = errors.New("conflict")
+)
+
+// VCDError is the error returned when vCD answers a request with a non
+// successful status code. It exposes the fields of the types.Error body
+// along with the HTTP status of the response.
+type VCDError struct {
+	HTTPStatus              int
+	MajorErrorCode          int
+	MinorErrorCode          string
+	VendorSpecificErrorCode string
+	Message                 string
+	StackTrace              string
+}
+
+// newVCDError builds a VCDError from the HTTP status and the decoded error
+// body. body may be nil when the response did not contain a valid error.
+func newVCDError(status int, body *types.Error) *VCDError {
+	vcdErr := &VCDError{HTTPStatus: status}
+	if body != nil {
+		vcdErr.MajorErrorCode = body.MajorErrorCode
+		vcdErr.MinorErrorCode = body.MinorErrorCode
+		vcdErr.VendorSpecificErrorCode = body.VendorSpecificErrorCode
+		vcdErr.Message = body.Message
+		vcdErr.StackTrace = body.StackTrace
+	}
+	return vcdErr
+}
+
+func (e *VCDError) Error() string {
+	code := e.MajorErrorCode
+	if code == 0 {
+		code = e.HTTPStatus
+	}
+	return fmt.Sprintf("API Error: %d: %s", code, e.Message)
+}
+
+// Is reports whether the error matches one of the sentinel errors, so that
+// errors.Is(err, ErrNotFound) and friends work through wrapped errors.
+func (e *VCDError) Is(target error) bool {
+	status := e.MajorErrorCode
+	if status == 0 {
+		status = e.HTTPStatus
+	}
+	switch target {
+	case ErrNotFound:
+		return status == 404 || e.MinorErrorCode == "RESOURCE_NOT_FOUND"
+	case ErrBusy:
+		return e.MinorErrorCode == "BUSY_ENTITY" || strings.Contains(e.Message, "is busy")
+	case ErrUnauthorized:
+		return status == 401 || e.HTTPStatus == 401
+	case ErrConflict:
+		return status == 409 || e.HTTPStatus == 409
+	}
+	return false
+}
+
+// IsNotFound reports whether err, or any error it wraps, means that the
+// requested entity does not exist.
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound)
+}
+
+// IsBusy reports whether err, or any error it wraps, means that the entity
+// is busy and the operation can be retried.
+func IsBusy(err error) bool {
+	return errors.Is(err, ErrBusy)
+}
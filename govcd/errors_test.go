@@ -0,0 +1,84 @@
+/*
+ * Copyright 2014 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func newErrorResponse(status int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: status,
+		Status:     http.StatusText(status),
+		Body:       ioutil.NopCloser(strings.NewReader(body)),
+	}
+}
+
+func TestCheckResp_VCDError(t *testing.T) {
+	body := `<Error xmlns="http://www.vmware.com/vcloud/v1.5" majorErrorCode="404" minorErrorCode="RESOURCE_NOT_FOUND" ` +
+		`vendorSpecificErrorCode="1234" message="No access to entity" stackTrace="trace"></Error>`
+
+	_, err := checkResp(newErrorResponse(404, body), nil)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+
+	wrapped := fmt.Errorf("error fetching vApp: %w", err)
+	var vcdErr *VCDError
+	if !errors.As(wrapped, &vcdErr) {
+		t.Fatalf("expected a *VCDError, got %T", err)
+	}
+	if vcdErr.HTTPStatus != 404 || vcdErr.MajorErrorCode != 404 || vcdErr.MinorErrorCode != "RESOURCE_NOT_FOUND" ||
+		vcdErr.VendorSpecificErrorCode != "1234" || vcdErr.Message != "No access to entity" || vcdErr.StackTrace != "trace" {
+		t.Fatalf("unexpected error fields: %+v", vcdErr)
+	}
+	if err.Error() != "API Error: 404: No access to entity" {
+		t.Fatalf("unexpected error message: %s", err)
+	}
+	if !errors.Is(wrapped, ErrNotFound) || !IsNotFound(wrapped) {
+		t.Fatal("expected error to match ErrNotFound")
+	}
+	if errors.Is(wrapped, ErrBusy) || errors.Is(wrapped, ErrConflict) || errors.Is(wrapped, ErrUnauthorized) {
+		t.Fatal("error matched an unexpected sentinel")
+	}
+}
+
+func TestCheckResp_UndecodableBody(t *testing.T) {
+	_, err := checkResp(newErrorResponse(401, "not xml"), nil)
+
+	var vcdErr *VCDError
+	if !errors.As(err, &vcdErr) {
+		t.Fatalf("expected a *VCDError, got %T", err)
+	}
+	if vcdErr.HTTPStatus != 401 {
+		t.Fatalf("expected HTTP status 401, got %d", vcdErr.HTTPStatus)
+	}
+	if !errors.Is(err, ErrUnauthorized) {
+		t.Fatal("expected error to match ErrUnauthorized")
+	}
+}
+
+func TestVCDError_Is(t *testing.T) {
+	busy := &VCDError{HTTPStatus: 400, MajorErrorCode: 400, Message: "The entity gateway is busy completing an operation."}
+	if !IsBusy(busy) {
+		t.Fatal("expected busy message to match ErrBusy")
+	}
+	busy = &VCDError{HTTPStatus: 400, MinorErrorCode: "BUSY_ENTITY"}
+	if !errors.Is(busy, ErrBusy) {
+		t.Fatal("expected BUSY_ENTITY to match ErrBusy")
+	}
+	conflict := &VCDError{HTTPStatus: 409}
+	if !errors.Is(conflict, ErrConflict) {
+		t.Fatal("expected 409 to match ErrConflict")
+	}
+	if conflict.Error() != "API Error: 409: " {
+		t.Fatalf("unexpected error message: %s", conflict)
+	}
+}
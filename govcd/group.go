@@ -0,0 +1,258 @@
+/*
+ * Copyright 2014 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"strings"
+
+	types "github.com/vmware/go-vcloud-director/types/v56"
+)
+
+// OrgGroup is a group of an organization, imported from its LDAP service.
+type OrgGroup struct {
+	Group    *types.Group
+	AdminOrg *AdminOrg
+	c        *Client
+}
+
+// NewGroup returns an empty group of adminOrg.
+func NewGroup(c *Client, adminOrg *AdminOrg) *OrgGroup {
+	return &OrgGroup{
+		Group:    new(types.Group),
+		AdminOrg: adminOrg,
+		c:        c,
+	}
+}
+
+// GetLdapSettings returns the LDAP settings of the org.
+func (adminOrg *AdminOrg) GetLdapSettings() (*types.OrgLdapSettingsType, error) {
+	settings := new(types.OrgLdapSettingsType)
+	if err := adminOrg.getSettings("/ldap", "LDAP settings", settings); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// UpdateLdapSettings sets the LDAP mode of the org, one of
+// types.OrgLdapModeNone, types.OrgLdapModeSystem and
+// types.OrgLdapModeCustom, and returns the resulting settings. The custom
+// mode requires CustomOrgLdapSettings.
+func (adminOrg *AdminOrg) UpdateLdapSettings(settings *types.OrgLdapSettingsType) (*types.OrgLdapSettingsType, error) {
+	switch settings.OrgLdapMode {
+	case types.OrgLdapModeNone, types.OrgLdapModeSystem:
+	case types.OrgLdapModeCustom:
+		if settings.CustomOrgLdapSettings == nil {
+			return nil, fmt.Errorf("LDAP mode %s requires custom LDAP settings", settings.OrgLdapMode)
+		}
+	default:
+		return nil, fmt.Errorf("unknown LDAP mode %q", settings.OrgLdapMode)
+	}
+	updated := new(types.OrgLdapSettingsType)
+	if err := adminOrg.updateSettings("/ldap", "LDAP settings", "OrgLdapSettings",
+		types.MimeOrgLdapSettings, settings, updated); err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// ImportGroup imports the LDAP group having the given name into the org,
+// giving its users the role having the given name. The org must use an
+// LDAP service, see UpdateLdapSettings.
+func (adminOrg *AdminOrg) ImportGroup(name, roleName string) (*OrgGroup, error) {
+	role, err := adminOrg.GetRoleReference(roleName)
+	if err != nil {
+		return nil, err
+	}
+	groupsHREF, err := url.ParseRequestURI(adminOrg.AdminOrg.HREF)
+	if err != nil {
+		return nil, fmt.Errorf("error getting AdminOrg HREF %s : %w", adminOrg.AdminOrg.HREF, err)
+	}
+	groupsHREF.Path += "/groups"
+
+	group := NewGroup(adminOrg.c, adminOrg)
+	groupConfiguration := &types.Group{
+		Name:         name,
+		NameInSource: name,
+		Role:         role,
+		ProviderType: "INTEGRATED",
+	}
+	if err = group.send("POST", *groupsHREF, groupConfiguration); err != nil {
+		return nil, fmt.Errorf("error importing group %s: %w", name, err)
+	}
+	return group, nil
+}
+
+// GetGroups returns the references to the groups of the org.
+func (adminOrg *AdminOrg) GetGroups() ([]*types.Reference, error) {
+	if err := adminOrg.Refresh(); err != nil {
+		return nil, err
+	}
+	if adminOrg.AdminOrg.Groups == nil {
+		return nil, nil
+	}
+	return adminOrg.AdminOrg.Groups.Group, nil
+}
+
+// GetGroupByName returns the group of the org having the given name. The
+// returned error matches ErrNotFound when the org has no such group.
+func (adminOrg *AdminOrg) GetGroupByName(name string) (*OrgGroup, error) {
+	groups, err := adminOrg.GetGroups()
+	if err != nil {
+		return nil, err
+	}
+	for _, reference := range groups {
+		if reference.Name == name {
+			return adminOrg.getGroupByHREF(reference.HREF)
+		}
+	}
+	return nil, fmt.Errorf("group %s in org %s: %w", name, adminOrg.AdminOrg.Name, ErrNotFound)
+}
+
+// GetGroupById returns the group having the given ID, either a URN such as
+// "urn:vcloud:group:<uuid>" or the bare UUID.
+func (adminOrg *AdminOrg) GetGroupById(id string) (*OrgGroup, error) {
+	groupHREF := adminOrg.c.VCDHREF
+	groupHREF.Path += "/admin/group/" + id[strings.LastIndex(id, ":")+1:]
+	return adminOrg.getGroupByHREF(groupHREF.String())
+}
+
+func (adminOrg *AdminOrg) getGroupByHREF(href string) (*OrgGroup, error) {
+	group := NewGroup(adminOrg.c, adminOrg)
+	group.Group.HREF = href
+	if err := group.Refresh(); err != nil {
+		return nil, err
+	}
+	return group, nil
+}
+
+// Refresh fetches the current state of the group.
+func (group *OrgGroup) Refresh() error {
+	groupHREF, err := url.ParseRequestURI(group.Group.HREF)
+	if err != nil {
+		return fmt.Errorf("error getting group HREF %s : %w", group.Group.HREF, err)
+	}
+	req := group.c.NewRequest(map[string]string{}, "GET", *groupHREF, nil)
+	resp, err := group.c.doRequest(req)
+	if err != nil {
+		return fmt.Errorf("error retrieving group: %w", err)
+	}
+	refreshed := new(types.Group)
+	if err = decodeBody(resp, refreshed); err != nil {
+		return fmt.Errorf("error decoding group response: %w", err)
+	}
+	group.Group = refreshed
+	return nil
+}
+
+// Update sends the changes made to group.Group, such as the description or
+// the role, to vCD.
+func (group *OrgGroup) Update() error {
+	groupHREF, err := url.ParseRequestURI(group.Group.HREF)
+	if err != nil {
+		return fmt.Errorf("error getting group HREF %s : %w", group.Group.HREF, err)
+	}
+	if err = group.send("PUT", *groupHREF, group.Group); err != nil {
+		return fmt.Errorf("error updating group %s: %w", group.Group.Name, err)
+	}
+	return nil
+}
+
+// send sends groupConfiguration to href and stores the group returned by
+// vCD.
+func (group *OrgGroup) send(method string, href url.URL, groupConfiguration *types.Group) error {
+	groupConfiguration.Xmlns = "http://www.vmware.com/vcloud/v1.5"
+	output, _ := xml.MarshalIndent(groupConfiguration, "  ", "    ")
+	xmlData := bytes.NewBufferString(xml.Header + string(output))
+
+	req := group.c.NewRequest(map[string]string{}, method, href, xmlData)
+	req.Header.Add("Content-Type", types.MimeAdminGroup)
+	resp, err := group.c.doRequest(req)
+	if err != nil {
+		return err
+	}
+	updated := new(types.Group)
+	if err = decodeBody(resp, updated); err != nil {
+		return fmt.Errorf("error decoding group response: %w", err)
+	}
+	group.Group = updated
+	return nil
+}
+
+// ChangeRole gives the users of the group the role having the given name.
+func (group *OrgGroup) ChangeRole(roleName string) error {
+	role, err := group.AdminOrg.GetRoleReference(roleName)
+	if err != nil {
+		return err
+	}
+	group.Group.Role = role
+	return group.Update()
+}
+
+// GetUsers returns the users of the group known to the org. LDAP users are
+// only known once they have logged in or have been imported.
+func (group *OrgGroup) GetUsers() ([]*OrgUser, error) {
+	var users []*OrgUser
+	if group.Group.UsersList == nil {
+		return users, nil
+	}
+	for _, reference := range group.Group.UsersList.UserReference {
+		user, err := group.AdminOrg.getUserByHREF(reference.HREF)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	return users, nil
+}
+
+// HasUser tells whether the user having the given name is a member of the
+// group.
+func (group *OrgGroup) HasUser(userName string) bool {
+	if group.Group.UsersList == nil {
+		return false
+	}
+	for _, reference := range group.Group.UsersList.UserReference {
+		if reference.Name == userName {
+			return true
+		}
+	}
+	return false
+}
+
+// Delete removes the group from the org. The users of the group stay in
+// the org.
+func (group *OrgGroup) Delete() error {
+	groupHREF, err := url.ParseRequestURI(group.Group.HREF)
+	if err != nil {
+		return fmt.Errorf("error getting group HREF %s : %w", group.Group.HREF, err)
+	}
+	req := group.c.NewRequest(map[string]string{}, "DELETE", *groupHREF, nil)
+	resp, err := group.c.doRequest(req)
+	if err != nil {
+		return fmt.Errorf("error deleting group %s: %w", group.Group.Name, err)
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// GetGroups returns the groups the user is a member of.
+func (user *OrgUser) GetGroups() ([]*OrgGroup, error) {
+	var groups []*OrgGroup
+	if user.User.GroupReferences == nil {
+		return groups, nil
+	}
+	for _, reference := range user.User.GroupReferences.GroupReference {
+		group, err := user.AdminOrg.getGroupByHREF(reference.HREF)
+		if err != nil {
+			return nil, err
+		}
+		groups = append(groups, group)
+	}
+	return groups, nil
+}
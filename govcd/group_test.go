@@ -0,0 +1,137 @@
+/*
+ * Copyright 2014 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"errors"
+	"testing"
+
+	types "github.com/vmware/go-vcloud-director/types/v56"
+	. "gopkg.in/check.v1"
+)
+
+// Checks that the LDAP settings of the test org can be read and written
+// back unchanged.
+func (vcd *TestVCD) Test_LdapSettings(check *C) {
+	adminOrg, err := GetAdminOrgByName(vcd.client, vcd.config.VCD.Org)
+	check.Assert(err, IsNil)
+	settings, err := adminOrg.GetLdapSettings()
+	check.Assert(err, IsNil)
+	if settings.OrgLdapMode == types.OrgLdapModeCustom {
+		check.Skip("the custom LDAP settings need the LDAP password to be written back")
+	}
+	updated, err := adminOrg.UpdateLdapSettings(settings)
+	check.Assert(err, IsNil)
+	check.Assert(updated.OrgLdapMode, Equals, settings.OrgLdapMode)
+}
+
+func TestAdminOrg_GroupsFakeVCD(t *testing.T) {
+	server, _, client := newFakeVCD(t)
+	defer server.Close()
+
+	fakeOrg := server.AddOrg("ldap-org")
+	fakeOrg.AddUser("bob", "vApp User")
+	fakeOrg.AddLdapGroup("developers", "alice", "bob")
+
+	adminOrg, err := GetAdminOrgByName(client, "ldap-org")
+	if err != nil {
+		t.Fatalf("error getting admin org: %s", err)
+	}
+	settings, err := adminOrg.GetLdapSettings()
+	if err != nil || settings.OrgLdapMode != types.OrgLdapModeNone {
+		t.Fatalf("expected LDAP mode NONE, got %+v, %v", settings, err)
+	}
+	if _, err = adminOrg.ImportGroup("developers", "vApp Author"); err == nil {
+		t.Fatal("expected an error importing a group without LDAP")
+	}
+
+	if _, err = adminOrg.UpdateLdapSettings(&types.OrgLdapSettingsType{OrgLdapMode: types.OrgLdapModeCustom}); err == nil {
+		t.Fatal("expected an error setting the custom mode without custom settings")
+	}
+	if _, err = adminOrg.UpdateLdapSettings(&types.OrgLdapSettingsType{OrgLdapMode: "LDAP"}); err == nil {
+		t.Fatal("expected an error setting an unknown mode")
+	}
+	server.ResetRequests()
+	settings, err = adminOrg.UpdateLdapSettings(&types.OrgLdapSettingsType{
+		OrgLdapMode: types.OrgLdapModeCustom,
+		CustomOrgLdapSettings: &types.CustomOrgLdapSettings{
+			HostName:                "ldap.example.com",
+			Port:                    389,
+			SearchBase:              "dc=example,dc=com",
+			AuthenticationMechanism: "SIMPLE",
+			ConnectorType:           "OPEN_LDAP",
+			Username:                "cn=admin,dc=example,dc=com",
+			Password:                "secret",
+		},
+	})
+	if err != nil {
+		t.Fatalf("error updating LDAP settings: %s", err)
+	}
+	checkRequests(t, server, apiRequest{
+		Method: "PUT", Path: "/admin/org/[^/]+/settings/ldap", ContentType: "application/vnd.vmware.admin.organizationLdapSettings+xml",
+		Body: []string{"<OrgLdapSettings ", "<OrgLdapMode>CUSTOM</OrgLdapMode>", "<HostName>ldap.example.com</HostName>"},
+	})
+	if settings.OrgLdapMode != types.OrgLdapModeCustom || settings.CustomOrgLdapSettings.HostName != "ldap.example.com" {
+		t.Fatalf("unexpected LDAP settings: %+v", settings)
+	}
+
+	if _, err = adminOrg.ImportGroup("testers", "vApp Author"); err == nil {
+		t.Fatal("expected an error importing a group missing from LDAP")
+	}
+	server.ResetRequests()
+	group, err := adminOrg.ImportGroup("developers", "vApp Author")
+	if err != nil {
+		t.Fatalf("error importing group: %s", err)
+	}
+	checkRequests(t, server, apiRequest{
+		Method: "POST", Path: "/admin/org/[^/]+/groups", ContentType: "application/vnd.vmware.admin.group+xml",
+		Body: []string{"<Group ", `name="developers"`, "<Role href="},
+	})
+	if group.Group.Role.Name != "vApp Author" || !group.HasUser("alice") || !group.HasUser("bob") || group.HasUser("carol") {
+		t.Fatalf("unexpected imported group: %+v", group.Group)
+	}
+
+	if err = group.ChangeRole("Catalog Author"); err != nil {
+		t.Fatalf("error changing group role: %s", err)
+	}
+	groups, err := adminOrg.GetGroups()
+	if err != nil || len(groups) != 1 || groups[0].Name != "developers" {
+		t.Fatalf("unexpected groups: %v, %v", groups, err)
+	}
+	byID, err := adminOrg.GetGroupById(group.Group.ID)
+	if err != nil || byID.Group.Role.Name != "Catalog Author" {
+		t.Fatalf("error getting group by id: %v", err)
+	}
+	users, err := byID.GetUsers()
+	if err != nil || len(users) != 2 {
+		t.Fatalf("expected the 2 users of the group, got %d, %v", len(users), err)
+	}
+
+	alice, err := adminOrg.GetUserByName("alice")
+	if err != nil || !alice.User.IsExternal {
+		t.Fatalf("expected alice to be imported from LDAP: %v", err)
+	}
+	aliceGroups, err := alice.GetGroups()
+	if err != nil || len(aliceGroups) != 1 || aliceGroups[0].Group.Name != "developers" {
+		t.Fatalf("unexpected groups of alice: %v, %v", aliceGroups, err)
+	}
+
+	byName, err := adminOrg.GetGroupByName("developers")
+	if err != nil {
+		t.Fatalf("error getting group by name: %s", err)
+	}
+	if err = byName.Delete(); err != nil {
+		t.Fatalf("error deleting group: %s", err)
+	}
+	if _, err = adminOrg.GetGroupByName("developers"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound for a deleted group, got %v", err)
+	}
+	if err = alice.Refresh(); err != nil {
+		t.Fatalf("error refreshing user: %s", err)
+	}
+	if aliceGroups, err = alice.GetGroups(); err != nil || len(aliceGroups) != 0 {
+		t.Fatalf("expected alice to have no group left, got %v, %v", aliceGroups, err)
+	}
+}
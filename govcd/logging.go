@@ -0,0 +1,182 @@
+/*
+ * Copyright 2014 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vmware/go-vcloud-director/util"
+)
+
+// LogLevel is the severity of a log message. Higher levels are more
+// verbose.
+type LogLevel int
+
+const (
+	LogError LogLevel = iota
+	LogWarn
+	LogInfo
+	LogDebug
+	LogTrace
+)
+
+func (l LogLevel) String() string {
+	switch l {
+	case LogError:
+		return "ERROR"
+	case LogWarn:
+		return "WARN"
+	case LogInfo:
+		return "INFO"
+	case LogDebug:
+		return "DEBUG"
+	case LogTrace:
+		return "TRACE"
+	}
+	return "LEVEL(" + strconv.Itoa(int(l)) + ")"
+}
+
+// Logger receives the log messages of a client. keyvals holds alternating
+// keys and values giving the details of the message, e.g. "method", "GET".
+type Logger interface {
+	Log(level LogLevel, msg string, keyvals ...interface{})
+}
+
+// NewStdLogger returns a Logger writing the messages up to level to l, or
+// to the standard logger when l is nil. Messages are prefixed with their
+// level in brackets, e.g. "[DEBUG]", which log filters such as
+// hashicorp/logutils rely on.
+func NewStdLogger(l *log.Logger, level LogLevel) Logger {
+	return &stdLogger{logger: l, level: level}
+}
+
+// DiscardLogger returns a Logger ignoring every message.
+func DiscardLogger() Logger {
+	return discardLogger{}
+}
+
+// defaultLogger is used by clients without a logger. It sends the errors
+// and warnings to the standard logger, the requests are only logged with a
+// logger set explicitly or by WithBodyLogging.
+var defaultLogger = NewStdLogger(nil, LogWarn)
+
+type stdLogger struct {
+	logger *log.Logger
+	level  LogLevel
+}
+
+func (l *stdLogger) Log(level LogLevel, msg string, keyvals ...interface{}) {
+	if level > l.level {
+		return
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "[%s] %s", level, msg)
+	for i := 0; i < len(keyvals); i += 2 {
+		var value interface{} = "(MISSING)"
+		if i+1 < len(keyvals) {
+			value = keyvals[i+1]
+		}
+		s := fmt.Sprint(value)
+		switch {
+		case strings.Contains(s, "\n"):
+			// Bodies are easier to read on their own lines
+			fmt.Fprintf(&b, " %v=\n%s\n", keyvals[i], s)
+		case s == "" || strings.ContainsAny(s, " \"="):
+			fmt.Fprintf(&b, " %v=%q", keyvals[i], s)
+		default:
+			fmt.Fprintf(&b, " %v=%s", keyvals[i], s)
+		}
+	}
+	if l.logger == nil {
+		log.Print(b.String())
+		return
+	}
+	l.logger.Print(b.String())
+}
+
+type discardLogger struct{}
+
+func (discardLogger) Log(LogLevel, string, ...interface{}) {}
+
+// logger returns the logger of the client, or the default logger.
+func (c *Client) logger() Logger {
+	if c.Logger == nil {
+		return defaultLogger
+	}
+	return c.Logger
+}
+
+// log sends a message to the logger of the client.
+func (c *Client) log(level LogLevel, msg string, keyvals ...interface{}) {
+	c.logger().Log(level, msg, keyvals...)
+}
+
+// logRequest logs req at debug level. With body logging enabled, the
+// redacted headers and body are logged at trace level.
+func (c *Client) logRequest(req *http.Request) {
+	c.log(LogDebug, "sending request", "method", req.Method, "url", req.URL.String())
+	if !c.LogBodies {
+		return
+	}
+	keyvals := []interface{}{"method", req.Method, "url", req.URL.String(), "header", formatHeader(req.Header)}
+	if req.GetBody != nil && isTextContent(req.Header) {
+		if body, err := req.GetBody(); err == nil {
+			data, _ := ioutil.ReadAll(body)
+			body.Close()
+			keyvals = append(keyvals, "body", util.RedactXML(string(data)))
+		}
+	}
+	c.log(LogTrace, "request details", keyvals...)
+}
+
+// logResponse logs the outcome of a request at debug level. With body
+// logging enabled, the redacted headers and body of the response are logged
+// at trace level; the body is read and replaced with a copy.
+func (c *Client) logResponse(req *http.Request, resp *http.Response, err error, elapsed time.Duration) {
+	if resp == nil {
+		c.log(LogDebug, "request failed", "method", req.Method, "url", req.URL.String(), "duration", elapsed, "error", err)
+		return
+	}
+	c.log(LogDebug, "received response", "method", req.Method, "url", req.URL.String(), "status", resp.StatusCode, "duration", elapsed)
+	if !c.LogBodies {
+		return
+	}
+	keyvals := []interface{}{"method", req.Method, "url", req.URL.String(), "status", resp.StatusCode, "header", formatHeader(resp.Header)}
+	if resp.Body != nil && isTextContent(resp.Header) {
+		data, readErr := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		resp.Body = ioutil.NopCloser(bytes.NewReader(data))
+		if readErr == nil {
+			keyvals = append(keyvals, "body", util.RedactXML(string(data)))
+		}
+	}
+	c.log(LogTrace, "response details", keyvals...)
+}
+
+// isTextContent reports whether the body described by header is worth
+// logging, as opposed to the content of uploaded files.
+func isTextContent(header http.Header) bool {
+	contentType := header.Get("Content-Type")
+	return contentType == "" || strings.Contains(contentType, "xml") || strings.Contains(contentType, "json")
+}
+
+// formatHeader returns the redacted header as a single line.
+func formatHeader(header http.Header) string {
+	redacted := util.RedactHeader(header)
+	var lines []string
+	for name, values := range redacted {
+		lines = append(lines, name+": "+strings.Join(values, ", "))
+	}
+	sort.Strings(lines)
+	return strings.Join(lines, "; ")
+}
@@ -0,0 +1,113 @@
+/*
+ * Copyright 2014 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"bytes"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestStdLogger(t *testing.T) {
+	var out bytes.Buffer
+	logger := NewStdLogger(log.New(&out, "", 0), LogDebug)
+
+	logger.Log(LogDebug, "sending request", "method", "GET", "error", "not found", "odd")
+	logger.Log(LogTrace, "hidden")
+
+	expected := `[DEBUG] sending request method=GET error="not found" odd=(MISSING)` + "\n"
+	if out.String() != expected {
+		t.Fatalf("expected %q, got %q", expected, out.String())
+	}
+}
+
+// Checks that a client without a logger only reports warnings and errors.
+func TestClient_defaultLogger(t *testing.T) {
+	var out bytes.Buffer
+	log.SetOutput(&out)
+	defer log.SetOutput(os.Stderr)
+
+	client := &Client{}
+	client.log(LogDebug, "sending request", "method", "GET")
+	client.log(LogWarn, "request failed, retrying", "method", "GET")
+	if logged := out.String(); strings.Contains(logged, "sending request") || !strings.Contains(logged, "[WARN] request failed, retrying") {
+		t.Fatalf("unexpected default log:\n%s", logged)
+	}
+}
+
+func TestClient_doRequestLogsRedacted(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/vnd.vmware.vcloud.guestCustomizationSection+xml")
+		w.Header().Set("x-vcloud-authorization", "response-token")
+		_, _ = w.Write([]byte("<GuestCustomizationSection><AdminPassword>response-secret</AdminPassword></GuestCustomizationSection>"))
+	}))
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+
+	for _, logBodies := range []bool{false, true} {
+		var out bytes.Buffer
+		client := &Client{
+			VCDHREF:       *u,
+			VCDToken:      "request-token",
+			VCDAuthHeader: "x-vcloud-authorization",
+			Logger:        NewStdLogger(log.New(&out, "", 0), LogTrace),
+			LogBodies:     logBodies,
+		}
+		body := "<GuestCustomizationSection><DomainUserPassword>request-secret</DomainUserPassword></GuestCustomizationSection>"
+		req := client.NewRequest(map[string]string{}, "PUT", client.VCDHREF, strings.NewReader(body))
+		req.SetBasicAuth("user@org", "basic-secret")
+		resp, err := client.doRequest(req)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		// The body is still readable after being logged
+		data, _ := ioutil.ReadAll(resp.Body)
+		if !strings.Contains(string(data), "response-secret") {
+			t.Fatalf("response body was not preserved: %s", data)
+		}
+
+		logged := out.String()
+		for _, secret := range []string{"request-token", "response-token", "request-secret", "response-secret", "basic-secret"} {
+			if strings.Contains(logged, secret) {
+				t.Fatalf("log contains %s:\n%s", secret, logged)
+			}
+		}
+		if !strings.Contains(logged, "[DEBUG] received response method=PUT") {
+			t.Fatalf("response not logged:\n%s", logged)
+		}
+		if strings.Contains(logged, "GuestCustomizationSection") != logBodies {
+			t.Fatalf("expected bodies to be logged: %t, got:\n%s", logBodies, logged)
+		}
+	}
+}
+
+// Checks that WithBodyLogging is enough to see the bodies, while keeping a
+// logger set with WithLogger.
+func TestWithBodyLogging(t *testing.T) {
+	var out bytes.Buffer
+	log.SetOutput(&out)
+	defer log.SetOutput(os.Stderr)
+
+	client := &VCDClient{}
+	WithBodyLogging()(client)
+	client.Client.log(LogTrace, "request body")
+	if !client.Client.LogBodies || !strings.Contains(out.String(), "[TRACE] request body") {
+		t.Fatalf("body logging is not visible without a logger:\n%s", out.String())
+	}
+
+	logger := DiscardLogger()
+	client = &VCDClient{}
+	WithLogger(logger)(client)
+	WithBodyLogging()(client)
+	if client.Client.Logger != logger {
+		t.Fatalf("WithBodyLogging replaced the logger set with WithLogger")
+	}
+}
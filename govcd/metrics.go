@@ -0,0 +1,161 @@
+/*
+ * Copyright 2014 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	types "github.com/vmware/go-vcloud-director/types/v56"
+)
+
+// MetricsRegistry creates the metrics collected by a client. It is
+// implemented by the caller on top of its metrics library, so that govcd
+// does not depend on one. With Prometheus, NewCounter registers a
+// CounterVec and returns an adapter calling WithLabelValues(...).Inc().
+type MetricsRegistry interface {
+	// NewCounter returns a counter partitioned by the given labels.
+	NewCounter(name, help string, labelNames []string) CounterVec
+	// NewHistogram returns a histogram, of durations in seconds,
+	// partitioned by the given labels.
+	NewHistogram(name, help string, labelNames []string) HistogramVec
+}
+
+// CounterVec is a counter partitioned by labels.
+type CounterVec interface {
+	// Inc increments the counter having the label values, given in the
+	// order of the label names.
+	Inc(labelValues ...string)
+}
+
+// HistogramVec is a histogram partitioned by labels.
+type HistogramVec interface {
+	// Observe adds value to the histogram having the label values, given
+	// in the order of the label names.
+	Observe(value float64, labelValues ...string)
+}
+
+// Names of the metrics created by RegisterMetrics.
+const (
+	MetricRequestsTotal   = "govcd_requests_total"
+	MetricRequestDuration = "govcd_request_duration_seconds"
+	MetricAPIErrorsTotal  = "govcd_api_errors_total"
+	MetricTaskDuration    = "govcd_task_duration_seconds"
+	MetricRetriesTotal    = "govcd_retries_total"
+)
+
+// clientMetrics holds the metrics of a client.
+type clientMetrics struct {
+	requests        CounterVec
+	requestDuration HistogramVec
+	apiErrors       CounterVec
+	taskDuration    HistogramVec
+	retries         CounterVec
+}
+
+func newClientMetrics(registry MetricsRegistry) *clientMetrics {
+	return &clientMetrics{
+		requests: registry.NewCounter(MetricRequestsTotal,
+			"Number of vCD API requests, by method, resource type and HTTP status.",
+			[]string{"method", "resource", "status"}),
+		requestDuration: registry.NewHistogram(MetricRequestDuration,
+			"Duration of vCD API requests, by method and resource type.",
+			[]string{"method", "resource"}),
+		apiErrors: registry.NewCounter(MetricAPIErrorsTotal,
+			"Number of errors returned by the vCD API, by major error code.",
+			[]string{"major_error_code"}),
+		taskDuration: registry.NewHistogram(MetricTaskDuration,
+			"Duration of the vCD tasks waited for, by operation and final status.",
+			[]string{"operation", "status"}),
+		retries: registry.NewCounter(MetricRetriesTotal,
+			"Number of requests retried, by reason: busy, the HTTP status, or reauthentication.",
+			[]string{"reason"}),
+	}
+}
+
+// middleware returns the middleware measuring requests and API errors.
+func (m *clientMetrics) middleware() Middleware {
+	return TimingMiddleware(func(timing RequestTiming) {
+		resource := resourceType(timing.Endpoint)
+		m.requests.Inc(timing.Method, resource, strconv.Itoa(timing.StatusCode))
+		m.requestDuration.Observe(timing.Duration.Seconds(), timing.Method, resource)
+		var vcdErr *VCDError
+		if errors.As(timing.Err, &vcdErr) {
+			code := vcdErr.MajorErrorCode
+			if code == 0 {
+				code = vcdErr.HTTPStatus
+			}
+			m.apiErrors.Inc(strconv.Itoa(code))
+		}
+	})
+}
+
+// observeRetry counts a retry caused by err.
+func (m *clientMetrics) observeRetry(err error) {
+	if m == nil {
+		return
+	}
+	reason := "other"
+	var vcdErr *VCDError
+	switch {
+	case errors.Is(err, ErrUnauthorized):
+		reason = "reauthentication"
+	case IsBusy(err):
+		reason = "busy"
+	case errors.As(err, &vcdErr):
+		reason = strconv.Itoa(vcdErr.HTTPStatus)
+	}
+	m.retries.Inc(reason)
+}
+
+// observeTask records the duration of a finished task. The times reported
+// by vCD are used when available, otherwise the time spent waiting.
+func (m *clientMetrics) observeTask(task *types.Task, waited time.Duration) {
+	if m == nil || task == nil {
+		return
+	}
+	duration := waited
+	start, startErr := time.Parse(time.RFC3339, task.StartTime)
+	end, endErr := time.Parse(time.RFC3339, task.EndTime)
+	if startErr == nil && endErr == nil && !end.Before(start) {
+		duration = end.Sub(start)
+	}
+	operation := task.OperationName
+	if operation == "" {
+		operation = task.Operation
+	}
+	m.taskDuration.Observe(duration.Seconds(), operation, task.Status)
+}
+
+// resourceType returns the type of resource an endpoint, as returned by
+// endpointOf, refers to, e.g. "vApp", "vm" or "admin/org".
+func resourceType(endpoint string) string {
+	parts := strings.Split(strings.TrimPrefix(endpoint, "/api/"), "/")
+	var prefix []string
+	for len(parts) > 1 && (parts[0] == "admin" || parts[0] == "extension") {
+		prefix = append(prefix, parts[0])
+		parts = parts[1:]
+	}
+	resource := parts[0]
+	if len(parts) > 1 && (resource == "vApp" || resource == "vAppTemplate") && strings.HasPrefix(parts[1], "vm-") {
+		resource = "vm"
+	}
+	return strings.Join(append(prefix, resource), "/")
+}
+
+// RegisterMetrics creates the client metrics in registry and starts
+// collecting them:
+//
+//	govcd_requests_total{method, resource, status}
+//	govcd_request_duration_seconds{method, resource}
+//	govcd_api_errors_total{major_error_code}
+//	govcd_task_duration_seconds{operation, status}
+//	govcd_retries_total{reason}
+func (c *VCDClient) RegisterMetrics(registry MetricsRegistry) {
+	c.Client.metrics = newClientMetrics(registry)
+	c.Use(c.Client.metrics.middleware())
+}
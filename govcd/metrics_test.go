@@ -0,0 +1,122 @@
+/*
+ * Copyright 2014 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	types "github.com/vmware/go-vcloud-director/types/v56"
+)
+
+// testRegistry is a MetricsRegistry keeping the counts and the number of
+// observations of every metric, by name and label values.
+type testRegistry struct {
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+type testMetric struct {
+	registry *testRegistry
+	name     string
+}
+
+func (r *testRegistry) NewCounter(name, help string, labelNames []string) CounterVec {
+	return &testMetric{registry: r, name: name}
+}
+
+func (r *testRegistry) NewHistogram(name, help string, labelNames []string) HistogramVec {
+	return &testMetric{registry: r, name: name}
+}
+
+func (m *testMetric) Inc(labelValues ...string) {
+	m.Observe(1, labelValues...)
+}
+
+func (m *testMetric) Observe(value float64, labelValues ...string) {
+	m.registry.mu.Lock()
+	defer m.registry.mu.Unlock()
+	m.registry.counts[m.name+"{"+strings.Join(labelValues, ",")+"}"]++
+}
+
+func (r *testRegistry) count(name string, labelValues ...string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.counts[name+"{"+strings.Join(labelValues, ",")+"}"]
+}
+
+func TestVCDClient_RegisterMetricsFakeVCD(t *testing.T) {
+	server, fakeVdc, client := newFakeVCD(t)
+	defer server.Close()
+	server.TaskPolls = 1
+	fakeVdc.AddVApp("vapp")
+	registry := &testRegistry{counts: map[string]int{}}
+	client.RegisterMetrics(registry)
+	_, vdc := fakeOrgVdc(t, client)
+
+	vapp, err := vdc.FindVAppByName("vapp")
+	if err != nil {
+		t.Fatalf("error finding vapp: %s", err)
+	}
+	server.InjectError(http.MethodPost, "/power/action/powerOn", 1, http.StatusBadRequest, types.Error{
+		MajorErrorCode: http.StatusBadRequest,
+		MinorErrorCode: "BUSY_ENTITY",
+		Message:        "The entity vapp is busy completing an operation.",
+	})
+	task, err := vapp.PowerOn()
+	if err != nil {
+		t.Fatalf("error powering on: %s", err)
+	}
+	if err = task.WaitTaskCompletionWithOptions(context.Background(), TaskWaitOptions{PollInterval: time.Millisecond}); err != nil {
+		t.Fatalf("error waiting for power on: %s", err)
+	}
+	server.InjectError(http.MethodGet, vdc.Vdc.HREF[strings.LastIndex(vdc.Vdc.HREF, "/"):], 1, http.StatusForbidden, types.Error{
+		MajorErrorCode: http.StatusForbidden,
+		MinorErrorCode: "ACCESS_TO_RESOURCE_IS_FORBIDDEN",
+		Message:        "Access is forbidden.",
+	})
+	if err = vdc.Refresh(); err == nil {
+		t.Fatal("expected the refresh to fail")
+	}
+
+	for _, expected := range []struct {
+		name   string
+		labels []string
+		count  int
+	}{
+		{MetricRequestsTotal, []string{"POST", "vApp", "400"}, 1},
+		{MetricRequestsTotal, []string{"POST", "vApp", "202"}, 1},
+		{MetricRequestDuration, []string{"POST", "vApp"}, 2},
+		{MetricRequestsTotal, []string{"GET", "vdc", "403"}, 1},
+		{MetricAPIErrorsTotal, []string{"400"}, 1},
+		{MetricAPIErrorsTotal, []string{"403"}, 1},
+		{MetricRetriesTotal, []string{"busy"}, 1},
+		{MetricTaskDuration, []string{"vappPowerOn", "success"}, 1},
+	} {
+		if count := registry.count(expected.name, expected.labels...); count != expected.count {
+			t.Errorf("expected %s%v to be %d, got %d", expected.name, expected.labels, expected.count, count)
+		}
+	}
+}
+
+func TestResourceType(t *testing.T) {
+	for endpoint, expected := range map[string]string{
+		"/api/vApp/vapp-{id}/power/action/powerOn": "vApp",
+		"/api/vApp/vm-{id}/action/deploy":          "vm",
+		"/api/vdc/{id}":                            "vdc",
+		"/api/admin/org/{id}":                      "admin/org",
+		"/api/admin/extension/providervdc/{id}":    "admin/extension/providervdc",
+		"/api/admin":                               "admin",
+		"/api/sessions":                            "sessions",
+	} {
+		if resource := resourceType(endpoint); resource != expected {
+			t.Errorf("expected %s for %s, got %s", expected, endpoint, resource)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+/*
+ * Copyright 2014 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"regexp"
+	"sync"
+	"time"
+)
+
+// RequestHandler sends a request to vCD. The error is a *VCDError when vCD
+// answered with an error status.
+type RequestHandler func(req *http.Request) (*http.Response, error)
+
+// Middleware wraps the handler sending the requests of a client. It can
+// change the request before calling next, and observe or change the
+// response after. Middlewares run for every attempt of a request, retries
+// included.
+type Middleware func(next RequestHandler) RequestHandler
+
+// BeforeRequest returns a middleware calling hook before every request is
+// sent. The request is not sent when hook returns an error.
+func BeforeRequest(hook func(req *http.Request) error) Middleware {
+	return func(next RequestHandler) RequestHandler {
+		return func(req *http.Request) (*http.Response, error) {
+			if err := hook(req); err != nil {
+				return nil, err
+			}
+			return next(req)
+		}
+	}
+}
+
+// AfterResponse returns a middleware calling hook with the outcome of every
+// request.
+func AfterResponse(hook func(req *http.Request, resp *http.Response, err error)) Middleware {
+	return func(next RequestHandler) RequestHandler {
+		return func(req *http.Request) (*http.Response, error) {
+			resp, err := next(req)
+			hook(req, resp, err)
+			return resp, err
+		}
+	}
+}
+
+// RequestIDHeader is the header vCD records as the client request ID in
+// its logs and tasks.
+const RequestIDHeader = "X-VMWARE-VCLOUD-CLIENT-REQUEST-ID"
+
+// RequestIDMiddleware sets a random RequestIDHeader on the requests that
+// don't have one, so that they can be correlated with the vCD logs. All the
+// attempts of a request share its ID.
+func RequestIDMiddleware() Middleware {
+	return BeforeRequest(func(req *http.Request) error {
+		if req.Header.Get(RequestIDHeader) == "" {
+			req.Header.Set(RequestIDHeader, newRequestID())
+		}
+		return nil
+	})
+}
+
+func newRequestID() string {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(id)
+}
+
+// RequestTiming describes a request measured by TimingMiddleware.
+type RequestTiming struct {
+	Method string
+	// Endpoint is the request path with the identifiers replaced by
+	// "{id}", e.g. "/api/vApp/vapp-{id}/power/action/powerOn", so that the
+	// requests to the same endpoint can be grouped.
+	Endpoint string
+	// StatusCode is the HTTP status of the response, zero when no response
+	// was received.
+	StatusCode int
+	Duration   time.Duration
+	Err        error
+}
+
+// TimingMiddleware returns a middleware calling observe with the duration
+// and the outcome of every request.
+func TimingMiddleware(observe func(RequestTiming)) Middleware {
+	return func(next RequestHandler) RequestHandler {
+		return func(req *http.Request) (*http.Response, error) {
+			start := time.Now()
+			resp, err := next(req)
+			observe(RequestTiming{
+				Method:     req.Method,
+				Endpoint:   endpointOf(req),
+				StatusCode: statusCodeOf(resp, err),
+				Duration:   time.Since(start),
+				Err:        err,
+			})
+			return resp, err
+		}
+	}
+}
+
+// identifier matches the UUIDs and numeric identifiers in vCD paths.
+var identifier = regexp.MustCompile(`[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}|\b[0-9]+\b`)
+
+// endpointOf returns the path of req with its identifiers replaced.
+func endpointOf(req *http.Request) string {
+	return identifier.ReplaceAllString(req.URL.Path, "{id}")
+}
+
+// statusCodeOf returns the HTTP status of a request outcome.
+func statusCodeOf(resp *http.Response, err error) int {
+	var vcdErr *VCDError
+	if errors.As(err, &vcdErr) {
+		return vcdErr.HTTPStatus
+	}
+	if resp != nil {
+		return resp.StatusCode
+	}
+	return 0
+}
+
+// send sends req once through the middlewares of the client, logging it
+// and checking the response with checkResp. It first waits for the rate
+// limits of the client to allow the request. The request stays in flight
+// until its response body is read to the end or closed.
+func (c *Client) send(req *http.Request) (*http.Response, error) {
+	release, err := c.limiterFor(req).acquire(req.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	handler := func(req *http.Request) (*http.Response, error) {
+		c.logRequest(req)
+		start := time.Now()
+		resp, err := c.Http.Do(req)
+		c.logResponse(req, resp, err, time.Since(start))
+		return checkResp(resp, err)
+	}
+	for i := len(c.Middlewares) - 1; i >= 0; i-- {
+		handler = c.Middlewares[i](handler)
+	}
+	resp, err := handler(req)
+	if err != nil || resp == nil || resp.Body == nil {
+		release()
+		return resp, err
+	}
+	resp.Body = &releasingBody{ReadCloser: resp.Body, release: release}
+	return resp, nil
+}
+
+// releasingBody is a response body releasing the in-flight slot of its
+// request once read to the end or closed, whichever comes first.
+type releasingBody struct {
+	io.ReadCloser
+	once    sync.Once
+	release func()
+}
+
+func (b *releasingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if err != nil {
+		b.once.Do(b.release)
+	}
+	return n, err
+}
+
+func (b *releasingBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.release)
+	return err
+}
@@ -0,0 +1,121 @@
+/*
+ * Copyright 2014 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestClient_sendMiddlewareOrder(t *testing.T) {
+	var received string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Get("X-Correlation-Id")
+	}))
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+
+	var calls []string
+	trace := func(name string) Middleware {
+		return func(next RequestHandler) RequestHandler {
+			return func(req *http.Request) (*http.Response, error) {
+				calls = append(calls, "before "+name)
+				resp, err := next(req)
+				calls = append(calls, "after "+name)
+				return resp, err
+			}
+		}
+	}
+	client := &Client{VCDHREF: *u, Middlewares: []Middleware{
+		trace("outer"),
+		BeforeRequest(func(req *http.Request) error {
+			req.Header.Set("X-Correlation-Id", "correlation")
+			return nil
+		}),
+		trace("inner"),
+	}}
+
+	req := client.NewRequest(map[string]string{}, "GET", client.VCDHREF, nil)
+	if _, err := client.doRequest(req); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if received != "correlation" {
+		t.Fatalf("header set by the middleware not received: %q", received)
+	}
+	expected := []string{"before outer", "before inner", "after inner", "after outer"}
+	if len(calls) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, calls)
+	}
+	for i := range expected {
+		if calls[i] != expected[i] {
+			t.Fatalf("expected %v, got %v", expected, calls)
+		}
+	}
+
+	// An error from a hook stops the request
+	hookErr := errors.New("rejected")
+	client.Middlewares = []Middleware{BeforeRequest(func(*http.Request) error { return hookErr })}
+	received = ""
+	req = client.NewRequest(map[string]string{}, "GET", client.VCDHREF, nil)
+	if _, err := client.doRequest(req); err != hookErr || received != "" {
+		t.Fatalf("expected the request to be stopped, got %v", err)
+	}
+}
+
+func TestRequestIDMiddleware(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond, RetryableStatusCodes: []int{http.StatusServiceUnavailable}}
+	var ids []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ids = append(ids, r.Header.Get(RequestIDHeader))
+		if len(ids) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+	client := &Client{VCDHREF: *u, RetryPolicy: &policy, Middlewares: []Middleware{RequestIDMiddleware()}}
+
+	for i := 0; i < 2; i++ {
+		req := client.NewRequest(map[string]string{}, "GET", client.VCDHREF, nil)
+		if _, err := client.doRequest(req); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+	if len(ids) != 3 || len(ids[0]) != 32 {
+		t.Fatalf("unexpected request IDs %v", ids)
+	}
+	if ids[0] != ids[1] || ids[1] == ids[2] {
+		t.Fatalf("expected retries to share the request ID and requests to have their own: %v", ids)
+	}
+}
+
+func TestTimingMiddleware(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+	u, _ := url.Parse(server.URL + "/api/vApp/vapp-00000001-0000-4000-8000-000000000001/power/action/powerOn")
+
+	var timings []RequestTiming
+	client := &Client{VCDHREF: *u, Middlewares: []Middleware{TimingMiddleware(func(timing RequestTiming) {
+		timings = append(timings, timing)
+	})}}
+	req := client.NewRequest(map[string]string{}, "POST", client.VCDHREF, nil)
+	if _, err := client.doRequest(req); !IsNotFound(err) {
+		t.Fatalf("expected a not found error, got %v", err)
+	}
+	if len(timings) != 1 {
+		t.Fatalf("expected one timing, got %d", len(timings))
+	}
+	timing := timings[0]
+	if timing.Method != "POST" || timing.Endpoint != "/api/vApp/vapp-{id}/power/action/powerOn" ||
+		timing.StatusCode != http.StatusNotFound || !IsNotFound(timing.Err) || timing.Duration <= 0 {
+		t.Fatalf("unexpected timing %+v", timing)
+	}
+}
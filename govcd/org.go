@@ -6,6 +6,7 @@ package govcd
 
 import (
 	"bytes"
+	"context"
 	"encoding/xml"
 	"fmt"
 	types "github.com/vmware/go-vcloud-director/types/v56"
@@ -48,25 +49,62 @@ func NewAdminOrg(c *Client) *AdminOrg {
 	}
 }
 
+// GetTaskList returns the queued, running and recently completed tasks of
+// the org. When runningOnly is true, only queued and running tasks are
+// returned.
+func (org *Org) GetTaskList(runningOnly bool) ([]Task, error) {
+	return org.GetTaskListWithContext(context.Background(), runningOnly)
+}
+
+// GetTaskListWithContext behaves like GetTaskList, aborting if ctx is
+// cancelled before vCD answers.
+func (org *Org) GetTaskListWithContext(ctx context.Context, runningOnly bool) ([]Task, error) {
+	link := org.Org.Link.ForType(types.MimeTasksList, types.RelDown)
+	if link == nil {
+		return nil, fmt.Errorf("can't find the task list link for org %s", org.Org.Name)
+	}
+	tasksHREF, err := url.ParseRequestURI(link.HREF)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing url: %w", err)
+	}
+	req := org.c.NewRequestWithContext(ctx, map[string]string{}, "GET", *tasksHREF, nil)
+	resp, err := org.c.doRequest(req)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving task list: %w", err)
+	}
+
+	taskList := new(types.TasksList)
+	if err = decodeBody(resp, taskList); err != nil {
+		return nil, fmt.Errorf("error decoding task list response: %w", err)
+	}
+	return tasksFromList(org.c, taskList.Task, runningOnly), nil
+}
+
 // If user specifies valid vdc name then this returns a vdc object.
 // If no vdc is found, then it returns an empty vdc and no error.
 // Otherwise it returns an empty vdc and an error.
 func (org *Org) GetVdcByName(vdcname string) (Vdc, error) {
+	return org.GetVdcByNameWithContext(context.Background(), vdcname)
+}
+
+// GetVdcByNameWithContext behaves like GetVdcByName, aborting if ctx is
+// cancelled before vCD answers.
+func (org *Org) GetVdcByNameWithContext(ctx context.Context, vdcname string) (Vdc, error) {
 	for _, link := range org.Org.Link {
 		if link.Name == vdcname {
 			vdcHREF, err := url.ParseRequestURI(link.HREF)
 			if err != nil {
-				return Vdc{}, fmt.Errorf("Error parsing url: %v", err)
+				return Vdc{}, fmt.Errorf("Error parsing url: %w", err)
 			}
-			req := org.c.NewRequest(map[string]string{}, "GET", *vdcHREF, nil)
-			resp, err := checkResp(org.c.Http.Do(req))
+			req := org.c.NewRequestWithContext(ctx, map[string]string{}, "GET", *vdcHREF, nil)
+			resp, err := org.c.doRequest(req)
 			if err != nil {
-				return Vdc{}, fmt.Errorf("error getting vdc: %s", err)
+				return Vdc{}, fmt.Errorf("error getting vdc: %w", err)
 			}
 
 			vdc := NewVdc(org.c)
 			if err = decodeBody(resp, vdc.Vdc); err != nil {
-				return Vdc{}, fmt.Errorf("error decoding vdc response: %s", err)
+				return Vdc{}, fmt.Errorf("error decoding vdc response: %w", err)
 			}
 			// The request was successful
 			return *vdc, nil
@@ -80,23 +118,29 @@ func (org *Org) GetVdcByName(vdcname string) (Vdc, error) {
 // Otherwise it returns an empty vdc and an error. This function
 // allows users to use an AdminOrg to fetch a vdc as well.
 func (adminOrg *AdminOrg) GetVdcByName(vdcname string) (Vdc, error) {
+	return adminOrg.GetVdcByNameWithContext(context.Background(), vdcname)
+}
+
+// GetVdcByNameWithContext behaves like GetVdcByName, aborting if ctx is
+// cancelled before vCD answers.
+func (adminOrg *AdminOrg) GetVdcByNameWithContext(ctx context.Context, vdcname string) (Vdc, error) {
 	for _, vdcs := range adminOrg.AdminOrg.Vdcs.Vdcs {
 		if vdcs.Name == vdcname {
 			splitbyAdminHREF := strings.Split(vdcs.HREF, "/admin")
 			vdcHREF := splitbyAdminHREF[0] + splitbyAdminHREF[1]
 			vdcURL, err := url.ParseRequestURI(vdcHREF)
 			if err != nil {
-				return Vdc{}, fmt.Errorf("Error parsing url: %v", err)
+				return Vdc{}, fmt.Errorf("Error parsing url: %w", err)
 			}
-			req := adminOrg.c.NewRequest(map[string]string{}, "GET", *vdcURL, nil)
-			resp, err := checkResp(adminOrg.c.Http.Do(req))
+			req := adminOrg.c.NewRequestWithContext(ctx, map[string]string{}, "GET", *vdcURL, nil)
+			resp, err := adminOrg.c.doRequest(req)
 			if err != nil {
-				return Vdc{}, fmt.Errorf("error getting vdc: %s", err)
+				return Vdc{}, fmt.Errorf("error getting vdc: %w", err)
 			}
 
 			vdc := NewVdc(adminOrg.c)
 			if err = decodeBody(resp, vdc.Vdc); err != nil {
-				return Vdc{}, fmt.Errorf("error decoding vdc response: %s", err)
+				return Vdc{}, fmt.Errorf("error decoding vdc response: %w", err)
 			}
 			// The request was successful
 			return *vdc, nil
@@ -105,187 +149,245 @@ func (adminOrg *AdminOrg) GetVdcByName(vdcname string) (Vdc, error) {
 	return Vdc{}, nil
 }
 
+// Refresh fetches the current state of the org, including its users.
+func (adminOrg *AdminOrg) Refresh() error {
+	return adminOrg.RefreshWithContext(context.Background())
+}
+
+// RefreshWithContext behaves like Refresh, aborting if ctx is cancelled
+// before vCD answers.
+func (adminOrg *AdminOrg) RefreshWithContext(ctx context.Context) error {
+	orgHREF, err := url.ParseRequestURI(adminOrg.AdminOrg.HREF)
+	if err != nil {
+		return fmt.Errorf("error getting AdminOrg HREF %s : %w", adminOrg.AdminOrg.HREF, err)
+	}
+	req := adminOrg.c.NewRequestWithContext(ctx, map[string]string{}, "GET", *orgHREF, nil)
+	resp, err := adminOrg.c.doRequest(req)
+	if err != nil {
+		return fmt.Errorf("error retreiving org: %w", err)
+	}
+	refreshed := new(types.AdminOrg)
+	if err = decodeBody(resp, refreshed); err != nil {
+		return fmt.Errorf("error decoding org response: %w", err)
+	}
+	adminOrg.AdminOrg = refreshed
+	return nil
+}
+
 //   Deletes the org, returning an error if the vCD call fails.
 func (adminOrg *AdminOrg) Delete(force bool, recursive bool) error {
+	return adminOrg.DeleteWithContext(context.Background(), force, recursive)
+}
+
+// DeleteWithContext behaves like Delete, aborting if ctx is cancelled
+// before vCD answers.
+func (adminOrg *AdminOrg) DeleteWithContext(ctx context.Context, force bool, recursive bool) error {
 	if force && recursive {
 		//undeploys vapps
-		err := adminOrg.undeployAllVApps()
+		err := adminOrg.undeployAllVApps(ctx)
 		if err != nil {
-			return fmt.Errorf("error could not undeploy: %#v", err)
+			return fmt.Errorf("error could not undeploy: %w", err)
 		}
 		//removes vapps
-		err = adminOrg.removeAllVApps()
+		err = adminOrg.removeAllVApps(ctx)
 		if err != nil {
-			return fmt.Errorf("error could not remove vapp: %#v", err)
+			return fmt.Errorf("error could not remove vapp: %w", err)
 		}
 		//removes catalogs
-		err = adminOrg.removeCatalogs()
+		err = adminOrg.removeCatalogs(ctx)
 		if err != nil {
-			return fmt.Errorf("error could not remove all catalogs: %#v", err)
+			return fmt.Errorf("error could not remove all catalogs: %w", err)
 		}
 		//removes networks
-		err = adminOrg.removeAllOrgNetworks()
+		err = adminOrg.removeAllOrgNetworks(ctx)
 		if err != nil {
-			return fmt.Errorf("error could not remove all networks: %#v", err)
+			return fmt.Errorf("error could not remove all networks: %w", err)
 		}
 		//removes org vdcs
-		err = adminOrg.removeAllOrgVDCs()
+		err = adminOrg.removeAllOrgVDCs(ctx)
 		if err != nil {
-			return fmt.Errorf("error could not remove all vdcs: %#v", err)
+			return fmt.Errorf("error could not remove all vdcs: %w", err)
 		}
 	}
 	// Disable org
-	err := adminOrg.Disable()
+	err := adminOrg.DisableWithContext(ctx)
 	if err != nil {
-		return fmt.Errorf("error disabling Org %s: %s", adminOrg.AdminOrg.ID, err)
+		return fmt.Errorf("error disabling Org %s: %w", adminOrg.AdminOrg.ID, err)
 	}
 	// Get admin HREF
 	orgHREF, err := url.ParseRequestURI(adminOrg.AdminOrg.HREF)
 	if err != nil {
-		return fmt.Errorf("error getting AdminOrg HREF %s : %v", adminOrg.AdminOrg.HREF, err)
+		return fmt.Errorf("error getting AdminOrg HREF %s : %w", adminOrg.AdminOrg.HREF, err)
 	}
-	req := adminOrg.c.NewRequest(map[string]string{
+	req := adminOrg.c.NewRequestWithContext(ctx, map[string]string{
 		"force":     strconv.FormatBool(force),
 		"recursive": strconv.FormatBool(recursive),
 	}, "DELETE", *orgHREF, nil)
-	_, err = checkResp(adminOrg.c.Http.Do(req))
+	resp, err := adminOrg.c.doRequest(req)
 	if err != nil {
-		return fmt.Errorf("error deleting Org %s: %s", adminOrg.AdminOrg.ID, err)
+		return fmt.Errorf("error deleting Org %s: %w", adminOrg.AdminOrg.ID, err)
 	}
+	resp.Body.Close()
 	return nil
 }
 
 // Disables the org. Returns an error if the call to vCD fails.
 func (adminOrg *AdminOrg) Disable() error {
+	return adminOrg.DisableWithContext(context.Background())
+}
+
+// DisableWithContext behaves like Disable, aborting if ctx is cancelled
+// before vCD answers.
+func (adminOrg *AdminOrg) DisableWithContext(ctx context.Context) error {
 	orgHREF, err := url.ParseRequestURI(adminOrg.AdminOrg.HREF)
 	if err != nil {
-		return fmt.Errorf("error getting AdminOrg HREF %s : %v", adminOrg.AdminOrg.HREF, err)
+		return fmt.Errorf("error getting AdminOrg HREF %s : %w", adminOrg.AdminOrg.HREF, err)
 	}
 	orgHREF.Path += "/action/disable"
-	req := adminOrg.c.NewRequest(map[string]string{}, "POST", *orgHREF, nil)
-	_, err = checkResp(adminOrg.c.Http.Do(req))
-	return err
+	req := adminOrg.c.NewRequestWithContext(ctx, map[string]string{}, "POST", *orgHREF, nil)
+	resp, err := adminOrg.c.doRequest(req)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
 }
 
 //   Updates the Org definition from current org struct contents.
 //   Any differences that may be legally applied will be updated.
+//   Only the general, lease and LDAP settings are sent: the email section
+//   comes back from vCD without the SMTP password, so the email, password
+//   policy and federation settings are updated with their own methods.
 //   Returns an error if the call to vCD fails.
 func (adminOrg *AdminOrg) Update() (Task, error) {
+	return adminOrg.UpdateWithContext(context.Background())
+}
+
+// UpdateWithContext behaves like Update. The request is aborted if ctx is
+// cancelled before vCD accepts it.
+func (adminOrg *AdminOrg) UpdateWithContext(ctx context.Context) (Task, error) {
 	vcomp := &types.AdminOrg{
-		Xmlns:       "http://www.vmware.com/vcloud/v1.5",
-		Name:        adminOrg.AdminOrg.Name,
-		IsEnabled:   adminOrg.AdminOrg.IsEnabled,
-		FullName:    adminOrg.AdminOrg.FullName,
-		OrgSettings: adminOrg.AdminOrg.OrgSettings,
+		Xmlns:     "http://www.vmware.com/vcloud/v1.5",
+		Name:      adminOrg.AdminOrg.Name,
+		IsEnabled: adminOrg.AdminOrg.IsEnabled,
+		FullName:  adminOrg.AdminOrg.FullName,
+	}
+	if settings := adminOrg.AdminOrg.OrgSettings; settings != nil {
+		vcomp.OrgSettings = &types.OrgSettings{
+			OrgGeneralSettings:      settings.OrgGeneralSettings,
+			OrgVAppLeaseSettings:    settings.OrgVAppLeaseSettings,
+			OrgVAppTemplateSettings: settings.OrgVAppTemplateSettings,
+			OrgLdapSettings:         settings.OrgLdapSettings,
+		}
 	}
 	output, _ := xml.MarshalIndent(vcomp, "  ", "    ")
 	xmlData := bytes.NewBufferString(xml.Header + string(output))
 	// Update org
 	orgHREF, err := url.ParseRequestURI(adminOrg.AdminOrg.HREF)
 	if err != nil {
-		return Task{}, fmt.Errorf("error getting AdminOrg HREF %s : %v", adminOrg.AdminOrg.HREF, err)
+		return Task{}, fmt.Errorf("error getting AdminOrg HREF %s : %w", adminOrg.AdminOrg.HREF, err)
 	}
-	req := adminOrg.c.NewRequest(map[string]string{}, "PUT", *orgHREF, xmlData)
+	req := adminOrg.c.NewRequestWithContext(ctx, map[string]string{}, "PUT", *orgHREF, xmlData)
 	req.Header.Add("Content-Type", "application/vnd.vmware.admin.organization+xml")
-	resp, err := checkResp(adminOrg.c.Http.Do(req))
+	resp, err := adminOrg.c.doRequest(req)
 	if err != nil {
-		return Task{}, fmt.Errorf("error updating Org: %s", err)
+		return Task{}, fmt.Errorf("error updating Org: %w", err)
 	}
 	// Create Return object
 	task := NewTask(adminOrg.c)
 	if err = decodeBody(resp, task.Task); err != nil {
-		return Task{}, fmt.Errorf("error decoding task response: %s", err)
+		return Task{}, fmt.Errorf("error decoding task response: %w", err)
 	}
 	return *task, nil
 }
 
 // Undeploys every vapp within an organization
-func (adminOrg *AdminOrg) undeployAllVApps() error {
+func (adminOrg *AdminOrg) undeployAllVApps(ctx context.Context) error {
 	for _, vdcs := range adminOrg.AdminOrg.Vdcs.Vdcs {
 		adminVdcHREF, err := url.Parse(vdcs.HREF)
 		if err != nil {
 			return err
 		}
-		vdc, err := adminOrg.getVdcByAdminHREF(adminVdcHREF)
+		vdc, err := adminOrg.getVdcByAdminHREF(ctx, adminVdcHREF)
 		if err != nil {
-			return fmt.Errorf("Error retrieving vapp with url: %s and with error %s", adminVdcHREF.Path, err)
+			return fmt.Errorf("Error retrieving vapp with url: %s and with error %w", adminVdcHREF.Path, err)
 		}
-		err = vdc.undeployAllVdcVApps()
+		err = vdc.undeployAllVdcVApps(ctx)
 		if err != nil {
-			return fmt.Errorf("Error deleting vapp: %s", err)
+			return fmt.Errorf("Error deleting vapp: %w", err)
 		}
 	}
 	return nil
 }
 
 // Deletes every vapp within an organization
-func (adminOrg *AdminOrg) removeAllVApps() error {
+func (adminOrg *AdminOrg) removeAllVApps(ctx context.Context) error {
 	for _, vdcs := range adminOrg.AdminOrg.Vdcs.Vdcs {
 		adminVdcHREF, err := url.Parse(vdcs.HREF)
 		if err != nil {
 			return err
 		}
-		vdc, err := adminOrg.getVdcByAdminHREF(adminVdcHREF)
+		vdc, err := adminOrg.getVdcByAdminHREF(ctx, adminVdcHREF)
 		if err != nil {
-			return fmt.Errorf("Error retrieving vapp with url: %s and with error %s", adminVdcHREF.Path, err)
+			return fmt.Errorf("Error retrieving vapp with url: %s and with error %w", adminVdcHREF.Path, err)
 		}
-		err = vdc.removeAllVdcVApps()
+		err = vdc.removeAllVdcVApps(ctx)
 		if err != nil {
-			return fmt.Errorf("Error deleting vapp: %s", err)
+			return fmt.Errorf("Error deleting vapp: %w", err)
 		}
 	}
 	return nil
 }
 
 // Gets a vdc within org associated with an admin vdc url
-func (adminOrg *AdminOrg) getVdcByAdminHREF(adminVdcUrl *url.URL) (*Vdc, error) {
+func (adminOrg *AdminOrg) getVdcByAdminHREF(ctx context.Context, adminVdcUrl *url.URL) (*Vdc, error) {
 	// get non admin vdc path
 	non_admin := strings.Split(adminVdcUrl.Path, "/admin")
 	adminVdcUrl.Path = non_admin[0] + non_admin[1]
-	req := adminOrg.c.NewRequest(map[string]string{}, "GET", *adminVdcUrl, nil)
-	resp, err := checkResp(adminOrg.c.Http.Do(req))
+	req := adminOrg.c.NewRequestWithContext(ctx, map[string]string{}, "GET", *adminVdcUrl, nil)
+	resp, err := adminOrg.c.doRequest(req)
 	if err != nil {
-		return &Vdc{}, fmt.Errorf("error retreiving vdc: %s", err)
+		return &Vdc{}, fmt.Errorf("error retreiving vdc: %w", err)
 	}
 
 	vdc := NewVdc(adminOrg.c)
 	if err = decodeBody(resp, vdc.Vdc); err != nil {
-		return &Vdc{}, fmt.Errorf("error decoding vdc response: %s", err)
+		return &Vdc{}, fmt.Errorf("error decoding vdc response: %w", err)
 	}
 	return vdc, nil
 }
 
 // Removes all vdcs in a org
-func (adminOrg *AdminOrg) removeAllOrgVDCs() error {
+func (adminOrg *AdminOrg) removeAllOrgVDCs(ctx context.Context) error {
 	for _, vdcs := range adminOrg.AdminOrg.Vdcs.Vdcs {
 		// Get admin Vdc HREF
 		adminVdcUrl := adminOrg.c.VCDHREF
 		adminVdcUrl.Path += "/admin/vdc/" + strings.Split(vdcs.HREF, "/vdc/")[1] + "/action/disable"
-		req := adminOrg.c.NewRequest(map[string]string{}, "POST", adminVdcUrl, nil)
-		_, err := checkResp(adminOrg.c.Http.Do(req))
+		req := adminOrg.c.NewRequestWithContext(ctx, map[string]string{}, "POST", adminVdcUrl, nil)
+		resp, err := adminOrg.c.doRequest(req)
 		if err != nil {
-			return fmt.Errorf("error disabling vdc: %s", err)
+			return fmt.Errorf("error disabling vdc: %w", err)
 		}
+		resp.Body.Close()
 		// Get admin vdc HREF for normal deletion
 		adminVdcUrl.Path = strings.Split(adminVdcUrl.Path, "/action/disable")[0]
-		req = adminOrg.c.NewRequest(map[string]string{
+		req = adminOrg.c.NewRequestWithContext(ctx, map[string]string{
 			"recursive": "true",
 			"force":     "true",
 		}, "DELETE", adminVdcUrl, nil)
-		resp, err := checkResp(adminOrg.c.Http.Do(req))
+		resp, err = adminOrg.c.doRequest(req)
 		if err != nil {
-			return fmt.Errorf("error deleting vdc: %s", err)
+			return fmt.Errorf("error deleting vdc: %w", err)
 		}
 		task := NewTask(adminOrg.c)
 		if err = decodeBody(resp, task.Task); err != nil {
-			return fmt.Errorf("error decoding task response: %s", err)
+			return fmt.Errorf("error decoding task response: %w", err)
 		}
 		if task.Task.Status == "error" {
 			return fmt.Errorf("vdc not properly destroyed")
 		}
-		err = task.WaitTaskCompletion()
+		err = task.WaitTaskCompletionWithContext(ctx)
 		if err != nil {
-			return fmt.Errorf("Couldn't finish removing vdc %#v", err)
+			return fmt.Errorf("Couldn't finish removing vdc %w", err)
 		}
 
 	}
@@ -294,46 +396,47 @@ func (adminOrg *AdminOrg) removeAllOrgVDCs() error {
 }
 
 // Removes All networks in the org
-func (adminOrg *AdminOrg) removeAllOrgNetworks() error {
+func (adminOrg *AdminOrg) removeAllOrgNetworks(ctx context.Context) error {
 	for _, networks := range adminOrg.AdminOrg.Networks.Networks {
 		// Get Network HREF
 		networkHREF := adminOrg.c.VCDHREF
 		networkHREF.Path += "/admin/network/" + strings.Split(networks.HREF, "/network/")[1] //gets id
-		req := adminOrg.c.NewRequest(map[string]string{}, "DELETE", networkHREF, nil)
-		resp, err := checkResp(adminOrg.c.Http.Do(req))
+		req := adminOrg.c.NewRequestWithContext(ctx, map[string]string{}, "DELETE", networkHREF, nil)
+		resp, err := adminOrg.c.doRequest(req)
 		if err != nil {
-			return fmt.Errorf("error deleting newtork: %s, %s", err, networkHREF.Path)
+			return fmt.Errorf("error deleting newtork: %w, %s", err, networkHREF.Path)
 		}
 
 		task := NewTask(adminOrg.c)
 		if err = decodeBody(resp, task.Task); err != nil {
-			return fmt.Errorf("error decoding task response: %s", err)
+			return fmt.Errorf("error decoding task response: %w", err)
 		}
 		if task.Task.Status == "error" {
 			return fmt.Errorf("network not properly destroyed")
 		}
-		err = task.WaitTaskCompletion()
+		err = task.WaitTaskCompletionWithContext(ctx)
 		if err != nil {
-			return fmt.Errorf("Couldn't finish removing network %#v", err)
+			return fmt.Errorf("Couldn't finish removing network %w", err)
 		}
 	}
 	return nil
 }
 
 // Forced removal of all organization catalogs
-func (adminOrg *AdminOrg) removeCatalogs() error {
+func (adminOrg *AdminOrg) removeCatalogs(ctx context.Context) error {
 	for _, catalogs := range adminOrg.AdminOrg.Catalogs.Catalog {
 		// Get Catalog HREF
 		catalogHREF := adminOrg.c.VCDHREF
 		catalogHREF.Path += "/admin/catalog/" + strings.Split(catalogs.HREF, "/catalog/")[1] //gets id
-		req := adminOrg.c.NewRequest(map[string]string{
+		req := adminOrg.c.NewRequestWithContext(ctx, map[string]string{
 			"force":     "true",
 			"recursive": "true",
 		}, "DELETE", catalogHREF, nil)
-		_, err := checkResp(adminOrg.c.Http.Do(req))
+		resp, err := adminOrg.c.doRequest(req)
 		if err != nil {
-			return fmt.Errorf("error deleting catalog: %s, %s", err, catalogHREF.Path)
+			return fmt.Errorf("error deleting catalog: %w, %s", err, catalogHREF.Path)
 		}
+		resp.Body.Close()
 	}
 	return nil
 
@@ -344,6 +447,12 @@ func (adminOrg *AdminOrg) removeCatalogs() error {
 // Otherwise it returns an error. Function allows user to use an AdminOrg
 // to also fetch a Catalog.
 func (adminOrg *AdminOrg) FindCatalog(catalogName string) (Catalog, error) {
+	return adminOrg.FindCatalogWithContext(context.Background(), catalogName)
+}
+
+// FindCatalogWithContext behaves like FindCatalog, aborting if ctx is
+// cancelled before vCD answers.
+func (adminOrg *AdminOrg) FindCatalogWithContext(ctx context.Context, catalogName string) (Catalog, error) {
 	for _, catalogs := range adminOrg.AdminOrg.Catalogs.Catalog {
 		// Get Catalog HREF
 		if catalogs.Name == catalogName {
@@ -351,17 +460,17 @@ func (adminOrg *AdminOrg) FindCatalog(catalogName string) (Catalog, error) {
 			catalogHREF := splitbyAdminHREF[0] + splitbyAdminHREF[1]
 			catalogURL, err := url.ParseRequestURI(catalogHREF)
 			if err != nil {
-				return Catalog{}, fmt.Errorf("error decoding catalog url: %s", err)
+				return Catalog{}, fmt.Errorf("error decoding catalog url: %w", err)
 			}
-			req := adminOrg.c.NewRequest(map[string]string{}, "GET", *catalogURL, nil)
-			resp, err := checkResp(adminOrg.c.Http.Do(req))
+			req := adminOrg.c.NewRequestWithContext(ctx, map[string]string{}, "GET", *catalogURL, nil)
+			resp, err := adminOrg.c.doRequest(req)
 			if err != nil {
-				return Catalog{}, fmt.Errorf("error retreiving catalog: %s", err)
+				return Catalog{}, fmt.Errorf("error retreiving catalog: %w", err)
 			}
 			cat := NewCatalog(adminOrg.c)
 
 			if err = decodeBody(resp, cat.Catalog); err != nil {
-				return Catalog{}, fmt.Errorf("error decoding catalog response: %s", err)
+				return Catalog{}, fmt.Errorf("error decoding catalog response: %w", err)
 			}
 
 			// The request was successful
@@ -375,26 +484,32 @@ func (adminOrg *AdminOrg) FindCatalog(catalogName string) (Catalog, error) {
 // If no catalog is found, then returns an empty catalog and no error.
 // Otherwise it returns an error.
 func (org *Org) FindCatalog(catalogName string) (Catalog, error) {
+	return org.FindCatalogWithContext(context.Background(), catalogName)
+}
+
+// FindCatalogWithContext behaves like FindCatalog, aborting if ctx is
+// cancelled before vCD answers.
+func (org *Org) FindCatalogWithContext(ctx context.Context, catalogName string) (Catalog, error) {
 
 	for _, av := range org.Org.Link {
 		if av.Rel == "down" && av.Type == "application/vnd.vmware.vcloud.catalog+xml" && av.Name == catalogName {
 			u, err := url.ParseRequestURI(av.HREF)
 
 			if err != nil {
-				return Catalog{}, fmt.Errorf("error decoding org response: %s", err)
+				return Catalog{}, fmt.Errorf("error decoding org response: %w", err)
 			}
 
-			req := org.c.NewRequest(map[string]string{}, "GET", *u, nil)
+			req := org.c.NewRequestWithContext(ctx, map[string]string{}, "GET", *u, nil)
 
-			resp, err := checkResp(org.c.Http.Do(req))
+			resp, err := org.c.doRequest(req)
 			if err != nil {
-				return Catalog{}, fmt.Errorf("error retreiving catalog: %s", err)
+				return Catalog{}, fmt.Errorf("error retreiving catalog: %w", err)
 			}
 
 			cat := NewCatalog(org.c)
 
 			if err = decodeBody(resp, cat.Catalog); err != nil {
-				return Catalog{}, fmt.Errorf("error decoding catalog response: %s", err)
+				return Catalog{}, fmt.Errorf("error decoding catalog response: %w", err)
 			}
 
 			// The request was successful
@@ -0,0 +1,230 @@
+/*
+ * Copyright 2014 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"net/url"
+
+	types "github.com/vmware/go-vcloud-director/types/v56"
+)
+
+// Each section of the org settings is read and updated on its own, under
+// /admin/org/<id>/settings. The Update methods send the given section only
+// and return it as stored by vCD, without passwords.
+
+// GetSettings returns all the settings of the org.
+func (adminOrg *AdminOrg) GetSettings() (*types.OrgSettings, error) {
+	settings := new(types.OrgSettings)
+	if err := adminOrg.getSettings("", "settings", settings); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// GetGeneralSettings returns the general settings of the org, such as the
+// VM quotas.
+func (adminOrg *AdminOrg) GetGeneralSettings() (*types.OrgGeneralSettings, error) {
+	settings := new(types.OrgGeneralSettings)
+	if err := adminOrg.getSettings("/general", "general settings", settings); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// UpdateGeneralSettings updates the general settings of the org.
+func (adminOrg *AdminOrg) UpdateGeneralSettings(settings *types.OrgGeneralSettings) (*types.OrgGeneralSettings, error) {
+	updated := new(types.OrgGeneralSettings)
+	if err := adminOrg.updateSettings("/general", "general settings", "OrgGeneralSettings",
+		types.MimeOrgGeneralSettings, settings, updated); err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// GetVAppLeaseSettings returns the lease settings of the vApps of the org.
+func (adminOrg *AdminOrg) GetVAppLeaseSettings() (*types.VAppLeaseSettings, error) {
+	settings := new(types.VAppLeaseSettings)
+	if err := adminOrg.getSettings("/vAppLeaseSettings", "vApp lease settings", settings); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// UpdateVAppLeaseSettings updates the lease settings of the vApps of the
+// org.
+func (adminOrg *AdminOrg) UpdateVAppLeaseSettings(settings *types.VAppLeaseSettings) (*types.VAppLeaseSettings, error) {
+	updated := new(types.VAppLeaseSettings)
+	if err := adminOrg.updateSettings("/vAppLeaseSettings", "vApp lease settings", "VAppLeaseSettings",
+		types.MimeVAppLeaseSettings, settings, updated); err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// GetVAppTemplateLeaseSettings returns the lease settings of the vApp
+// templates of the org.
+func (adminOrg *AdminOrg) GetVAppTemplateLeaseSettings() (*types.VAppTemplateLeaseSettings, error) {
+	settings := new(types.VAppTemplateLeaseSettings)
+	if err := adminOrg.getSettings("/vAppTemplateLeaseSettings", "vApp template lease settings", settings); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// UpdateVAppTemplateLeaseSettings updates the lease settings of the vApp
+// templates of the org.
+func (adminOrg *AdminOrg) UpdateVAppTemplateLeaseSettings(settings *types.VAppTemplateLeaseSettings) (*types.VAppTemplateLeaseSettings, error) {
+	updated := new(types.VAppTemplateLeaseSettings)
+	if err := adminOrg.updateSettings("/vAppTemplateLeaseSettings", "vApp template lease settings", "VAppTemplateLeaseSettings",
+		types.MimeVAppTemplateLeaseSettings, settings, updated); err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// GetEmailSettings returns the email settings of the org.
+func (adminOrg *AdminOrg) GetEmailSettings() (*types.OrgEmailSettings, error) {
+	settings := new(types.OrgEmailSettings)
+	if err := adminOrg.getSettings("/email", "email settings", settings); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// UpdateEmailSettings updates the email settings of the org. The SMTP
+// server settings are required unless IsDefaultSmtpServer is set.
+func (adminOrg *AdminOrg) UpdateEmailSettings(settings *types.OrgEmailSettings) (*types.OrgEmailSettings, error) {
+	if !settings.IsDefaultSmtpServer && (settings.SmtpServerSettings == nil || settings.SmtpServerSettings.Host == "") {
+		return nil, fmt.Errorf("an SMTP server is required unless the default one is used")
+	}
+	updated := new(types.OrgEmailSettings)
+	if err := adminOrg.updateSettings("/email", "email settings", "OrgEmailSettings",
+		types.MimeOrgEmailSettings, settings, updated); err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// GetPasswordPolicySettings returns the password policy of the org.
+func (adminOrg *AdminOrg) GetPasswordPolicySettings() (*types.OrgPasswordPolicySettings, error) {
+	settings := new(types.OrgPasswordPolicySettings)
+	if err := adminOrg.getSettings("/passwordPolicy", "password policy", settings); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// UpdatePasswordPolicySettings updates the password policy of the org.
+func (adminOrg *AdminOrg) UpdatePasswordPolicySettings(settings *types.OrgPasswordPolicySettings) (*types.OrgPasswordPolicySettings, error) {
+	updated := new(types.OrgPasswordPolicySettings)
+	if err := adminOrg.updateSettings("/passwordPolicy", "password policy", "OrgPasswordPolicySettings",
+		types.MimeOrgPasswordPolicySettings, settings, updated); err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// GetGuestPersonalizationSettings returns the guest personalization
+// settings of the org.
+func (adminOrg *AdminOrg) GetGuestPersonalizationSettings() (*types.GuestPersonalizationSettings, error) {
+	settings := new(types.GuestPersonalizationSettings)
+	if err := adminOrg.getSettings("/guestPersonalizationSettings", "guest personalization settings", settings); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// UpdateGuestPersonalizationSettings updates the guest personalization
+// settings of the org.
+func (adminOrg *AdminOrg) UpdateGuestPersonalizationSettings(settings *types.GuestPersonalizationSettings) (*types.GuestPersonalizationSettings, error) {
+	updated := new(types.GuestPersonalizationSettings)
+	if err := adminOrg.updateSettings("/guestPersonalizationSettings", "guest personalization settings", "GuestPersonalizationSettings",
+		types.MimeGuestPersonalizationSettings, settings, updated); err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// GetFederationSettings returns the SAML federation settings of the org.
+func (adminOrg *AdminOrg) GetFederationSettings() (*types.OrgFederationSettings, error) {
+	settings := new(types.OrgFederationSettings)
+	if err := adminOrg.getSettings("/federation", "federation settings", settings); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// UpdateFederationSettings updates the SAML federation settings of the
+// org.
+func (adminOrg *AdminOrg) UpdateFederationSettings(settings *types.OrgFederationSettings) (*types.OrgFederationSettings, error) {
+	updated := new(types.OrgFederationSettings)
+	if err := adminOrg.updateSettings("/federation", "federation settings", "OrgFederationSettings",
+		types.MimeOrgFederationSettings, settings, updated); err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// settingsHREF returns the URL of the settings section, e.g. "/general".
+func (adminOrg *AdminOrg) settingsHREF(section string) (*url.URL, error) {
+	settingsHREF, err := url.ParseRequestURI(adminOrg.AdminOrg.HREF)
+	if err != nil {
+		return nil, fmt.Errorf("error getting AdminOrg HREF %s : %w", adminOrg.AdminOrg.HREF, err)
+	}
+	settingsHREF.Path += "/settings" + section
+	return settingsHREF, nil
+}
+
+// getSettings decodes the settings section into settings.
+func (adminOrg *AdminOrg) getSettings(section, description string, settings interface{}) error {
+	settingsHREF, err := adminOrg.settingsHREF(section)
+	if err != nil {
+		return err
+	}
+	req := adminOrg.c.NewRequest(map[string]string{}, "GET", *settingsHREF, nil)
+	resp, err := adminOrg.c.doRequest(req)
+	if err != nil {
+		return fmt.Errorf("error retrieving %s: %w", description, err)
+	}
+	if err = decodeBody(resp, settings); err != nil {
+		return fmt.Errorf("error decoding %s: %w", description, err)
+	}
+	return nil
+}
+
+// updateSettings sends settings as the settings section, with the given
+// root element and MIME type, and decodes the stored section into updated.
+func (adminOrg *AdminOrg) updateSettings(section, description, rootName, mime string, settings, updated interface{}) error {
+	settingsHREF, err := adminOrg.settingsHREF(section)
+	if err != nil {
+		return err
+	}
+
+	var output bytes.Buffer
+	output.WriteString(xml.Header)
+	encoder := xml.NewEncoder(&output)
+	encoder.Indent("  ", "    ")
+	root := xml.StartElement{
+		Name: xml.Name{Local: rootName},
+		Attr: []xml.Attr{{Name: xml.Name{Local: "xmlns"}, Value: "http://www.vmware.com/vcloud/v1.5"}},
+	}
+	if err = encoder.EncodeElement(settings, root); err != nil {
+		return fmt.Errorf("error encoding %s: %w", description, err)
+	}
+
+	req := adminOrg.c.NewRequest(map[string]string{}, "PUT", *settingsHREF, &output)
+	req.Header.Add("Content-Type", mime)
+	resp, err := adminOrg.c.doRequest(req)
+	if err != nil {
+		return fmt.Errorf("error updating %s: %w", description, err)
+	}
+	if err = decodeBody(resp, updated); err != nil {
+		return fmt.Errorf("error decoding %s: %w", description, err)
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+/*
+ * Copyright 2014 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"testing"
+
+	types "github.com/vmware/go-vcloud-director/types/v56"
+	. "gopkg.in/check.v1"
+)
+
+// Checks that the lease settings and the password policy of the test org
+// can be read and written back unchanged.
+func (vcd *TestVCD) Test_OrgSettings(check *C) {
+	adminOrg, err := GetAdminOrgByName(vcd.client, vcd.config.VCD.Org)
+	check.Assert(err, IsNil)
+
+	lease, err := adminOrg.GetVAppLeaseSettings()
+	check.Assert(err, IsNil)
+	updatedLease, err := adminOrg.UpdateVAppLeaseSettings(lease)
+	check.Assert(err, IsNil)
+	check.Assert(updatedLease.DeploymentLeaseSeconds, Equals, lease.DeploymentLeaseSeconds)
+	check.Assert(updatedLease.StorageLeaseSeconds, Equals, lease.StorageLeaseSeconds)
+
+	policy, err := adminOrg.GetPasswordPolicySettings()
+	check.Assert(err, IsNil)
+	updatedPolicy, err := adminOrg.UpdatePasswordPolicySettings(policy)
+	check.Assert(err, IsNil)
+	check.Assert(updatedPolicy.InvalidLoginsBeforeLockout, Equals, policy.InvalidLoginsBeforeLockout)
+}
+
+func TestAdminOrg_SettingsFakeVCD(t *testing.T) {
+	server, _, client := newFakeVCD(t)
+	defer server.Close()
+
+	adminOrg, err := GetAdminOrgByName(client, "org")
+	if err != nil {
+		t.Fatalf("error getting admin org: %s", err)
+	}
+
+	general, err := adminOrg.GetGeneralSettings()
+	if err != nil || !general.CanPublishCatalogs {
+		t.Fatalf("unexpected general settings: %+v, %v", general, err)
+	}
+	general.DeployedVMQuota = 10
+	// Turning a setting off must send it, not leave it out
+	general.CanPublishCatalogs = false
+	server.ResetRequests()
+	if general, err = adminOrg.UpdateGeneralSettings(general); err != nil || general.DeployedVMQuota != 10 || general.CanPublishCatalogs {
+		t.Fatalf("unexpected updated general settings: %+v, %v", general, err)
+	}
+	checkRequests(t, server, apiRequest{
+		Method: "PUT", Path: "/admin/org/[^/]+/settings/general", ContentType: "application/vnd.vmware.admin.organizationGeneralSettings+xml",
+		Body: []string{"<OrgGeneralSettings ", "<DeployedVMQuota>10</DeployedVMQuota>", "<CanPublishCatalogs>false</CanPublishCatalogs>", "<StoredVmQuota>0</StoredVmQuota>"},
+	})
+	if general, err = adminOrg.GetGeneralSettings(); err != nil || general.CanPublishCatalogs {
+		t.Fatalf("catalog publishing was not turned off: %+v, %v", general, err)
+	}
+
+	lease, err := adminOrg.GetVAppLeaseSettings()
+	if err != nil || lease.DeploymentLeaseSeconds != 604800 {
+		t.Fatalf("unexpected vApp lease settings: %+v, %v", lease, err)
+	}
+	lease.DeploymentLeaseSeconds = 0
+	lease.DeleteOnStorageLeaseExpiration = true
+	if lease, err = adminOrg.UpdateVAppLeaseSettings(lease); err != nil || lease.DeploymentLeaseSeconds != 0 || !lease.DeleteOnStorageLeaseExpiration {
+		t.Fatalf("unexpected updated vApp lease settings: %+v, %v", lease, err)
+	}
+
+	templateLease, err := adminOrg.GetVAppTemplateLeaseSettings()
+	if err != nil {
+		t.Fatalf("error getting vApp template lease settings: %s", err)
+	}
+	templateLease.StorageLeaseSeconds = 0
+	server.ResetRequests()
+	if templateLease, err = adminOrg.UpdateVAppTemplateLeaseSettings(templateLease); err != nil || templateLease.StorageLeaseSeconds != 0 {
+		t.Fatalf("unexpected updated vApp template lease settings: %+v, %v", templateLease, err)
+	}
+	checkRequests(t, server, apiRequest{
+		Method: "PUT", Path: "/admin/org/[^/]+/settings/vAppTemplateLeaseSettings", ContentType: "application/vnd.vmware.admin.vAppTemplateLeaseSettings+xml",
+		Body: []string{"<StorageLeaseSeconds>0</StorageLeaseSeconds>", "<DeleteOnStorageLeaseExpiration>false</DeleteOnStorageLeaseExpiration>"},
+	})
+
+	email, err := adminOrg.GetEmailSettings()
+	if err != nil || !email.IsDefaultSmtpServer {
+		t.Fatalf("unexpected email settings: %+v, %v", email, err)
+	}
+	email.IsDefaultSmtpServer = false
+	if _, err = adminOrg.UpdateEmailSettings(email); err == nil {
+		t.Fatal("expected an error updating the email settings without an SMTP server")
+	}
+	email.SmtpServerSettings = &types.SmtpServerSettings{
+		IsUseAuthentication: true,
+		Host:                "smtp.example.com",
+		Port:                25,
+		Username:            "mailer",
+		Password:            "secret",
+	}
+	server.ResetRequests()
+	email, err = adminOrg.UpdateEmailSettings(email)
+	if err != nil || email.SmtpServerSettings == nil || email.SmtpServerSettings.Host != "smtp.example.com" {
+		t.Fatalf("unexpected updated email settings: %+v, %v", email, err)
+	}
+	checkRequests(t, server, apiRequest{
+		Method: "PUT", Path: "/admin/org/[^/]+/settings/email", ContentType: "application/vnd.vmware.admin.organizationEmailSettings+xml",
+		Body: []string{"<OrgEmailSettings ", "<SmtpServerSettings>", "<Host>smtp.example.com</Host>", "<Password>secret</Password>"},
+	})
+	if email.SmtpServerSettings.Password != "" {
+		t.Fatal("expected the SMTP password not to be returned")
+	}
+
+	policy, err := adminOrg.GetPasswordPolicySettings()
+	if err != nil {
+		t.Fatalf("error getting password policy: %s", err)
+	}
+	policy.AccountLockoutEnabled = true
+	policy.InvalidLoginsBeforeLockout = 0
+	if _, err = adminOrg.UpdatePasswordPolicySettings(policy); err == nil {
+		t.Fatal("expected an error updating the password policy without invalid logins before lockout")
+	}
+	policy.InvalidLoginsBeforeLockout = 3
+	server.ResetRequests()
+	if policy, err = adminOrg.UpdatePasswordPolicySettings(policy); err != nil || !policy.AccountLockoutEnabled || policy.InvalidLoginsBeforeLockout != 3 {
+		t.Fatalf("unexpected updated password policy: %+v, %v", policy, err)
+	}
+	checkRequests(t, server, apiRequest{
+		Method: "PUT", Path: "/admin/org/[^/]+/settings/passwordPolicy", ContentType: "application/vnd.vmware.admin.organizationPasswordPolicySettings+xml",
+		Body: []string{"<OrgPasswordPolicySettings ", "<AccountLockoutEnabled>true</AccountLockoutEnabled>",
+			"<InvalidLoginsBeforeLockout>3</InvalidLoginsBeforeLockout>"},
+	})
+
+	guest, err := adminOrg.UpdateGuestPersonalizationSettings(&types.GuestPersonalizationSettings{
+		AllowDomainSettings: true,
+		DomainName:          "example.com",
+		AccountUsername:     "joiner",
+		AccountPassword:     "secret",
+	})
+	if err != nil || guest.DomainName != "example.com" || guest.AccountPassword != "" {
+		t.Fatalf("unexpected updated guest personalization settings: %+v, %v", guest, err)
+	}
+
+	if _, err = adminOrg.UpdateFederationSettings(&types.OrgFederationSettings{Enabled: true}); err == nil {
+		t.Fatal("expected an error enabling the federation without SAML metadata")
+	}
+	federation, err := adminOrg.UpdateFederationSettings(&types.OrgFederationSettings{Enabled: true, SAMLMetadata: "<md/>"})
+	if err != nil || !federation.Enabled {
+		t.Fatalf("unexpected updated federation settings: %+v, %v", federation, err)
+	}
+
+	settings, err := adminOrg.GetSettings()
+	if err != nil {
+		t.Fatalf("error getting settings: %s", err)
+	}
+	if settings.OrgGeneralSettings == nil || settings.OrgGeneralSettings.DeployedVMQuota != 10 ||
+		settings.OrgVAppLeaseSettings == nil || !settings.OrgVAppLeaseSettings.DeleteOnStorageLeaseExpiration ||
+		settings.OrgPasswordPolicySettings == nil || settings.OrgPasswordPolicySettings.InvalidLoginsBeforeLockout != 3 ||
+		settings.OrgFederationSettings == nil || !settings.OrgFederationSettings.Enabled {
+		t.Fatalf("unexpected settings: %+v", settings)
+	}
+}
@@ -5,8 +5,11 @@
 package govcd
 
 import (
+	"context"
+	"errors"
 	types "github.com/vmware/go-vcloud-director/types/v56"
 	. "gopkg.in/check.v1"
+	"testing"
 )
 
 // Creates a org DELETEORG and then deletes it to test functionality of
@@ -140,3 +143,103 @@ func (vcd *TestVCD) Test_Admin_FindCatalog(check *C) {
 	check.Assert(cat, Equals, Catalog{})
 	check.Assert(err, IsNil)
 }
+
+// Tests that the task list of the test org can be retrieved, and that
+// filtering on running tasks only returns queued or running tasks.
+func (vcd *TestVCD) Test_GetTaskList(check *C) {
+	tasks, err := vcd.org.GetTaskList(false)
+	check.Assert(err, IsNil)
+	for _, task := range tasks {
+		check.Assert(task.Task.HREF, Not(Equals), "")
+	}
+	tasks, err = vcd.org.GetTaskList(true)
+	check.Assert(err, IsNil)
+	for _, task := range tasks {
+		check.Assert(task.IsRunning(), Equals, true)
+	}
+}
+
+func TestOrg_GetTaskListFakeVCD(t *testing.T) {
+	server, fakeVdc, client := newFakeVCD(t)
+	defer server.Close()
+	server.TaskPolls = 1
+	fakeVdc.AddVApp("vapp")
+	_, vdc := fakeOrgVdc(t, client)
+
+	vapp, err := vdc.FindVAppByName("vapp")
+	if err != nil {
+		t.Fatalf("error finding vapp: %s", err)
+	}
+	if _, err = vapp.PowerOn(); err != nil {
+		t.Fatalf("error powering on: %s", err)
+	}
+	org, _ := fakeOrgVdc(t, client)
+	tasks, err := org.GetTaskList(true)
+	if err != nil || len(tasks) != 1 || tasks[0].Task.OperationName != "vappPowerOn" {
+		t.Fatalf("expected one running task, got %d, %v", len(tasks), err)
+	}
+	if tasks, err = vapp.GetTasksInProgressWithContext(context.Background()); err != nil || len(tasks) != 1 {
+		t.Fatalf("expected one task in progress on the vapp, got %d, %v", len(tasks), err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err = org.GetTaskListWithContext(ctx, true); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestAdminOrg_UpdateFakeVCD(t *testing.T) {
+	server, _, client := newFakeVCD(t)
+	defer server.Close()
+
+	adminOrg, err := GetAdminOrgByName(client, "org")
+	if err != nil {
+		t.Fatalf("error getting admin org: %s", err)
+	}
+	_, err = adminOrg.UpdateEmailSettings(&types.OrgEmailSettings{
+		IsDefaultOrgEmail:       true,
+		IsAlertEmailToAllAdmins: true,
+		SmtpServerSettings: &types.SmtpServerSettings{
+			IsUseAuthentication: true,
+			Host:                "smtp.example.com",
+			Port:                25,
+			Username:            "mailer",
+			Password:            "secret",
+		},
+	})
+	if err != nil {
+		t.Fatalf("error updating email settings: %s", err)
+	}
+	if err = adminOrg.Refresh(); err != nil {
+		t.Fatalf("error refreshing admin org: %s", err)
+	}
+	if adminOrg.AdminOrg.OrgSettings == nil || adminOrg.AdminOrg.OrgSettings.OrgEmailSettings == nil {
+		t.Fatalf("expected the admin org to come with its email settings: %+v", adminOrg.AdminOrg.OrgSettings)
+	}
+
+	// The email settings come back without the SMTP password, which
+	// Update must not send back.
+	server.ResetRequests()
+	adminOrg.AdminOrg.FullName = "Renamed org"
+	adminOrg.AdminOrg.OrgSettings.OrgGeneralSettings.DeployedVMQuota = 10
+	task, err := adminOrg.Update()
+	if err != nil {
+		t.Fatalf("error updating admin org: %s", err)
+	}
+	if err = task.WaitTaskCompletion(); err != nil {
+		t.Fatalf("error updating admin org: %s", err)
+	}
+	checkRequests(t, server, apiRequest{
+		Method:      "PUT",
+		Path:        "/admin/org/[^/]+",
+		ContentType: "application/vnd.vmware.admin.organization+xml",
+		Body:        []string{"<FullName>Renamed org</FullName>", "<OrgGeneralSettings ", "<DeployedVMQuota>10</DeployedVMQuota>"},
+		NotBody:     []string{"OrgEmailSettings", "OrgPasswordPolicySettings", "OrgFederationSettings"},
+	})
+
+	email, err := adminOrg.GetEmailSettings()
+	if err != nil || email.SmtpServerSettings == nil || email.SmtpServerSettings.Host != "smtp.example.com" {
+		t.Fatalf("expected the email settings to be kept: %+v, %v", email, err)
+	}
+}
@@ -6,15 +6,12 @@ package govcd
 
 import (
 	"bytes"
+	"context"
 	"encoding/xml"
 	"fmt"
 	types "github.com/vmware/go-vcloud-director/types/v56"
-	"log"
-	"net/http"
 	"net/url"
-	"regexp"
 	"strings"
-	"time"
 )
 
 // OrgVDCNetwork an org vdc network client
@@ -32,17 +29,23 @@ func NewOrgVDCNetwork(c *Client) *OrgVDCNetwork {
 }
 
 func (o *OrgVDCNetwork) Refresh() error {
+	return o.RefreshWithContext(context.Background())
+}
+
+// RefreshWithContext behaves like Refresh, aborting if ctx is cancelled
+// before vCD answers.
+func (o *OrgVDCNetwork) RefreshWithContext(ctx context.Context) error {
 	if o.OrgVDCNetwork.HREF == "" {
 		return fmt.Errorf("cannot refresh, Object is empty")
 	}
 
 	u, _ := url.ParseRequestURI(o.OrgVDCNetwork.HREF)
 
-	req := o.c.NewRequest(map[string]string{}, "GET", *u, nil)
+	req := o.c.NewRequestWithContext(ctx, map[string]string{}, "GET", *u, nil)
 
-	resp, err := checkResp(o.c.Http.Do(req))
+	resp, err := o.c.doRequest(req)
 	if err != nil {
-		return fmt.Errorf("error retrieving task: %s", err)
+		return fmt.Errorf("error retrieving task: %w", err)
 	}
 
 	// Empty struct before a new unmarshal, otherwise we end up with duplicate
@@ -50,7 +53,7 @@ func (o *OrgVDCNetwork) Refresh() error {
 	o.OrgVDCNetwork = &types.OrgVDCNetwork{}
 
 	if err = decodeBody(resp, o.OrgVDCNetwork); err != nil {
-		return fmt.Errorf("error decoding task response: %s", err)
+		return fmt.Errorf("error decoding task response: %w", err)
 	}
 
 	// The request was successful
@@ -58,32 +61,30 @@ func (o *OrgVDCNetwork) Refresh() error {
 }
 
 func (o *OrgVDCNetwork) Delete() (Task, error) {
-	err := o.Refresh()
+	return o.DeleteWithContext(context.Background())
+}
+
+// DeleteWithContext behaves like Delete. The request is aborted if ctx is
+// cancelled before vCD accepts it.
+func (o *OrgVDCNetwork) DeleteWithContext(ctx context.Context) (Task, error) {
+	err := o.RefreshWithContext(ctx)
 	if err != nil {
-		return Task{}, fmt.Errorf("Error refreshing network: %s", err)
+		return Task{}, fmt.Errorf("Error refreshing network: %w", err)
 	}
 	pathArr := strings.Split(o.OrgVDCNetwork.HREF, "/")
 	s, _ := url.ParseRequestURI(o.OrgVDCNetwork.HREF)
 	s.Path = "/api/admin/network/" + pathArr[len(pathArr)-1]
 
-	var resp *http.Response
-	for {
-		req := o.c.NewRequest(map[string]string{}, "DELETE", *s, nil)
-		resp, err = checkResp(o.c.Http.Do(req))
-		if err != nil {
-			if v, _ := regexp.MatchString("is busy, cannot proceed with the operation.$", err.Error()); v {
-				time.Sleep(3 * time.Second)
-				continue
-			}
-			return Task{}, fmt.Errorf("error deleting Network: %s", err)
-		}
-		break
+	req := o.c.NewRequestWithContext(ctx, map[string]string{}, "DELETE", *s, nil)
+	resp, err := o.c.doRequest(req)
+	if err != nil {
+		return Task{}, fmt.Errorf("error deleting Network: %w", err)
 	}
 
 	task := NewTask(o.c)
 
 	if err = decodeBody(resp, task.Task); err != nil {
-		return Task{}, fmt.Errorf("error decoding Task response: %s", err)
+		return Task{}, fmt.Errorf("error decoding Task response: %w", err)
 	}
 
 	// The request was successful
@@ -91,48 +92,45 @@ func (o *OrgVDCNetwork) Delete() (Task, error) {
 }
 
 func (v *Vdc) CreateOrgVDCNetwork(networkConfig *types.OrgVDCNetwork) error {
+	return v.CreateOrgVDCNetworkWithContext(context.Background(), networkConfig)
+}
+
+// CreateOrgVDCNetworkWithContext behaves like CreateOrgVDCNetwork, aborting
+// if ctx is cancelled before the operation completes.
+func (v *Vdc) CreateOrgVDCNetworkWithContext(ctx context.Context, networkConfig *types.OrgVDCNetwork) error {
 	for _, av := range v.Vdc.Link {
 		if av.Rel == "add" && av.Type == "application/vnd.vmware.vcloud.orgVdcNetwork+xml" {
 			u, err := url.ParseRequestURI(av.HREF)
 			//return fmt.Errorf("Test output: %#v")
 
 			if err != nil {
-				return fmt.Errorf("error decoding vdc response: %s", err)
+				return fmt.Errorf("error decoding vdc response: %w", err)
 			}
 
 			output, err := xml.MarshalIndent(networkConfig, "  ", "    ")
 			if err != nil {
-				return fmt.Errorf("error marshaling OrgVDCNetwork compose: %s", err)
+				return fmt.Errorf("error marshaling OrgVDCNetwork compose: %w", err)
 			}
 
 			//return fmt.Errorf("Test output: %s\n%#v", b, v.c)
 
-			var resp *http.Response
-			for {
-				b := bytes.NewBufferString(xml.Header + string(output))
-				log.Printf("[DEBUG] VCD Client configuration: %s", b)
-				req := v.c.NewRequest(map[string]string{}, "POST", *u, b)
-				req.Header.Add("Content-Type", av.Type)
-				resp, err = checkResp(v.c.Http.Do(req))
-				if err != nil {
-					if v, _ := regexp.MatchString("is busy, cannot proceed with the operation.$", err.Error()); v {
-						time.Sleep(3 * time.Second)
-						continue
-					}
-					return fmt.Errorf("error instantiating a new OrgVDCNetwork: %s", err)
-				}
-				break
+			b := bytes.NewBufferString(xml.Header + string(output))
+			req := v.c.NewRequestWithContext(ctx, map[string]string{}, "POST", *u, b)
+			req.Header.Add("Content-Type", av.Type)
+			resp, err := v.c.doRequest(req)
+			if err != nil {
+				return fmt.Errorf("error instantiating a new OrgVDCNetwork: %w", err)
 			}
 			newstuff := NewOrgVDCNetwork(v.c)
 			if err = decodeBody(resp, newstuff.OrgVDCNetwork); err != nil {
-				return fmt.Errorf("error decoding orgvdcnetwork response: %s", err)
+				return fmt.Errorf("error decoding orgvdcnetwork response: %w", err)
 			}
 			task := NewTask(v.c)
 			for _, t := range newstuff.OrgVDCNetwork.Tasks.Task {
 				task.Task = t
-				err = task.WaitTaskCompletion()
+				err = task.WaitTaskCompletionWithContext(ctx)
 				if err != nil {
-					return fmt.Errorf("Error performing task: %#v", err)
+					return fmt.Errorf("Error performing task: %w", err)
 				}
 			}
 		}
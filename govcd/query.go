@@ -5,14 +5,22 @@
 package govcd
 
 import (
+	"context"
 	"fmt"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
 
 	types "github.com/vmware/go-vcloud-director/types/v56"
 )
 
 type Results struct {
 	Results *types.QueryResultRecordsType
-	c       *Client
+	// References holds the results of a query in references format.
+	References *types.QueryResultReferencesType
+	c          *Client
 }
 
 func NewResults(c *Client) *Results {
@@ -23,20 +31,494 @@ func NewResults(c *Client) *Results {
 }
 
 func (c *VCDClient) Query(params map[string]string) (Results, error) {
+	return c.QueryWithContext(context.Background(), params)
+}
+
+// QueryWithContext runs a query against the vCD query service, aborting
+// if ctx is cancelled before the results are returned.
+func (c *VCDClient) QueryWithContext(ctx context.Context, params map[string]string) (Results, error) {
+	return c.Client.query(ctx, c.QueryHREF, params)
+}
+
+// query runs a query against the query service at queryHREF, for the
+// entities holding a Client but no VCDClient.
+func (c *Client) query(ctx context.Context, queryHREF url.URL, params map[string]string) (Results, error) {
 
-	req := c.Client.NewRequest(params, "GET", c.QueryHREF, nil)
-	req.Header.Add("Accept", "vnd.vmware.vcloud.org+xml;version=5.5")
+	req := c.NewRequestWithContext(ctx, params, "GET", queryHREF, nil)
+	req.Header.Add("Accept", "vnd.vmware.vcloud.org+xml;version="+c.APIVersion)
 
-	resp, err := checkResp(c.Client.Http.Do(req))
+	resp, err := c.doRequest(req)
 	if err != nil {
-		return Results{}, fmt.Errorf("error retreiving query: %s", err)
+		return Results{}, fmt.Errorf("error retreiving query: %w", err)
 	}
 
-	results := NewResults(&c.Client)
+	results := NewResults(c)
 
-	if err = decodeBody(resp, results.Results); err != nil {
-		return Results{}, fmt.Errorf("error decoding query results: %s", err)
+	if params["format"] == string(QueryFormatReferences) {
+		results.Results = nil
+		results.References = new(types.QueryResultReferencesType)
+		err = decodeBody(resp, results.References)
+	} else {
+		err = decodeBody(resp, results.Results)
+	}
+	if err != nil {
+		return Results{}, fmt.Errorf("error decoding query results: %w", err)
 	}
 
 	return *results, nil
 }
+
+// RunQuery runs the query built with q.
+func (c *VCDClient) RunQuery(q *QueryBuilder) (Results, error) {
+	return c.RunQueryWithContext(context.Background(), q)
+}
+
+// RunQueryWithContext runs the query built with q, aborting if ctx is
+// cancelled before the results are returned.
+func (c *VCDClient) RunQueryWithContext(ctx context.Context, q *QueryBuilder) (Results, error) {
+	return c.QueryWithContext(ctx, q.Params())
+}
+
+// GetQueryList returns the list of the queries available to the user.
+func (c *VCDClient) GetQueryList() (*types.QueryList, error) {
+	return c.GetQueryListWithContext(context.Background())
+}
+
+// GetQueryListWithContext returns the list of the queries available to the
+// user, aborting if ctx is cancelled first.
+func (c *VCDClient) GetQueryListWithContext(ctx context.Context) (*types.QueryList, error) {
+	req := c.Client.NewRequestWithContext(ctx, map[string]string{}, "GET", c.QueryHREF, nil)
+
+	resp, err := c.Client.doRequest(req)
+	if err != nil {
+		return nil, fmt.Errorf("error retreiving query list: %w", err)
+	}
+
+	queryList := new(types.QueryList)
+	if err = decodeBody(resp, queryList); err != nil {
+		return nil, fmt.Errorf("error decoding query list: %w", err)
+	}
+	return queryList, nil
+}
+
+// GetQueryTypes returns the sorted names of the query types available to
+// the user, e.g. "vApp" or "vm".
+func (c *VCDClient) GetQueryTypes() ([]string, error) {
+	queryList, err := c.GetQueryList()
+	if err != nil {
+		return nil, err
+	}
+	seen := map[string]bool{}
+	var queryTypes []string
+	for _, link := range queryList.Link {
+		if link.Name != "" && !seen[link.Name] {
+			seen[link.Name] = true
+			queryTypes = append(queryTypes, link.Name)
+		}
+	}
+	sort.Strings(queryTypes)
+	return queryTypes, nil
+}
+
+// QueryFormat is the format of the results of a query.
+type QueryFormat string
+
+const (
+	// QueryFormatRecords returns records holding the attributes of the
+	// results. It is the default format.
+	QueryFormatRecords QueryFormat = "records"
+	// QueryFormatReferences returns references to the results.
+	QueryFormatReferences QueryFormat = "references"
+	// QueryFormatIdRecords returns records identifying the results by ID
+	// rather than by HREF.
+	QueryFormatIdRecords QueryFormat = "idrecords"
+)
+
+// QueryFilter is a filter expression of the query service. Filters are
+// built with FilterEq, FilterNe, FilterLt, FilterLe, FilterGt, FilterGe and
+// combined with FilterAnd and FilterOr.
+type QueryFilter struct {
+	expression string
+	// or is set for a disjunction, which has to be parenthesized when
+	// it is part of a conjunction.
+	or bool
+}
+
+// String returns the filter expression, e.g. "name==web;numberOfVMs=gt=2".
+func (f QueryFilter) String() string {
+	return f.expression
+}
+
+// filterEscaper escapes the characters of filter values having a meaning in
+// filter expressions.
+var filterEscaper = strings.NewReplacer(`\`, `\\`, `(`, `\(`, `)`, `\)`, `;`, `\;`, `,`, `\,`)
+
+func comparison(attribute, operator, value string) QueryFilter {
+	return QueryFilter{expression: attribute + operator + filterEscaper.Replace(value)}
+}
+
+// FilterEq matches the results having attribute equal to value. The value
+// may contain the '*' wildcard.
+func FilterEq(attribute, value string) QueryFilter {
+	return comparison(attribute, "==", value)
+}
+
+// FilterNe matches the results having attribute different from value.
+func FilterNe(attribute, value string) QueryFilter {
+	return comparison(attribute, "!=", value)
+}
+
+// FilterLt matches the results having attribute lower than value.
+func FilterLt(attribute, value string) QueryFilter {
+	return comparison(attribute, "=lt=", value)
+}
+
+// FilterLe matches the results having attribute lower than or equal to
+// value.
+func FilterLe(attribute, value string) QueryFilter {
+	return comparison(attribute, "=le=", value)
+}
+
+// FilterGt matches the results having attribute greater than value.
+func FilterGt(attribute, value string) QueryFilter {
+	return comparison(attribute, "=gt=", value)
+}
+
+// FilterGe matches the results having attribute greater than or equal to
+// value.
+func FilterGe(attribute, value string) QueryFilter {
+	return comparison(attribute, "=ge=", value)
+}
+
+// FilterAnd matches the results matched by all the filters.
+func FilterAnd(filters ...QueryFilter) QueryFilter {
+	var operands []QueryFilter
+	for _, filter := range filters {
+		if filter.expression != "" {
+			operands = append(operands, filter)
+		}
+	}
+	if len(operands) == 1 {
+		return operands[0]
+	}
+	var parts []string
+	for _, filter := range operands {
+		if filter.or {
+			parts = append(parts, "("+filter.expression+")")
+		} else {
+			parts = append(parts, filter.expression)
+		}
+	}
+	return QueryFilter{expression: strings.Join(parts, ";")}
+}
+
+// FilterOr matches the results matched by any of the filters.
+func FilterOr(filters ...QueryFilter) QueryFilter {
+	var operands []QueryFilter
+	for _, filter := range filters {
+		if filter.expression != "" {
+			operands = append(operands, filter)
+		}
+	}
+	if len(operands) == 1 {
+		return operands[0]
+	}
+	var parts []string
+	for _, filter := range operands {
+		parts = append(parts, filter.expression)
+	}
+	return QueryFilter{expression: strings.Join(parts, ","), or: len(parts) > 1}
+}
+
+// QueryBuilder builds the parameters of a typed query:
+//
+//	q := NewQuery("vm").
+//		Filter(FilterEq("containerName", "web")).
+//		SortAsc("name").
+//		Fields("name", "status")
+//	results, err := vcdClient.RunQuery(q)
+type QueryBuilder struct {
+	queryType string
+	filter    QueryFilter
+	sortAsc   string
+	sortDesc  string
+	fields    []string
+	format    QueryFormat
+	page      int
+	pageSize  int
+}
+
+// NewQuery returns a builder for a query of the given type, e.g. "vApp".
+// The available types are returned by GetQueryTypes.
+func NewQuery(queryType string) *QueryBuilder {
+	return &QueryBuilder{queryType: queryType}
+}
+
+// Filter restricts the results to the ones matched by filter. Filters set
+// by successive calls must all match.
+func (q *QueryBuilder) Filter(filter QueryFilter) *QueryBuilder {
+	q.filter = FilterAnd(q.filter, filter)
+	return q
+}
+
+// SortAsc sorts the results by ascending values of attribute.
+func (q *QueryBuilder) SortAsc(attribute string) *QueryBuilder {
+	q.sortAsc, q.sortDesc = attribute, ""
+	return q
+}
+
+// SortDesc sorts the results by descending values of attribute.
+func (q *QueryBuilder) SortDesc(attribute string) *QueryBuilder {
+	q.sortAsc, q.sortDesc = "", attribute
+	return q
+}
+
+// Fields restricts the attributes returned in the records.
+func (q *QueryBuilder) Fields(attributes ...string) *QueryBuilder {
+	q.fields = append(q.fields, attributes...)
+	return q
+}
+
+// Format sets the format of the results.
+func (q *QueryBuilder) Format(format QueryFormat) *QueryBuilder {
+	q.format = format
+	return q
+}
+
+// Page selects the page of results to return, the first page being 1.
+func (q *QueryBuilder) Page(page int) *QueryBuilder {
+	q.page = page
+	return q
+}
+
+// PageSize sets the number of results per page.
+func (q *QueryBuilder) PageSize(pageSize int) *QueryBuilder {
+	q.pageSize = pageSize
+	return q
+}
+
+// Params returns the parameters of the query, as taken by Query.
+func (q *QueryBuilder) Params() map[string]string {
+	params := map[string]string{"type": q.queryType}
+	if q.filter.expression != "" {
+		params["filter"] = q.filter.expression
+	}
+	if q.sortAsc != "" {
+		params["sortAsc"] = q.sortAsc
+	}
+	if q.sortDesc != "" {
+		params["sortDesc"] = q.sortDesc
+	}
+	if len(q.fields) > 0 {
+		params["fields"] = strings.Join(q.fields, ",")
+	}
+	if q.format != "" {
+		params["format"] = string(q.format)
+	}
+	if q.page > 0 {
+		params["page"] = strconv.Itoa(q.page)
+	}
+	if q.pageSize > 0 {
+		params["pageSize"] = strconv.Itoa(q.pageSize)
+	}
+	return params
+}
+
+// QueryPages iterates lazily over the pages of the results of a query:
+//
+//	pages := vcdClient.NewQueryPages(ctx, NewQuery("vm").PageSize(128))
+//	for pages.Next() {
+//		for _, vm := range pages.Page().Results.VMRecord {
+//			...
+//		}
+//	}
+//	if err := pages.Err(); err != nil {
+//		...
+//	}
+//
+// A QueryPages must not be used concurrently.
+type QueryPages struct {
+	c         *Client
+	queryHREF url.URL
+	ctx       context.Context
+	params    map[string]string
+	prefetch  int
+
+	page     int
+	lastPage int
+	hasNext  bool
+	current  Results
+	pending  map[int]chan queryPage
+	err      error
+}
+
+// queryPage is the outcome of the request for a page.
+type queryPage struct {
+	results Results
+	err     error
+}
+
+// NewQueryPages returns an iterator over the pages of the results of q,
+// starting at the page set on q, or the first one.
+func (c *VCDClient) NewQueryPages(ctx context.Context, q *QueryBuilder) *QueryPages {
+	return c.Client.newQueryPages(ctx, c.QueryHREF, q)
+}
+
+// newQueryPages returns an iterator over the pages of the results of q
+// from the query service at queryHREF, for the entities holding a Client
+// but no VCDClient.
+func (c *Client) newQueryPages(ctx context.Context, queryHREF url.URL, q *QueryBuilder) *QueryPages {
+	page := q.page
+	if page < 1 {
+		page = 1
+	}
+	return &QueryPages{
+		c:         c,
+		queryHREF: queryHREF,
+		ctx:       ctx,
+		params:    q.Params(),
+		page:      page - 1,
+		hasNext:   true,
+		pending:   map[int]chan queryPage{},
+	}
+}
+
+// Prefetch makes the iterator request up to n of the following pages in
+// parallel, while the current one is processed. It must be called before
+// the first call to Next.
+func (p *QueryPages) Prefetch(n int) *QueryPages {
+	p.prefetch = n
+	return p
+}
+
+// Next fetches the next page of results. It returns false when there are
+// no more pages or when the request failed, see Err.
+func (p *QueryPages) Next() bool {
+	if p.err != nil || !p.hasNext {
+		return false
+	}
+	p.page++
+	if p.lastPage > 0 {
+		for page := p.page; page <= p.page+p.prefetch && page <= p.lastPage; page++ {
+			p.fetch(page)
+		}
+	}
+	fetched := <-p.fetch(p.page)
+	delete(p.pending, p.page)
+	if fetched.err != nil {
+		p.err = fetched.err
+		return false
+	}
+	p.current = fetched.results
+
+	pageSize, total, nextLink := pageInfo(p.current)
+	if pageSize > 0 && p.lastPage == 0 {
+		p.lastPage = (total + pageSize - 1) / pageSize
+	}
+	p.hasNext = nextLink || (pageSize > 0 && p.page*pageSize < total)
+	return true
+}
+
+// fetch requests page in the background, unless it is already requested,
+// and returns the channel receiving the outcome.
+func (p *QueryPages) fetch(page int) chan queryPage {
+	if fetched, ok := p.pending[page]; ok {
+		return fetched
+	}
+	params := make(map[string]string, len(p.params)+1)
+	for key, value := range p.params {
+		params[key] = value
+	}
+	params["page"] = strconv.Itoa(page)
+
+	fetched := make(chan queryPage, 1)
+	p.pending[page] = fetched
+	go func() {
+		results, err := p.c.query(p.ctx, p.queryHREF, params)
+		if err != nil {
+			err = fmt.Errorf("error retrieving page %d: %w", page, err)
+		}
+		fetched <- queryPage{results: results, err: err}
+	}()
+	return fetched
+}
+
+// Page returns the page fetched by the last call to Next.
+func (p *QueryPages) Page() Results {
+	return p.current
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (p *QueryPages) Err() error {
+	return p.err
+}
+
+// All collects the records, or the references, of all the remaining pages
+// into a single Results.
+func (p *QueryPages) All() (Results, error) {
+	var all Results
+	for p.Next() {
+		if all.c == nil {
+			all = p.current
+			if all.Results != nil {
+				first := *all.Results
+				first.Link = nil
+				all.Results = &first
+			}
+			if all.References != nil {
+				first := *all.References
+				first.Link = nil
+				all.References = &first
+			}
+			continue
+		}
+		if all.Results != nil {
+			appendRecords(all.Results, p.current.Results)
+		}
+		if all.References != nil {
+			all.References.Reference = append(all.References.Reference, p.current.References.Reference...)
+		}
+	}
+	if p.err != nil {
+		return Results{}, p.err
+	}
+	return all, nil
+}
+
+// QueryAll runs the query built with q and returns the records, or the
+// references, of all its pages.
+func (c *VCDClient) QueryAll(q *QueryBuilder) (Results, error) {
+	return c.NewQueryPages(context.Background(), q).All()
+}
+
+// pageInfo returns the page size and the total number of results of a
+// page, and whether it links to a next page.
+func pageInfo(results Results) (pageSize, total int, nextLink bool) {
+	var links []*types.Link
+	switch {
+	case results.Results != nil:
+		pageSize, total, links = results.Results.PageSize, int(results.Results.Total), results.Results.Link
+	case results.References != nil:
+		pageSize, total, links = results.References.PageSize, int(results.References.Total), results.References.Link
+	}
+	for _, link := range links {
+		if link.Rel == "nextPage" {
+			nextLink = true
+		}
+	}
+	return pageSize, total, nextLink
+}
+
+// appendRecords appends the records of every type held by from to the ones
+// of to.
+func appendRecords(to, from *types.QueryResultRecordsType) {
+	toValue := reflect.ValueOf(to).Elem()
+	fromValue := reflect.ValueOf(from).Elem()
+	for i := 0; i < toValue.NumField(); i++ {
+		field := toValue.Field(i)
+		if field.Kind() != reflect.Slice || toValue.Type().Field(i).Name == "Link" {
+			continue
+		}
+		field.Set(reflect.AppendSlice(field, fromValue.Field(i)))
+	}
+}
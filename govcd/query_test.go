@@ -5,6 +5,15 @@
 package govcd
 
 import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"testing"
+
+	types "github.com/vmware/go-vcloud-director/types/v56"
 	. "gopkg.in/check.v1"
 )
 
@@ -14,3 +23,222 @@ func (vcd *TestVCD) Test_Query(check *C) {
 	_, err := vcd.client.Query(map[string]string{"type": "vm"})
 	check.Assert(err, IsNil)
 }
+
+func TestQueryBuilder_Params(t *testing.T) {
+	q := NewQuery("vApp").
+		Filter(FilterEq("name", "web;(1),2")).
+		Filter(FilterOr(FilterGt("numberOfVMs", "2"), FilterNe("status", "POWERED_ON"))).
+		SortDesc("creationDate").
+		Fields("name", "status").
+		Format(QueryFormatIdRecords).
+		PageSize(10)
+	expected := map[string]string{
+		"type":     "vApp",
+		"filter":   `name==web\;\(1\)\,2;(numberOfVMs=gt=2,status!=POWERED_ON)`,
+		"sortDesc": "creationDate",
+		"fields":   "name,status",
+		"format":   "idrecords",
+		"pageSize": "10",
+	}
+	params := q.Params()
+	if len(params) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, params)
+	}
+	for key, value := range expected {
+		if params[key] != value {
+			t.Fatalf("expected %s=%s, got %s", key, value, params[key])
+		}
+	}
+
+	// A single disjunction needs no parentheses
+	filter := FilterAnd(QueryFilter{}, FilterOr(FilterEq("a", "1"), FilterEq("b", "2")))
+	if filter.String() != "a==1,b==2" {
+		t.Fatalf("unexpected filter %s", filter)
+	}
+	if filter = FilterAnd(filter, FilterLe("c", "3")); filter.String() != "(a==1,b==2);c=le=3" {
+		t.Fatalf("unexpected filter %s", filter)
+	}
+
+	// A single operand keeps its own precedence
+	a, b, c := FilterEq("a", "1"), FilterEq("b", "2"), FilterEq("c", "3")
+	tests := []struct {
+		filter   QueryFilter
+		expected string
+	}{
+		{FilterAnd(FilterOr(FilterOr(a, b)), c), "(a==1,b==2);c==3"},
+		{FilterAnd(FilterOr(QueryFilter{}, FilterOr(a, b)), c), "(a==1,b==2);c==3"},
+		{FilterOr(FilterAnd(a, b)), "a==1;b==2"},
+		{FilterOr(FilterAnd(FilterOr(a, b)), c), "a==1,b==2,c==3"},
+		{FilterOr(), ""},
+	}
+	for _, test := range tests {
+		if test.filter.String() != test.expected {
+			t.Fatalf("expected filter %s, got %s", test.expected, test.filter)
+		}
+	}
+}
+
+func TestVCDClient_RunQueryFakeVCD(t *testing.T) {
+	server, fakeVdc, client := newFakeVCD(t)
+	defer server.Close()
+	fakeVdc.AddVApp("web")
+	db := fakeVdc.AddVApp("db")
+	db.AddVM("db1")
+	db.AddVM("db2")
+	fakeVdc.AddVApp("cache").AddVM("cache1")
+
+	results, err := client.RunQuery(NewQuery("vApp").
+		Filter(FilterOr(FilterEq("name", "w*"), FilterGe("numberOfVMs", "2"))).
+		SortDesc("name"))
+	if err != nil {
+		t.Fatalf("error running query: %s", err)
+	}
+	records := results.Results.VAppRecord
+	if len(records) != 2 || records[0].Name != "web" || records[1].Name != "db" {
+		t.Fatalf("unexpected records %+v", records)
+	}
+
+	results, err = client.RunQuery(NewQuery("vm").Format(QueryFormatReferences).SortAsc("name"))
+	if err != nil {
+		t.Fatalf("error running query: %s", err)
+	}
+	references := results.References.Reference
+	if len(references) != 3 || references[0].Name != "cache1" || references[0].HREF == "" {
+		t.Fatalf("unexpected references %+v", references)
+	}
+
+	queryTypes, err := client.GetQueryTypes()
+	if err != nil {
+		t.Fatalf("error getting query types: %s", err)
+	}
+	if i := sort.SearchStrings(queryTypes, "vm"); !sort.StringsAreSorted(queryTypes) || i == len(queryTypes) || queryTypes[i] != "vm" {
+		t.Fatalf("unexpected query types %v", queryTypes)
+	}
+}
+
+func TestQueryPagesFakeVCD(t *testing.T) {
+	server, fakeVdc, client := newFakeVCD(t)
+	defer server.Close()
+	vapp := fakeVdc.AddVApp("vapp")
+	for i := 0; i < 7; i++ {
+		vapp.AddVM(fmt.Sprintf("vm%d", i))
+	}
+	q := NewQuery("vm").SortAsc("name").PageSize(3)
+
+	var pages []int
+	pagesIterator := client.NewQueryPages(context.Background(), q)
+	for pagesIterator.Next() {
+		pages = append(pages, len(pagesIterator.Page().Results.VMRecord))
+	}
+	if err := pagesIterator.Err(); err != nil || len(pages) != 3 || pages[0] != 3 || pages[2] != 1 {
+		t.Fatalf("unexpected pages %v, %v", pages, err)
+	}
+
+	for _, prefetch := range []int{0, 2} {
+		results, err := client.NewQueryPages(context.Background(), q).Prefetch(prefetch).All()
+		if err != nil {
+			t.Fatalf("error collecting the records: %s", err)
+		}
+		records := results.Results.VMRecord
+		if len(records) != 7 || int(results.Results.Total) != 7 {
+			t.Fatalf("expected 7 records, got %d", len(records))
+		}
+		for i, record := range records {
+			if record.Name != fmt.Sprintf("vm%d", i) {
+				t.Fatalf("unexpected record %d: %s", i, record.Name)
+			}
+		}
+	}
+
+	results, err := client.QueryAll(NewQuery("vm").Format(QueryFormatReferences).PageSize(5))
+	if err != nil || len(results.References.Reference) != 7 {
+		t.Fatalf("expected 7 references, got %+v, %v", results.References, err)
+	}
+
+	// An error stops the iteration
+	pagesIterator = client.NewQueryPages(context.Background(), q)
+	if !pagesIterator.Next() {
+		t.Fatalf("error fetching the first page: %v", pagesIterator.Err())
+	}
+	server.InjectError(http.MethodGet, "/query", 1, http.StatusForbidden, types.Error{MajorErrorCode: http.StatusForbidden, Message: "forbidden"})
+	var vcdErr *VCDError
+	if pagesIterator.Next() || !errors.As(pagesIterator.Err(), &vcdErr) || vcdErr.HTTPStatus != http.StatusForbidden {
+		t.Fatalf("expected a forbidden error, got %v", pagesIterator.Err())
+	}
+}
+
+func TestQueryResultRecordsType_Decode(t *testing.T) {
+	body := `<QueryResultRecords xmlns="http://www.vmware.com/vcloud/v1.5" total="6" pageSize="25" page="1">
+	<MediaRecord name="ubuntu.iso" storageB="1048576" catalogName="iso" isBusy="false"/>
+	<DiskRecord name="data" sizeB="2147483648" isAttached="true" busType="6"/>
+	<UserRecord name="alice" fullName="Alice Smith" isEnabled="true" isLdapUser="true" numDeployedVMs="3"/>
+	<GroupRecord name="admins" roleName="Organization Administrator" identityProviderType="INTEGRATED"/>
+	<EventRecord eventType="com/vmware/vcloud/event/vapp/deploy" eventStatus="1" entityName="web" userName="alice"/>
+	<NetworkRecord name="public" gateway="10.0.0.1" netmask="255.255.255.0" vcName="vc1"/>
+</QueryResultRecords>`
+	var results types.QueryResultRecordsType
+	if err := xml.Unmarshal([]byte(body), &results); err != nil {
+		t.Fatalf("error decoding records: %s", err)
+	}
+	if len(results.MediaRecord) != 1 || results.MediaRecord[0].StorageB != 1048576 ||
+		len(results.DiskRecord) != 1 || !results.DiskRecord[0].IsAttached ||
+		len(results.UserRecord) != 1 || results.UserRecord[0].NumDeployedVMs != 3 || !results.UserRecord[0].IsLdapUser ||
+		len(results.GroupRecord) != 1 || results.GroupRecord[0].IdentityProviderType != "INTEGRATED" ||
+		len(results.EventRecord) != 1 || results.EventRecord[0].EventStatus != 1 ||
+		len(results.NetworkRecord) != 1 || results.NetworkRecord[0].Gateway != "10.0.0.1" {
+		t.Fatalf("unexpected records %+v", results)
+	}
+}
+
+func TestVCDClient_QueryRecordTypesFakeVCD(t *testing.T) {
+	server, fakeVdc, client := newFakeVCD(t)
+	defer server.Close()
+	fakeVdc.AddVApp("vapp")
+	fakeVdc.AddNetwork("net")
+	fakeVdc.Org().AddCatalog("catalog").AddItem("template")
+
+	for _, query := range []struct {
+		queryType string
+		count     func(*types.QueryResultRecordsType) int
+	}{
+		{"orgVdc", func(r *types.QueryResultRecordsType) int { return len(r.OrgVdcRecord) }},
+		{"adminOrgVdc", func(r *types.QueryResultRecordsType) int { return len(r.AdminVdcRecord) }},
+		{"orgVdcNetwork", func(r *types.QueryResultRecordsType) int { return len(r.OrgVdcNetworkRecord) }},
+		{"catalog", func(r *types.QueryResultRecordsType) int { return len(r.CatalogRecord) }},
+		{"catalogItem", func(r *types.QueryResultRecordsType) int { return len(r.CatalogItemRecord) }},
+		{"vAppTemplate", func(r *types.QueryResultRecordsType) int { return len(r.VAppTemplateRecord) }},
+	} {
+		results, err := client.QueryAll(NewQuery(query.queryType))
+		if err != nil {
+			t.Fatalf("error querying %s: %s", query.queryType, err)
+		}
+		if count := query.count(results.Results); count != 1 {
+			t.Fatalf("expected one %s record, got %d", query.queryType, count)
+		}
+	}
+
+	results, err := client.RunQuery(NewQuery("orgVdc").Filter(FilterEq("name", "vdc")))
+	if err != nil || results.Results.OrgVdcRecord[0].NumberOfVApps != 1 {
+		t.Fatalf("unexpected VDC record %+v, %v", results.Results.OrgVdcRecord, err)
+	}
+
+	_, vdc := fakeOrgVdc(t, client)
+	vapp, err := vdc.FindVAppByName("vapp")
+	if err != nil {
+		t.Fatalf("error finding vapp: %s", err)
+	}
+	task, err := vapp.PowerOn()
+	if err != nil {
+		t.Fatalf("error powering on: %s", err)
+	}
+	if err = task.WaitTaskCompletion(); err != nil {
+		t.Fatalf("error waiting for power on: %s", err)
+	}
+	results, err = client.RunQuery(NewQuery("task").Filter(FilterEq("objectName", "vapp")))
+	if err != nil || len(results.Results.TaskRecord) != 1 {
+		t.Fatalf("expected one task record, got %+v, %v", results.Results, err)
+	}
+	if record := results.Results.TaskRecord[0]; record.Name != "vappPowerOn" || record.Status != "success" || record.OrgName != "org" {
+		t.Fatalf("unexpected task record %+v", record)
+	}
+}
@@ -0,0 +1,128 @@
+/*
+ * Copyright 2014 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// RateLimit limits the requests a client sends to vCD, so that bulk jobs
+// stay within the limits of the provider. Every attempt of a request counts,
+// retries included.
+type RateLimit struct {
+	// RequestsPerSecond is the sustained rate of requests. Zero means no
+	// rate limit.
+	RequestsPerSecond float64
+	// Burst is the number of requests that can be sent at once before the
+	// rate applies. Values lower than 1 mean 1.
+	Burst int
+	// MaxInFlight is the maximum number of requests waiting for a response
+	// at the same time. Zero means no limit.
+	MaxInFlight int
+}
+
+// limiter enforces a RateLimit with a token bucket and a semaphore.
+type limiter struct {
+	rate  float64
+	burst float64
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+
+	inFlight chan struct{}
+}
+
+func newLimiter(limit RateLimit) *limiter {
+	l := &limiter{rate: limit.RequestsPerSecond, burst: float64(limit.Burst)}
+	if l.burst < 1 {
+		l.burst = 1
+	}
+	l.tokens = l.burst
+	if limit.MaxInFlight > 0 {
+		l.inFlight = make(chan struct{}, limit.MaxInFlight)
+	}
+	return l
+}
+
+// acquire waits until a request can be sent, or ctx is done. The returned
+// function must be called once the response body is read.
+func (l *limiter) acquire(ctx context.Context) (func(), error) {
+	if l == nil {
+		return func() {}, nil
+	}
+	if err := l.take(ctx); err != nil {
+		return nil, err
+	}
+	if l.inFlight == nil {
+		return func() {}, nil
+	}
+	select {
+	case l.inFlight <- struct{}{}:
+		return func() { <-l.inFlight }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// take takes a token from the bucket, waiting for one to be available.
+func (l *limiter) take(ctx context.Context) error {
+	if l.rate <= 0 {
+		return nil
+	}
+	for {
+		l.mu.Lock()
+		now := time.Now()
+		if !l.last.IsZero() {
+			l.tokens += now.Sub(l.last).Seconds() * l.rate
+			if l.tokens > l.burst {
+				l.tokens = l.burst
+			}
+		}
+		l.last = now
+		if l.tokens >= 1 {
+			l.tokens--
+			l.mu.Unlock()
+			return nil
+		}
+		wait := time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+		l.mu.Unlock()
+		if err := sleepWithContext(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
+
+type uploadKey struct{}
+
+// withUpload marks the requests created with ctx as file uploads, limited
+// by the upload rate limit of the client.
+func withUpload(ctx context.Context) context.Context {
+	return context.WithValue(ctx, uploadKey{}, true)
+}
+
+// limiterFor returns the limiter applying to req. Uploads fall back to the
+// API limiter when the client has no upload limiter.
+func (c *Client) limiterFor(req *http.Request) *limiter {
+	if upload, _ := req.Context().Value(uploadKey{}).(bool); upload && c.uploadLimiter != nil {
+		return c.uploadLimiter
+	}
+	return c.apiLimiter
+}
+
+// SetRateLimit limits the API calls of the client. Uploads share this limit
+// unless SetUploadRateLimit is used.
+func (c *VCDClient) SetRateLimit(limit RateLimit) {
+	c.Client.apiLimiter = newLimiter(limit)
+}
+
+// SetUploadRateLimit limits the file uploads of the client, such as the
+// ones of UploadOvf, separately from the API calls.
+func (c *VCDClient) SetUploadRateLimit(limit RateLimit) {
+	c.Client.uploadLimiter = newLimiter(limit)
+}
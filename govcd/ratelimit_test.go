@@ -0,0 +1,145 @@
+/*
+ * Copyright 2014 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClient_sendRateLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+	client := &Client{VCDHREF: *u, apiLimiter: newLimiter(RateLimit{RequestsPerSecond: 50, Burst: 2})}
+
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		req := client.NewRequest(map[string]string{}, "GET", client.VCDHREF, nil)
+		if _, err := client.doRequest(req); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+	// The burst allows two requests at once, the next two wait 20ms each
+	if elapsed := time.Since(start); elapsed < 35*time.Millisecond {
+		t.Fatalf("expected the requests to be throttled, took %s", elapsed)
+	}
+
+	// Waiting for a token stops with the request context
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	client.apiLimiter = newLimiter(RateLimit{RequestsPerSecond: 0.001})
+	client.apiLimiter.tokens = 0
+	req := client.NewRequestWithContext(ctx, map[string]string{}, "GET", client.VCDHREF, nil)
+	if _, err := client.doRequest(req); err != context.Canceled {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestClient_sendMaxInFlight(t *testing.T) {
+	var current, max int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&current, 1)
+		for {
+			m := atomic.LoadInt32(&max)
+			if n <= m || atomic.CompareAndSwapInt32(&max, m, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		atomic.AddInt32(&current, -1)
+	}))
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+	client := &Client{VCDHREF: *u, apiLimiter: newLimiter(RateLimit{MaxInFlight: 2})}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req := client.NewRequest(map[string]string{}, "GET", client.VCDHREF, nil)
+			resp, err := client.doRequest(req)
+			if err != nil {
+				t.Errorf("unexpected error: %s", err)
+				return
+			}
+			resp.Body.Close()
+		}()
+	}
+	wg.Wait()
+	if max != 2 {
+		t.Fatalf("expected at most 2 requests in flight, got %d", max)
+	}
+}
+
+func TestClient_limiterFor(t *testing.T) {
+	client := &Client{}
+	api := httptest.NewRequest("GET", "/api/vdc/1", nil)
+	upload := api.WithContext(withUpload(context.Background()))
+	if client.limiterFor(api) != nil || client.limiterFor(upload) != nil {
+		t.Fatal("expected no limiter")
+	}
+
+	client.apiLimiter = newLimiter(RateLimit{RequestsPerSecond: 10})
+	if client.limiterFor(upload) != client.apiLimiter {
+		t.Fatal("expected uploads to share the API limiter")
+	}
+	client.uploadLimiter = newLimiter(RateLimit{MaxInFlight: 1})
+	if client.limiterFor(api) != client.apiLimiter || client.limiterFor(upload) != client.uploadLimiter {
+		t.Fatal("expected uploads to use their own limiter")
+	}
+}
+
+// Checks that a request stays in flight until its response body is read to
+// the end or closed.
+func TestClient_sendReleasesWithBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("<Task/>"))
+	}))
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+	client := &Client{VCDHREF: *u, apiLimiter: newLimiter(RateLimit{MaxInFlight: 1})}
+
+	inFlight := func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		resp, err := client.doRequest(client.NewRequestWithContext(ctx, map[string]string{}, "GET", client.VCDHREF, nil))
+		if err == nil {
+			resp.Body.Close()
+		}
+		return err
+	}
+
+	resp, err := client.doRequest(client.NewRequest(map[string]string{}, "GET", client.VCDHREF, nil))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err = inFlight(); err != context.DeadlineExceeded {
+		t.Fatalf("expected the unread response to hold the slot, got %v", err)
+	}
+	resp.Body.Close()
+	resp.Body.Close()
+	if err = inFlight(); err != nil {
+		t.Fatalf("expected the closed response to release the slot, got %v", err)
+	}
+
+	resp, err = client.doRequest(client.NewRequest(map[string]string{}, "GET", client.VCDHREF, nil))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err = decodeBody(resp, &struct{}{}); err != nil {
+		t.Fatalf("error decoding body: %s", err)
+	}
+	if err = inFlight(); err != nil {
+		t.Fatalf("expected the read response to release the slot, got %v", err)
+	}
+}
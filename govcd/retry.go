@@ -0,0 +1,174 @@
+/*
+ * Copyright 2014 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+// RetryPolicy controls how the request layer retries calls that fail
+// with a transient error, such as a 503 response or an entity that is busy
+// completing another operation.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first
+	// one. A value of 1 or lower disables retries.
+	MaxAttempts int
+	// InitialBackoff is the wait before the first retry. It doubles after
+	// every attempt.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the wait between two attempts. Zero means no cap.
+	MaxBackoff time.Duration
+	// Jitter randomizes every wait by up to this fraction of its value,
+	// e.g. 0.2 gives a wait between 80% and 120% of the computed backoff.
+	Jitter float64
+	// RetryableStatusCodes lists the HTTP status codes that are retried.
+	// They are only retried for GET, HEAD, PUT and DELETE requests, which
+	// can be repeated safely, and for the requests sent with a context
+	// returned by MarkRetryable. A POST that timed out may have been
+	// performed, and sending it again could create a duplicate.
+	RetryableStatusCodes []int
+	// RetryBusy retries the errors matching ErrBusy, whatever the method:
+	// vCD rejects the request of a busy entity without performing it.
+	RetryBusy bool
+}
+
+// DefaultRetryPolicy returns the retry policy used by NewVCDClient. It
+// replaces the loops that retried the edge gateway reconfigurations for as
+// long as the gateway was busy: they now give up after 5 attempts, about
+// 15 seconds, with the busy error.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:          5,
+		InitialBackoff:       time.Second,
+		MaxBackoff:           30 * time.Second,
+		Jitter:               0.2,
+		RetryableStatusCodes: []int{http.StatusServiceUnavailable, http.StatusGatewayTimeout},
+		RetryBusy:            true,
+	}
+}
+
+type retryableKey struct{}
+
+// MarkRetryable returns a copy of ctx making the requests sent with it
+// retried on the retryable status codes of the retry policy whatever their
+// method, for POST requests the caller knows can be repeated safely.
+func MarkRetryable(ctx context.Context) context.Context {
+	return context.WithValue(ctx, retryableKey{}, true)
+}
+
+// shouldRetry reports whether err, returned for req, is a transient error
+// according to the policy.
+func (p RetryPolicy) shouldRetry(req *http.Request, err error) bool {
+	if p.RetryBusy && IsBusy(err) {
+		return true
+	}
+	var vcdErr *VCDError
+	if !errors.As(err, &vcdErr) || !isRetryable(req) {
+		return false
+	}
+	for _, code := range p.RetryableStatusCodes {
+		if vcdErr.HTTPStatus == code {
+			return true
+		}
+	}
+	return false
+}
+
+// isRetryable tells whether req can be sent again after a failure that may
+// have happened once vCD performed it.
+func isRetryable(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	retryable, _ := req.Context().Value(retryableKey{}).(bool)
+	return retryable
+}
+
+// backoff returns the wait before the given retry, retry being 1 for the
+// wait that follows the first attempt.
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	d := p.InitialBackoff
+	for i := 1; i < retry; i++ {
+		d *= 2
+		if p.MaxBackoff > 0 && d >= p.MaxBackoff {
+			break
+		}
+	}
+	if p.MaxBackoff > 0 && d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+	if p.Jitter > 0 {
+		d = time.Duration(float64(d) * (1 - p.Jitter + 2*p.Jitter*rand.Float64()))
+	}
+	return d
+}
+
+// doRequest sends req and checks the response with checkResp. Transient
+// failures are retried according to the client retry policy, and a request
+// rejected because the session expired is sent again once after logging
+// in, as long as the request body can be replayed. The waits between
+// attempts stop when the request context is cancelled. Every attempt goes
+// through the client middlewares and is logged.
+func (c *Client) doRequest(req *http.Request) (*http.Response, error) {
+	reauthenticated := false
+	for attempt := 1; ; attempt++ {
+		resp, err := c.send(req)
+		if err == nil {
+			return resp, nil
+		}
+
+		header, _ := c.authorization()
+		if token := req.Header.Get(header); c.reauthenticate != nil && !reauthenticated && token != "" && errors.Is(err, ErrUnauthorized) {
+			if !rewindBody(req) {
+				return resp, err
+			}
+			c.log(LogInfo, "session expired, logging in again", "method", req.Method, "url", req.URL.String(), "error", err)
+			if authErr := c.reauthenticate(req.Context(), token); authErr != nil {
+				return nil, fmt.Errorf("error logging in again after %s: %w", err, authErr)
+			}
+			req.Header.Set(c.authorization())
+			c.metrics.observeRetry(err)
+			reauthenticated = true
+			// A new login does not use one of the retry attempts
+			attempt--
+			continue
+		}
+
+		if c.RetryPolicy == nil || attempt >= c.RetryPolicy.MaxAttempts || !c.RetryPolicy.shouldRetry(req, err) || !rewindBody(req) {
+			return resp, err
+		}
+
+		c.metrics.observeRetry(err)
+		wait := c.RetryPolicy.backoff(attempt)
+		c.log(LogWarn, "request failed, retrying", "method", req.Method, "url", req.URL.String(),
+			"attempt", attempt, "max_attempts", c.RetryPolicy.MaxAttempts, "wait", wait, "error", err)
+		if sleepErr := sleepWithContext(req.Context(), wait); sleepErr != nil {
+			return nil, sleepErr
+		}
+	}
+}
+
+// rewindBody prepares req to be sent again. It returns false when the
+// request body cannot be replayed.
+func rewindBody(req *http.Request) bool {
+	if req.Body == nil || req.Body == http.NoBody {
+		return true
+	}
+	if req.GetBody == nil {
+		return false
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return false
+	}
+	req.Body = body
+	return true
+}
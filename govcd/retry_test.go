@@ -0,0 +1,154 @@
+/*
+ * Copyright 2014 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+const busyErrorBody = `<Error xmlns="http://www.vmware.com/vcloud/v1.5" majorErrorCode="400" minorErrorCode="BUSY_ENTITY" ` +
+	`message="The entity gateway is busy completing an operation."></Error>`
+
+// newRetryTestClient returns a client pointing at a server that answers
+// the first failures requests with the given status and body, then 200.
+func newRetryTestClient(t *testing.T, failures, status int, body string, policy *RetryPolicy) (*Client, *[]string, func()) {
+	var received []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload, _ := ioutil.ReadAll(r.Body)
+		received = append(received, string(payload))
+		if len(received) <= failures {
+			w.WriteHeader(status)
+			_, _ = w.Write([]byte(body))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	u, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &Client{VCDHREF: *u, RetryPolicy: policy}, &received, server.Close
+}
+
+func TestClient_doRequestRetriesBusy(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, RetryBusy: true}
+	client, received, closeServer := newRetryTestClient(t, 2, http.StatusBadRequest, busyErrorBody, &policy)
+	defer closeServer()
+
+	req := client.NewRequest(map[string]string{}, "POST", client.VCDHREF, bytes.NewBufferString("payload"))
+	if _, err := client.doRequest(req); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(*received) != 3 {
+		t.Fatalf("expected 3 attempts, got %d", len(*received))
+	}
+	for _, payload := range *received {
+		if payload != "payload" {
+			t.Fatalf("expected the body to be replayed, got %q", payload)
+		}
+	}
+}
+
+func TestClient_doRequestMaxAttempts(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond, RetryableStatusCodes: []int{http.StatusServiceUnavailable}}
+	client, received, closeServer := newRetryTestClient(t, 5, http.StatusServiceUnavailable, "", &policy)
+	defer closeServer()
+
+	_, err := client.doRequest(client.NewRequest(map[string]string{}, "GET", client.VCDHREF, nil))
+	var vcdErr *VCDError
+	if !errors.As(err, &vcdErr) || vcdErr.HTTPStatus != http.StatusServiceUnavailable {
+		t.Fatalf("expected a 503 VCDError, got %v", err)
+	}
+	if len(*received) != 2 {
+		t.Fatalf("expected 2 attempts, got %d", len(*received))
+	}
+}
+
+func TestClient_doRequestNotRetryable(t *testing.T) {
+	policy := DefaultRetryPolicy()
+	client, received, closeServer := newRetryTestClient(t, 5, http.StatusNotFound, "", &policy)
+	defer closeServer()
+
+	_, err := client.doRequest(client.NewRequest(map[string]string{}, "GET", client.VCDHREF, nil))
+	if !IsNotFound(err) {
+		t.Fatalf("expected a not found error, got %v", err)
+	}
+	if len(*received) != 1 {
+		t.Fatalf("expected a single attempt, got %d", len(*received))
+	}
+
+	// Without a policy nothing is retried either
+	client.RetryPolicy = nil
+	*received = nil
+	_, _ = client.doRequest(client.NewRequest(map[string]string{}, "GET", client.VCDHREF, nil))
+	if len(*received) != 1 {
+		t.Fatalf("expected a single attempt, got %d", len(*received))
+	}
+}
+
+func TestClient_doRequestRetriesIdempotentMethods(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, RetryableStatusCodes: []int{http.StatusServiceUnavailable}}
+	tests := []struct {
+		method   string
+		ctx      context.Context
+		attempts int
+	}{
+		{"GET", context.Background(), 3},
+		{"PUT", context.Background(), 3},
+		{"DELETE", context.Background(), 3},
+		// A POST may have been performed, it is only retried when marked
+		{"POST", context.Background(), 1},
+		{"POST", MarkRetryable(context.Background()), 3},
+	}
+	for _, test := range tests {
+		client, received, closeServer := newRetryTestClient(t, 5, http.StatusServiceUnavailable, "", &policy)
+		req := client.NewRequestWithContext(test.ctx, map[string]string{}, test.method, client.VCDHREF, bytes.NewBufferString("payload"))
+		if _, err := client.doRequest(req); err == nil {
+			t.Fatalf("%s: expected a 503 error", test.method)
+		}
+		closeServer()
+		if len(*received) != test.attempts {
+			t.Fatalf("%s: expected %d attempts, got %d", test.method, test.attempts, len(*received))
+		}
+	}
+}
+
+func TestClient_doRequestContextCancelled(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 5, InitialBackoff: time.Hour, RetryBusy: true}
+	client, _, closeServer := newRetryTestClient(t, 5, http.StatusBadRequest, busyErrorBody, &policy)
+	defer closeServer()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := client.doRequest(client.NewRequestWithContext(ctx, map[string]string{}, "GET", client.VCDHREF, nil))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestRetryPolicy_backoff(t *testing.T) {
+	policy := RetryPolicy{InitialBackoff: time.Second, MaxBackoff: 5 * time.Second}
+	expected := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+	for i, want := range expected {
+		if got := policy.backoff(i + 1); got != want {
+			t.Fatalf("retry %d: expected %s, got %s", i+1, want, got)
+		}
+	}
+
+	policy.Jitter = 0.5
+	for i := 0; i < 20; i++ {
+		if got := policy.backoff(1); got < 500*time.Millisecond || got > 1500*time.Millisecond {
+			t.Fatalf("jittered backoff out of range: %s", got)
+		}
+	}
+}
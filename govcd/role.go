@@ -0,0 +1,421 @@
+/*
+ * Copyright 2014 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"net/url"
+
+	types "github.com/vmware/go-vcloud-director/types/v56"
+)
+
+// Role is a named set of rights. Global roles are managed by the system
+// administrator and have no AdminOrg, local roles belong to AdminOrg.
+//
+// Local roles and the rights of an org need API version 27.0, above the
+// versions govcd negotiates: a client using them must be created with
+// WithAPIVersion("27.0") or a later version offered by vCD.
+type Role struct {
+	Role     *types.Role
+	AdminOrg *AdminOrg
+	c        *Client
+}
+
+// NewRole returns an empty global role.
+func NewRole(c *Client) *Role {
+	return &Role{
+		Role: new(types.Role),
+		c:    c,
+	}
+}
+
+// GetRights returns the references to all the rights of the system.
+func (c *VCDClient) GetRights() ([]*types.Reference, error) {
+	vcloud, err := c.Client.getAdminVCloud()
+	if err != nil {
+		return nil, err
+	}
+	if vcloud.RightReferences == nil {
+		return nil, nil
+	}
+	return vcloud.RightReferences.RightReference, nil
+}
+
+// GetRightByName returns the right of the system having the given name,
+// e.g. "vApp: Power Operations".
+func (c *VCDClient) GetRightByName(name string) (*types.Right, error) {
+	rights, err := c.GetRights()
+	if err != nil {
+		return nil, err
+	}
+	return c.Client.getRight(rights, name)
+}
+
+// GetRoles returns the references to the global roles.
+func (c *VCDClient) GetRoles() ([]*types.Reference, error) {
+	vcloud, err := c.Client.getAdminVCloud()
+	if err != nil {
+		return nil, err
+	}
+	if vcloud.RoleReferences == nil {
+		return nil, nil
+	}
+	return vcloud.RoleReferences.RoleReference, nil
+}
+
+// GetRoleByName returns the global role having the given name. The
+// returned error matches ErrNotFound when there is no such role.
+func (c *VCDClient) GetRoleByName(name string) (*Role, error) {
+	roles, err := c.GetRoles()
+	if err != nil {
+		return nil, err
+	}
+	reference := findReference(roles, name)
+	if reference == nil {
+		return nil, fmt.Errorf("role %s: %w", name, ErrNotFound)
+	}
+	return getRoleByHREF(&c.Client, nil, reference.HREF)
+}
+
+// CreateRole creates a global role having the rights with the given
+// names.
+func (c *VCDClient) CreateRole(name, description string, rightNames ...string) (*Role, error) {
+	available, err := c.GetRights()
+	if err != nil {
+		return nil, err
+	}
+	rolesHREF := c.Client.VCDHREF
+	rolesHREF.Path += "/admin/roles"
+	return createRole(&c.Client, nil, rolesHREF, name, description, available, rightNames)
+}
+
+// orgRolesAPIVersion is the first API version in which an org lists the
+// rights available to it and has roles of its own. It is above
+// SupportedAPIVersions, so it is only used when pinned with WithAPIVersion.
+const orgRolesAPIVersion = "27.0"
+
+// checkOrgRoles returns an error when the API version of the client
+// predates the rights and roles of an org.
+func (adminOrg *AdminOrg) checkOrgRoles() error {
+	if !adminOrg.c.APIVersionIsAtLeast(orgRolesAPIVersion) {
+		return fmt.Errorf("the rights and roles of org %s need API version %s or later, the client uses %s: pin it with WithAPIVersion or use the global roles",
+			adminOrg.AdminOrg.Name, orgRolesAPIVersion, adminOrg.c.APIVersion)
+	}
+	return nil
+}
+
+// GetRights returns the references to the rights available to the org. It
+// needs API version 27.0 or later, pinned with WithAPIVersion.
+func (adminOrg *AdminOrg) GetRights() ([]*types.Reference, error) {
+	if err := adminOrg.checkOrgRoles(); err != nil {
+		return nil, err
+	}
+	if err := adminOrg.Refresh(); err != nil {
+		return nil, err
+	}
+	if adminOrg.AdminOrg.RightReferences == nil {
+		return nil, nil
+	}
+	return adminOrg.AdminOrg.RightReferences.RightReference, nil
+}
+
+// GetRightByName returns the right available to the org having the given
+// name. Like GetRights, it needs a client pinned to API version 27.0.
+func (adminOrg *AdminOrg) GetRightByName(name string) (*types.Right, error) {
+	rights, err := adminOrg.GetRights()
+	if err != nil {
+		return nil, err
+	}
+	return adminOrg.c.getRight(rights, name)
+}
+
+// GetRoles returns the references to the roles local to the org. Global
+// roles are returned by VCDClient.GetRoles. It needs API version 27.0 or
+// later, pinned with WithAPIVersion.
+func (adminOrg *AdminOrg) GetRoles() ([]*types.Reference, error) {
+	if err := adminOrg.checkOrgRoles(); err != nil {
+		return nil, err
+	}
+	if err := adminOrg.Refresh(); err != nil {
+		return nil, err
+	}
+	if adminOrg.AdminOrg.RoleReferences == nil {
+		return nil, nil
+	}
+	return adminOrg.AdminOrg.RoleReferences.RoleReference, nil
+}
+
+// GetRoleByName returns the role local to the org having the given name.
+// The returned error matches ErrNotFound when there is no such role. Like
+// GetRoles, it needs a client pinned to API version 27.0.
+func (adminOrg *AdminOrg) GetRoleByName(name string) (*Role, error) {
+	roles, err := adminOrg.GetRoles()
+	if err != nil {
+		return nil, err
+	}
+	reference := findReference(roles, name)
+	if reference == nil {
+		return nil, fmt.Errorf("role %s in org %s: %w", name, adminOrg.AdminOrg.Name, ErrNotFound)
+	}
+	return getRoleByHREF(adminOrg.c, adminOrg, reference.HREF)
+}
+
+// CreateRole creates a role local to the org having the rights with the
+// given names. It needs API version 27.0 or later, pinned with
+// WithAPIVersion.
+func (adminOrg *AdminOrg) CreateRole(name, description string, rightNames ...string) (*Role, error) {
+	available, err := adminOrg.GetRights()
+	if err != nil {
+		return nil, err
+	}
+	rolesHREF, err := url.ParseRequestURI(adminOrg.AdminOrg.HREF)
+	if err != nil {
+		return nil, fmt.Errorf("error getting AdminOrg HREF %s : %w", adminOrg.AdminOrg.HREF, err)
+	}
+	rolesHREF.Path += "/roles"
+	return createRole(adminOrg.c, adminOrg, *rolesHREF, name, description, available, rightNames)
+}
+
+func createRole(c *Client, adminOrg *AdminOrg, rolesHREF url.URL, name, description string, available []*types.Reference, rightNames []string) (*Role, error) {
+	rights, err := resolveRights(available, rightNames)
+	if err != nil {
+		return nil, err
+	}
+	role := &Role{Role: new(types.Role), AdminOrg: adminOrg, c: c}
+	roleConfiguration := &types.Role{
+		Name:            name,
+		Description:     description,
+		RightReferences: &types.RightReferences{RightReference: rights},
+	}
+	if err = role.send("POST", rolesHREF, roleConfiguration); err != nil {
+		return nil, fmt.Errorf("error creating role %s: %w", name, err)
+	}
+	return role, nil
+}
+
+func getRoleByHREF(c *Client, adminOrg *AdminOrg, href string) (*Role, error) {
+	role := &Role{Role: &types.Role{HREF: href}, AdminOrg: adminOrg, c: c}
+	if err := role.Refresh(); err != nil {
+		return nil, err
+	}
+	return role, nil
+}
+
+// Refresh fetches the current state of the role.
+func (role *Role) Refresh() error {
+	roleHREF, err := url.ParseRequestURI(role.Role.HREF)
+	if err != nil {
+		return fmt.Errorf("error getting role HREF %s : %w", role.Role.HREF, err)
+	}
+	req := role.c.NewRequest(map[string]string{}, "GET", *roleHREF, nil)
+	resp, err := role.c.doRequest(req)
+	if err != nil {
+		return fmt.Errorf("error retrieving role: %w", err)
+	}
+	refreshed := new(types.Role)
+	if err = decodeBody(resp, refreshed); err != nil {
+		return fmt.Errorf("error decoding role response: %w", err)
+	}
+	role.Role = refreshed
+	return nil
+}
+
+// Update sends the changes made to role.Role, such as the name, the
+// description or the rights, to vCD.
+func (role *Role) Update() error {
+	roleHREF, err := url.ParseRequestURI(role.Role.HREF)
+	if err != nil {
+		return fmt.Errorf("error getting role HREF %s : %w", role.Role.HREF, err)
+	}
+	if err = role.send("PUT", *roleHREF, role.Role); err != nil {
+		return fmt.Errorf("error updating role %s: %w", role.Role.Name, err)
+	}
+	return nil
+}
+
+// send sends roleConfiguration to href and stores the role returned by
+// vCD.
+func (role *Role) send(method string, href url.URL, roleConfiguration *types.Role) error {
+	roleConfiguration.Xmlns = "http://www.vmware.com/vcloud/v1.5"
+	output, _ := xml.MarshalIndent(roleConfiguration, "  ", "    ")
+	xmlData := bytes.NewBufferString(xml.Header + string(output))
+
+	req := role.c.NewRequest(map[string]string{}, method, href, xmlData)
+	req.Header.Add("Content-Type", types.MimeAdminRole)
+	resp, err := role.c.doRequest(req)
+	if err != nil {
+		return err
+	}
+	updated := new(types.Role)
+	if err = decodeBody(resp, updated); err != nil {
+		return fmt.Errorf("error decoding role response: %w", err)
+	}
+	role.Role = updated
+	return nil
+}
+
+// GetRights returns the references to the rights of the role.
+func (role *Role) GetRights() []*types.Reference {
+	if role.Role.RightReferences == nil {
+		return nil
+	}
+	return role.Role.RightReferences.RightReference
+}
+
+// HasRight tells whether the role has the right with the given name.
+func (role *Role) HasRight(name string) bool {
+	return findReference(role.GetRights(), name) != nil
+}
+
+// AddRights adds the rights with the given names to the role.
+func (role *Role) AddRights(rightNames ...string) error {
+	available, err := role.availableRights()
+	if err != nil {
+		return err
+	}
+	added, err := resolveRights(available, rightNames)
+	if err != nil {
+		return err
+	}
+	rights := role.GetRights()
+	for _, right := range added {
+		if findReference(rights, right.Name) == nil {
+			rights = append(rights, right)
+		}
+	}
+	role.Role.RightReferences = &types.RightReferences{RightReference: rights}
+	return role.Update()
+}
+
+// RemoveRights removes the rights with the given names from the role.
+// Rights the role does not have are ignored.
+func (role *Role) RemoveRights(rightNames ...string) error {
+	removed := make(map[string]bool)
+	for _, name := range rightNames {
+		removed[name] = true
+	}
+	var rights []*types.Reference
+	for _, right := range role.GetRights() {
+		if !removed[right.Name] {
+			rights = append(rights, right)
+		}
+	}
+	role.Role.RightReferences = &types.RightReferences{RightReference: rights}
+	return role.Update()
+}
+
+// Clone creates a role with the rights of this one, in the same scope.
+// Cloning a local role needs API version 27.0, as AdminOrg.CreateRole.
+func (role *Role) Clone(name, description string) (*Role, error) {
+	var rightNames []string
+	for _, right := range role.GetRights() {
+		rightNames = append(rightNames, right.Name)
+	}
+	if role.AdminOrg != nil {
+		return role.AdminOrg.CreateRole(name, description, rightNames...)
+	}
+	available, err := role.availableRights()
+	if err != nil {
+		return nil, err
+	}
+	rolesHREF := role.c.VCDHREF
+	rolesHREF.Path += "/admin/roles"
+	return createRole(role.c, nil, rolesHREF, name, description, available, rightNames)
+}
+
+// Delete removes the role. vCD refuses to delete a role given to users or
+// groups.
+func (role *Role) Delete() error {
+	roleHREF, err := url.ParseRequestURI(role.Role.HREF)
+	if err != nil {
+		return fmt.Errorf("error getting role HREF %s : %w", role.Role.HREF, err)
+	}
+	req := role.c.NewRequest(map[string]string{}, "DELETE", *roleHREF, nil)
+	resp, err := role.c.doRequest(req)
+	if err != nil {
+		return fmt.Errorf("error deleting role %s: %w", role.Role.Name, err)
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// availableRights returns the rights that can be given to the role.
+func (role *Role) availableRights() ([]*types.Reference, error) {
+	if role.AdminOrg != nil {
+		return role.AdminOrg.GetRights()
+	}
+	vcloud, err := role.c.getAdminVCloud()
+	if err != nil {
+		return nil, err
+	}
+	if vcloud.RightReferences == nil {
+		return nil, nil
+	}
+	return vcloud.RightReferences.RightReference, nil
+}
+
+// getAdminVCloud returns the admin view of the cloud, listing the global
+// roles and the rights.
+func (c *Client) getAdminVCloud() (*types.VCloud, error) {
+	adminHREF := c.VCDHREF
+	adminHREF.Path += "/admin"
+	req := c.NewRequest(map[string]string{}, "GET", adminHREF, nil)
+	resp, err := c.doRequest(req)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving the admin view: %w", err)
+	}
+	vcloud := new(types.VCloud)
+	if err = decodeBody(resp, vcloud); err != nil {
+		return nil, fmt.Errorf("error decoding the admin view: %w", err)
+	}
+	return vcloud, nil
+}
+
+// getRight fetches the right of rights having the given name.
+func (c *Client) getRight(rights []*types.Reference, name string) (*types.Right, error) {
+	reference := findReference(rights, name)
+	if reference == nil {
+		return nil, fmt.Errorf("right %s: %w", name, ErrNotFound)
+	}
+	rightHREF, err := url.ParseRequestURI(reference.HREF)
+	if err != nil {
+		return nil, fmt.Errorf("error getting right HREF %s : %w", reference.HREF, err)
+	}
+	req := c.NewRequest(map[string]string{}, "GET", *rightHREF, nil)
+	resp, err := c.doRequest(req)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving right: %w", err)
+	}
+	right := new(types.Right)
+	if err = decodeBody(resp, right); err != nil {
+		return nil, fmt.Errorf("error decoding right response: %w", err)
+	}
+	return right, nil
+}
+
+// resolveRights returns the references of available having the given
+// names.
+func resolveRights(available []*types.Reference, names []string) ([]*types.Reference, error) {
+	var rights []*types.Reference
+	for _, name := range names {
+		reference := findReference(available, name)
+		if reference == nil {
+			return nil, fmt.Errorf("right %s: %w", name, ErrNotFound)
+		}
+		rights = append(rights, reference)
+	}
+	return rights, nil
+}
+
+func findReference(references []*types.Reference, name string) *types.Reference {
+	for _, reference := range references {
+		if reference.Name == name {
+			return reference
+		}
+	}
+	return nil
+}
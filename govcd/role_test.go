@@ -0,0 +1,183 @@
+/*
+ * Copyright 2014 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/vmware/go-vcloud-director/fakevcd"
+	types "github.com/vmware/go-vcloud-director/types/v56"
+	. "gopkg.in/check.v1"
+)
+
+// Clones the vApp User role into a local role of the test org, changes
+// its rights and deletes it.
+func (vcd *TestVCD) Test_OrgRole(check *C) {
+	if !vcd.client.Client.APIVersionIsAtLeast(orgRolesAPIVersion) {
+		check.Skip("org roles need API version " + orgRolesAPIVersion)
+	}
+	adminOrg, err := GetAdminOrgByName(vcd.client, vcd.config.VCD.Org)
+	check.Assert(err, IsNil)
+	vappUser, err := vcd.client.GetRoleByName("vApp User")
+	check.Assert(err, IsNil)
+
+	role, err := adminOrg.CreateRole("govcd-test-role", "govcd test role")
+	check.Assert(err, IsNil)
+	defer role.Delete()
+	check.Assert(len(role.GetRights()), Equals, 0)
+
+	var rightNames []string
+	for _, right := range vappUser.GetRights() {
+		rightNames = append(rightNames, right.Name)
+	}
+	check.Assert(role.AddRights(rightNames...), IsNil)
+	check.Assert(len(role.GetRights()), Equals, len(rightNames))
+}
+
+func TestVCDClient_RolesFakeVCD(t *testing.T) {
+	server, _, client := newFakeVCD(t)
+	defer server.Close()
+
+	rights, err := client.GetRights()
+	if err != nil || len(rights) == 0 {
+		t.Fatalf("expected the rights of the system, got %d, %v", len(rights), err)
+	}
+	right, err := client.GetRightByName("vApp: Power Operations")
+	if err != nil || right.Category != "vApp" {
+		t.Fatalf("error getting right: %+v, %v", right, err)
+	}
+	if _, err = client.GetRightByName("vApp: Fly"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound for an unknown right, got %v", err)
+	}
+
+	vappUser, err := client.GetRoleByName("vApp User")
+	if err != nil || !vappUser.HasRight("vApp: Power Operations") || vappUser.HasRight("vApp: Delete") {
+		t.Fatalf("unexpected vApp User role: %v", err)
+	}
+	if _, err = client.CreateRole("Operator", "", "vApp: Fly"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound creating a role with an unknown right, got %v", err)
+	}
+	server.ResetRequests()
+	operator, err := client.CreateRole("Operator", "Powers vApps", "Organization: View", "vApp: Power Operations")
+	if err != nil {
+		t.Fatalf("error creating global role: %s", err)
+	}
+	checkRequests(t, server, apiRequest{
+		Method: "POST", Path: "/admin/roles", ContentType: "application/vnd.vmware.admin.role+xml",
+		Body: []string{"<Role ", `name="Operator"`, "<Description>Powers vApps</Description>",
+			"<RightReferences>", `type="application/vnd.vmware.admin.right+xml"`},
+	})
+	if operator.AdminOrg != nil || len(operator.GetRights()) != 2 || operator.Role.Description != "Powers vApps" {
+		t.Fatalf("unexpected global role: %+v", operator.Role)
+	}
+	if err = operator.AddRights("vApp: Use Console", "vApp: Power Operations"); err != nil {
+		t.Fatalf("error adding rights: %s", err)
+	}
+	if err = operator.RemoveRights("Organization: View"); err != nil {
+		t.Fatalf("error removing rights: %s", err)
+	}
+	operator, err = client.GetRoleByName("Operator")
+	if err != nil || len(operator.GetRights()) != 2 || !operator.HasRight("vApp: Use Console") || operator.HasRight("Organization: View") {
+		t.Fatalf("unexpected updated role: %+v, %v", operator, err)
+	}
+	clone, err := operator.Clone("Operator copy", "")
+	if err != nil || clone.AdminOrg != nil || len(clone.GetRights()) != 2 {
+		t.Fatalf("error cloning global role: %v", err)
+	}
+	if err = clone.Delete(); err != nil {
+		t.Fatalf("error deleting global role: %s", err)
+	}
+
+	adminOrg, err := GetAdminOrgByName(client, "org")
+	if err != nil {
+		t.Fatalf("error getting admin org: %s", err)
+	}
+	// The rights and roles of an org need API version 27.0, the global
+	// roles are used before.
+	server.ResetRequests()
+	if _, err = adminOrg.GetRights(); err == nil || !strings.Contains(err.Error(), "27.0") {
+		t.Fatalf("expected an API version error getting the org rights, got %v", err)
+	}
+	if _, err = adminOrg.GetRoles(); err == nil || !strings.Contains(err.Error(), "27.0") {
+		t.Fatalf("expected an API version error getting the org roles, got %v", err)
+	}
+	if _, err = adminOrg.CreateRole("Local operator", "", "vApp: Power Operations"); err == nil || !strings.Contains(err.Error(), "27.0") {
+		t.Fatalf("expected an API version error creating an org role, got %v", err)
+	}
+	if len(server.Requests()) != 0 {
+		t.Fatalf("expected no request for the org roles, got %+v", server.Requests())
+	}
+	if reference, err := adminOrg.GetRoleReference("vApp User"); err != nil || reference.HREF != vappUser.Role.HREF {
+		t.Fatalf("expected the global role, got %+v, %v", reference, err)
+	}
+
+	// vCD offering 27.0 is not enough: the negotiation stays on the
+	// versions govcd supports, and the client must pin 27.0.
+	server.Versions = append(server.Versions, orgRolesAPIVersion)
+	negotiated := NewVCDClient(server.APIURL(), true)
+	if err = negotiated.Authenticate(fakevcd.DefaultUser, fakevcd.DefaultPassword, "org"); err != nil {
+		t.Fatalf("error authenticating: %s", err)
+	}
+	if negotiated.APIVersion() != "5.6" {
+		t.Fatalf("expected the negotiated API version 5.6, got %s", negotiated.APIVersion())
+	}
+	if adminOrg, err = GetAdminOrgByName(negotiated, "org"); err != nil {
+		t.Fatalf("error getting admin org: %s", err)
+	}
+	if _, err = adminOrg.GetRoles(); err == nil || !strings.Contains(err.Error(), "WithAPIVersion") {
+		t.Fatalf("expected an API version error with the negotiated version, got %v", err)
+	}
+	pinned := NewVCDClient(server.APIURL(), true, WithAPIVersion(orgRolesAPIVersion))
+	if err = pinned.Authenticate(fakevcd.DefaultUser, fakevcd.DefaultPassword, "org"); err != nil {
+		t.Fatalf("error authenticating with API version %s: %s", orgRolesAPIVersion, err)
+	}
+	if pinned.APIVersion() != orgRolesAPIVersion {
+		t.Fatalf("expected the pinned API version, got %s", pinned.APIVersion())
+	}
+	if adminOrg, err = GetAdminOrgByName(pinned, "org"); err != nil {
+		t.Fatalf("error getting admin org: %s", err)
+	}
+
+	local, err := adminOrg.CreateRole("Local operator", "", "vApp: Power Operations")
+	if err != nil || local.AdminOrg == nil {
+		t.Fatalf("error creating local role: %v", err)
+	}
+	localClone, err := local.Clone("Local operator copy", "")
+	if err != nil || localClone.AdminOrg != &adminOrg {
+		t.Fatalf("expected the clone of a local role to be local: %v", err)
+	}
+	roles, err := adminOrg.GetRoles()
+	if err != nil || len(roles) != 2 {
+		t.Fatalf("expected 2 local roles, got %v, %v", roles, err)
+	}
+	if _, err = client.GetRoleByName("Local operator"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected local roles not to be global, got %v", err)
+	}
+
+	// Local roles can be given to the users of the org, and cannot be
+	// deleted while in use
+	reference, err := adminOrg.GetRoleReference("Local operator")
+	if err != nil || reference.HREF != local.Role.HREF {
+		t.Fatalf("error getting local role reference: %v", err)
+	}
+	user, err := adminOrg.CreateUser(&types.User{Name: "op", Password: "secret", Role: reference})
+	if err != nil {
+		t.Fatalf("error creating user: %s", err)
+	}
+	if err = local.Delete(); err == nil {
+		t.Fatal("expected an error deleting a role in use")
+	}
+	if err = user.Delete(); err != nil {
+		t.Fatalf("error deleting user: %s", err)
+	}
+	if err = local.Delete(); err != nil {
+		t.Fatalf("error deleting local role: %s", err)
+	}
+	if _, err = adminOrg.GetRoleByName("Local operator"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound for a deleted role, got %v", err)
+	}
+}
@@ -0,0 +1,160 @@
+/*
+ * Copyright 2014 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"strings"
+
+	types "github.com/vmware/go-vcloud-director/types/v56"
+)
+
+// GetStorageProfiles returns the storage profiles of the VDC with their
+// limit and the storage the VDC uses on them, in MB.
+func (vdc *Vdc) GetStorageProfiles() ([]*types.QueryResultOrgVdcStorageProfileRecordType, error) {
+	results, err := vdc.queryAll(NewQuery("orgVdcStorageProfile").Filter(FilterEq("vdc", vdc.Vdc.HREF)).PageSize(128))
+	if err != nil {
+		return nil, fmt.Errorf("error querying storage profiles of VDC %s: %w", vdc.Vdc.Name, err)
+	}
+	return results.Results.OrgVdcStorageProfileRecord, nil
+}
+
+// GetStorageProfile returns the admin view of the storage profile of the
+// VDC having the given name. The returned error matches ErrNotFound when
+// the VDC has no such storage profile.
+func (adminVdc *AdminVdc) GetStorageProfile(name string) (*types.AdminVdcStorageProfile, error) {
+	for _, profiles := range adminVdc.AdminVdc.VdcStorageProfiles {
+		for _, reference := range profiles.VdcStorageProfile {
+			if reference.Name == name {
+				profile := new(types.AdminVdcStorageProfile)
+				if err := adminVdc.c.getByHREF(adminStorageProfileHREF(reference.HREF), "storage profile", profile); err != nil {
+					return nil, err
+				}
+				return profile, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("storage profile %s: %w", name, ErrNotFound)
+}
+
+// adminStorageProfileHREF returns the admin HREF of a storage profile from
+// either of its HREFs.
+func adminStorageProfileHREF(href string) string {
+	return strings.Replace(href, "/api/vdcStorageProfile/", "/api/admin/vdcStorageProfile/", 1)
+}
+
+// AddStorageProfiles adds storage profiles of the provider VDC to the VDC
+// and waits for the change to complete.
+func (adminVdc *AdminVdc) AddStorageProfiles(profiles ...*types.VdcStorageProfileParams) error {
+	for _, profile := range profiles {
+		if profile.ProviderVdcStorageProfile == nil {
+			return fmt.Errorf("storage profile of VDC %s needs a provider VDC storage profile", adminVdc.AdminVdc.Name)
+		}
+	}
+	return adminVdc.updateStorageProfiles(&types.UpdateVdcStorageProfiles{AddStorageProfile: profiles})
+}
+
+// RemoveStorageProfiles removes the storage profiles having the given
+// names from the VDC and waits for the change to complete. The profiles
+// are disabled first, as vCD requires. The default profile and the
+// profiles still used by VMs cannot be removed.
+func (adminVdc *AdminVdc) RemoveStorageProfiles(names ...string) error {
+	update := &types.UpdateVdcStorageProfiles{}
+	for _, name := range names {
+		profile, err := adminVdc.GetStorageProfile(name)
+		if err != nil {
+			return err
+		}
+		if profile.Default {
+			return fmt.Errorf("cannot remove the default storage profile %s of VDC %s", name, adminVdc.AdminVdc.Name)
+		}
+		if profile.Enabled {
+			profile.Enabled = false
+			if profile, err = adminVdc.UpdateStorageProfile(profile); err != nil {
+				return err
+			}
+		}
+		update.RemoveStorageProfile = append(update.RemoveStorageProfile, &types.Reference{HREF: profile.HREF, Name: profile.Name})
+	}
+	return adminVdc.updateStorageProfiles(update)
+}
+
+func (adminVdc *AdminVdc) updateStorageProfiles(update *types.UpdateVdcStorageProfiles) error {
+	update.Xmlns = "http://www.vmware.com/vcloud/v1.5"
+	output, err := xml.MarshalIndent(update, "  ", "    ")
+	if err != nil {
+		return fmt.Errorf("error encoding storage profiles of VDC %s: %w", adminVdc.AdminVdc.Name, err)
+	}
+	xmlData := bytes.NewBufferString(xml.Header + string(output))
+
+	updateHREF, err := url.ParseRequestURI(adminVdc.AdminVdc.HREF)
+	if err != nil {
+		return fmt.Errorf("error getting VDC HREF %s : %w", adminVdc.AdminVdc.HREF, err)
+	}
+	updateHREF.Path += "/vdcStorageProfiles"
+	req := adminVdc.c.NewRequest(map[string]string{}, "POST", *updateHREF, xmlData)
+	req.Header.Add("Content-Type", types.MimeUpdateVdcStorageProfiles)
+	resp, err := adminVdc.c.doRequest(req)
+	if err != nil {
+		return fmt.Errorf("error updating storage profiles of VDC %s: %w", adminVdc.AdminVdc.Name, err)
+	}
+	defer resp.Body.Close()
+	task := NewTask(adminVdc.c)
+	if err = decodeBody(resp, task.Task); err != nil {
+		return fmt.Errorf("error decoding task response: %w", err)
+	}
+	if err = task.WaitTaskCompletion(); err != nil {
+		return fmt.Errorf("error updating storage profiles of VDC %s: %w", adminVdc.AdminVdc.Name, err)
+	}
+	return adminVdc.Refresh()
+}
+
+// UpdateStorageProfile sends the limit, the enabled and default flags of
+// profile to vCD and returns the updated profile. Making a profile the
+// default one makes the previous default profile a regular one.
+func (adminVdc *AdminVdc) UpdateStorageProfile(profile *types.AdminVdcStorageProfile) (*types.AdminVdcStorageProfile, error) {
+	profile.Xmlns = "http://www.vmware.com/vcloud/v1.5"
+	output, err := xml.MarshalIndent(profile, "  ", "    ")
+	if err != nil {
+		return nil, fmt.Errorf("error encoding storage profile %s: %w", profile.Name, err)
+	}
+	xmlData := bytes.NewBufferString(xml.Header + string(output))
+
+	profileHREF, err := url.ParseRequestURI(adminStorageProfileHREF(profile.HREF))
+	if err != nil {
+		return nil, fmt.Errorf("error getting storage profile HREF %s : %w", profile.HREF, err)
+	}
+	req := adminVdc.c.NewRequest(map[string]string{}, "PUT", *profileHREF, xmlData)
+	req.Header.Add("Content-Type", types.MimeAdminVdcStorageProfile)
+	resp, err := adminVdc.c.doRequest(req)
+	if err != nil {
+		return nil, fmt.Errorf("error updating storage profile %s: %w", profile.Name, err)
+	}
+	defer resp.Body.Close()
+	updated := new(types.AdminVdcStorageProfile)
+	if err = decodeBody(resp, updated); err != nil {
+		return nil, fmt.Errorf("error decoding storage profile response: %w", err)
+	}
+	return updated, nil
+}
+
+// SetDefaultStorageProfile makes the storage profile having the given name
+// the default profile of the VDC, used by the VMs created without one.
+func (adminVdc *AdminVdc) SetDefaultStorageProfile(name string) error {
+	profile, err := adminVdc.GetStorageProfile(name)
+	if err != nil {
+		return err
+	}
+	if profile.Default {
+		return nil
+	}
+	profile.Default = true
+	profile.Enabled = true
+	_, err = adminVdc.UpdateStorageProfile(profile)
+	return err
+}
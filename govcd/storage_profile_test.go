@@ -0,0 +1,197 @@
+/*
+ * Copyright 2014 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	types "github.com/vmware/go-vcloud-director/types/v56"
+	. "gopkg.in/check.v1"
+)
+
+// Checks that the storage profiles of the test VDC are listed with a
+// default one, whose admin view can be read.
+func (vcd *TestVCD) Test_StorageProfiles(check *C) {
+	profiles, err := vcd.vdc.GetStorageProfiles()
+	check.Assert(err, IsNil)
+	var defaultProfile string
+	for _, profile := range profiles {
+		if profile.IsDefaultStorageProfile {
+			defaultProfile = profile.Name
+		}
+	}
+	check.Assert(defaultProfile, Not(Equals), "")
+
+	adminOrg, err := GetAdminOrgByName(vcd.client, vcd.config.VCD.Org)
+	check.Assert(err, IsNil)
+	adminVdc, err := adminOrg.GetAdminVdcByName(vcd.vdc.Vdc.Name)
+	check.Assert(err, IsNil)
+	profile, err := adminVdc.GetStorageProfile(defaultProfile)
+	check.Assert(err, IsNil)
+	check.Assert(profile.Default, Equals, true)
+}
+
+func TestAdminVdc_StorageProfilesFakeVCD(t *testing.T) {
+	server, fakeVdc, client := newFakeVCD(t)
+	defer server.Close()
+	fakeProviderVdc := server.AddProviderVdc("pvdc")
+	fakeProviderVdc.AddStorageProfile("gold")
+	fakeVdc.SetProviderVdc(fakeProviderVdc)
+	fakeVdc.AddVApp("web").AddVM("web-1")
+
+	providerVdc, err := client.GetProviderVdcByName("pvdc")
+	if err != nil {
+		t.Fatalf("error getting provider VDC: %s", err)
+	}
+	gold := providerVdc.ProviderVdc.StorageProfiles.ProviderVdcStorageProfile[1]
+	adminOrg, err := GetAdminOrgByName(client, "org")
+	if err != nil {
+		t.Fatalf("error getting admin org: %s", err)
+	}
+	adminVdc, err := adminOrg.GetAdminVdcByName("vdc")
+	if err != nil {
+		t.Fatalf("error getting admin VDC: %s", err)
+	}
+
+	goldParams := &types.VdcStorageProfileParams{Enabled: true, Units: "GB", Limit: 50, ProviderVdcStorageProfile: gold}
+	server.ResetRequests()
+	if err = adminVdc.AddStorageProfiles(goldParams); err != nil {
+		t.Fatalf("error adding storage profile: %s", err)
+	}
+	checkRequests(t, server, apiRequest{
+		Method: "POST", Path: "/admin/vdc/[^/]+/vdcStorageProfiles", ContentType: "application/vnd.vmware.admin.updateVdcStorageProfiles+xml",
+		Body: []string{"<UpdateVdcStorageProfiles ", "<AddStorageProfile>", "<Units>GB</Units>", "<Limit>50</Limit>",
+			`<ProviderVdcStorageProfile href="` + gold.HREF},
+	})
+	if err = adminVdc.AddStorageProfiles(goldParams); err == nil {
+		t.Fatal("expected an error adding a storage profile twice")
+	}
+	_, vdc := fakeOrgVdc(t, client)
+	profiles, err := vdc.GetStorageProfiles()
+	if err != nil || len(profiles) != 2 {
+		t.Fatalf("expected 2 storage profiles, got %+v, %v", profiles, err)
+	}
+	if profiles[1].Name != "gold" || profiles[1].StorageLimitMB != 51200 || profiles[1].IsDefaultStorageProfile || !profiles[0].IsDefaultStorageProfile {
+		t.Fatalf("unexpected storage profiles: %+v, %+v", profiles[0], profiles[1])
+	}
+
+	star, err := adminVdc.GetStorageProfile("*")
+	if err != nil {
+		t.Fatalf("error getting storage profile: %s", err)
+	}
+	star.Units, star.Limit = "MB", 204800
+	server.ResetRequests()
+	if star, err = adminVdc.UpdateStorageProfile(star); err != nil || star.Limit != 204800 {
+		t.Fatalf("error updating storage profile limit: %+v, %v", star, err)
+	}
+	checkRequests(t, server, apiRequest{
+		Method: "PUT", Path: "/admin/vdcStorageProfile/[^/]+", ContentType: "application/vnd.vmware.admin.vdcStorageProfile+xml",
+		Body: []string{"<AdminVdcStorageProfile ", "<Units>MB</Units>", "<Limit>204800</Limit>"},
+	})
+	if err = adminVdc.RemoveStorageProfiles("*"); err == nil {
+		t.Fatal("expected an error removing the default storage profile")
+	}
+	if err = adminVdc.SetDefaultStorageProfile("gold"); err != nil {
+		t.Fatalf("error setting the default storage profile: %s", err)
+	}
+	if star, err = adminVdc.GetStorageProfile("*"); err != nil || star.Default {
+		t.Fatalf("expected * to no longer be the default storage profile: %+v, %v", star, err)
+	}
+
+	// The disk of the VM is on *, which cannot be removed until the disk
+	// moves to gold.
+	if err = adminVdc.RemoveStorageProfiles("*"); err == nil {
+		t.Fatal("expected an error removing a storage profile in use")
+	}
+	vapp, err := vdc.FindVAppByName("web")
+	if err != nil {
+		t.Fatalf("error finding vApp: %s", err)
+	}
+	vm := NewVM(vdc.c)
+	vm.VM.HREF = vapp.VApp.Children.VM[0].HREF
+	vm.VM.Name = vapp.VApp.Children.VM[0].Name
+	if err = vdc.Refresh(); err != nil {
+		t.Fatalf("error refreshing VDC: %s", err)
+	}
+	goldReference, err := vdc.FindStorageProfileReference("gold")
+	if err != nil {
+		t.Fatalf("error finding storage profile: %s", err)
+	}
+	if _, err = vm.ChangeDiskStorageProfile("Hard disk 9", goldReference); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound for a missing disk, got %v", err)
+	}
+	server.ResetRequests()
+	task, err := vm.ChangeDiskStorageProfile("Hard disk 1", goldReference)
+	if err != nil {
+		t.Fatalf("error moving disk: %s", err)
+	}
+	checkRequests(t, server, apiRequest{
+		Method: "PUT", Path: "/vApp/vm-[^/]+/virtualHardwareSection/disks", ContentType: "application/vnd.vmware.vcloud.rasdItemsList+xml",
+		Body: []string{"<vcloud:RasdItemsList ", "<rasd:HostResource ", `storageProfileHref="` + goldReference.HREF,
+			`storageProfileOverrideVmDefault="true"`},
+	})
+	if err = task.WaitTaskCompletion(); err != nil {
+		t.Fatalf("error moving disk: %s", err)
+	}
+	disks, err := vm.GetDisks()
+	if err != nil || len(disks.Item) != 2 {
+		t.Fatalf("unexpected disks: %+v, %v", disks, err)
+	}
+	disk := disks.Item[1].HostResource[0]
+	if disk.StorageProfile != goldReference.HREF || disk.Capacity != 16384 || disks.Item[1].Parent != 2 {
+		t.Fatalf("unexpected disk after the move: %+v", disk)
+	}
+
+	if err = adminVdc.RemoveStorageProfiles("*"); err != nil {
+		t.Fatalf("error removing storage profile: %s", err)
+	}
+	if _, err = adminVdc.GetStorageProfile("*"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound after removal, got %v", err)
+	}
+}
+
+// Checks that the storage profiles are listed past the first page.
+func TestVdc_GetStorageProfilesPagesFakeVCD(t *testing.T) {
+	server, fakeVdc, client := newFakeVCD(t)
+	defer server.Close()
+	fakeProviderVdc := server.AddProviderVdc("pvdc")
+	for i := 0; i < 130; i++ {
+		fakeProviderVdc.AddStorageProfile(fmt.Sprintf("profile-%d", i))
+	}
+	fakeVdc.SetProviderVdc(fakeProviderVdc)
+
+	providerVdc, err := client.GetProviderVdcByName("pvdc")
+	if err != nil {
+		t.Fatalf("error getting provider VDC: %s", err)
+	}
+	adminOrg, err := GetAdminOrgByName(client, "org")
+	if err != nil {
+		t.Fatalf("error getting admin org: %s", err)
+	}
+	adminVdc, err := adminOrg.GetAdminVdcByName("vdc")
+	if err != nil {
+		t.Fatalf("error getting admin VDC: %s", err)
+	}
+	var params []*types.VdcStorageProfileParams
+	for _, profile := range providerVdc.ProviderVdc.StorageProfiles.ProviderVdcStorageProfile[1:] {
+		params = append(params, &types.VdcStorageProfileParams{Enabled: true, Units: "MB", ProviderVdcStorageProfile: profile})
+	}
+	if err = adminVdc.AddStorageProfiles(params...); err != nil {
+		t.Fatalf("error adding storage profiles: %s", err)
+	}
+
+	_, vdc := fakeOrgVdc(t, client)
+	server.ResetRequests()
+	profiles, err := vdc.GetStorageProfiles()
+	if err != nil || len(profiles) != 131 {
+		t.Fatalf("expected 131 storage profiles, got %d, %v", len(profiles), err)
+	}
+	checkRequests(t, server,
+		apiRequest{Method: "GET", Path: "/query", Query: map[string]string{"type": "orgVdcStorageProfile", "page": "1"}},
+		apiRequest{Method: "GET", Path: "/query", Query: map[string]string{"type": "orgVdcStorageProfile", "page": "2"}},
+	)
+}
@@ -2,6 +2,7 @@ package govcd
 
 import (
 	"bytes"
+	"context"
 	"encoding/xml"
 	"fmt"
 	types "github.com/vmware/go-vcloud-director/types/v56"
@@ -14,6 +15,12 @@ import (
 // settings parameter. The settings variable is defined in types.go.
 // Method will fail unless user has an admin token.
 func CreateOrg(vcdClient *VCDClient, name string, fullName string, isEnabled bool, settings *types.OrgSettings) (Task, error) {
+	return CreateOrgWithContext(context.Background(), vcdClient, name, fullName, isEnabled, settings)
+}
+
+// CreateOrgWithContext behaves like CreateOrg. The request is aborted if
+// ctx is cancelled before vCD accepts it.
+func CreateOrgWithContext(ctx context.Context, vcdClient *VCDClient, name string, fullName string, isEnabled bool, settings *types.OrgSettings) (Task, error) {
 	vcomp := &types.AdminOrg{
 		Xmlns:       "http://www.vmware.com/vcloud/v1.5",
 		Name:        name,
@@ -26,16 +33,16 @@ func CreateOrg(vcdClient *VCDClient, name string, fullName string, isEnabled boo
 	// Make Request
 	orgCreateHREF := vcdClient.Client.VCDHREF
 	orgCreateHREF.Path += "/admin/orgs"
-	req := vcdClient.Client.NewRequest(map[string]string{}, "POST", orgCreateHREF, xmlData)
+	req := vcdClient.Client.NewRequestWithContext(ctx, map[string]string{}, "POST", orgCreateHREF, xmlData)
 	req.Header.Add("Content-Type", "application/vnd.vmware.admin.organization+xml")
-	resp, err := checkResp(vcdClient.Client.Http.Do(req))
+	resp, err := vcdClient.Client.doRequest(req)
 	if err != nil {
-		return Task{}, fmt.Errorf("error instantiating a new Org: %s", err)
+		return Task{}, fmt.Errorf("error instantiating a new Org: %w", err)
 	}
 
 	task := NewTask(&vcdClient.Client)
 	if err = decodeBody(resp, task.Task); err != nil {
-		return Task{}, fmt.Errorf("error decoding task response: %s", err)
+		return Task{}, fmt.Errorf("error decoding task response: %w", err)
 	}
 	return *task, nil
 }
@@ -45,23 +52,29 @@ func CreateOrg(vcdClient *VCDClient, name string, fullName string, isEnabled boo
 // org and no error. Otherwise it returns an error and an empty
 // Org object
 func GetOrgByName(vcdClient *VCDClient, orgname string) (Org, error) {
-	orgUrl, err := getOrgHREF(vcdClient, orgname)
+	return GetOrgByNameWithContext(context.Background(), vcdClient, orgname)
+}
+
+// GetOrgByNameWithContext behaves like GetOrgByName, aborting if ctx is
+// cancelled before vCD answers.
+func GetOrgByNameWithContext(ctx context.Context, vcdClient *VCDClient, orgname string) (Org, error) {
+	orgUrl, err := getOrgHREF(ctx, vcdClient, orgname)
 	if err != nil {
 		return Org{}, nil
 	}
 	orgHREF, err := url.ParseRequestURI(orgUrl)
 	if err != nil {
-		return Org{}, fmt.Errorf("Error parsing org href: %v", err)
+		return Org{}, fmt.Errorf("Error parsing org href: %w", err)
 	}
-	req := vcdClient.Client.NewRequest(map[string]string{}, "GET", *orgHREF, nil)
-	resp, err := checkResp(vcdClient.Client.Http.Do(req))
+	req := vcdClient.Client.NewRequestWithContext(ctx, map[string]string{}, "GET", *orgHREF, nil)
+	resp, err := vcdClient.Client.doRequest(req)
 	if err != nil {
-		return Org{}, fmt.Errorf("error retreiving org: %s", err)
+		return Org{}, fmt.Errorf("error retreiving org: %w", err)
 	}
 
 	org := NewOrg(&vcdClient.Client)
 	if err = decodeBody(resp, org.Org); err != nil {
-		return Org{}, fmt.Errorf("error decoding org response: %s", err)
+		return Org{}, fmt.Errorf("error decoding org response: %w", err)
 	}
 	return *org, nil
 }
@@ -72,36 +85,42 @@ func GetOrgByName(vcdClient *VCDClient, orgname string) (Org, error) {
 // org and no error. Otherwise returns an empty AdminOrg
 // and an error.
 func GetAdminOrgByName(vcdClient *VCDClient, orgname string) (AdminOrg, error) {
-	orgUrl, err := getOrgHREF(vcdClient, orgname)
+	return GetAdminOrgByNameWithContext(context.Background(), vcdClient, orgname)
+}
+
+// GetAdminOrgByNameWithContext behaves like GetAdminOrgByName, aborting if
+// ctx is cancelled before vCD answers.
+func GetAdminOrgByNameWithContext(ctx context.Context, vcdClient *VCDClient, orgname string) (AdminOrg, error) {
+	orgUrl, err := getOrgHREF(ctx, vcdClient, orgname)
 	if err != nil {
 		return AdminOrg{}, nil
 	}
 	orgHREF := vcdClient.Client.VCDHREF
 	orgHREF.Path += "/admin/org/" + strings.Split(orgUrl, "/org/")[1]
-	req := vcdClient.Client.NewRequest(map[string]string{}, "GET", orgHREF, nil)
-	resp, err := checkResp(vcdClient.Client.Http.Do(req))
+	req := vcdClient.Client.NewRequestWithContext(ctx, map[string]string{}, "GET", orgHREF, nil)
+	resp, err := vcdClient.Client.doRequest(req)
 	if err != nil {
-		return AdminOrg{}, fmt.Errorf("error retreiving org: %s", err)
+		return AdminOrg{}, fmt.Errorf("error retreiving org: %w", err)
 	}
 	org := NewAdminOrg(&vcdClient.Client)
 	if err = decodeBody(resp, org.AdminOrg); err != nil {
-		return AdminOrg{}, fmt.Errorf("error decoding org response: %s", err)
+		return AdminOrg{}, fmt.Errorf("error decoding org response: %w", err)
 	}
 	return *org, nil
 }
 
 // Returns the HREF of the org with the name orgname
-func getOrgHREF(vcdClient *VCDClient, orgname string) (string, error) {
+func getOrgHREF(ctx context.Context, vcdClient *VCDClient, orgname string) (string, error) {
 	orgListHREF := vcdClient.Client.VCDHREF
 	orgListHREF.Path += "/org"
-	req := vcdClient.Client.NewRequest(map[string]string{}, "GET", orgListHREF, nil)
-	resp, err := checkResp(vcdClient.Client.Http.Do(req))
+	req := vcdClient.Client.NewRequestWithContext(ctx, map[string]string{}, "GET", orgListHREF, nil)
+	resp, err := vcdClient.Client.doRequest(req)
 	if err != nil {
-		return "", fmt.Errorf("error retreiving org list: %s", err)
+		return "", fmt.Errorf("error retreiving org list: %w", err)
 	}
 	orgList := new(types.OrgList)
 	if err = decodeBody(resp, orgList); err != nil {
-		return "", fmt.Errorf("error decoding response: %s", err)
+		return "", fmt.Errorf("error decoding response: %w", err)
 	}
 	// Look for orgname within OrgList
 	for _, a := range orgList.Org {
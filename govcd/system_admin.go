@@ -0,0 +1,312 @@
+/*
+ * Copyright 2014 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"net/url"
+
+	types "github.com/vmware/go-vcloud-director/types/v56"
+)
+
+// The methods of this file use the system administrator API, /api/admin
+// and /api/admin/extension. They fail unless the client is logged in the
+// System org.
+
+// ProviderVdc is a provider VDC, the vCenter resources offered to org
+// VDCs.
+type ProviderVdc struct {
+	ProviderVdc *types.ProviderVdc
+	c           *Client
+}
+
+// NewProviderVdc returns an empty provider VDC.
+func NewProviderVdc(c *Client) *ProviderVdc {
+	return &ProviderVdc{
+		ProviderVdc: new(types.ProviderVdc),
+		c:           c,
+	}
+}
+
+// ExternalNetwork is a network of the vCenter made available to the
+// edge gateways and org VDC networks.
+type ExternalNetwork struct {
+	ExternalNetwork *types.ExternalNetwork
+	c               *Client
+}
+
+// NewExternalNetwork returns an empty external network.
+func NewExternalNetwork(c *Client) *ExternalNetwork {
+	return &ExternalNetwork{
+		ExternalNetwork: new(types.ExternalNetwork),
+		c:               c,
+	}
+}
+
+// GetOrgReferences returns the references to all the orgs of the system.
+func (c *VCDClient) GetOrgReferences() ([]*types.Reference, error) {
+	vcloud, err := c.Client.getAdminVCloud()
+	if err != nil {
+		return nil, err
+	}
+	if vcloud.OrganizationReferences == nil {
+		return nil, nil
+	}
+	return vcloud.OrganizationReferences.OrganizationReference, nil
+}
+
+// GetAdminOrgs returns all the orgs of the system, including the System
+// org.
+func (c *VCDClient) GetAdminOrgs() ([]AdminOrg, error) {
+	references, err := c.GetOrgReferences()
+	if err != nil {
+		return nil, err
+	}
+	var orgs []AdminOrg
+	for _, reference := range references {
+		org := NewAdminOrg(&c.Client)
+		if err = c.Client.getByHREF(reference.HREF, "org", org.AdminOrg); err != nil {
+			return nil, err
+		}
+		orgs = append(orgs, *org)
+	}
+	return orgs, nil
+}
+
+// GetProviderVdcReferences returns the references to the provider VDCs.
+func (c *VCDClient) GetProviderVdcReferences() ([]*types.Reference, error) {
+	vcloud, err := c.Client.getAdminVCloud()
+	if err != nil {
+		return nil, err
+	}
+	if vcloud.ProviderVdcReferences == nil {
+		return nil, nil
+	}
+	return vcloud.ProviderVdcReferences.ProviderVdcReference, nil
+}
+
+// GetProviderVdcs returns the provider VDCs with their capacity. Each
+// provider VDC is fetched on its own.
+func (c *VCDClient) GetProviderVdcs() ([]*ProviderVdc, error) {
+	references, err := c.GetProviderVdcReferences()
+	if err != nil {
+		return nil, err
+	}
+	var providerVdcs []*ProviderVdc
+	for _, reference := range references {
+		providerVdc := NewProviderVdc(&c.Client)
+		if err = c.Client.getByHREF(reference.HREF, "provider VDC", providerVdc.ProviderVdc); err != nil {
+			return nil, err
+		}
+		providerVdcs = append(providerVdcs, providerVdc)
+	}
+	return providerVdcs, nil
+}
+
+// GetProviderVdcByName returns the provider VDC having the given name. The
+// returned error matches ErrNotFound when there is no such provider VDC.
+func (c *VCDClient) GetProviderVdcByName(name string) (*ProviderVdc, error) {
+	references, err := c.GetProviderVdcReferences()
+	if err != nil {
+		return nil, err
+	}
+	reference := findReference(references, name)
+	if reference == nil {
+		return nil, fmt.Errorf("provider VDC %s: %w", name, ErrNotFound)
+	}
+	providerVdc := NewProviderVdc(&c.Client)
+	if err = c.Client.getByHREF(reference.HREF, "provider VDC", providerVdc.ProviderVdc); err != nil {
+		return nil, err
+	}
+	return providerVdc, nil
+}
+
+// Refresh fetches the provider VDC again, with its current capacity.
+func (providerVdc *ProviderVdc) Refresh() error {
+	if providerVdc.ProviderVdc.HREF == "" {
+		return fmt.Errorf("cannot refresh, Object is empty")
+	}
+	refreshed := new(types.ProviderVdc)
+	if err := providerVdc.c.getByHREF(providerVdc.ProviderVdc.HREF, "provider VDC", refreshed); err != nil {
+		return err
+	}
+	providerVdc.ProviderVdc = refreshed
+	return nil
+}
+
+// GetExternalNetworks returns the references to the external networks.
+func (c *VCDClient) GetExternalNetworks() ([]*types.Reference, error) {
+	references := new(types.ExternalNetworkReferences)
+	if err := c.Client.getByHREF(c.extensionHREF("/externalNetworkReferences"), "external networks", references); err != nil {
+		return nil, err
+	}
+	return references.ExternalNetworkReference, nil
+}
+
+// GetExternalNetworkByName returns the external network having the given
+// name. The returned error matches ErrNotFound when there is no such
+// network.
+func (c *VCDClient) GetExternalNetworkByName(name string) (*ExternalNetwork, error) {
+	references, err := c.GetExternalNetworks()
+	if err != nil {
+		return nil, err
+	}
+	reference := findReference(references, name)
+	if reference == nil {
+		return nil, fmt.Errorf("external network %s: %w", name, ErrNotFound)
+	}
+	network := NewExternalNetwork(&c.Client)
+	if err = c.Client.getByHREF(reference.HREF, "external network", network.ExternalNetwork); err != nil {
+		return nil, err
+	}
+	return network, nil
+}
+
+// CreateExternalNetwork creates an external network backed by the port
+// group of networkConfiguration.VimPortGroupRef, and waits for its
+// creation to complete.
+func (c *VCDClient) CreateExternalNetwork(networkConfiguration *types.ExternalNetwork) (*ExternalNetwork, error) {
+	if networkConfiguration.Name == "" {
+		return nil, fmt.Errorf("external network name is required")
+	}
+	if networkConfiguration.Configuration == nil || networkConfiguration.Configuration.IPScopes == nil {
+		return nil, fmt.Errorf("external network %s needs an IP scope", networkConfiguration.Name)
+	}
+	portGroup := networkConfiguration.VimPortGroupRef
+	if portGroup == nil || portGroup.VimServerRef == nil || portGroup.MoRef == "" {
+		return nil, fmt.Errorf("external network %s needs a vCenter port group", networkConfiguration.Name)
+	}
+
+	var output bytes.Buffer
+	output.WriteString(xml.Header)
+	encoder := xml.NewEncoder(&output)
+	encoder.Indent("  ", "    ")
+	root := xml.StartElement{
+		Name: xml.Name{Local: "vmext:VMWExternalNetwork"},
+		Attr: []xml.Attr{
+			{Name: xml.Name{Local: "xmlns"}, Value: types.NsVCloud},
+			{Name: xml.Name{Local: "xmlns:vmext"}, Value: types.NsVCloudExtension},
+		},
+	}
+	if err := encoder.EncodeElement(networkConfiguration, root); err != nil {
+		return nil, fmt.Errorf("error encoding external network %s: %w", networkConfiguration.Name, err)
+	}
+
+	createHREF, err := url.ParseRequestURI(c.extensionHREF("/externalnets"))
+	if err != nil {
+		return nil, fmt.Errorf("error getting external networks HREF: %w", err)
+	}
+	req := c.Client.NewRequest(map[string]string{}, "POST", *createHREF, &output)
+	req.Header.Add("Content-Type", types.MimeExternalNetwork)
+	resp, err := c.Client.doRequest(req)
+	if err != nil {
+		return nil, fmt.Errorf("error creating external network %s: %w", networkConfiguration.Name, err)
+	}
+	network := NewExternalNetwork(&c.Client)
+	if err = decodeBody(resp, network.ExternalNetwork); err != nil {
+		return nil, fmt.Errorf("error decoding external network response: %w", err)
+	}
+
+	if network.ExternalNetwork.Tasks != nil {
+		task := NewTask(&c.Client)
+		for _, t := range network.ExternalNetwork.Tasks.Task {
+			task.Task = t
+			if err = task.WaitTaskCompletion(); err != nil {
+				return nil, fmt.Errorf("error creating external network %s: %w", networkConfiguration.Name, err)
+			}
+		}
+	}
+	if err = network.Refresh(); err != nil {
+		return nil, err
+	}
+	return network, nil
+}
+
+// Refresh fetches the external network again.
+func (network *ExternalNetwork) Refresh() error {
+	if network.ExternalNetwork.HREF == "" {
+		return fmt.Errorf("cannot refresh, Object is empty")
+	}
+	refreshed := new(types.ExternalNetwork)
+	if err := network.c.getByHREF(network.ExternalNetwork.HREF, "external network", refreshed); err != nil {
+		return err
+	}
+	network.ExternalNetwork = refreshed
+	return nil
+}
+
+// Delete deletes the external network and waits for the deletion to
+// complete. vCD refuses to delete a network used by an edge gateway or an
+// org VDC network.
+func (network *ExternalNetwork) Delete() error {
+	networkHREF, err := url.ParseRequestURI(network.ExternalNetwork.HREF)
+	if err != nil {
+		return fmt.Errorf("error getting external network HREF %s : %w", network.ExternalNetwork.HREF, err)
+	}
+	req := network.c.NewRequest(map[string]string{}, "DELETE", *networkHREF, nil)
+	resp, err := network.c.doRequest(req)
+	if err != nil {
+		return fmt.Errorf("error deleting external network %s: %w", network.ExternalNetwork.Name, err)
+	}
+	task := NewTask(network.c)
+	if err = decodeBody(resp, task.Task); err != nil {
+		return fmt.Errorf("error decoding task response: %w", err)
+	}
+	return task.WaitTaskCompletion()
+}
+
+// GetNetworkPools returns the references to the network pools.
+func (c *VCDClient) GetNetworkPools() ([]*types.Reference, error) {
+	references := new(types.NetworkPoolReferences)
+	if err := c.Client.getByHREF(c.extensionHREF("/networkPoolReferences"), "network pools", references); err != nil {
+		return nil, err
+	}
+	return references.NetworkPoolReference, nil
+}
+
+// GetNetworkPoolByName returns the network pool having the given name. The
+// returned error matches ErrNotFound when there is no such pool.
+func (c *VCDClient) GetNetworkPoolByName(name string) (*types.NetworkPool, error) {
+	references, err := c.GetNetworkPools()
+	if err != nil {
+		return nil, err
+	}
+	reference := findReference(references, name)
+	if reference == nil {
+		return nil, fmt.Errorf("network pool %s: %w", name, ErrNotFound)
+	}
+	pool := new(types.NetworkPool)
+	if err = c.Client.getByHREF(reference.HREF, "network pool", pool); err != nil {
+		return nil, err
+	}
+	return pool, nil
+}
+
+// extensionHREF returns the HREF of a path of the extension API, e.g.
+// "/externalNetworkReferences".
+func (c *VCDClient) extensionHREF(path string) string {
+	extensionHREF := c.Client.VCDHREF
+	extensionHREF.Path += "/admin/extension" + path
+	return extensionHREF.String()
+}
+
+// getByHREF decodes the entity at href into v.
+func (c *Client) getByHREF(href, description string, v interface{}) error {
+	entityHREF, err := url.ParseRequestURI(href)
+	if err != nil {
+		return fmt.Errorf("error getting %s HREF %s : %w", description, href, err)
+	}
+	req := c.NewRequest(map[string]string{}, "GET", *entityHREF, nil)
+	resp, err := c.doRequest(req)
+	if err != nil {
+		return fmt.Errorf("error retrieving %s: %w", description, err)
+	}
+	if err = decodeBody(resp, v); err != nil {
+		return fmt.Errorf("error decoding %s: %w", description, err)
+	}
+	return nil
+}
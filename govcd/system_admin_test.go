@@ -0,0 +1,108 @@
+/*
+ * Copyright 2014 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"errors"
+	"testing"
+
+	types "github.com/vmware/go-vcloud-director/types/v56"
+	. "gopkg.in/check.v1"
+)
+
+// Checks that the test org is among the orgs of the system and that the
+// provider VDCs are returned with their capacity.
+func (vcd *TestVCD) Test_SystemAdmin(check *C) {
+	orgs, err := vcd.client.GetOrgReferences()
+	check.Assert(err, IsNil)
+	check.Assert(findReference(orgs, vcd.config.VCD.Org), NotNil)
+
+	providerVdcs, err := vcd.client.GetProviderVdcs()
+	check.Assert(err, IsNil)
+	for _, providerVdc := range providerVdcs {
+		check.Assert(providerVdc.ProviderVdc.ComputeCapacity, NotNil)
+	}
+	_, err = vcd.client.GetExternalNetworks()
+	check.Assert(err, IsNil)
+	_, err = vcd.client.GetNetworkPools()
+	check.Assert(err, IsNil)
+}
+
+func TestVCDClient_SystemFakeVCD(t *testing.T) {
+	server, _, client := newFakeVCD(t)
+	defer server.Close()
+	server.AddOrg("other")
+	server.AddProviderVdc("pvdc")
+	server.AddExternalNetwork("public")
+	server.AddNetworkPool("vlan-pool")
+
+	orgs, err := client.GetAdminOrgs()
+	if err != nil || len(orgs) != 2 || orgs[0].AdminOrg.Name != "org" || orgs[1].AdminOrg.Name != "other" {
+		t.Fatalf("unexpected orgs: %+v, %v", orgs, err)
+	}
+
+	providerVdcs, err := client.GetProviderVdcs()
+	if err != nil || len(providerVdcs) != 1 {
+		t.Fatalf("unexpected provider VDCs: %+v, %v", providerVdcs, err)
+	}
+	capacity := providerVdcs[0].ProviderVdc.ComputeCapacity
+	if capacity == nil || capacity.CPU.Total != 20000 || capacity.Memory.Total != 65536 {
+		t.Fatalf("unexpected provider VDC capacity: %+v", capacity)
+	}
+	providerVdc, err := client.GetProviderVdcByName("pvdc")
+	if err != nil || providerVdc.ProviderVdc.NetworkPoolReferences == nil ||
+		providerVdc.ProviderVdc.NetworkPoolReferences.NetworkPoolReference[0].Name != "vlan-pool" {
+		t.Fatalf("unexpected provider VDC: %+v, %v", providerVdc, err)
+	}
+	if _, err = client.GetProviderVdcByName("missing"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+
+	pool, err := client.GetNetworkPoolByName("vlan-pool")
+	if err != nil || pool.PoolType != "vmext:VlanPoolType" {
+		t.Fatalf("unexpected network pool: %+v, %v", pool, err)
+	}
+
+	public, err := client.GetExternalNetworkByName("public")
+	if err != nil || public.ExternalNetwork.VimPortGroupRef == nil || public.ExternalNetwork.VimPortGroupRef.MoRef == "" {
+		t.Fatalf("unexpected external network: %+v, %v", public, err)
+	}
+	if _, err = client.CreateExternalNetwork(&types.ExternalNetwork{Name: "backup"}); err == nil {
+		t.Fatal("expected an error creating an external network without port group")
+	}
+	server.ResetRequests()
+	backup, err := client.CreateExternalNetwork(&types.ExternalNetwork{
+		Name:        "backup",
+		Description: "backup network",
+		Configuration: &types.NetworkConfiguration{
+			IPScopes: &types.IPScopes{IPScope: types.IPScope{
+				Gateway:   "10.0.0.1",
+				Netmask:   "255.255.255.0",
+				IsEnabled: true,
+			}},
+			FenceMode: "isolated",
+		},
+		VimPortGroupRef: public.ExternalNetwork.VimPortGroupRef,
+	})
+	if err != nil || backup.ExternalNetwork.Description != "backup network" ||
+		backup.ExternalNetwork.Configuration.IPScopes.IPScope.Gateway != "10.0.0.1" {
+		t.Fatalf("unexpected created external network: %+v, %v", backup, err)
+	}
+	checkRequests(t, server, apiRequest{
+		Method: "POST", Path: "/admin/extension/externalnets", ContentType: "application/vnd.vmware.admin.vmwexternalnet+xml",
+		Body: []string{"<vmext:VMWExternalNetwork ", `name="backup"`, "<Gateway>10.0.0.1</Gateway>",
+			"<FenceMode>isolated</FenceMode>", `<VimPortGroupRef xmlns="http://www.vmware.com/vcloud/extension/v1.5">`, "<MoRef>"},
+	})
+	networks, err := client.GetExternalNetworks()
+	if err != nil || len(networks) != 2 {
+		t.Fatalf("expected 2 external networks, got %+v, %v", networks, err)
+	}
+	if err = backup.Delete(); err != nil {
+		t.Fatalf("error deleting external network: %s", err)
+	}
+	if _, err = client.GetExternalNetworkByName("backup"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound after deletion, got %v", err)
+	}
+}
@@ -5,8 +5,10 @@
 package govcd
 
 import (
+	"context"
 	"fmt"
 	"net/url"
+	"sync"
 	"time"
 
 	types "github.com/vmware/go-vcloud-director/types/v56"
@@ -25,6 +27,12 @@ func NewTask(c *Client) *Task {
 }
 
 func (t *Task) Refresh() error {
+	return t.RefreshWithContext(context.Background())
+}
+
+// RefreshWithContext retrieves the current state of the task, aborting if
+// ctx is cancelled before vCD answers.
+func (t *Task) RefreshWithContext(ctx context.Context) error {
 
 	if t.Task == nil {
 		return fmt.Errorf("cannot refresh, Object is empty")
@@ -32,11 +40,11 @@ func (t *Task) Refresh() error {
 
 	u, _ := url.ParseRequestURI(t.Task.HREF)
 
-	req := t.c.NewRequest(map[string]string{}, "GET", *u, nil)
+	req := t.c.NewRequestWithContext(ctx, map[string]string{}, "GET", *u, nil)
 
-	resp, err := checkResp(t.c.Http.Do(req))
+	resp, err := t.c.doRequest(req)
 	if err != nil {
-		return fmt.Errorf("error retrieving task: %s", err)
+		return fmt.Errorf("error retrieving task: %w", err)
 	}
 
 	// Empty struct before a new unmarshal, otherwise we end up with duplicate
@@ -44,34 +52,206 @@ func (t *Task) Refresh() error {
 	t.Task = &types.Task{}
 
 	if err = decodeBody(resp, t.Task); err != nil {
-		return fmt.Errorf("error decoding task response: %s", err)
+		return fmt.Errorf("error decoding task response: %w", err)
 	}
 
 	// The request was successful
 	return nil
 }
 
+// TaskProgressFunc receives a snapshot of a task after every poll. The
+// snapshot carries the task Status, its Progress percentage and any nested
+// Tasks reported by vCD.
+type TaskProgressFunc func(task *types.Task)
+
+// TaskWaitOptions configures how WaitTaskCompletionWithOptions polls a task.
+// The zero value polls every 3 seconds with no time limit, which is the
+// behaviour of WaitTaskCompletion.
+type TaskWaitOptions struct {
+	Timeout         time.Duration    // Maximum time to wait for the task; zero means no limit
+	PollInterval    time.Duration    // Delay before the first re-poll; defaults to 3 seconds
+	MaxPollInterval time.Duration    // Upper bound for the delay when backing off; zero means no bound
+	BackoffFactor   float64          // Multiplier applied to the delay after each poll; values <= 1 keep it constant
+	OnProgress      TaskProgressFunc // Optional callback invoked after every successful poll
+}
+
+// defaultTaskPollInterval is the delay between polls when none is configured.
+const defaultTaskPollInterval = 3 * time.Second
+
+// nextPollInterval returns the delay to use after a poll that waited current.
+func (o TaskWaitOptions) nextPollInterval(current time.Duration) time.Duration {
+	if current <= 0 {
+		current = o.PollInterval
+		if current <= 0 {
+			return defaultTaskPollInterval
+		}
+		return current
+	}
+	if o.BackoffFactor <= 1 {
+		return current
+	}
+	next := time.Duration(float64(current) * o.BackoffFactor)
+	if o.MaxPollInterval > 0 && next > o.MaxPollInterval {
+		next = o.MaxPollInterval
+	}
+	return next
+}
+
+// TaskError is returned when a task ends in the error state. Details holds
+// the error reported by vCD for the task, when there is one.
+type TaskError struct {
+	Task    *types.Task
+	Details *types.Error
+}
+
+func (e *TaskError) Error() string {
+	if e.Details != nil && e.Details.Message != "" {
+		return fmt.Sprintf("task did not complete succesfully: %s: %d: %s",
+			e.Task.Description, e.Details.MajorErrorCode, e.Details.Message)
+	}
+	return fmt.Sprintf("task did not complete succesfully: %s", e.Task.Description)
+}
+
 func (t *Task) WaitTaskCompletion() error {
+	return t.WaitTaskCompletionWithContext(context.Background())
+}
+
+// WaitTaskCompletionWithContext polls the task until it leaves the queued
+// and running states. Polling stops with the context error as soon as ctx
+// is cancelled or its deadline expires.
+func (t *Task) WaitTaskCompletionWithContext(ctx context.Context) error {
+	return t.WaitTaskCompletionWithOptions(ctx, TaskWaitOptions{})
+}
+
+// WaitTaskCompletionWithOptions polls the task until it leaves the queued
+// and running states, using the timeout, polling interval and backoff given
+// in options. If the task ends in error, the returned error is a *TaskError
+// carrying the details reported by vCD. If the wait is cut short by ctx or
+// by options.Timeout, the returned error wraps the context error.
+func (t *Task) WaitTaskCompletionWithOptions(ctx context.Context, options TaskWaitOptions) error {
 
 	if t.Task == nil {
 		return fmt.Errorf("cannot refresh, Object is empty")
 	}
 
+	if options.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, options.Timeout)
+		defer cancel()
+	}
+
+	var interval time.Duration
+	start := time.Now()
 	for {
-		err := t.Refresh()
+		// The error of RefreshWithContext already says the task could not
+		// be retrieved
+		err := t.RefreshWithContext(ctx)
 		if err != nil {
-			return fmt.Errorf("error retreiving task: %s", err)
+			return err
+		}
+
+		if options.OnProgress != nil {
+			options.OnProgress(t.Task)
 		}
 
 		// If task is not in a waiting status we're done, check if there's an error and return it.
-		if t.Task.Status != "queued" && t.Task.Status != "preRunning" && t.Task.Status != "running" {
+		if !t.IsRunning() {
+			t.c.metrics.observeTask(t.Task, time.Since(start))
 			if t.Task.Status == "error" {
-				return fmt.Errorf("task did not complete succesfully: %s", t.Task.Description)
+				return &TaskError{Task: t.Task, Details: t.Task.Error}
 			}
 			return nil
 		}
 
-		// Sleep for 3 seconds and try again.
-		time.Sleep(3 * time.Second)
+		interval = options.nextPollInterval(interval)
+		if err = sleepWithContext(ctx, interval); err != nil {
+			return fmt.Errorf("error waiting for task: %w", err)
+		}
+	}
+}
+
+// IsRunning reports whether the task is still queued or running, as of the
+// last refresh.
+func (t *Task) IsRunning() bool {
+	return t.Task.Status == "queued" || t.Task.Status == "preRunning" || t.Task.Status == "running"
+}
+
+// Cancel requests the cancellation of a running task. vCD cancels tasks
+// asynchronously: use WaitTaskCompletion to wait for the task to stop.
+func (t *Task) Cancel() error {
+	return t.CancelWithContext(context.Background())
+}
+
+// CancelWithContext requests the cancellation of a running task, aborting
+// the request if ctx is cancelled before vCD accepts it.
+func (t *Task) CancelWithContext(ctx context.Context) error {
+
+	if t.Task == nil || t.Task.HREF == "" {
+		return fmt.Errorf("cannot cancel, Object is empty")
+	}
+
+	u, err := url.ParseRequestURI(t.cancelHREF())
+	if err != nil {
+		return fmt.Errorf("error parsing task cancel href: %w", err)
+	}
+
+	req := t.c.NewRequestWithContext(ctx, map[string]string{}, "POST", *u, nil)
+
+	resp, err := t.c.doRequest(req)
+	if err != nil {
+		return fmt.Errorf("error cancelling task: %w", err)
+	}
+	resp.Body.Close()
+
+	t.Task.CancelRequested = true
+	return nil
+}
+
+// cancelHREF returns the HREF cancelling the task: the task:cancel link
+// when the task holds it, else the documented action of the task.
+func (t *Task) cancelHREF() string {
+	if t.Task.Link != nil && t.Task.Link.Rel == types.RelTaskCancel {
+		return t.Task.Link.HREF
+	}
+	return t.Task.HREF + "/action/cancel"
+}
+
+// TaskResult holds the outcome of waiting for one task with
+// WaitTasksCompletion.
+type TaskResult struct {
+	Task Task  // The task, as of its last refresh
+	Err  error // The error returned while waiting for the task, if any
+}
+
+// WaitTasksCompletion waits concurrently for all the given tasks, using the
+// same options for each of them, and returns one result per task in the same
+// order as tasks. It returns once every task has finished or ctx is done.
+func WaitTasksCompletion(ctx context.Context, tasks []Task, options TaskWaitOptions) []TaskResult {
+	results := make([]TaskResult, len(tasks))
+	var wg sync.WaitGroup
+	for i := range tasks {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			task := tasks[i]
+			err := task.WaitTaskCompletionWithOptions(ctx, options)
+			results[i] = TaskResult{Task: task, Err: err}
+		}(i)
+	}
+	wg.Wait()
+	return results
+}
+
+// tasksFromList wraps a list of task elements into Task objects, optionally
+// keeping only the ones that are still queued or running.
+func tasksFromList(c *Client, list []*types.Task, runningOnly bool) []Task {
+	var tasks []Task
+	for _, t := range list {
+		task := Task{Task: t, c: c}
+		if runningOnly && !task.IsRunning() {
+			continue
+		}
+		tasks = append(tasks, task)
 	}
+	return tasks
 }
@@ -23,3 +23,147 @@ func (vcd *TestVCD) Test_WaitTaskCompletion(check *C) {
 
 }
 */
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	types "github.com/vmware/go-vcloud-director/types/v56"
+)
+
+// Tests the polling intervals computed from TaskWaitOptions, with and
+// without backoff.
+func TestTaskWaitOptions_nextPollInterval(t *testing.T) {
+	if got := (TaskWaitOptions{}).nextPollInterval(0); got != defaultTaskPollInterval {
+		t.Fatalf("expected default interval %s, got %s", defaultTaskPollInterval, got)
+	}
+	constant := TaskWaitOptions{PollInterval: time.Second}
+	if got := constant.nextPollInterval(constant.nextPollInterval(0)); got != time.Second {
+		t.Fatalf("expected constant interval of 1s, got %s", got)
+	}
+	backoff := TaskWaitOptions{PollInterval: time.Second, BackoffFactor: 2, MaxPollInterval: 5 * time.Second}
+	expected := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+	var interval time.Duration
+	for i, want := range expected {
+		interval = backoff.nextPollInterval(interval)
+		if interval != want {
+			t.Fatalf("poll %d: expected %s, got %s", i, want, interval)
+		}
+	}
+}
+
+// Tests that TaskError reports the vCD error details when available.
+func TestTaskError_Error(t *testing.T) {
+	task := &types.Task{Description: "Creating vApp"}
+	err := &TaskError{Task: task}
+	if err.Error() != "task did not complete succesfully: Creating vApp" {
+		t.Fatalf("unexpected message: %s", err.Error())
+	}
+	err.Details = &types.Error{MajorErrorCode: 400, Message: "invalid name"}
+	if err.Error() != "task did not complete succesfully: Creating vApp: 400: invalid name" {
+		t.Fatalf("unexpected message: %s", err.Error())
+	}
+}
+
+// Tests that WaitTasksCompletion waits for every task and reports one
+// result per task, in order, and that Cancel posts to the cancel link.
+func TestWaitTasksCompletionAndCancel(t *testing.T) {
+	var mu sync.Mutex
+	polls := map[string]int{}
+	cancelled := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if strings.HasSuffix(r.URL.Path, "/action/cancel") {
+			cancelled = true
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		polls[r.URL.Path]++
+		status := "running"
+		if polls[r.URL.Path] > 1 {
+			status = "success"
+			if strings.HasSuffix(r.URL.Path, "task-2") {
+				status = "error"
+			}
+		}
+		fmt.Fprintf(w, `<Task xmlns="http://www.vmware.com/vcloud/v1.5" href="http://%s%s" status="%s">`+
+			`<Link rel="task:cancel" href="http://%s%s/action/cancel"/><Error majorErrorCode="500" message="boom"/></Task>`,
+			r.Host, r.URL.Path, status, r.Host, r.URL.Path)
+	}))
+	defer server.Close()
+
+	u, _ := url.ParseRequestURI(server.URL)
+	client := &Client{APIVersion: "5.5", VCDHREF: *u}
+	var tasks []Task
+	for i := 1; i <= 3; i++ {
+		task := NewTask(client)
+		task.Task.HREF = fmt.Sprintf("%s/api/task/task-%d", server.URL, i)
+		tasks = append(tasks, *task)
+	}
+
+	results := WaitTasksCompletion(context.Background(), tasks, TaskWaitOptions{PollInterval: time.Millisecond})
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(results))
+	}
+	for i, result := range results {
+		if i == 1 {
+			taskErr, ok := result.Err.(*TaskError)
+			if !ok || taskErr.Details == nil || taskErr.Details.Message != "boom" {
+				t.Fatalf("expected a TaskError with details for task %d, got %v", i+1, result.Err)
+			}
+			continue
+		}
+		if result.Err != nil || result.Task.Task.Status != "success" {
+			t.Fatalf("unexpected result for task %d: %s %v", i+1, result.Task.Task.Status, result.Err)
+		}
+	}
+
+	if err := tasks[0].Cancel(); err != nil {
+		t.Fatalf("error cancelling task: %s", err)
+	}
+	if !cancelled {
+		t.Fatalf("cancel link was not called")
+	}
+}
+
+func TestTask_cancelHREF(t *testing.T) {
+	tests := []struct {
+		link *types.Link
+		want string
+	}{
+		{nil, "https://vcd/api/task/1/action/cancel"},
+		{&types.Link{Rel: types.RelDown, HREF: "https://vcd/api/task/1/owner"}, "https://vcd/api/task/1/action/cancel"},
+		{&types.Link{Rel: types.RelTaskCancel, HREF: "https://vcd/api/task/1/cancel"}, "https://vcd/api/task/1/cancel"},
+	}
+	for _, test := range tests {
+		task := NewTask(&Client{})
+		task.Task.HREF = "https://vcd/api/task/1"
+		task.Task.Link = test.link
+		if got := task.cancelHREF(); got != test.want {
+			t.Fatalf("expected %s for link %+v, got %s", test.want, test.link, got)
+		}
+	}
+}
+
+func TestTask_WaitTaskCompletionRefreshError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	u, _ := url.ParseRequestURI(server.URL)
+	task := NewTask(&Client{APIVersion: "5.5", VCDHREF: *u})
+	task.Task.HREF = server.URL + "/api/task/1"
+	err := task.WaitTaskCompletion()
+	if !IsNotFound(err) || strings.Count(err.Error(), "task") != 1 {
+		t.Fatalf("expected a single not found error retrieving the task, got %v", err)
+	}
+}
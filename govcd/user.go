@@ -0,0 +1,216 @@
+/*
+ * Copyright 2014 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"strings"
+
+	types "github.com/vmware/go-vcloud-director/types/v56"
+)
+
+// OrgUser is a user of an organization, managed through its AdminOrg.
+type OrgUser struct {
+	User     *types.User
+	AdminOrg *AdminOrg
+	c        *Client
+}
+
+// NewUser returns an empty user of adminOrg.
+func NewUser(c *Client, adminOrg *AdminOrg) *OrgUser {
+	return &OrgUser{
+		User:     new(types.User),
+		AdminOrg: adminOrg,
+		c:        c,
+	}
+}
+
+// CreateUser creates a user in the org. Name, Password and Role are
+// required, the role being a reference as returned by GetRoleReference.
+// The user is enabled when IsEnabled is set.
+func (adminOrg *AdminOrg) CreateUser(userConfiguration *types.User) (*OrgUser, error) {
+	if userConfiguration.Name == "" || userConfiguration.Password == "" || userConfiguration.Role == nil {
+		return nil, fmt.Errorf("name, password and role are required to create a user")
+	}
+	usersHREF, err := url.ParseRequestURI(adminOrg.AdminOrg.HREF)
+	if err != nil {
+		return nil, fmt.Errorf("error getting AdminOrg HREF %s : %w", adminOrg.AdminOrg.HREF, err)
+	}
+	usersHREF.Path += "/users"
+
+	user := NewUser(adminOrg.c, adminOrg)
+	if err = user.send("POST", *usersHREF, userConfiguration); err != nil {
+		return nil, fmt.Errorf("error creating user %s: %w", userConfiguration.Name, err)
+	}
+	return user, nil
+}
+
+// GetUserByName returns the user of the org having the given name. The
+// returned error matches ErrNotFound when the org has no such user.
+func (adminOrg *AdminOrg) GetUserByName(name string) (*OrgUser, error) {
+	if err := adminOrg.Refresh(); err != nil {
+		return nil, err
+	}
+	if adminOrg.AdminOrg.Users != nil {
+		for _, reference := range adminOrg.AdminOrg.Users.User {
+			if reference.Name == name {
+				return adminOrg.getUserByHREF(reference.HREF)
+			}
+		}
+	}
+	return nil, fmt.Errorf("user %s in org %s: %w", name, adminOrg.AdminOrg.Name, ErrNotFound)
+}
+
+// GetUserById returns the user having the given ID, either a URN such as
+// "urn:vcloud:user:<uuid>" or the bare UUID.
+func (adminOrg *AdminOrg) GetUserById(id string) (*OrgUser, error) {
+	userHREF := adminOrg.c.VCDHREF
+	userHREF.Path += "/admin/user/" + id[strings.LastIndex(id, ":")+1:]
+	return adminOrg.getUserByHREF(userHREF.String())
+}
+
+func (adminOrg *AdminOrg) getUserByHREF(href string) (*OrgUser, error) {
+	user := NewUser(adminOrg.c, adminOrg)
+	user.User.HREF = href
+	if err := user.Refresh(); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// GetRoleReference returns the reference to the role having the given
+// name, e.g. "vApp Author", to be set as the role of a user. Roles local to
+// the org take precedence over global roles, which are the only roles
+// before API version 27.0.
+func (adminOrg *AdminOrg) GetRoleReference(roleName string) (*types.Reference, error) {
+	if adminOrg.c.APIVersionIsAtLeast(orgRolesAPIVersion) {
+		localRoles, err := adminOrg.GetRoles()
+		if err != nil {
+			return nil, err
+		}
+		if reference := findReference(localRoles, roleName); reference != nil {
+			return reference, nil
+		}
+	}
+	vcloud, err := adminOrg.c.getAdminVCloud()
+	if err != nil {
+		return nil, err
+	}
+	if vcloud.RoleReferences != nil {
+		if reference := findReference(vcloud.RoleReferences.RoleReference, roleName); reference != nil {
+			return reference, nil
+		}
+	}
+	return nil, fmt.Errorf("role %s: %w", roleName, ErrNotFound)
+}
+
+// Refresh fetches the current state of the user.
+func (user *OrgUser) Refresh() error {
+	userHREF, err := url.ParseRequestURI(user.User.HREF)
+	if err != nil {
+		return fmt.Errorf("error getting user HREF %s : %w", user.User.HREF, err)
+	}
+	req := user.c.NewRequest(map[string]string{}, "GET", *userHREF, nil)
+	resp, err := user.c.doRequest(req)
+	if err != nil {
+		return fmt.Errorf("error retrieving user: %w", err)
+	}
+	refreshed := new(types.User)
+	if err = decodeBody(resp, refreshed); err != nil {
+		return fmt.Errorf("error decoding user response: %w", err)
+	}
+	user.User = refreshed
+	return nil
+}
+
+// Update sends the changes made to user.User, such as the full name, the
+// email address, the role or the VM quotas, to vCD. The password is
+// changed when user.User.Password is set, see ChangePassword.
+func (user *OrgUser) Update() error {
+	userHREF, err := url.ParseRequestURI(user.User.HREF)
+	if err != nil {
+		return fmt.Errorf("error getting user HREF %s : %w", user.User.HREF, err)
+	}
+	if err = user.send("PUT", *userHREF, user.User); err != nil {
+		return fmt.Errorf("error updating user %s: %w", user.User.Name, err)
+	}
+	return nil
+}
+
+// send sends userConfiguration to href and stores the user returned by
+// vCD.
+func (user *OrgUser) send(method string, href url.URL, userConfiguration *types.User) error {
+	userConfiguration.Xmlns = "http://www.vmware.com/vcloud/v1.5"
+	output, _ := xml.MarshalIndent(userConfiguration, "  ", "    ")
+	xmlData := bytes.NewBufferString(xml.Header + string(output))
+
+	req := user.c.NewRequest(map[string]string{}, method, href, xmlData)
+	req.Header.Add("Content-Type", types.MimeAdminUser)
+	resp, err := user.c.doRequest(req)
+	if err != nil {
+		return err
+	}
+	updated := new(types.User)
+	if err = decodeBody(resp, updated); err != nil {
+		return fmt.Errorf("error decoding user response: %w", err)
+	}
+	user.User = updated
+	return nil
+}
+
+// Enable allows the user to log in.
+func (user *OrgUser) Enable() error {
+	user.User.IsEnabled = true
+	return user.Update()
+}
+
+// Disable prevents the user from logging in.
+func (user *OrgUser) Disable() error {
+	user.User.IsEnabled = false
+	return user.Update()
+}
+
+// ChangePassword sets a new password for the user.
+func (user *OrgUser) ChangePassword(newPassword string) error {
+	user.User.Password = newPassword
+	err := user.Update()
+	user.User.Password = ""
+	return err
+}
+
+// ChangeRole gives the user the role having the given name.
+func (user *OrgUser) ChangeRole(roleName string) error {
+	role, err := user.AdminOrg.GetRoleReference(roleName)
+	if err != nil {
+		return err
+	}
+	user.User.Role = role
+	return user.Update()
+}
+
+// Unlock unlocks a user locked out after too many failed logins, by
+// clearing its IsLocked flag.
+func (user *OrgUser) Unlock() error {
+	user.User.IsLocked = false
+	return user.Update()
+}
+
+// Delete removes the user from the org.
+func (user *OrgUser) Delete() error {
+	userHREF, err := url.ParseRequestURI(user.User.HREF)
+	if err != nil {
+		return fmt.Errorf("error getting user HREF %s : %w", user.User.HREF, err)
+	}
+	req := user.c.NewRequest(map[string]string{}, "DELETE", *userHREF, nil)
+	resp, err := user.c.doRequest(req)
+	if err != nil {
+		return fmt.Errorf("error deleting user %s: %w", user.User.Name, err)
+	}
+	resp.Body.Close()
+	return nil
+}
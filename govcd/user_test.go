@@ -0,0 +1,152 @@
+/*
+ * Copyright 2014 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"errors"
+	"testing"
+
+	types "github.com/vmware/go-vcloud-director/types/v56"
+	. "gopkg.in/check.v1"
+)
+
+// Creates a user in the test org, changes it and deletes it.
+func (vcd *TestVCD) Test_OrgUser(check *C) {
+	adminOrg, err := GetAdminOrgByName(vcd.client, vcd.config.VCD.Org)
+	check.Assert(err, IsNil)
+	role, err := adminOrg.GetRoleReference("vApp Author")
+	check.Assert(err, IsNil)
+
+	user, err := adminOrg.CreateUser(&types.User{
+		Name:      "govcd-test-user",
+		Password:  "P4ssw0rd-govcd",
+		FullName:  "Test User",
+		IsEnabled: true,
+		Role:      role,
+	})
+	check.Assert(err, IsNil)
+	defer user.Delete()
+
+	user.User.EmailAddress = "test@example.com"
+	user.User.DeployedVmQuota = 5
+	check.Assert(user.Update(), IsNil)
+	check.Assert(user.ChangeRole("vApp User"), IsNil)
+	check.Assert(user.Disable(), IsNil)
+
+	user, err = adminOrg.GetUserByName("govcd-test-user")
+	check.Assert(err, IsNil)
+	check.Assert(user.User.EmailAddress, Equals, "test@example.com")
+	check.Assert(user.User.DeployedVmQuota, Equals, 5)
+	check.Assert(user.User.Role.Name, Equals, "vApp User")
+	check.Assert(user.User.IsEnabled, Equals, false)
+}
+
+func TestAdminOrg_UsersFakeVCD(t *testing.T) {
+	server, fakeVdc, client := newFakeVCD(t)
+	defer server.Close()
+	fakeVdc.Org().AddUser("bob", "vApp User").User.IsLocked = true
+
+	adminOrg, err := GetAdminOrgByName(client, "org")
+	if err != nil {
+		t.Fatalf("error getting admin org: %s", err)
+	}
+	role, err := adminOrg.GetRoleReference("vApp Author")
+	if err != nil {
+		t.Fatalf("error getting role: %s", err)
+	}
+	if _, err = adminOrg.GetRoleReference("Nobody"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound for an unknown role, got %v", err)
+	}
+
+	if _, err = adminOrg.CreateUser(&types.User{Name: "alice"}); err == nil {
+		t.Fatal("expected an error creating a user without password and role")
+	}
+	server.ResetRequests()
+	user, err := adminOrg.CreateUser(&types.User{
+		Name:            "alice",
+		Password:        "secret",
+		FullName:        "Alice",
+		EmailAddress:    "alice@example.com",
+		IsEnabled:       true,
+		DeployedVmQuota: 2,
+		Role:            role,
+	})
+	if err != nil {
+		t.Fatalf("error creating user: %s", err)
+	}
+	checkRequests(t, server, apiRequest{
+		Method: "POST", Path: "/admin/org/[^/]+/users", ContentType: "application/vnd.vmware.admin.user+xml",
+		Body: []string{"<User ", `name="alice"`, "<Password>secret</Password>", "<Role href="},
+	})
+	if user.User.HREF == "" || user.User.Role.Name != "vApp Author" || user.User.Password != "" {
+		t.Fatalf("unexpected created user: %+v", user.User)
+	}
+
+	user.User.FullName = "Alice Liddell"
+	user.User.StoredVmQuota = 10
+	if err = user.Update(); err != nil {
+		t.Fatalf("error updating user: %s", err)
+	}
+	if err = user.ChangeRole("Catalog Author"); err != nil {
+		t.Fatalf("error changing role: %s", err)
+	}
+	server.ResetRequests()
+	if err = user.ChangePassword("another"); err != nil || user.User.Password != "" {
+		t.Fatalf("error changing password: %v", err)
+	}
+	checkRequests(t, server, apiRequest{
+		Method: "PUT", Path: "/admin/user/[^/]+", ContentType: "application/vnd.vmware.admin.user+xml",
+		Body: []string{`name="alice"`, "<Password>another</Password>"},
+	})
+	if err = user.Disable(); err != nil {
+		t.Fatalf("error disabling user: %s", err)
+	}
+
+	byName, err := adminOrg.GetUserByName("alice")
+	if err != nil {
+		t.Fatalf("error getting user by name: %s", err)
+	}
+	got := byName.User
+	if got.FullName != "Alice Liddell" || got.StoredVmQuota != 10 || got.DeployedVmQuota != 2 ||
+		got.Role.Name != "Catalog Author" || got.IsEnabled {
+		t.Fatalf("unexpected user: %+v", got)
+	}
+	byID, err := adminOrg.GetUserById(got.ID)
+	if err != nil || byID.User.Name != "alice" {
+		t.Fatalf("error getting user by id: %v", err)
+	}
+	if err = byID.Enable(); err != nil || !byID.User.IsEnabled {
+		t.Fatalf("error enabling user: %v", err)
+	}
+
+	// vCD has no unlock action: a user is unlocked by clearing IsLocked.
+	bob, err := adminOrg.GetUserByName("bob")
+	if err != nil || !bob.User.IsLocked {
+		t.Fatalf("expected bob to be locked: %+v, %v", bob, err)
+	}
+	server.ResetRequests()
+	if err = bob.Unlock(); err != nil || bob.User.IsLocked {
+		t.Fatalf("error unlocking user: %v", err)
+	}
+	checkRequests(t, server, apiRequest{
+		Method: "PUT", Path: "/admin/user/[^/]+", ContentType: types.MimeAdminUser,
+		Body: []string{"<IsLocked>false</IsLocked>", `name="bob"`},
+	})
+	if bob, err = adminOrg.GetUserByName("bob"); err != nil || bob.User.IsLocked {
+		t.Fatalf("expected bob to be unlocked: %+v, %v", bob, err)
+	}
+
+	server.ResetRequests()
+	if err = byID.Delete(); err != nil {
+		t.Fatalf("error deleting user: %s", err)
+	}
+	checkRequests(t, server, apiRequest{Method: "DELETE", Path: "/admin/user/[^/]+"})
+	if _, err = adminOrg.GetUserByName("alice"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound for a deleted user, got %v", err)
+	}
+	if _, err = adminOrg.GetUserById(got.ID); !IsNotFound(err) {
+		t.Fatalf("expected a not found error for a deleted user, got %v", err)
+	}
+}
@@ -6,11 +6,10 @@ package govcd
 
 import (
 	"bytes"
+	"context"
 	"encoding/xml"
 	"fmt"
-	"log"
 	"net/url"
-	"os"
 
 	types "github.com/vmware/go-vcloud-director/types/v56"
 	"strconv"
@@ -34,19 +33,19 @@ func (v *VCDClient) NewVApp(c *Client) VApp {
 }
 
 // Returns the vdc where the vapp resides in.
-func (v *VApp) getParentVDC() (Vdc, error) {
+func (v *VApp) getParentVDC(ctx context.Context) (Vdc, error) {
 	for _, a := range v.VApp.Link {
 		if a.Type == "application/vnd.vmware.vcloud.vdc+xml" {
 			u, err := url.ParseRequestURI(a.HREF)
 			if err != nil {
-				return Vdc{}, fmt.Errorf("Cannot parse HREF : %v", err)
+				return Vdc{}, fmt.Errorf("Cannot parse HREF : %w", err)
 			}
-			req := v.c.NewRequest(map[string]string{}, "GET", *u, nil)
-			resp, err := checkResp(v.c.Http.Do(req))
+			req := v.c.NewRequestWithContext(ctx, map[string]string{}, "GET", *u, nil)
+			resp, err := v.c.doRequest(req)
 
 			vdc := NewVdc(v.c)
 			if err = decodeBody(resp, vdc.Vdc); err != nil {
-				return Vdc{}, fmt.Errorf("error decoding task response: %s", err)
+				return Vdc{}, fmt.Errorf("error decoding task response: %w", err)
 			}
 			return *vdc, nil
 		}
@@ -55,6 +54,12 @@ func (v *VApp) getParentVDC() (Vdc, error) {
 }
 
 func (v *VApp) Refresh() error {
+	return v.RefreshWithContext(context.Background())
+}
+
+// RefreshWithContext retrieves the current state of the vApp, aborting
+// if ctx is cancelled before vCD answers.
+func (v *VApp) RefreshWithContext(ctx context.Context) error {
 
 	if v.VApp.HREF == "" {
 		return fmt.Errorf("cannot refresh, Object is empty")
@@ -62,11 +67,11 @@ func (v *VApp) Refresh() error {
 
 	u, _ := url.ParseRequestURI(v.VApp.HREF)
 
-	req := v.c.NewRequest(map[string]string{}, "GET", *u, nil)
+	req := v.c.NewRequestWithContext(ctx, map[string]string{}, "GET", *u, nil)
 
-	resp, err := checkResp(v.c.Http.Do(req))
+	resp, err := v.c.doRequest(req)
 	if err != nil {
-		return fmt.Errorf("error retrieving task: %s", err)
+		return fmt.Errorf("error retrieving task: %w", err)
 	}
 
 	// Empty struct before a new unmarshal, otherwise we end up with duplicate
@@ -74,14 +79,39 @@ func (v *VApp) Refresh() error {
 	v.VApp = &types.VApp{}
 
 	if err = decodeBody(resp, v.VApp); err != nil {
-		return fmt.Errorf("error decoding task response: %s", err)
+		return fmt.Errorf("error decoding task response: %w", err)
 	}
 
 	// The request was successful
 	return nil
 }
 
+// GetTasksInProgress refreshes the vApp and returns the tasks that are
+// still queued or running on it.
+func (v *VApp) GetTasksInProgress() ([]Task, error) {
+	return v.GetTasksInProgressWithContext(context.Background())
+}
+
+// GetTasksInProgressWithContext behaves like GetTasksInProgress, aborting
+// if ctx is cancelled before vCD answers.
+func (v *VApp) GetTasksInProgressWithContext(ctx context.Context) ([]Task, error) {
+	err := v.RefreshWithContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error refreshing vapp: %w", err)
+	}
+	if v.VApp.Tasks == nil {
+		return nil, nil
+	}
+	return tasksFromList(v.c, v.VApp.Tasks.Task, true), nil
+}
+
 func (v *VApp) AddVM(orgvdcnetworks []*types.OrgVDCNetwork, vapptemplate VAppTemplate, name string) (Task, error) {
+	return v.AddVMWithContext(context.Background(), orgvdcnetworks, vapptemplate, name)
+}
+
+// AddVMWithContext behaves like AddVM. The request is aborted if ctx is
+// cancelled before vCD accepts it.
+func (v *VApp) AddVMWithContext(ctx context.Context, orgvdcnetworks []*types.OrgVDCNetwork, vapptemplate VAppTemplate, name string) (Task, error) {
 
 	vcomp := &types.ReComposeVAppParams{
 		Ovf:         "http://schemas.dmtf.org/ovf/envelope/1",
@@ -129,38 +159,43 @@ func (v *VApp) AddVM(orgvdcnetworks []*types.OrgVDCNetwork, vapptemplate VAppTem
 	s, _ := url.ParseRequestURI(v.VApp.HREF)
 	s.Path += "/action/recomposeVApp"
 
-	log.Printf("[TRACE] Recompose XML: %s", string(output))
 
 	b := bytes.NewBufferString(xml.Header + string(output))
 
-	req := v.c.NewRequest(map[string]string{}, "POST", *s, b)
+	req := v.c.NewRequestWithContext(ctx, map[string]string{}, "POST", *s, b)
 
 	req.Header.Add("Content-Type", "application/vnd.vmware.vcloud.recomposeVAppParams+xml")
 
-	resp, err := checkResp(v.c.Http.Do(req))
+	resp, err := v.c.doRequest(req)
 	if err != nil {
-		return Task{}, fmt.Errorf("error instantiating a new VM: %s", err)
+		return Task{}, fmt.Errorf("error instantiating a new VM: %w", err)
 	}
 
 	task := NewTask(v.c)
 
 	if err = decodeBody(resp, task.Task); err != nil {
-		return Task{}, fmt.Errorf("error decoding task response: %s", err)
+		return Task{}, fmt.Errorf("error decoding task response: %w", err)
 	}
 
 	return *task, nil
 }
 
 func (v *VApp) RemoveVM(vm VM) error {
+	return v.RemoveVMWithContext(context.Background(), vm)
+}
+
+// RemoveVMWithContext behaves like RemoveVM, aborting if ctx is cancelled
+// before the operation completes.
+func (v *VApp) RemoveVMWithContext(ctx context.Context, vm VM) error {
 
-	v.Refresh()
+	v.RefreshWithContext(ctx)
 	task := NewTask(v.c)
 	if v.VApp.Tasks != nil {
 		for _, t := range v.VApp.Tasks.Task {
 			task.Task = t
-			err := task.WaitTaskCompletion()
+			err := task.WaitTaskCompletionWithContext(ctx)
 			if err != nil {
-				return fmt.Errorf("Error performing task: %#v", err)
+				return fmt.Errorf("Error performing task: %w", err)
 			}
 		}
 	}
@@ -181,45 +216,51 @@ func (v *VApp) RemoveVM(vm VM) error {
 
 	b := bytes.NewBufferString(xml.Header + string(output))
 
-	req := v.c.NewRequest(map[string]string{}, "POST", *s, b)
+	req := v.c.NewRequestWithContext(ctx, map[string]string{}, "POST", *s, b)
 
 	req.Header.Add("Content-Type", "application/vnd.vmware.vcloud.recomposeVAppParams+xml")
 
-	resp, err := checkResp(v.c.Http.Do(req))
+	resp, err := v.c.doRequest(req)
 	if err != nil {
-		return fmt.Errorf("error instantiating a new vApp: %s", err)
+		return fmt.Errorf("error instantiating a new vApp: %w", err)
 	}
 
 	task = NewTask(v.c)
 
 	if err = decodeBody(resp, task.Task); err != nil {
-		return fmt.Errorf("error decoding task response: %s", err)
+		return fmt.Errorf("error decoding task response: %w", err)
 	}
 
-	err = task.WaitTaskCompletion()
+	err = task.WaitTaskCompletionWithContext(ctx)
 	if err != nil {
-		return fmt.Errorf("Error performing task: %#v", err)
+		return fmt.Errorf("Error performing task: %w", err)
 	}
 
 	return nil
 }
 
 func (v *VApp) PowerOn() (Task, error) {
+	return v.PowerOnWithContext(context.Background())
+}
+
+// PowerOnWithContext powers on the vApp. The request is
+// aborted if ctx is cancelled before vCD accepts it.
+func (v *VApp) PowerOnWithContext(ctx context.Context) (Task, error) {
 
 	s, _ := url.ParseRequestURI(v.VApp.HREF)
 	s.Path += "/power/action/powerOn"
 
-	req := v.c.NewRequest(map[string]string{}, "POST", *s, nil)
+	req := v.c.NewRequestWithContext(ctx, map[string]string{}, "POST", *s, nil)
 
-	resp, err := checkResp(v.c.Http.Do(req))
+	resp, err := v.c.doRequest(req)
 	if err != nil {
-		return Task{}, fmt.Errorf("error powering on vApp: %s", err)
+		return Task{}, fmt.Errorf("error powering on vApp: %w", err)
 	}
 
 	task := NewTask(v.c)
 
 	if err = decodeBody(resp, task.Task); err != nil {
-		return Task{}, fmt.Errorf("error decoding Task response: %s", err)
+		return Task{}, fmt.Errorf("error decoding Task response: %w", err)
 	}
 
 	// The request was successful
@@ -228,21 +269,27 @@ func (v *VApp) PowerOn() (Task, error) {
 }
 
 func (v *VApp) PowerOff() (Task, error) {
+	return v.PowerOffWithContext(context.Background())
+}
+
+// PowerOffWithContext powers off the vApp. The request is
+// aborted if ctx is cancelled before vCD accepts it.
+func (v *VApp) PowerOffWithContext(ctx context.Context) (Task, error) {
 
 	s, _ := url.ParseRequestURI(v.VApp.HREF)
 	s.Path += "/power/action/powerOff"
 
-	req := v.c.NewRequest(map[string]string{}, "POST", *s, nil)
+	req := v.c.NewRequestWithContext(ctx, map[string]string{}, "POST", *s, nil)
 
-	resp, err := checkResp(v.c.Http.Do(req))
+	resp, err := v.c.doRequest(req)
 	if err != nil {
-		return Task{}, fmt.Errorf("error powering off vApp: %s", err)
+		return Task{}, fmt.Errorf("error powering off vApp: %w", err)
 	}
 
 	task := NewTask(v.c)
 
 	if err = decodeBody(resp, task.Task); err != nil {
-		return Task{}, fmt.Errorf("error decoding Task response: %s", err)
+		return Task{}, fmt.Errorf("error decoding Task response: %w", err)
 	}
 
 	// The request was successful
@@ -251,21 +298,27 @@ func (v *VApp) PowerOff() (Task, error) {
 }
 
 func (v *VApp) Reboot() (Task, error) {
+	return v.RebootWithContext(context.Background())
+}
+
+// RebootWithContext reboots the vApp. The request is aborted if
+// ctx is cancelled before vCD accepts it.
+func (v *VApp) RebootWithContext(ctx context.Context) (Task, error) {
 
 	s, _ := url.ParseRequestURI(v.VApp.HREF)
 	s.Path += "/power/action/reboot"
 
-	req := v.c.NewRequest(map[string]string{}, "POST", *s, nil)
+	req := v.c.NewRequestWithContext(ctx, map[string]string{}, "POST", *s, nil)
 
-	resp, err := checkResp(v.c.Http.Do(req))
+	resp, err := v.c.doRequest(req)
 	if err != nil {
-		return Task{}, fmt.Errorf("error rebooting vApp: %s", err)
+		return Task{}, fmt.Errorf("error rebooting vApp: %w", err)
 	}
 
 	task := NewTask(v.c)
 
 	if err = decodeBody(resp, task.Task); err != nil {
-		return Task{}, fmt.Errorf("error decoding Task response: %s", err)
+		return Task{}, fmt.Errorf("error decoding Task response: %w", err)
 	}
 
 	// The request was successful
@@ -274,21 +327,27 @@ func (v *VApp) Reboot() (Task, error) {
 }
 
 func (v *VApp) Reset() (Task, error) {
+	return v.ResetWithContext(context.Background())
+}
+
+// ResetWithContext resets the vApp. The request is aborted if
+// ctx is cancelled before vCD accepts it.
+func (v *VApp) ResetWithContext(ctx context.Context) (Task, error) {
 
 	s, _ := url.ParseRequestURI(v.VApp.HREF)
 	s.Path += "/power/action/reset"
 
-	req := v.c.NewRequest(map[string]string{}, "POST", *s, nil)
+	req := v.c.NewRequestWithContext(ctx, map[string]string{}, "POST", *s, nil)
 
-	resp, err := checkResp(v.c.Http.Do(req))
+	resp, err := v.c.doRequest(req)
 	if err != nil {
-		return Task{}, fmt.Errorf("error resetting vApp: %s", err)
+		return Task{}, fmt.Errorf("error resetting vApp: %w", err)
 	}
 
 	task := NewTask(v.c)
 
 	if err = decodeBody(resp, task.Task); err != nil {
-		return Task{}, fmt.Errorf("error decoding Task response: %s", err)
+		return Task{}, fmt.Errorf("error decoding Task response: %w", err)
 	}
 
 	// The request was successful
@@ -297,21 +356,27 @@ func (v *VApp) Reset() (Task, error) {
 }
 
 func (v *VApp) Suspend() (Task, error) {
+	return v.SuspendWithContext(context.Background())
+}
+
+// SuspendWithContext suspends the vApp. The request is aborted if
+// ctx is cancelled before vCD accepts it.
+func (v *VApp) SuspendWithContext(ctx context.Context) (Task, error) {
 
 	s, _ := url.ParseRequestURI(v.VApp.HREF)
 	s.Path += "/power/action/suspend"
 
-	req := v.c.NewRequest(map[string]string{}, "POST", *s, nil)
+	req := v.c.NewRequestWithContext(ctx, map[string]string{}, "POST", *s, nil)
 
-	resp, err := checkResp(v.c.Http.Do(req))
+	resp, err := v.c.doRequest(req)
 	if err != nil {
-		return Task{}, fmt.Errorf("error suspending vApp: %s", err)
+		return Task{}, fmt.Errorf("error suspending vApp: %w", err)
 	}
 
 	task := NewTask(v.c)
 
 	if err = decodeBody(resp, task.Task); err != nil {
-		return Task{}, fmt.Errorf("error decoding Task response: %s", err)
+		return Task{}, fmt.Errorf("error decoding Task response: %w", err)
 	}
 
 	// The request was successful
@@ -320,21 +385,27 @@ func (v *VApp) Suspend() (Task, error) {
 }
 
 func (v *VApp) Shutdown() (Task, error) {
+	return v.ShutdownWithContext(context.Background())
+}
+
+// ShutdownWithContext shuts down the vApp guest OS. The request is
+// aborted if ctx is cancelled before vCD accepts it.
+func (v *VApp) ShutdownWithContext(ctx context.Context) (Task, error) {
 
 	s, _ := url.ParseRequestURI(v.VApp.HREF)
 	s.Path += "/power/action/shutdown"
 
-	req := v.c.NewRequest(map[string]string{}, "POST", *s, nil)
+	req := v.c.NewRequestWithContext(ctx, map[string]string{}, "POST", *s, nil)
 
-	resp, err := checkResp(v.c.Http.Do(req))
+	resp, err := v.c.doRequest(req)
 	if err != nil {
-		return Task{}, fmt.Errorf("error shutting down vApp: %s", err)
+		return Task{}, fmt.Errorf("error shutting down vApp: %w", err)
 	}
 
 	task := NewTask(v.c)
 
 	if err = decodeBody(resp, task.Task); err != nil {
-		return Task{}, fmt.Errorf("error decoding Task response: %s", err)
+		return Task{}, fmt.Errorf("error decoding Task response: %w", err)
 	}
 
 	// The request was successful
@@ -343,6 +414,12 @@ func (v *VApp) Shutdown() (Task, error) {
 }
 
 func (v *VApp) Undeploy() (Task, error) {
+	return v.UndeployWithContext(context.Background())
+}
+
+// UndeployWithContext undeploys the vApp, powering it off. The
+// request is aborted if ctx is cancelled before vCD accepts it.
+func (v *VApp) UndeployWithContext(ctx context.Context) (Task, error) {
 
 	vu := &types.UndeployVAppParams{
 		Xmlns:               "http://www.vmware.com/vcloud/v1.5",
@@ -351,13 +428,7 @@ func (v *VApp) Undeploy() (Task, error) {
 
 	output, err := xml.MarshalIndent(vu, "  ", "    ")
 	if err != nil {
-		fmt.Printf("error: %v\n", err)
-	}
-
-	debug := os.Getenv("GOVCLOUDAIR_DEBUG")
-
-	if debug == "true" {
-		fmt.Printf("\n\nXML DEBUG: %s\n\n", string(output))
+		v.c.log(LogError, "error marshaling request body", "error", err)
 	}
 
 	b := bytes.NewBufferString(xml.Header + string(output))
@@ -365,19 +436,19 @@ func (v *VApp) Undeploy() (Task, error) {
 	s, _ := url.ParseRequestURI(v.VApp.HREF)
 	s.Path += "/action/undeploy"
 
-	req := v.c.NewRequest(map[string]string{}, "POST", *s, b)
+	req := v.c.NewRequestWithContext(ctx, map[string]string{}, "POST", *s, b)
 
 	req.Header.Add("Content-Type", "application/vnd.vmware.vcloud.undeployVAppParams+xml")
 
-	resp, err := checkResp(v.c.Http.Do(req))
+	resp, err := v.c.doRequest(req)
 	if err != nil {
-		return Task{}, fmt.Errorf("error undeploy vApp: %s", err)
+		return Task{}, fmt.Errorf("error undeploy vApp: %w", err)
 	}
 
 	task := NewTask(v.c)
 
 	if err = decodeBody(resp, task.Task); err != nil {
-		return Task{}, fmt.Errorf("error decoding Task response: %s", err)
+		return Task{}, fmt.Errorf("error decoding Task response: %w", err)
 	}
 
 	// The request was successful
@@ -386,6 +457,12 @@ func (v *VApp) Undeploy() (Task, error) {
 }
 
 func (v *VApp) Deploy() (Task, error) {
+	return v.DeployWithContext(context.Background())
+}
+
+// DeployWithContext deploys the vApp. The request is aborted if
+// ctx is cancelled before vCD accepts it.
+func (v *VApp) DeployWithContext(ctx context.Context) (Task, error) {
 
 	vu := &types.DeployVAppParams{
 		Xmlns:   "http://www.vmware.com/vcloud/v1.5",
@@ -394,13 +471,7 @@ func (v *VApp) Deploy() (Task, error) {
 
 	output, err := xml.MarshalIndent(vu, "  ", "    ")
 	if err != nil {
-		fmt.Printf("error: %v\n", err)
-	}
-
-	debug := os.Getenv("GOVCLOUDAIR_DEBUG")
-
-	if debug == "true" {
-		fmt.Printf("\n\nXML DEBUG: %s\n\n", string(output))
+		v.c.log(LogError, "error marshaling request body", "error", err)
 	}
 
 	b := bytes.NewBufferString(xml.Header + string(output))
@@ -408,19 +479,19 @@ func (v *VApp) Deploy() (Task, error) {
 	s, _ := url.ParseRequestURI(v.VApp.HREF)
 	s.Path += "/action/deploy"
 
-	req := v.c.NewRequest(map[string]string{}, "POST", *s, b)
+	req := v.c.NewRequestWithContext(ctx, map[string]string{}, "POST", *s, b)
 
 	req.Header.Add("Content-Type", "application/vnd.vmware.vcloud.deployVAppParams+xml")
 
-	resp, err := checkResp(v.c.Http.Do(req))
+	resp, err := v.c.doRequest(req)
 	if err != nil {
-		return Task{}, fmt.Errorf("error undeploy vApp: %s", err)
+		return Task{}, fmt.Errorf("error undeploy vApp: %w", err)
 	}
 
 	task := NewTask(v.c)
 
 	if err = decodeBody(resp, task.Task); err != nil {
-		return Task{}, fmt.Errorf("error decoding Task response: %s", err)
+		return Task{}, fmt.Errorf("error decoding Task response: %w", err)
 	}
 
 	// The request was successful
@@ -429,20 +500,26 @@ func (v *VApp) Deploy() (Task, error) {
 }
 
 func (v *VApp) Delete() (Task, error) {
+	return v.DeleteWithContext(context.Background())
+}
+
+// DeleteWithContext deletes the vApp. The request is aborted if
+// ctx is cancelled before vCD accepts it.
+func (v *VApp) DeleteWithContext(ctx context.Context) (Task, error) {
 
 	s, _ := url.ParseRequestURI(v.VApp.HREF)
 
-	req := v.c.NewRequest(map[string]string{}, "DELETE", *s, nil)
+	req := v.c.NewRequestWithContext(ctx, map[string]string{}, "DELETE", *s, nil)
 
-	resp, err := checkResp(v.c.Http.Do(req))
+	resp, err := v.c.doRequest(req)
 	if err != nil {
-		return Task{}, fmt.Errorf("error deleting vApp: %s", err)
+		return Task{}, fmt.Errorf("error deleting vApp: %w", err)
 	}
 
 	task := NewTask(v.c)
 
 	if err = decodeBody(resp, task.Task); err != nil {
-		return Task{}, fmt.Errorf("error decoding Task response: %s", err)
+		return Task{}, fmt.Errorf("error decoding Task response: %w", err)
 	}
 
 	// The request was successful
@@ -451,13 +528,25 @@ func (v *VApp) Delete() (Task, error) {
 }
 
 func (v *VApp) RunCustomizationScript(computername, script string) (Task, error) {
-	return v.Customize(computername, script, false)
+	return v.RunCustomizationScriptWithContext(context.Background(), computername, script)
+}
+
+// RunCustomizationScriptWithContext behaves like RunCustomizationScript.
+// The request is aborted if ctx is cancelled before vCD accepts it.
+func (v *VApp) RunCustomizationScriptWithContext(ctx context.Context, computername, script string) (Task, error) {
+	return v.CustomizeWithContext(ctx, computername, script, false)
 }
 
 func (v *VApp) Customize(computername, script string, changeSid bool) (Task, error) {
-	err := v.Refresh()
+	return v.CustomizeWithContext(context.Background(), computername, script, changeSid)
+}
+
+// CustomizeWithContext behaves like Customize. The request is aborted if
+// ctx is cancelled before vCD accepts it.
+func (v *VApp) CustomizeWithContext(ctx context.Context, computername, script string, changeSid bool) (Task, error) {
+	err := v.RefreshWithContext(ctx)
 	if err != nil {
-		return Task{}, fmt.Errorf("error refreshing vapp before running customization: %v", err)
+		return Task{}, fmt.Errorf("error refreshing vapp before running customization: %w", err)
 	}
 
 	// Check if VApp Children is populated
@@ -481,35 +570,28 @@ func (v *VApp) Customize(computername, script string, changeSid bool) (Task, err
 
 	output, err := xml.MarshalIndent(vu, "  ", "    ")
 	if err != nil {
-		fmt.Printf("error: %v\n", err)
+		v.c.log(LogError, "error marshaling request body", "error", err)
 	}
 
-	log.Printf("[DEBUG] VCD Client configuration: %s", output)
-
-	debug := os.Getenv("GOVCLOUDAIR_DEBUG")
-
-	if debug == "true" {
-		fmt.Printf("\n\nXML DEBUG: %s\n\n", string(output))
-	}
 
 	b := bytes.NewBufferString(xml.Header + string(output))
 
 	s, _ := url.ParseRequestURI(v.VApp.Children.VM[0].HREF)
 	s.Path += "/guestCustomizationSection/"
 
-	req := v.c.NewRequest(map[string]string{}, "PUT", *s, b)
+	req := v.c.NewRequestWithContext(ctx, map[string]string{}, "PUT", *s, b)
 
 	req.Header.Add("Content-Type", "application/vnd.vmware.vcloud.guestCustomizationSection+xml")
 
-	resp, err := checkResp(v.c.Http.Do(req))
+	resp, err := v.c.doRequest(req)
 	if err != nil {
-		return Task{}, fmt.Errorf("error customizing VM: %s", err)
+		return Task{}, fmt.Errorf("error customizing VM: %w", err)
 	}
 
 	task := NewTask(v.c)
 
 	if err = decodeBody(resp, task.Task); err != nil {
-		return Task{}, fmt.Errorf("error decoding Task response: %s", err)
+		return Task{}, fmt.Errorf("error decoding Task response: %w", err)
 	}
 
 	// The request was successful
@@ -517,14 +599,27 @@ func (v *VApp) Customize(computername, script string, changeSid bool) (Task, err
 }
 
 func (v *VApp) GetStatus() (string, error) {
-	err := v.Refresh()
+	return v.GetStatusWithContext(context.Background())
+}
+
+// GetStatusWithContext behaves like GetStatus, aborting if ctx is cancelled
+// before vCD answers.
+func (v *VApp) GetStatusWithContext(ctx context.Context) (string, error) {
+	err := v.RefreshWithContext(ctx)
 	if err != nil {
-		return "", fmt.Errorf("error refreshing vapp: %v", err)
+		return "", fmt.Errorf("error refreshing vapp: %w", err)
 	}
 	return types.VAppStatuses[v.VApp.Status], nil
 }
 
 func (v *VApp) GetNetworkConnectionSection() (*types.NetworkConnectionSection, error) {
+	return v.GetNetworkConnectionSectionWithContext(context.Background())
+}
+
+// GetNetworkConnectionSectionWithContext behaves like
+// GetNetworkConnectionSection, aborting if ctx is cancelled before vCD
+// answers.
+func (v *VApp) GetNetworkConnectionSectionWithContext(ctx context.Context) (*types.NetworkConnectionSection, error) {
 
 	networkConnectionSection := &types.NetworkConnectionSection{}
 
@@ -534,17 +629,17 @@ func (v *VApp) GetNetworkConnectionSection() (*types.NetworkConnectionSection, e
 
 	u, _ := url.ParseRequestURI(v.VApp.Children.VM[0].HREF + "/networkConnectionSection/")
 
-	req := v.c.NewRequest(map[string]string{}, "GET", *u, nil)
+	req := v.c.NewRequestWithContext(ctx, map[string]string{}, "GET", *u, nil)
 
 	req.Header.Add("Content-Type", "application/vnd.vmware.vcloud.networkConnectionSection+xml")
 
-	resp, err := checkResp(v.c.Http.Do(req))
+	resp, err := v.c.doRequest(req)
 	if err != nil {
-		return networkConnectionSection, fmt.Errorf("error retrieving task: %s", err)
+		return networkConnectionSection, fmt.Errorf("error retrieving task: %w", err)
 	}
 
 	if err = decodeBody(resp, networkConnectionSection); err != nil {
-		return networkConnectionSection, fmt.Errorf("error decoding task response: %s", err)
+		return networkConnectionSection, fmt.Errorf("error decoding task response: %w", err)
 	}
 
 	// The request was successful
@@ -552,10 +647,16 @@ func (v *VApp) GetNetworkConnectionSection() (*types.NetworkConnectionSection, e
 }
 
 func (v *VApp) ChangeCPUcount(size int) (Task, error) {
+	return v.ChangeCPUcountWithContext(context.Background(), size)
+}
+
+// ChangeCPUcountWithContext behaves like ChangeCPUcount. The request is
+// aborted if ctx is cancelled before vCD accepts it.
+func (v *VApp) ChangeCPUcountWithContext(ctx context.Context, size int) (Task, error) {
 
-	err := v.Refresh()
+	err := v.RefreshWithContext(ctx)
 	if err != nil {
-		return Task{}, fmt.Errorf("error refreshing vapp before running customization: %v", err)
+		return Task{}, fmt.Errorf("error refreshing vapp before running customization: %w", err)
 	}
 
 	// Check if VApp Children is populated
@@ -586,13 +687,7 @@ func (v *VApp) ChangeCPUcount(size int) (Task, error) {
 
 	output, err := xml.MarshalIndent(newcpu, "  ", "    ")
 	if err != nil {
-		fmt.Printf("error: %v\n", err)
-	}
-
-	debug := os.Getenv("GOVCLOUDAIR_DEBUG")
-
-	if debug == "true" {
-		fmt.Printf("\n\nXML DEBUG: %s\n\n", string(output))
+		v.c.log(LogError, "error marshaling request body", "error", err)
 	}
 
 	b := bytes.NewBufferString(xml.Header + string(output))
@@ -600,19 +695,19 @@ func (v *VApp) ChangeCPUcount(size int) (Task, error) {
 	s, _ := url.ParseRequestURI(v.VApp.Children.VM[0].HREF)
 	s.Path += "/virtualHardwareSection/cpu"
 
-	req := v.c.NewRequest(map[string]string{}, "PUT", *s, b)
+	req := v.c.NewRequestWithContext(ctx, map[string]string{}, "PUT", *s, b)
 
 	req.Header.Add("Content-Type", "application/vnd.vmware.vcloud.rasdItem+xml")
 
-	resp, err := checkResp(v.c.Http.Do(req))
+	resp, err := v.c.doRequest(req)
 	if err != nil {
-		return Task{}, fmt.Errorf("error customizing VM: %s", err)
+		return Task{}, fmt.Errorf("error customizing VM: %w", err)
 	}
 
 	task := NewTask(v.c)
 
 	if err = decodeBody(resp, task.Task); err != nil {
-		return Task{}, fmt.Errorf("error decoding Task response: %s", err)
+		return Task{}, fmt.Errorf("error decoding Task response: %w", err)
 	}
 
 	// The request was successful
@@ -621,16 +716,22 @@ func (v *VApp) ChangeCPUcount(size int) (Task, error) {
 }
 
 func (v *VApp) ChangeStorageProfile(name string) (Task, error) {
-	err := v.Refresh()
+	return v.ChangeStorageProfileWithContext(context.Background(), name)
+}
+
+// ChangeStorageProfileWithContext behaves like ChangeStorageProfile. The
+// request is aborted if ctx is cancelled before vCD accepts it.
+func (v *VApp) ChangeStorageProfileWithContext(ctx context.Context, name string) (Task, error) {
+	err := v.RefreshWithContext(ctx)
 	if err != nil {
-		return Task{}, fmt.Errorf("error refreshing vapp before running customization: %v", err)
+		return Task{}, fmt.Errorf("error refreshing vapp before running customization: %w", err)
 	}
 
 	if v.VApp.Children == nil {
 		return Task{}, fmt.Errorf("vApp doesn't contain any children, aborting customization")
 	}
 
-	vdc, err := v.getParentVDC()
+	vdc, err := v.getParentVDC(ctx)
 	storageprofileref, err := vdc.FindStorageProfileReference(name)
 
 	newprofile := &types.VM{
@@ -641,28 +742,27 @@ func (v *VApp) ChangeStorageProfile(name string) (Task, error) {
 
 	output, err := xml.MarshalIndent(newprofile, "  ", "    ")
 	if err != nil {
-		fmt.Printf("error: %v\n", err)
+		v.c.log(LogError, "error marshaling request body", "error", err)
 	}
 
-	log.Printf("[DEBUG] VCD Client configuration: %s", output)
 
 	b := bytes.NewBufferString(xml.Header + string(output))
 
 	s, _ := url.ParseRequestURI(v.VApp.Children.VM[0].HREF)
 
-	req := v.c.NewRequest(map[string]string{}, "PUT", *s, b)
+	req := v.c.NewRequestWithContext(ctx, map[string]string{}, "PUT", *s, b)
 
 	req.Header.Add("Content-Type", "application/vnd.vmware.vcloud.vm+xml")
 
-	resp, err := checkResp(v.c.Http.Do(req))
+	resp, err := v.c.doRequest(req)
 	if err != nil {
-		return Task{}, fmt.Errorf("error customizing VM: %s", err)
+		return Task{}, fmt.Errorf("error customizing VM: %w", err)
 	}
 
 	task := NewTask(v.c)
 
 	if err = decodeBody(resp, task.Task); err != nil {
-		return Task{}, fmt.Errorf("error decoding Task response: %s", err)
+		return Task{}, fmt.Errorf("error decoding Task response: %w", err)
 	}
 
 	// The request was successful
@@ -671,9 +771,15 @@ func (v *VApp) ChangeStorageProfile(name string) (Task, error) {
 }
 
 func (v *VApp) ChangeVMName(name string) (Task, error) {
-	err := v.Refresh()
+	return v.ChangeVMNameWithContext(context.Background(), name)
+}
+
+// ChangeVMNameWithContext behaves like ChangeVMName. The request is aborted
+// if ctx is cancelled before vCD accepts it.
+func (v *VApp) ChangeVMNameWithContext(ctx context.Context, name string) (Task, error) {
+	err := v.RefreshWithContext(ctx)
 	if err != nil {
-		return Task{}, fmt.Errorf("error refreshing vapp before running customization: %v", err)
+		return Task{}, fmt.Errorf("error refreshing vapp before running customization: %w", err)
 	}
 
 	if v.VApp.Children == nil {
@@ -687,28 +793,27 @@ func (v *VApp) ChangeVMName(name string) (Task, error) {
 
 	output, err := xml.MarshalIndent(newname, "  ", "    ")
 	if err != nil {
-		fmt.Printf("error: %v\n", err)
+		v.c.log(LogError, "error marshaling request body", "error", err)
 	}
 
-	log.Printf("[DEBUG] VCD Client configuration: %s", output)
 
 	b := bytes.NewBufferString(xml.Header + string(output))
 
 	s, _ := url.ParseRequestURI(v.VApp.Children.VM[0].HREF)
 
-	req := v.c.NewRequest(map[string]string{}, "PUT", *s, b)
+	req := v.c.NewRequestWithContext(ctx, map[string]string{}, "PUT", *s, b)
 
 	req.Header.Add("Content-Type", "application/vnd.vmware.vcloud.vm+xml")
 
-	resp, err := checkResp(v.c.Http.Do(req))
+	resp, err := v.c.doRequest(req)
 	if err != nil {
-		return Task{}, fmt.Errorf("error customizing VM: %s", err)
+		return Task{}, fmt.Errorf("error customizing VM: %w", err)
 	}
 
 	task := NewTask(v.c)
 
 	if err = decodeBody(resp, task.Task); err != nil {
-		return Task{}, fmt.Errorf("error decoding Task response: %s", err)
+		return Task{}, fmt.Errorf("error decoding Task response: %w", err)
 	}
 
 	// The request was successful
@@ -717,9 +822,15 @@ func (v *VApp) ChangeVMName(name string) (Task, error) {
 }
 
 func (v *VApp) DeleteMetadata(key string) (Task, error) {
-	err := v.Refresh()
+	return v.DeleteMetadataWithContext(context.Background(), key)
+}
+
+// DeleteMetadataWithContext behaves like DeleteMetadata. The request is
+// aborted if ctx is cancelled before vCD accepts it.
+func (v *VApp) DeleteMetadataWithContext(ctx context.Context, key string) (Task, error) {
+	err := v.RefreshWithContext(ctx)
 	if err != nil {
-		return Task{}, fmt.Errorf("error refreshing vapp before running customization: %v", err)
+		return Task{}, fmt.Errorf("error refreshing vapp before running customization: %w", err)
 	}
 
 	if v.VApp.Children == nil {
@@ -729,17 +840,17 @@ func (v *VApp) DeleteMetadata(key string) (Task, error) {
 	s, _ := url.ParseRequestURI(v.VApp.Children.VM[0].HREF)
 	s.Path += "/metadata/" + key
 
-	req := v.c.NewRequest(map[string]string{}, "DELETE", *s, nil)
+	req := v.c.NewRequestWithContext(ctx, map[string]string{}, "DELETE", *s, nil)
 
-	resp, err := checkResp(v.c.Http.Do(req))
+	resp, err := v.c.doRequest(req)
 	if err != nil {
-		return Task{}, fmt.Errorf("error deleting Metadata: %s", err)
+		return Task{}, fmt.Errorf("error deleting Metadata: %w", err)
 	}
 
 	task := NewTask(v.c)
 
 	if err = decodeBody(resp, task.Task); err != nil {
-		return Task{}, fmt.Errorf("error decoding Task response: %s", err)
+		return Task{}, fmt.Errorf("error decoding Task response: %w", err)
 	}
 
 	// The request was successful
@@ -747,9 +858,15 @@ func (v *VApp) DeleteMetadata(key string) (Task, error) {
 }
 
 func (v *VApp) AddMetadata(key, value string) (Task, error) {
-	err := v.Refresh()
+	return v.AddMetadataWithContext(context.Background(), key, value)
+}
+
+// AddMetadataWithContext behaves like AddMetadata. The request is aborted
+// if ctx is cancelled before vCD accepts it.
+func (v *VApp) AddMetadataWithContext(ctx context.Context, key, value string) (Task, error) {
+	err := v.RefreshWithContext(ctx)
 	if err != nil {
-		return Task{}, fmt.Errorf("error refreshing vapp before running customization: %v", err)
+		return Task{}, fmt.Errorf("error refreshing vapp before running customization: %w", err)
 	}
 
 	if v.VApp.Children == nil {
@@ -767,29 +884,28 @@ func (v *VApp) AddMetadata(key, value string) (Task, error) {
 
 	output, err := xml.MarshalIndent(newmetadata, "  ", "    ")
 	if err != nil {
-		fmt.Printf("error: %v\n", err)
+		v.c.log(LogError, "error marshaling request body", "error", err)
 	}
 
-	log.Printf("[DEBUG] NetworkXML: %s", output)
 
 	b := bytes.NewBufferString(xml.Header + string(output))
 
 	s, _ := url.ParseRequestURI(v.VApp.Children.VM[0].HREF)
 	s.Path += "/metadata/" + key
 
-	req := v.c.NewRequest(map[string]string{}, "PUT", *s, b)
+	req := v.c.NewRequestWithContext(ctx, map[string]string{}, "PUT", *s, b)
 
 	req.Header.Add("Content-Type", "application/vnd.vmware.vcloud.metadata.value+xml")
 
-	resp, err := checkResp(v.c.Http.Do(req))
+	resp, err := v.c.doRequest(req)
 	if err != nil {
-		return Task{}, fmt.Errorf("error customizing VM Network: %s", err)
+		return Task{}, fmt.Errorf("error customizing VM Network: %w", err)
 	}
 
 	task := NewTask(v.c)
 
 	if err = decodeBody(resp, task.Task); err != nil {
-		return Task{}, fmt.Errorf("error decoding Task response: %s", err)
+		return Task{}, fmt.Errorf("error decoding Task response: %w", err)
 	}
 
 	// The request was successful
@@ -798,9 +914,15 @@ func (v *VApp) AddMetadata(key, value string) (Task, error) {
 }
 
 func (v *VApp) SetOvf(parameters map[string]string) (Task, error) {
-	err := v.Refresh()
+	return v.SetOvfWithContext(context.Background(), parameters)
+}
+
+// SetOvfWithContext behaves like SetOvf. The request is aborted if ctx is
+// cancelled before vCD accepts it.
+func (v *VApp) SetOvfWithContext(ctx context.Context, parameters map[string]string) (Task, error) {
+	err := v.RefreshWithContext(ctx)
 	if err != nil {
-		return Task{}, fmt.Errorf("error refreshing vapp before running customization: %v", err)
+		return Task{}, fmt.Errorf("error refreshing vapp before running customization: %w", err)
 	}
 
 	if v.VApp.Children == nil {
@@ -828,29 +950,28 @@ func (v *VApp) SetOvf(parameters map[string]string) (Task, error) {
 
 	output, err := xml.MarshalIndent(newmetadata, "  ", "    ")
 	if err != nil {
-		fmt.Printf("error: %v\n", err)
+		v.c.log(LogError, "error marshaling request body", "error", err)
 	}
 
-	log.Printf("[DEBUG] NetworkXML: %s", output)
 
 	b := bytes.NewBufferString(xml.Header + string(output))
 
 	s, _ := url.ParseRequestURI(v.VApp.Children.VM[0].HREF)
 	s.Path += "/productSections"
 
-	req := v.c.NewRequest(map[string]string{}, "PUT", *s, b)
+	req := v.c.NewRequestWithContext(ctx, map[string]string{}, "PUT", *s, b)
 
 	req.Header.Add("Content-Type", "application/vnd.vmware.vcloud.productSections+xml")
 
-	resp, err := checkResp(v.c.Http.Do(req))
+	resp, err := v.c.doRequest(req)
 	if err != nil {
-		return Task{}, fmt.Errorf("error customizing VM Network: %s", err)
+		return Task{}, fmt.Errorf("error customizing VM Network: %w", err)
 	}
 
 	task := NewTask(v.c)
 
 	if err = decodeBody(resp, task.Task); err != nil {
-		return Task{}, fmt.Errorf("error decoding Task response: %s", err)
+		return Task{}, fmt.Errorf("error decoding Task response: %w", err)
 	}
 
 	// The request was successful
@@ -859,16 +980,22 @@ func (v *VApp) SetOvf(parameters map[string]string) (Task, error) {
 }
 
 func (v *VApp) ChangeNetworkConfig(networks []map[string]interface{}, ip string) (Task, error) {
-	err := v.Refresh()
+	return v.ChangeNetworkConfigWithContext(context.Background(), networks, ip)
+}
+
+// ChangeNetworkConfigWithContext behaves like ChangeNetworkConfig. The
+// request is aborted if ctx is cancelled before vCD accepts it.
+func (v *VApp) ChangeNetworkConfigWithContext(ctx context.Context, networks []map[string]interface{}, ip string) (Task, error) {
+	err := v.RefreshWithContext(ctx)
 	if err != nil {
-		return Task{}, fmt.Errorf("error refreshing VM before running customization: %v", err)
+		return Task{}, fmt.Errorf("error refreshing VM before running customization: %w", err)
 	}
 
 	if v.VApp.Children == nil {
 		return Task{}, fmt.Errorf("vApp doesn't contain any children, aborting customization")
 	}
 
-	networksection, err := v.GetNetworkConnectionSection()
+	networksection, err := v.GetNetworkConnectionSectionWithContext(ctx)
 
 	for index, network := range networks {
 		// Determine what type of address is requested for the vApp
@@ -888,7 +1015,7 @@ func (v *VApp) ChangeNetworkConfig(networks []map[string]interface{}, ip string)
 			ipAddress = ip
 		}
 
-		log.Printf("[DEBUG] Function ChangeNetworkConfig() for %s invoked", network["orgnetwork"])
+		v.c.log(LogDebug, "changing network configuration", "vapp", v.VApp.Name, "network", network["orgnetwork"])
 
 		networksection.Xmlns = "http://www.vmware.com/vcloud/v1.5"
 		networksection.Ovf = "http://schemas.dmtf.org/ovf/envelope/1"
@@ -907,29 +1034,28 @@ func (v *VApp) ChangeNetworkConfig(networks []map[string]interface{}, ip string)
 
 	output, err := xml.MarshalIndent(networksection, "  ", "    ")
 	if err != nil {
-		fmt.Printf("error: %v\n", err)
+		v.c.log(LogError, "error marshaling request body", "error", err)
 	}
 
-	log.Printf("[DEBUG] NetworkXML: %s", output)
 
 	b := bytes.NewBufferString(xml.Header + string(output))
 
 	s, _ := url.ParseRequestURI(v.VApp.Children.VM[0].HREF)
 	s.Path += "/networkConnectionSection/"
 
-	req := v.c.NewRequest(map[string]string{}, "PUT", *s, b)
+	req := v.c.NewRequestWithContext(ctx, map[string]string{}, "PUT", *s, b)
 
 	req.Header.Add("Content-Type", "application/vnd.vmware.vcloud.networkConnectionSection+xml")
 
-	resp, err := checkResp(v.c.Http.Do(req))
+	resp, err := v.c.doRequest(req)
 	if err != nil {
-		return Task{}, fmt.Errorf("error customizing VM Network: %s", err)
+		return Task{}, fmt.Errorf("error customizing VM Network: %w", err)
 	}
 
 	task := NewTask(v.c)
 
 	if err = decodeBody(resp, task.Task); err != nil {
-		return Task{}, fmt.Errorf("error decoding Task response: %s", err)
+		return Task{}, fmt.Errorf("error decoding Task response: %w", err)
 	}
 
 	// The request was successful
@@ -937,10 +1063,16 @@ func (v *VApp) ChangeNetworkConfig(networks []map[string]interface{}, ip string)
 }
 
 func (v *VApp) ChangeMemorySize(size int) (Task, error) {
+	return v.ChangeMemorySizeWithContext(context.Background(), size)
+}
+
+// ChangeMemorySizeWithContext behaves like ChangeMemorySize. The request is
+// aborted if ctx is cancelled before vCD accepts it.
+func (v *VApp) ChangeMemorySizeWithContext(ctx context.Context, size int) (Task, error) {
 
-	err := v.Refresh()
+	err := v.RefreshWithContext(ctx)
 	if err != nil {
-		return Task{}, fmt.Errorf("error refreshing vapp before running customization: %v", err)
+		return Task{}, fmt.Errorf("error refreshing vapp before running customization: %w", err)
 	}
 
 	// Check if VApp Children is populated
@@ -971,13 +1103,7 @@ func (v *VApp) ChangeMemorySize(size int) (Task, error) {
 
 	output, err := xml.MarshalIndent(newmem, "  ", "    ")
 	if err != nil {
-		fmt.Printf("error: %v\n", err)
-	}
-
-	debug := os.Getenv("GOVCLOUDAIR_DEBUG")
-
-	if debug == "true" {
-		fmt.Printf("\n\nXML DEBUG: %s\n\n", string(output))
+		v.c.log(LogError, "error marshaling request body", "error", err)
 	}
 
 	b := bytes.NewBufferString(xml.Header + string(output))
@@ -985,19 +1111,19 @@ func (v *VApp) ChangeMemorySize(size int) (Task, error) {
 	s, _ := url.ParseRequestURI(v.VApp.Children.VM[0].HREF)
 	s.Path += "/virtualHardwareSection/memory"
 
-	req := v.c.NewRequest(map[string]string{}, "PUT", *s, b)
+	req := v.c.NewRequestWithContext(ctx, map[string]string{}, "PUT", *s, b)
 
 	req.Header.Add("Content-Type", "application/vnd.vmware.vcloud.rasdItem+xml")
 
-	resp, err := checkResp(v.c.Http.Do(req))
+	resp, err := v.c.doRequest(req)
 	if err != nil {
-		return Task{}, fmt.Errorf("error customizing VM: %s", err)
+		return Task{}, fmt.Errorf("error customizing VM: %w", err)
 	}
 
 	task := NewTask(v.c)
 
 	if err = decodeBody(resp, task.Task); err != nil {
-		return Task{}, fmt.Errorf("error decoding Task response: %s", err)
+		return Task{}, fmt.Errorf("error decoding Task response: %w", err)
 	}
 
 	// The request was successful
@@ -1006,6 +1132,12 @@ func (v *VApp) ChangeMemorySize(size int) (Task, error) {
 }
 
 func (v *VApp) GetNetworkConfig() (*types.NetworkConfigSection, error) {
+	return v.GetNetworkConfigWithContext(context.Background())
+}
+
+// GetNetworkConfigWithContext behaves like GetNetworkConfig, aborting if
+// ctx is cancelled before vCD answers.
+func (v *VApp) GetNetworkConfigWithContext(ctx context.Context) (*types.NetworkConfigSection, error) {
 
 	networkConfig := &types.NetworkConfigSection{}
 
@@ -1015,17 +1147,17 @@ func (v *VApp) GetNetworkConfig() (*types.NetworkConfigSection, error) {
 
 	u, _ := url.ParseRequestURI(v.VApp.HREF + "/networkConfigSection/")
 
-	req := v.c.NewRequest(map[string]string{}, "GET", *u, nil)
+	req := v.c.NewRequestWithContext(ctx, map[string]string{}, "GET", *u, nil)
 
 	req.Header.Add("Content-Type", "application/vnd.vmware.vcloud.networkConfigSection+xml")
 
-	resp, err := checkResp(v.c.Http.Do(req))
+	resp, err := v.c.doRequest(req)
 	if err != nil {
-		return networkConfig, fmt.Errorf("error retrieving task: %s", err)
+		return networkConfig, fmt.Errorf("error retrieving task: %w", err)
 	}
 
 	if err = decodeBody(resp, networkConfig); err != nil {
-		return networkConfig, fmt.Errorf("error decoding task response: %s", err)
+		return networkConfig, fmt.Errorf("error decoding task response: %w", err)
 	}
 
 	// The request was successful
@@ -1033,6 +1165,12 @@ func (v *VApp) GetNetworkConfig() (*types.NetworkConfigSection, error) {
 }
 
 func (v *VApp) AddRAWNetworkConfig(orgvdcnetworks []*types.OrgVDCNetwork) (Task, error) {
+	return v.AddRAWNetworkConfigWithContext(context.Background(), orgvdcnetworks)
+}
+
+// AddRAWNetworkConfigWithContext behaves like AddRAWNetworkConfig. The
+// request is aborted if ctx is cancelled before vCD accepts it.
+func (v *VApp) AddRAWNetworkConfigWithContext(ctx context.Context, orgvdcnetworks []*types.OrgVDCNetwork) (Task, error) {
 
 	networkConfig := &types.NetworkConfigSection{
 		Info:  "Configuration parameters for logical networks",
@@ -1057,29 +1195,28 @@ func (v *VApp) AddRAWNetworkConfig(orgvdcnetworks []*types.OrgVDCNetwork) (Task,
 
 	output, err := xml.MarshalIndent(networkConfig, "  ", "    ")
 	if err != nil {
-		fmt.Printf("error: %v\n", err)
+		v.c.log(LogError, "error marshaling request body", "error", err)
 	}
 
-	log.Printf("[DEBUG] RAWNETWORK Config NetworkXML: %s", output)
 
 	b := bytes.NewBufferString(xml.Header + string(output))
 
 	s, _ := url.ParseRequestURI(v.VApp.HREF)
 	s.Path += "/networkConfigSection/"
 
-	req := v.c.NewRequest(map[string]string{}, "PUT", *s, b)
+	req := v.c.NewRequestWithContext(ctx, map[string]string{}, "PUT", *s, b)
 
 	req.Header.Add("Content-Type", "application/vnd.vmware.vcloud.networkconfigsection+xml")
 
-	resp, err := checkResp(v.c.Http.Do(req))
+	resp, err := v.c.doRequest(req)
 	if err != nil {
-		return Task{}, fmt.Errorf("error adding vApp Network: %s", err)
+		return Task{}, fmt.Errorf("error adding vApp Network: %w", err)
 	}
 
 	task := NewTask(v.c)
 
 	if err = decodeBody(resp, task.Task); err != nil {
-		return Task{}, fmt.Errorf("error decoding Task response: %s", err)
+		return Task{}, fmt.Errorf("error decoding Task response: %w", err)
 	}
 
 	// The request was successful
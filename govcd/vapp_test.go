@@ -5,10 +5,13 @@
 package govcd
 
 import (
+	"context"
 	"fmt"
 	"github.com/vmware/go-vcloud-director/types/v56"
 
 	. "gopkg.in/check.v1"
+	"testing"
+	"time"
 )
 
 // Tests the helper function getParentVDC with the vapp
@@ -20,7 +23,7 @@ func (vcd *TestVCD) TestGetParentVDC(check *C) {
 	vapp, err := vcd.vdc.FindVAppByName(vcd.vapp.VApp.Name)
 	check.Assert(err, IsNil)
 
-	vdc, err := vapp.getParentVDC()
+	vdc, err := vapp.getParentVDC(context.Background())
 
 	check.Assert(err, IsNil)
 	check.Assert(vdc.Vdc.Name, Equals, vcd.vdc.Vdc.Name)
@@ -84,6 +87,18 @@ func (vcd *TestVCD) Test_PowerOn(check *C) {
 	check.Assert(task.Task.Status, Equals, "success")
 }
 
+// Tests that a power operation bound to an already cancelled context
+// fails without reaching vCD.
+func (vcd *TestVCD) Test_PowerOnWithContextCancelled(check *C) {
+	if vcd.skipVappTests {
+		check.Skip("Skipping test because vapp was not successfully created at setup")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := vcd.vapp.PowerOnWithContext(ctx)
+	check.Assert(err, NotNil)
+}
+
 // TODO: Find out if there is a way to check if the vapp is on without
 // powering it on.
 func (vcd *TestVCD) Test_Reboot(check *C) {
@@ -598,3 +613,37 @@ var vappExample = `
 	  </Children>
 	</VApp>
 	`
+
+func TestVApp_PowerOnFakeVCD(t *testing.T) {
+	server, fakeVdc, client := newFakeVCD(t)
+	defer server.Close()
+	server.TaskPolls = 1
+	fakeVdc.AddVApp("vapp").AddVM("vm")
+	_, vdc := fakeOrgVdc(t, client)
+
+	vapp, err := vdc.FindVAppByName("vapp")
+	if err != nil {
+		t.Fatalf("error finding vapp: %s", err)
+	}
+	task, err := vapp.PowerOn()
+	if err != nil {
+		t.Fatalf("error powering on: %s", err)
+	}
+	if err = task.WaitTaskCompletionWithOptions(context.Background(), TaskWaitOptions{PollInterval: time.Millisecond}); err != nil {
+		t.Fatalf("error waiting for power on: %s", err)
+	}
+	status, err := vapp.GetStatus()
+	if err != nil || status != "POWERED_ON" {
+		t.Fatalf("expected POWERED_ON, got %s, %v", status, err)
+	}
+
+	server.FailNextTask("unable to power off")
+	task, err = vapp.PowerOff()
+	if err != nil {
+		t.Fatalf("error powering off: %s", err)
+	}
+	err = task.WaitTaskCompletionWithOptions(context.Background(), TaskWaitOptions{PollInterval: time.Millisecond})
+	if taskErr, ok := err.(*TaskError); !ok || taskErr.Details.Message != "unable to power off" {
+		t.Fatalf("expected a task error, got %v", err)
+	}
+}
@@ -6,6 +6,7 @@ package govcd
 
 import (
 	"bytes"
+	"context"
 	"encoding/xml"
 	"fmt"
 	types "github.com/vmware/go-vcloud-director/types/v56"
@@ -25,36 +26,42 @@ func NewVAppTemplate(c *Client) *VAppTemplate {
 }
 
 func (v *Vdc) InstantiateVAppTemplate(template *types.InstantiateVAppTemplateParams) error {
+	return v.InstantiateVAppTemplateWithContext(context.Background(), template)
+}
+
+// InstantiateVAppTemplateWithContext behaves like InstantiateVAppTemplate,
+// aborting if ctx is cancelled before the operation completes.
+func (v *Vdc) InstantiateVAppTemplateWithContext(ctx context.Context, template *types.InstantiateVAppTemplateParams) error {
 	output, err := xml.MarshalIndent(template, "", "  ")
 	if err != nil {
-		return fmt.Errorf("Error finding VAppTemplate: %#v", err)
+		return fmt.Errorf("Error finding VAppTemplate: %w", err)
 	}
 	requestData := bytes.NewBufferString(xml.Header + string(output))
 
 	vdcHref, err := url.ParseRequestURI(v.Vdc.HREF)
 	if err != nil {
-		return fmt.Errorf("error getting vdc href: %v", err)
+		return fmt.Errorf("error getting vdc href: %w", err)
 	}
 	vdcHref.Path += "/action/instantiateVAppTemplate"
 
-	req := v.c.NewRequest(map[string]string{}, "POST", *vdcHref, requestData)
+	req := v.c.NewRequestWithContext(ctx, map[string]string{}, "POST", *vdcHref, requestData)
 	req.Header.Add("Content-Type", "application/vnd.vmware.vcloud.instantiateVAppTemplateParams+xml")
 
-	resp, err := checkResp(v.c.Http.Do(req))
+	resp, err := v.c.doRequest(req)
 	if err != nil {
-		return fmt.Errorf("error instantiating a new template: %s", err)
+		return fmt.Errorf("error instantiating a new template: %w", err)
 	}
 
 	vapptemplate := NewVAppTemplate(v.c)
 	if err = decodeBody(resp, vapptemplate.VAppTemplate); err != nil {
-		return fmt.Errorf("error decoding orgvdcnetwork response: %s", err)
+		return fmt.Errorf("error decoding orgvdcnetwork response: %w", err)
 	}
 	task := NewTask(v.c)
 	for _, t := range vapptemplate.VAppTemplate.Tasks.Task {
 		task.Task = t
-		err = task.WaitTaskCompletion()
+		err = task.WaitTaskCompletionWithContext(ctx)
 		if err != nil {
-			return fmt.Errorf("Error performing task: %#v", err)
+			return fmt.Errorf("Error performing task: %w", err)
 		}
 	}
 	return nil
@@ -6,12 +6,11 @@ package govcd
 
 import (
 	"bytes"
+	"context"
 	"encoding/xml"
 	"fmt"
 	types "github.com/vmware/go-vcloud-director/types/v56"
-	"log"
 	"net/url"
-	"os"
 	"strings"
 )
 
@@ -29,23 +28,23 @@ func NewVdc(c *Client) *Vdc {
 }
 
 // Gets a vapp with a specific url vappHREF
-func (vdc *Vdc) getVdcVAppbyHREF(vappHREF *url.URL) (*VApp, error) {
-	req := vdc.c.NewRequest(map[string]string{}, "GET", *vappHREF, nil)
-	resp, err := checkResp(vdc.c.Http.Do(req))
+func (vdc *Vdc) getVdcVAppbyHREF(ctx context.Context, vappHREF *url.URL) (*VApp, error) {
+	req := vdc.c.NewRequestWithContext(ctx, map[string]string{}, "GET", *vappHREF, nil)
+	resp, err := vdc.c.doRequest(req)
 	if err != nil {
-		return &VApp{}, fmt.Errorf("error retreiving VApp: %s", err)
+		return &VApp{}, fmt.Errorf("error retreiving VApp: %w", err)
 	}
 
 	vapp := NewVApp(vdc.c)
 
 	if err = decodeBody(resp, vapp.VApp); err != nil {
-		return &VApp{}, fmt.Errorf("error decoding VApp response: %s", err)
+		return &VApp{}, fmt.Errorf("error decoding VApp response: %w", err)
 	}
 	return vapp, nil
 }
 
 // Undeploys every vapp in the vdc
-func (vdc *Vdc) undeployAllVdcVApps() error {
+func (vdc *Vdc) undeployAllVdcVApps(ctx context.Context) error {
 	for _, resents := range vdc.Vdc.ResourceEntities {
 		for _, resent := range resents.ResourceEntity {
 			if resent.Type == "application/vnd.vmware.vcloud.vApp+xml" {
@@ -53,15 +52,15 @@ func (vdc *Vdc) undeployAllVdcVApps() error {
 				if err != nil {
 					return err
 				}
-				vapp, err := vdc.getVdcVAppbyHREF(vappHREF)
+				vapp, err := vdc.getVdcVAppbyHREF(ctx, vappHREF)
 				if err != nil {
-					return fmt.Errorf("Error retrieving vapp with url: %s and with error %s", vappHREF.Path, err)
+					return fmt.Errorf("Error retrieving vapp with url: %s and with error %w", vappHREF.Path, err)
 				}
-				task, err := vapp.Undeploy()
+				task, err := vapp.UndeployWithContext(ctx)
 				if task == (Task{}) {
 					continue
 				}
-				err = task.WaitTaskCompletion()
+				err = task.WaitTaskCompletionWithContext(ctx)
 			}
 		}
 	}
@@ -69,7 +68,7 @@ func (vdc *Vdc) undeployAllVdcVApps() error {
 }
 
 // Removes all vapps in the vdc
-func (vdc *Vdc) removeAllVdcVApps() error {
+func (vdc *Vdc) removeAllVdcVApps(ctx context.Context) error {
 	for _, resents := range vdc.Vdc.ResourceEntities {
 		for _, resent := range resents.ResourceEntity {
 			if resent.Type == "application/vnd.vmware.vcloud.vApp+xml" {
@@ -77,17 +76,17 @@ func (vdc *Vdc) removeAllVdcVApps() error {
 				if err != nil {
 					return err
 				}
-				vapp, err := vdc.getVdcVAppbyHREF(vappHREF)
+				vapp, err := vdc.getVdcVAppbyHREF(ctx, vappHREF)
 				if err != nil {
-					return fmt.Errorf("Error retrieving vapp with url: %s and with error %s", vappHREF.Path, err)
+					return fmt.Errorf("Error retrieving vapp with url: %s and with error %w", vappHREF.Path, err)
 				}
-				task, err := vapp.Delete()
+				task, err := vapp.DeleteWithContext(ctx)
 				if err != nil {
-					return fmt.Errorf("Error deleting vapp: %s", err)
+					return fmt.Errorf("Error deleting vapp: %w", err)
 				}
-				err = task.WaitTaskCompletion()
+				err = task.WaitTaskCompletionWithContext(ctx)
 				if err != nil {
-					return fmt.Errorf("Couldn't finish removing vapp %#v", err)
+					return fmt.Errorf("Couldn't finish removing vapp %w", err)
 				}
 			}
 		}
@@ -96,6 +95,12 @@ func (vdc *Vdc) removeAllVdcVApps() error {
 }
 
 func (v *Vdc) Refresh() error {
+	return v.RefreshWithContext(context.Background())
+}
+
+// RefreshWithContext retrieves the current state of the vdc, aborting if
+// ctx is cancelled before vCD answers.
+func (v *Vdc) RefreshWithContext(ctx context.Context) error {
 
 	if v.Vdc.HREF == "" {
 		return fmt.Errorf("cannot refresh, Object is empty")
@@ -103,11 +108,11 @@ func (v *Vdc) Refresh() error {
 
 	u, _ := url.ParseRequestURI(v.Vdc.HREF)
 
-	req := v.c.NewRequest(map[string]string{}, "GET", *u, nil)
+	req := v.c.NewRequestWithContext(ctx, map[string]string{}, "GET", *u, nil)
 
-	resp, err := checkResp(v.c.Http.Do(req))
+	resp, err := v.c.doRequest(req)
 	if err != nil {
-		return fmt.Errorf("error retreiving Edge Gateway: %s", err)
+		return fmt.Errorf("error retreiving Edge Gateway: %w", err)
 	}
 
 	// Empty struct before a new unmarshal, otherwise we end up with duplicate
@@ -115,7 +120,7 @@ func (v *Vdc) Refresh() error {
 	unmarshalledVdc := &types.Vdc{}
 
 	if err = decodeBody(resp, unmarshalledVdc); err != nil {
-		return fmt.Errorf("error decoding vdc response: %s", err)
+		return fmt.Errorf("error decoding vdc response: %w", err)
 	}
 
 	v.Vdc = unmarshalledVdc
@@ -125,26 +130,32 @@ func (v *Vdc) Refresh() error {
 }
 
 func (v *Vdc) FindVDCNetwork(network string) (OrgVDCNetwork, error) {
+	return v.FindVDCNetworkWithContext(context.Background(), network)
+}
+
+// FindVDCNetworkWithContext behaves like FindVDCNetwork, aborting if ctx is
+// cancelled before vCD answers.
+func (v *Vdc) FindVDCNetworkWithContext(ctx context.Context, network string) (OrgVDCNetwork, error) {
 
 	for _, an := range v.Vdc.AvailableNetworks {
 		for _, n := range an.Network {
 			if n.Name == network {
 				u, err := url.ParseRequestURI(n.HREF)
 				if err != nil {
-					return OrgVDCNetwork{}, fmt.Errorf("error decoding vdc response: %s", err)
+					return OrgVDCNetwork{}, fmt.Errorf("error decoding vdc response: %w", err)
 				}
 
-				req := v.c.NewRequest(map[string]string{}, "GET", *u, nil)
+				req := v.c.NewRequestWithContext(ctx, map[string]string{}, "GET", *u, nil)
 
-				resp, err := checkResp(v.c.Http.Do(req))
+				resp, err := v.c.doRequest(req)
 				if err != nil {
-					return OrgVDCNetwork{}, fmt.Errorf("error retreiving orgvdcnetwork: %s", err)
+					return OrgVDCNetwork{}, fmt.Errorf("error retreiving orgvdcnetwork: %w", err)
 				}
 
 				orgnet := NewOrgVDCNetwork(v.c)
 
 				if err = decodeBody(resp, orgnet.OrgVDCNetwork); err != nil {
-					return OrgVDCNetwork{}, fmt.Errorf("error decoding orgvdcnetwork response: %s", err)
+					return OrgVDCNetwork{}, fmt.Errorf("error decoding orgvdcnetwork response: %w", err)
 				}
 
 				// The request was successful
@@ -181,27 +192,33 @@ func (v *Vdc) GetDefaultStorageProfileReference(storageprofiles *types.QueryResu
 }
 
 func (v *Vdc) FindEdgeGateway(edgegateway string) (EdgeGateway, error) {
+	return v.FindEdgeGatewayWithContext(context.Background(), edgegateway)
+}
+
+// FindEdgeGatewayWithContext behaves like FindEdgeGateway, aborting if ctx
+// is cancelled before vCD answers.
+func (v *Vdc) FindEdgeGatewayWithContext(ctx context.Context, edgegateway string) (EdgeGateway, error) {
 
 	for _, av := range v.Vdc.Link {
 		if av.Rel == "edgeGateways" && av.Type == "application/vnd.vmware.vcloud.query.records+xml" {
 			u, err := url.ParseRequestURI(av.HREF)
 
 			if err != nil {
-				return EdgeGateway{}, fmt.Errorf("error decoding vdc response: %s", err)
+				return EdgeGateway{}, fmt.Errorf("error decoding vdc response: %w", err)
 			}
 
 			// Querying the Result list
-			req := v.c.NewRequest(map[string]string{}, "GET", *u, nil)
+			req := v.c.NewRequestWithContext(ctx, map[string]string{}, "GET", *u, nil)
 
-			resp, err := checkResp(v.c.Http.Do(req))
+			resp, err := v.c.doRequest(req)
 			if err != nil {
-				return EdgeGateway{}, fmt.Errorf("error retrieving edge gateway records: %s", err)
+				return EdgeGateway{}, fmt.Errorf("error retrieving edge gateway records: %w", err)
 			}
 
 			query := new(types.QueryResultEdgeGatewayRecordsType)
 
 			if err = decodeBody(resp, query); err != nil {
-				return EdgeGateway{}, fmt.Errorf("error decoding edge gateway query response: %s", err)
+				return EdgeGateway{}, fmt.Errorf("error decoding edge gateway query response: %w", err)
 			}
 
 			var href string
@@ -218,21 +235,21 @@ func (v *Vdc) FindEdgeGateway(edgegateway string) (EdgeGateway, error) {
 
 			u, err = url.ParseRequestURI(href)
 			if err != nil {
-				return EdgeGateway{}, fmt.Errorf("error decoding edge gateway query response: %s", err)
+				return EdgeGateway{}, fmt.Errorf("error decoding edge gateway query response: %w", err)
 			}
 
 			// Querying the Result list
-			req = v.c.NewRequest(map[string]string{}, "GET", *u, nil)
+			req = v.c.NewRequestWithContext(ctx, map[string]string{}, "GET", *u, nil)
 
-			resp, err = checkResp(v.c.Http.Do(req))
+			resp, err = v.c.doRequest(req)
 			if err != nil {
-				return EdgeGateway{}, fmt.Errorf("error retrieving edge gateway: %s", err)
+				return EdgeGateway{}, fmt.Errorf("error retrieving edge gateway: %w", err)
 			}
 
 			edge := NewEdgeGateway(v.c)
 
 			if err = decodeBody(resp, edge.EdgeGateway); err != nil {
-				return EdgeGateway{}, fmt.Errorf("error decoding edge gateway response: %s", err)
+				return EdgeGateway{}, fmt.Errorf("error decoding edge gateway response: %w", err)
 			}
 
 			return *edge, nil
@@ -244,6 +261,12 @@ func (v *Vdc) FindEdgeGateway(edgegateway string) (EdgeGateway, error) {
 }
 
 func (v *Vdc) ComposeRawVApp(name string) error {
+	return v.ComposeRawVAppWithContext(context.Background(), name)
+}
+
+// ComposeRawVAppWithContext behaves like ComposeRawVApp, aborting if ctx is
+// cancelled before the operation completes.
+func (v *Vdc) ComposeRawVAppWithContext(ctx context.Context, name string) error {
 	vcomp := &types.ComposeVAppParams{
 		Ovf:     "http://schemas.dmtf.org/ovf/envelope/1",
 		Xsi:     "http://www.w3.org/2001/XMLSchema-instance",
@@ -255,41 +278,35 @@ func (v *Vdc) ComposeRawVApp(name string) error {
 
 	output, err := xml.MarshalIndent(vcomp, "  ", "    ")
 	if err != nil {
-		return fmt.Errorf("error marshaling vapp compose: %s", err)
-	}
-
-	debug := os.Getenv("GOVCLOUDAIR_DEBUG")
-
-	if debug == "true" {
-		fmt.Printf("\n\nXML DEBUG: %s\n\n", string(output))
+		return fmt.Errorf("error marshaling vapp compose: %w", err)
 	}
 
 	requestData := bytes.NewBufferString(xml.Header + string(output))
 
 	vdcHref, err := url.ParseRequestURI(v.Vdc.HREF)
 	if err != nil {
-		return fmt.Errorf("error getting vdc href: %v", err)
+		return fmt.Errorf("error getting vdc href: %w", err)
 	}
 	vdcHref.Path += "/action/composeVApp"
 
-	req := v.c.NewRequest(map[string]string{}, "POST", *vdcHref, requestData)
+	req := v.c.NewRequestWithContext(ctx, map[string]string{}, "POST", *vdcHref, requestData)
 
 	req.Header.Add("Content-Type", "application/vnd.vmware.vcloud.composeVAppParams+xml")
 
-	resp, err := checkResp(v.c.Http.Do(req))
+	resp, err := v.c.doRequest(req)
 	if err != nil {
-		return fmt.Errorf("error instantiating a new vApp: %s", err)
+		return fmt.Errorf("error instantiating a new vApp: %w", err)
 	}
 
 	task := NewTask(v.c)
 
 	if err = decodeBody(resp, task.Task); err != nil {
-		return fmt.Errorf("error decoding task response: %s", err)
+		return fmt.Errorf("error decoding task response: %w", err)
 	}
 
-	err = task.WaitTaskCompletion()
+	err = task.WaitTaskCompletionWithContext(ctx)
 	if err != nil {
-		return fmt.Errorf("Error performing task: %#v", err)
+		return fmt.Errorf("Error performing task: %w", err)
 	}
 
 	return nil
@@ -299,6 +316,12 @@ func (v *Vdc) ComposeRawVApp(name string) error {
 // that uses the storageprofile and networks given. Returns a successful task
 // if completed successfully, otherwise returns an error and an empty task.
 func (v *Vdc) ComposeVApp(orgvdcnetworks []*types.OrgVDCNetwork, vapptemplate VAppTemplate, storageprofileref types.Reference, name string, description string) (Task, error) {
+	return v.ComposeVAppWithContext(context.Background(), orgvdcnetworks, vapptemplate, storageprofileref, name, description)
+}
+
+// ComposeVAppWithContext behaves like ComposeVApp, aborting the request if
+// ctx is cancelled before vCD accepts it.
+func (v *Vdc) ComposeVAppWithContext(ctx context.Context, orgvdcnetworks []*types.OrgVDCNetwork, vapptemplate VAppTemplate, storageprofileref types.Reference, name string, description string) (Task, error) {
 	if vapptemplate.VAppTemplate.Children == nil || orgvdcnetworks == nil {
 		return Task{}, fmt.Errorf("can't compose a new vApp, objects passed are not valid")
 	}
@@ -367,27 +390,26 @@ func (v *Vdc) ComposeVApp(orgvdcnetworks []*types.OrgVDCNetwork, vapptemplate VA
 
 	output, err := xml.MarshalIndent(vcomp, "  ", "    ")
 	if err != nil {
-		return Task{}, fmt.Errorf("error marshaling vapp compose: %s", err)
+		return Task{}, fmt.Errorf("error marshaling vapp compose: %w", err)
 	}
-	log.Printf("\n\nXML DEBUG: %s\n\n", string(output))
 	requestData := bytes.NewBufferString(xml.Header + string(output))
 
 	vdcHref, err := url.ParseRequestURI(v.Vdc.HREF)
 	if err != nil {
-		return Task{}, fmt.Errorf("error getting vdc href: %v", err)
+		return Task{}, fmt.Errorf("error getting vdc href: %w", err)
 	}
 	vdcHref.Path += "/action/composeVApp"
 
-	req := v.c.NewRequest(map[string]string{}, "POST", *vdcHref, requestData)
+	req := v.c.NewRequestWithContext(ctx, map[string]string{}, "POST", *vdcHref, requestData)
 	req.Header.Add("Content-Type", "application/vnd.vmware.vcloud.composeVAppParams+xml")
-	resp, err := checkResp(v.c.Http.Do(req))
+	resp, err := v.c.doRequest(req)
 	if err != nil {
-		return Task{}, fmt.Errorf("error instantiating a new vApp: %s", err)
+		return Task{}, fmt.Errorf("error instantiating a new vApp: %w", err)
 	}
 
 	vapp := NewVApp(v.c)
 	if err = decodeBody(resp, vapp.VApp); err != nil {
-		return Task{}, fmt.Errorf("error decoding vApp response: %s", err)
+		return Task{}, fmt.Errorf("error decoding vApp response: %w", err)
 	}
 
 	task := NewTask(v.c)
@@ -397,10 +419,16 @@ func (v *Vdc) ComposeVApp(orgvdcnetworks []*types.OrgVDCNetwork, vapptemplate VA
 }
 
 func (v *Vdc) FindVAppByName(vapp string) (VApp, error) {
+	return v.FindVAppByNameWithContext(context.Background(), vapp)
+}
+
+// FindVAppByNameWithContext behaves like FindVAppByName, aborting if ctx is
+// cancelled before vCD answers.
+func (v *Vdc) FindVAppByNameWithContext(ctx context.Context, vapp string) (VApp, error) {
 
-	err := v.Refresh()
+	err := v.RefreshWithContext(ctx)
 	if err != nil {
-		return VApp{}, fmt.Errorf("error refreshing vdc: %s", err)
+		return VApp{}, fmt.Errorf("error refreshing vdc: %w", err)
 	}
 
 	for _, resents := range v.Vdc.ResourceEntities {
@@ -411,21 +439,21 @@ func (v *Vdc) FindVAppByName(vapp string) (VApp, error) {
 				u, err := url.ParseRequestURI(resent.HREF)
 
 				if err != nil {
-					return VApp{}, fmt.Errorf("error decoding vdc response: %s", err)
+					return VApp{}, fmt.Errorf("error decoding vdc response: %w", err)
 				}
 
 				// Querying the VApp
-				req := v.c.NewRequest(map[string]string{}, "GET", *u, nil)
+				req := v.c.NewRequestWithContext(ctx, map[string]string{}, "GET", *u, nil)
 
-				resp, err := checkResp(v.c.Http.Do(req))
+				resp, err := v.c.doRequest(req)
 				if err != nil {
-					return VApp{}, fmt.Errorf("error retrieving vApp: %s", err)
+					return VApp{}, fmt.Errorf("error retrieving vApp: %w", err)
 				}
 
 				newvapp := NewVApp(v.c)
 
 				if err = decodeBody(resp, newvapp.VApp); err != nil {
-					return VApp{}, fmt.Errorf("error decoding vApp response: %s", err.Error())
+					return VApp{}, fmt.Errorf("error decoding vApp response: %w", err)
 				}
 
 				return *newvapp, nil
@@ -437,15 +465,21 @@ func (v *Vdc) FindVAppByName(vapp string) (VApp, error) {
 }
 
 func (v *Vdc) FindVMByName(vapp VApp, vm string) (VM, error) {
+	return v.FindVMByNameWithContext(context.Background(), vapp, vm)
+}
+
+// FindVMByNameWithContext behaves like FindVMByName, aborting if ctx is
+// cancelled before vCD answers.
+func (v *Vdc) FindVMByNameWithContext(ctx context.Context, vapp VApp, vm string) (VM, error) {
 
-	err := v.Refresh()
+	err := v.RefreshWithContext(ctx)
 	if err != nil {
-		return VM{}, fmt.Errorf("error refreshing vdc: %s", err)
+		return VM{}, fmt.Errorf("error refreshing vdc: %w", err)
 	}
 
-	err = vapp.Refresh()
+	err = vapp.RefreshWithContext(ctx)
 	if err != nil {
-		return VM{}, fmt.Errorf("error refreshing vapp: %s", err)
+		return VM{}, fmt.Errorf("error refreshing vapp: %w", err)
 	}
 
 	//vApp Might Not Have Any VMs
@@ -454,24 +488,24 @@ func (v *Vdc) FindVMByName(vapp VApp, vm string) (VM, error) {
 		return VM{}, fmt.Errorf("VApp Has No VMs")
 	}
 
-	log.Printf("[TRACE] Looking for VM: %s", vm)
+	v.c.log(LogTrace, "looking for VM", "vm", vm, "vapp", vapp.VApp.Name)
 	for _, child := range vapp.VApp.Children.VM {
 
-		log.Printf("[TRACE] Found: %s", child.Name)
+		v.c.log(LogTrace, "checking VM", "vm", child.Name)
 		if child.Name == vm {
 
 			u, err := url.ParseRequestURI(child.HREF)
 
 			if err != nil {
-				return VM{}, fmt.Errorf("error decoding vdc response: %s", err)
+				return VM{}, fmt.Errorf("error decoding vdc response: %w", err)
 			}
 
 			// Querying the VApp
-			req := v.c.NewRequest(map[string]string{}, "GET", *u, nil)
+			req := v.c.NewRequestWithContext(ctx, map[string]string{}, "GET", *u, nil)
 
-			resp, err := checkResp(v.c.Http.Do(req))
+			resp, err := v.c.doRequest(req)
 			if err != nil {
-				return VM{}, fmt.Errorf("error retrieving vm: %s", err)
+				return VM{}, fmt.Errorf("error retrieving vm: %w", err)
 			}
 
 			newvm := NewVM(v.c)
@@ -480,7 +514,7 @@ func (v *Vdc) FindVMByName(vapp VApp, vm string) (VM, error) {
 			//fmt.Println(string(body))
 
 			if err = decodeBody(resp, newvm.VM); err != nil {
-				return VM{}, fmt.Errorf("error decoding vm response: %s", err.Error())
+				return VM{}, fmt.Errorf("error decoding vm response: %w", err)
 			}
 
 			return *newvm, nil
@@ -488,18 +522,24 @@ func (v *Vdc) FindVMByName(vapp VApp, vm string) (VM, error) {
 		}
 
 	}
-	log.Printf("[TRACE] Couldn't find VM: %s", vm)
+	v.c.log(LogTrace, "VM not found", "vm", vm, "vapp", vapp.VApp.Name)
 	return VM{}, fmt.Errorf("can't find vm: %s", vm)
 }
 
 func (v *Vdc) FindVAppByID(vappid string) (VApp, error) {
+	return v.FindVAppByIDWithContext(context.Background(), vappid)
+}
+
+// FindVAppByIDWithContext behaves like FindVAppByID, aborting if ctx is
+// cancelled before vCD answers.
+func (v *Vdc) FindVAppByIDWithContext(ctx context.Context, vappid string) (VApp, error) {
 
 	// Horrible hack to fetch a vapp with its id.
 	// urn:vcloud:vapp:00000000-0000-0000-0000-000000000000
 
-	err := v.Refresh()
+	err := v.RefreshWithContext(ctx)
 	if err != nil {
-		return VApp{}, fmt.Errorf("error refreshing vdc: %s", err)
+		return VApp{}, fmt.Errorf("error refreshing vdc: %w", err)
 	}
 
 	urnslice := strings.SplitAfter(vappid, ":")
@@ -517,21 +557,21 @@ func (v *Vdc) FindVAppByID(vappid string) (VApp, error) {
 				u, err := url.ParseRequestURI(resent.HREF)
 
 				if err != nil {
-					return VApp{}, fmt.Errorf("error decoding vdc response: %s", err)
+					return VApp{}, fmt.Errorf("error decoding vdc response: %w", err)
 				}
 
 				// Querying the VApp
-				req := v.c.NewRequest(map[string]string{}, "GET", *u, nil)
+				req := v.c.NewRequestWithContext(ctx, map[string]string{}, "GET", *u, nil)
 
-				resp, err := checkResp(v.c.Http.Do(req))
+				resp, err := v.c.doRequest(req)
 				if err != nil {
-					return VApp{}, fmt.Errorf("error retrieving vApp: %s", err)
+					return VApp{}, fmt.Errorf("error retrieving vApp: %w", err)
 				}
 
 				newvapp := NewVApp(v.c)
 
 				if err = decodeBody(resp, newvapp.VApp); err != nil {
-					return VApp{}, fmt.Errorf("error decoding vApp response: %s", err)
+					return VApp{}, fmt.Errorf("error decoding vApp response: %w", err)
 				}
 
 				return *newvapp, nil
@@ -0,0 +1,273 @@
+/*
+ * Copyright 2014 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	types "github.com/vmware/go-vcloud-director/types/v56"
+)
+
+// CapacityReport summarizes the compute capacity, storage and quotas of a
+// VDC, or of all the VDCs of an org. CPU is in MHz, memory and storage in
+// MB, whatever the units vCD reports them in.
+type CapacityReport struct {
+	Name     string
+	HREF     string
+	CPU      ComputeUsage
+	Memory   ComputeUsage
+	Storage  []StorageUsage
+	VMs      QuotaUsage
+	Networks QuotaUsage
+}
+
+// ComputeUsage is the CPU or memory capacity of a VDC. A Limit of 0 is
+// unlimited. Percent is Used over Limit, or over Allocated when there is
+// no limit, and 0 when there is neither.
+type ComputeUsage struct {
+	Allocated int64
+	Reserved  int64
+	Limit     int64
+	Used      int64
+	Percent   float64
+}
+
+// StorageUsage is the storage used on a storage profile. A LimitMB of 0 is
+// unlimited and has a Percent of 0.
+type StorageUsage struct {
+	Name    string
+	Default bool
+	Enabled bool
+	UsedMB  int64
+	LimitMB int64
+	Percent float64
+}
+
+// QuotaUsage is a count of VMs or networks against their quota. A Quota of
+// 0 is unlimited and has a Percent of 0.
+type QuotaUsage struct {
+	Used    int
+	Quota   int
+	Percent float64
+}
+
+// OrgCapacityReport holds the capacity report of every VDC of an org and
+// their total. In the total, a limit or quota is unlimited as soon as it
+// is unlimited in one of the VDCs.
+type OrgCapacityReport struct {
+	Name  string
+	Vdcs  []*CapacityReport
+	Total CapacityReport
+}
+
+// GetCapacityReport returns the capacity report of the VDC. The storage
+// usage comes from GetStorageProfiles and the VM count from the vm query.
+func (vdc *Vdc) GetCapacityReport() (*CapacityReport, error) {
+	report := &CapacityReport{
+		Name:     vdc.Vdc.Name,
+		HREF:     vdc.Vdc.HREF,
+		Networks: newQuotaUsage(vdc.Vdc.UsedNetworkCount, vdc.Vdc.NetworkQuota),
+	}
+	if len(vdc.Vdc.ComputeCapacity) > 0 {
+		var err error
+		capacity := vdc.Vdc.ComputeCapacity[0]
+		if report.CPU, err = newComputeUsage(capacity.CPU, cpuUnits); err != nil {
+			return nil, fmt.Errorf("error reading CPU capacity of VDC %s: %w", vdc.Vdc.Name, err)
+		}
+		if report.Memory, err = newComputeUsage(capacity.Memory, memoryUnits); err != nil {
+			return nil, fmt.Errorf("error reading memory capacity of VDC %s: %w", vdc.Vdc.Name, err)
+		}
+	}
+
+	storage, err := vdc.GetStorageProfiles()
+	if err != nil {
+		return nil, err
+	}
+	for _, record := range storage {
+		report.Storage = append(report.Storage, newStorageUsage(record))
+	}
+
+	vms, err := vdc.query(NewQuery("vm").Filter(FilterAnd(
+		FilterEq("vdc", vdc.Vdc.HREF),
+		FilterEq("isVAppTemplate", "false"),
+	)).PageSize(1))
+	if err != nil {
+		return nil, fmt.Errorf("error querying VMs of VDC %s: %w", vdc.Vdc.Name, err)
+	}
+	report.VMs = newQuotaUsage(int(vms.Results.Total), vdc.Vdc.VMQuota)
+	return report, nil
+}
+
+// query runs q against the query service of the vCD the VDC belongs to.
+func (vdc *Vdc) query(q *QueryBuilder) (Results, error) {
+	queryHREF := vdc.c.VCDHREF
+	queryHREF.Path += "/query"
+	return vdc.c.query(context.Background(), queryHREF, q.Params())
+}
+
+// queryAll runs q against the query service of the vCD the VDC belongs to
+// and returns the records of all its pages.
+func (vdc *Vdc) queryAll(q *QueryBuilder) (Results, error) {
+	queryHREF := vdc.c.VCDHREF
+	queryHREF.Path += "/query"
+	return vdc.c.newQueryPages(context.Background(), queryHREF, q).All()
+}
+
+// GetCapacityReport returns the capacity report of every VDC of the org,
+// with their total, for capacity planning.
+func (adminOrg *AdminOrg) GetCapacityReport() (*OrgCapacityReport, error) {
+	if err := adminOrg.Refresh(); err != nil {
+		return nil, err
+	}
+	report := &OrgCapacityReport{Name: adminOrg.AdminOrg.Name}
+	report.Total.Name = adminOrg.AdminOrg.Name
+	report.Total.HREF = adminOrg.AdminOrg.HREF
+	if adminOrg.AdminOrg.Vdcs == nil {
+		return report, nil
+	}
+	for i, reference := range adminOrg.AdminOrg.Vdcs.Vdcs {
+		adminVdcHREF, err := url.Parse(reference.HREF)
+		if err != nil {
+			return nil, fmt.Errorf("error getting VDC HREF %s : %w", reference.HREF, err)
+		}
+		vdc, err := adminOrg.getVdcByAdminHREF(context.Background(), adminVdcHREF)
+		if err != nil {
+			return nil, err
+		}
+		vdcReport, err := vdc.GetCapacityReport()
+		if err != nil {
+			return nil, err
+		}
+		report.Vdcs = append(report.Vdcs, vdcReport)
+		report.Total.addReport(vdcReport, i == 0)
+	}
+	return report, nil
+}
+
+// addReport adds the capacity of a VDC to the total. first tells whether
+// it is the first VDC, whose quotas start the totals.
+func (total *CapacityReport) addReport(report *CapacityReport, first bool) {
+	total.CPU = addComputeUsage(total.CPU, report.CPU, first)
+	total.Memory = addComputeUsage(total.Memory, report.Memory, first)
+	total.VMs = addQuotaUsage(total.VMs, report.VMs, first)
+	total.Networks = addQuotaUsage(total.Networks, report.Networks, first)
+	for _, storage := range report.Storage {
+		found := false
+		for i, totalStorage := range total.Storage {
+			if totalStorage.Name == storage.Name {
+				total.Storage[i] = addStorageUsage(totalStorage, storage)
+				found = true
+				break
+			}
+		}
+		if !found {
+			total.Storage = append(total.Storage, storage)
+		}
+	}
+}
+
+var (
+	cpuUnits    = map[string]int64{"MHz": 1, "GHz": 1000}
+	memoryUnits = map[string]int64{"MB": 1, "GB": 1024, "TB": 1024 * 1024}
+)
+
+// newComputeUsage converts capacity to the unit of factor 1 in units.
+func newComputeUsage(capacity *types.CapacityWithUsage, units map[string]int64) (ComputeUsage, error) {
+	if capacity == nil {
+		return ComputeUsage{}, nil
+	}
+	factor, ok := units[capacity.Units]
+	if !ok {
+		// Some versions report units in a different case.
+		for unit, f := range units {
+			if strings.EqualFold(unit, capacity.Units) {
+				factor, ok = f, true
+			}
+		}
+	}
+	if !ok {
+		return ComputeUsage{}, fmt.Errorf("unknown capacity units %q", capacity.Units)
+	}
+	usage := ComputeUsage{
+		Allocated: capacity.Allocated * factor,
+		Reserved:  capacity.Reserved * factor,
+		Limit:     capacity.Limit * factor,
+		Used:      capacity.Used * factor,
+	}
+	usage.Percent = computePercent(usage)
+	return usage, nil
+}
+
+func addComputeUsage(a, b ComputeUsage, first bool) ComputeUsage {
+	sum := ComputeUsage{
+		Allocated: a.Allocated + b.Allocated,
+		Reserved:  a.Reserved + b.Reserved,
+		Used:      a.Used + b.Used,
+	}
+	if first {
+		sum.Limit = b.Limit
+	} else if a.Limit > 0 && b.Limit > 0 {
+		sum.Limit = a.Limit + b.Limit
+	}
+	sum.Percent = computePercent(sum)
+	return sum
+}
+
+func computePercent(usage ComputeUsage) float64 {
+	if usage.Limit > 0 {
+		return percent(usage.Used, usage.Limit)
+	}
+	return percent(usage.Used, usage.Allocated)
+}
+
+func newStorageUsage(record *types.QueryResultOrgVdcStorageProfileRecordType) StorageUsage {
+	return StorageUsage{
+		Name:    record.Name,
+		Default: record.IsDefaultStorageProfile,
+		Enabled: record.IsEnabled,
+		UsedMB:  int64(record.StorageUsedMB),
+		LimitMB: int64(record.StorageLimitMB),
+		Percent: percent(int64(record.StorageUsedMB), int64(record.StorageLimitMB)),
+	}
+}
+
+func addStorageUsage(a, b StorageUsage) StorageUsage {
+	sum := StorageUsage{
+		Name:    a.Name,
+		Default: a.Default || b.Default,
+		Enabled: a.Enabled || b.Enabled,
+		UsedMB:  a.UsedMB + b.UsedMB,
+	}
+	if a.LimitMB > 0 && b.LimitMB > 0 {
+		sum.LimitMB = a.LimitMB + b.LimitMB
+	}
+	sum.Percent = percent(sum.UsedMB, sum.LimitMB)
+	return sum
+}
+
+func newQuotaUsage(used, quota int) QuotaUsage {
+	return QuotaUsage{Used: used, Quota: quota, Percent: percent(int64(used), int64(quota))}
+}
+
+func addQuotaUsage(a, b QuotaUsage, first bool) QuotaUsage {
+	quota := 0
+	if first {
+		quota = b.Quota
+	} else if a.Quota > 0 && b.Quota > 0 {
+		quota = a.Quota + b.Quota
+	}
+	return newQuotaUsage(a.Used+b.Used, quota)
+}
+
+// percent returns used over limit in percent, 0 when limit is unlimited.
+func percent(used, limit int64) float64 {
+	if limit <= 0 {
+		return 0
+	}
+	return float64(used) * 100 / float64(limit)
+}
@@ -0,0 +1,93 @@
+/*
+ * Copyright 2014 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"testing"
+
+	types "github.com/vmware/go-vcloud-director/types/v56"
+	. "gopkg.in/check.v1"
+)
+
+// Checks that the capacity report of the test VDC is part of the report of
+// its org.
+func (vcd *TestVCD) Test_VdcCapacityReport(check *C) {
+	report, err := vcd.vdc.GetCapacityReport()
+	check.Assert(err, IsNil)
+	check.Assert(report.Name, Equals, vcd.vdc.Vdc.Name)
+	check.Assert(len(report.Storage) > 0, Equals, true)
+
+	adminOrg, err := GetAdminOrgByName(vcd.client, vcd.config.VCD.Org)
+	check.Assert(err, IsNil)
+	orgReport, err := adminOrg.GetCapacityReport()
+	check.Assert(err, IsNil)
+	check.Assert(len(orgReport.Vdcs) > 0, Equals, true)
+	check.Assert(orgReport.Total.VMs.Used >= report.VMs.Used, Equals, true)
+}
+
+func TestVdc_CapacityReportFakeVCD(t *testing.T) {
+	server, fakeVdc, client := newFakeVCD(t)
+	defer server.Close()
+	fakeVdc.Vdc.ComputeCapacity = []*types.ComputeCapacity{{
+		CPU:    &types.CapacityWithUsage{Units: "GHz", Allocated: 4, Limit: 4, Used: 1},
+		Memory: &types.CapacityWithUsage{Units: "GB", Allocated: 8, Limit: 8, Used: 2},
+	}}
+	fakeVdc.Vdc.VMQuota = 4
+	fakeVdc.AddVApp("web").AddVM("web-1")
+	fakeVdc.AddNetwork("net")
+	fakeVdc.SetStorageUsed("*", 512)
+	fakeVdc.Org().AddVdc("other").AddVApp("db").AddVM("db-1")
+
+	_, vdc := fakeOrgVdc(t, client)
+	server.ResetRequests()
+	report, err := vdc.GetCapacityReport()
+	if err != nil {
+		t.Fatalf("error getting capacity report: %s", err)
+	}
+	checkRequests(t, server,
+		apiRequest{Method: "GET", Path: "/query", Query: map[string]string{
+			"type": "orgVdcStorageProfile", "filter": "vdc==" + vdc.Vdc.HREF,
+		}},
+		apiRequest{Method: "GET", Path: "/query", Query: map[string]string{
+			"type": "vm", "filter": "vdc==" + vdc.Vdc.HREF + ";isVAppTemplate==false",
+		}},
+	)
+	if report.CPU.Limit != 4000 || report.CPU.Used != 1000 || report.CPU.Percent != 25 {
+		t.Fatalf("unexpected CPU usage: %+v", report.CPU)
+	}
+	if report.Memory.Limit != 8192 || report.Memory.Used != 2048 || report.Memory.Percent != 25 {
+		t.Fatalf("unexpected memory usage: %+v", report.Memory)
+	}
+	if len(report.Storage) != 1 || report.Storage[0].Name != "*" || !report.Storage[0].Default ||
+		report.Storage[0].UsedMB != 512 || report.Storage[0].LimitMB != 0 {
+		t.Fatalf("unexpected storage usage: %+v", report.Storage)
+	}
+	if report.VMs != (QuotaUsage{Used: 1, Quota: 4, Percent: 25}) {
+		t.Fatalf("unexpected VM usage: %+v", report.VMs)
+	}
+	if report.Networks != (QuotaUsage{Used: 1, Quota: 20, Percent: 5}) {
+		t.Fatalf("unexpected network usage: %+v", report.Networks)
+	}
+
+	adminOrg, err := GetAdminOrgByName(client, "org")
+	if err != nil {
+		t.Fatalf("error getting admin org: %s", err)
+	}
+	orgReport, err := adminOrg.GetCapacityReport()
+	if err != nil {
+		t.Fatalf("error getting org capacity report: %s", err)
+	}
+	if len(orgReport.Vdcs) != 2 || orgReport.Vdcs[1].Name != "other" {
+		t.Fatalf("unexpected VDC reports: %+v", orgReport.Vdcs)
+	}
+	total := orgReport.Total
+	// The other VDC is pay-as-you-go: no CPU limit and no VM quota.
+	if total.CPU.Limit != 0 || total.CPU.Allocated != 4000 || total.VMs.Used != 2 || total.VMs.Quota != 0 {
+		t.Fatalf("unexpected total: %+v", total)
+	}
+	if len(total.Storage) != 1 || total.Storage[0].UsedMB != 512 || total.Networks.Quota != 40 {
+		t.Fatalf("unexpected total storage and networks: %+v, %+v", total.Storage, total.Networks)
+	}
+}
@@ -5,9 +5,12 @@
 package govcd
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"github.com/vmware/go-vcloud-director/types/v56"
 	. "gopkg.in/check.v1"
+	"testing"
 )
 
 func (vcd *TestVCD) Test_FindVDCNetwork(check *C) {
@@ -219,3 +222,61 @@ var vdcExample = `
 	  </VdcStorageProfiles>
 	</Vdc>
 	`
+
+func TestVdc_FindVMByNameFakeVCD(t *testing.T) {
+	server, fakeVdc, client := newFakeVCD(t)
+	defer server.Close()
+	fakeVdc.AddVApp("vapp").AddVM("vm")
+	_, vdc := fakeOrgVdc(t, client)
+
+	vapp, err := vdc.FindVAppByName("vapp")
+	if err != nil {
+		t.Fatalf("error finding vapp: %s", err)
+	}
+	vm, err := vdc.FindVMByName(vapp, "vm")
+	if err != nil || vm.VM.Name != "vm" {
+		t.Fatalf("error finding vm: %v", err)
+	}
+	if _, err = vdc.FindVAppByName("missing"); err == nil {
+		t.Fatalf("expected an error finding a missing vapp")
+	}
+}
+
+// Tests that lookups and VM operations bound to a cancelled context fail
+// without reaching vCD.
+func TestVdc_FindWithContextCancelledFakeVCD(t *testing.T) {
+	server, fakeVdc, client := newFakeVCD(t)
+	defer server.Close()
+	fakeVdc.AddVApp("vapp").AddVM("vm")
+	_, vdc := fakeOrgVdc(t, client)
+	vapp, err := vdc.FindVAppByNameWithContext(context.Background(), "vapp")
+	if err != nil {
+		t.Fatalf("error finding vapp: %s", err)
+	}
+	vm, err := vdc.FindVMByNameWithContext(context.Background(), vapp, "vm")
+	if err != nil {
+		t.Fatalf("error finding vm: %s", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	server.ResetRequests()
+	if _, err = vdc.FindVAppByNameWithContext(ctx, "vapp"); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled finding a vapp, got %v", err)
+	}
+	if _, err = vdc.FindVMByNameWithContext(ctx, vapp, "vm"); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled finding a vm, got %v", err)
+	}
+	if _, err = vapp.GetStatusWithContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled getting the vapp status, got %v", err)
+	}
+	if _, err = vm.UndeployWithContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled undeploying the vm, got %v", err)
+	}
+	if _, err = vm.ChangeMemorySizeWithContext(ctx, 2048); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled changing the memory size, got %v", err)
+	}
+	if requests := server.Requests(); len(requests) != 0 {
+		t.Fatalf("expected no request with a cancelled context, got:\n%s", formatRequests(requests))
+	}
+}
@@ -0,0 +1,75 @@
+/*
+ * Copyright 2014 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SupportedAPIVersions lists the vCD API versions govcd can talk to, from
+// the oldest to the newest.
+var SupportedAPIVersions = []string{"5.5", "5.6", "5.7", "5.11"}
+
+// compareAPIVersions compares two dotted API versions numerically. It
+// returns -1, 0 or 1 when a is respectively lower than, equal to or
+// greater than b. Missing or non numeric parts count as 0.
+func compareAPIVersions(a, b string) int {
+	aParts := strings.Split(a, ".")
+	bParts := strings.Split(b, ".")
+	for i := 0; i < len(aParts) || i < len(bParts); i++ {
+		var x, y int
+		if i < len(aParts) {
+			x, _ = strconv.Atoi(aParts[i])
+		}
+		if i < len(bParts) {
+			y, _ = strconv.Atoi(bParts[i])
+		}
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+	}
+	return 0
+}
+
+// negotiateAPIVersion picks the API version to use among the versions
+// offered by vCD. When pinned is not empty it must be offered by vCD,
+// otherwise the highest version supported by both sides is returned.
+func negotiateAPIVersion(offered []string, pinned string) (string, error) {
+	if pinned != "" {
+		for _, version := range offered {
+			if compareAPIVersions(version, pinned) == 0 {
+				return version, nil
+			}
+		}
+		return "", fmt.Errorf("API version %s is not supported by vCD, supported versions: %s",
+			pinned, strings.Join(offered, ", "))
+	}
+
+	best := ""
+	for _, version := range offered {
+		for _, supported := range SupportedAPIVersions {
+			if compareAPIVersions(version, supported) == 0 && (best == "" || compareAPIVersions(version, best) > 0) {
+				best = version
+			}
+		}
+	}
+	if best == "" {
+		return "", fmt.Errorf("no API version supported by both govcd (%s) and vCD (%s)",
+			strings.Join(SupportedAPIVersions, ", "), strings.Join(offered, ", "))
+	}
+	return best, nil
+}
+
+// APIVersionIsAtLeast reports whether the API version used by the client
+// is equal to or greater than version. Feature code uses it to gate calls
+// that only exist in recent versions of vCD.
+func (c *Client) APIVersionIsAtLeast(version string) bool {
+	return compareAPIVersions(c.APIVersion, version) >= 0
+}
@@ -0,0 +1,89 @@
+/*
+ * Copyright 2014 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package govcd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestCompareAPIVersions(t *testing.T) {
+	cases := []struct {
+		a, b     string
+		expected int
+	}{
+		{"5.5", "5.5", 0},
+		{"5.5", "5.11", -1},
+		{"5.11", "5.7", 1},
+		{"27.0", "5.11", 1},
+		{"5.5", "5.5.0", 0},
+	}
+	for _, c := range cases {
+		if got := compareAPIVersions(c.a, c.b); got != c.expected {
+			t.Errorf("compareAPIVersions(%s, %s): expected %d, got %d", c.a, c.b, c.expected, got)
+		}
+	}
+}
+
+func TestNegotiateAPIVersion(t *testing.T) {
+	version, err := negotiateAPIVersion([]string{"1.5", "5.1", "5.5", "5.6", "27.0"}, "")
+	if err != nil || version != "5.6" {
+		t.Fatalf("expected 5.6, got %s, %v", version, err)
+	}
+
+	version, err = negotiateAPIVersion([]string{"5.5", "5.6"}, "5.5")
+	if err != nil || version != "5.5" {
+		t.Fatalf("expected pinned 5.5, got %s, %v", version, err)
+	}
+
+	if _, err = negotiateAPIVersion([]string{"5.5", "5.6"}, "5.11"); err == nil {
+		t.Fatal("expected an error for a pinned version vCD does not offer")
+	}
+	if _, err = negotiateAPIVersion([]string{"1.5", "5.1"}, ""); err == nil || !strings.Contains(err.Error(), "no API version supported") {
+		t.Fatalf("expected an error for disjoint versions, got %v", err)
+	}
+}
+
+func TestVCDClient_NegotiateAPIVersion(t *testing.T) {
+	server := httptest.NewServer(nil)
+	defer server.Close()
+	server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "<SupportedVersions>")
+		for _, version := range []string{"5.1", "5.5", "5.6"} {
+			fmt.Fprintf(w, "<VersionInfo><Version>%s</Version><LoginUrl>%s/api/sessions-%s</LoginUrl></VersionInfo>", version, server.URL, version)
+		}
+		fmt.Fprint(w, "</SupportedVersions>")
+	})
+
+	u, _ := url.ParseRequestURI(server.URL + "/api")
+	client := NewVCDClient(*u, true)
+	if err := client.NegotiateAPIVersionWithContext(context.Background()); err != nil {
+		t.Fatalf("error negotiating API version: %s", err)
+	}
+	if client.APIVersion() != "5.6" || client.sessionHREF.Path != "/api/sessions-5.6" {
+		t.Fatalf("unexpected negotiation result: %s, %s", client.APIVersion(), client.sessionHREF.Path)
+	}
+	if len(client.ServerAPIVersions()) != 3 {
+		t.Fatalf("expected 3 server versions, got %v", client.ServerAPIVersions())
+	}
+	if !client.Client.APIVersionIsAtLeast("5.5") || client.Client.APIVersionIsAtLeast("5.7") {
+		t.Fatalf("unexpected APIVersionIsAtLeast result for %s", client.APIVersion())
+	}
+
+	client = NewVCDClient(*u, true, WithAPIVersion("5.5"))
+	if err := client.NegotiateAPIVersion(); err != nil || client.APIVersion() != "5.5" {
+		t.Fatalf("expected pinned version 5.5, got %s, %v", client.APIVersion(), err)
+	}
+
+	client = NewVCDClient(*u, true, WithAPIVersion("5.11"))
+	if err := client.NegotiateAPIVersion(); err == nil {
+		t.Fatal("expected an error for a pinned version vCD does not offer")
+	}
+}
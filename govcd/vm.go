@@ -6,11 +6,10 @@ package govcd
 
 import (
 	"bytes"
+	"context"
 	"encoding/xml"
 	"fmt"
-	"log"
 	"net/url"
-	"os"
 	"strconv"
 
 	types "github.com/vmware/go-vcloud-director/types/v56"
@@ -29,14 +28,26 @@ func NewVM(c *Client) *VM {
 }
 
 func (v *VM) GetStatus() (string, error) {
-	err := v.Refresh()
+	return v.GetStatusWithContext(context.Background())
+}
+
+// GetStatusWithContext behaves like GetStatus, aborting if ctx is cancelled
+// before vCD answers.
+func (v *VM) GetStatusWithContext(ctx context.Context) (string, error) {
+	err := v.RefreshWithContext(ctx)
 	if err != nil {
-		return "", fmt.Errorf("error refreshing VM: %v", err)
+		return "", fmt.Errorf("error refreshing VM: %w", err)
 	}
 	return types.VAppStatuses[v.VM.Status], nil
 }
 
 func (v *VM) Refresh() error {
+	return v.RefreshWithContext(context.Background())
+}
+
+// RefreshWithContext retrieves the current state of the VM, aborting if
+// ctx is cancelled before vCD answers.
+func (v *VM) RefreshWithContext(ctx context.Context) error {
 
 	if v.VM.HREF == "" {
 		return fmt.Errorf("cannot refresh VM, Object is empty")
@@ -44,11 +55,11 @@ func (v *VM) Refresh() error {
 
 	u, _ := url.ParseRequestURI(v.VM.HREF)
 
-	req := v.c.NewRequest(map[string]string{}, "GET", *u, nil)
+	req := v.c.NewRequestWithContext(ctx, map[string]string{}, "GET", *u, nil)
 
-	resp, err := checkResp(v.c.Http.Do(req))
+	resp, err := v.c.doRequest(req)
 	if err != nil {
-		return fmt.Errorf("error retrieving task: %s", err)
+		return fmt.Errorf("error retrieving task: %w", err)
 	}
 
 	// Empty struct before a new unmarshal, otherwise we end up with duplicate
@@ -56,14 +67,40 @@ func (v *VM) Refresh() error {
 	v.VM = &types.VM{}
 
 	if err = decodeBody(resp, v.VM); err != nil {
-		return fmt.Errorf("error decoding task response VM: %s", err)
+		return fmt.Errorf("error decoding task response VM: %w", err)
 	}
 
 	// The request was successful
 	return nil
 }
 
+// GetTasksInProgress refreshes the VM and returns the tasks that are still
+// queued or running on it.
+func (v *VM) GetTasksInProgress() ([]Task, error) {
+	return v.GetTasksInProgressWithContext(context.Background())
+}
+
+// GetTasksInProgressWithContext behaves like GetTasksInProgress, aborting
+// if ctx is cancelled before vCD answers.
+func (v *VM) GetTasksInProgressWithContext(ctx context.Context) ([]Task, error) {
+	err := v.RefreshWithContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error refreshing VM: %w", err)
+	}
+	if v.VM.Tasks == nil {
+		return nil, nil
+	}
+	return tasksFromList(v.c, v.VM.Tasks.Task, true), nil
+}
+
 func (v *VM) GetNetworkConnectionSection() (*types.NetworkConnectionSection, error) {
+	return v.GetNetworkConnectionSectionWithContext(context.Background())
+}
+
+// GetNetworkConnectionSectionWithContext behaves like
+// GetNetworkConnectionSection, aborting if ctx is cancelled before vCD
+// answers.
+func (v *VM) GetNetworkConnectionSectionWithContext(ctx context.Context) (*types.NetworkConnectionSection, error) {
 
 	networkConnectionSection := &types.NetworkConnectionSection{}
 
@@ -73,17 +110,17 @@ func (v *VM) GetNetworkConnectionSection() (*types.NetworkConnectionSection, err
 
 	u, _ := url.ParseRequestURI(v.VM.HREF + "/networkConnectionSection/")
 
-	req := v.c.NewRequest(map[string]string{}, "GET", *u, nil)
+	req := v.c.NewRequestWithContext(ctx, map[string]string{}, "GET", *u, nil)
 
 	req.Header.Add("Content-Type", "application/vnd.vmware.vcloud.networkConnectionSection+xml")
 
-	resp, err := checkResp(v.c.Http.Do(req))
+	resp, err := v.c.doRequest(req)
 	if err != nil {
-		return networkConnectionSection, fmt.Errorf("error retrieving task: %s", err)
+		return networkConnectionSection, fmt.Errorf("error retrieving task: %w", err)
 	}
 
 	if err = decodeBody(resp, networkConnectionSection); err != nil {
-		return networkConnectionSection, fmt.Errorf("error decoding task response: %s", err)
+		return networkConnectionSection, fmt.Errorf("error decoding task response: %w", err)
 	}
 
 	// The request was successful
@@ -91,25 +128,31 @@ func (v *VM) GetNetworkConnectionSection() (*types.NetworkConnectionSection, err
 }
 
 func (c *VCDClient) FindVMByHREF(vmhref string) (VM, error) {
+	return c.FindVMByHREFWithContext(context.Background(), vmhref)
+}
+
+// FindVMByHREFWithContext behaves like FindVMByHREF, aborting if ctx is
+// cancelled before vCD answers.
+func (c *VCDClient) FindVMByHREFWithContext(ctx context.Context, vmhref string) (VM, error) {
 
 	u, err := url.ParseRequestURI(vmhref)
 
 	if err != nil {
-		return VM{}, fmt.Errorf("error decoding vm HREF: %s", err)
+		return VM{}, fmt.Errorf("error decoding vm HREF: %w", err)
 	}
 
 	// Querying the VApp
-	req := c.Client.NewRequest(map[string]string{}, "GET", *u, nil)
+	req := c.Client.NewRequestWithContext(ctx, map[string]string{}, "GET", *u, nil)
 
-	resp, err := checkResp(c.Client.Http.Do(req))
+	resp, err := c.Client.doRequest(req)
 	if err != nil {
-		return VM{}, fmt.Errorf("error retrieving VM: %s", err)
+		return VM{}, fmt.Errorf("error retrieving VM: %w", err)
 	}
 
 	newvm := NewVM(&c.Client)
 
 	if err = decodeBody(resp, newvm.VM); err != nil {
-		return VM{}, fmt.Errorf("error decoding VM response: %s", err)
+		return VM{}, fmt.Errorf("error decoding VM response: %w", err)
 	}
 
 	return *newvm, nil
@@ -117,21 +160,27 @@ func (c *VCDClient) FindVMByHREF(vmhref string) (VM, error) {
 }
 
 func (v *VM) PowerOn() (Task, error) {
+	return v.PowerOnWithContext(context.Background())
+}
+
+// PowerOnWithContext powers on the VM. The request is aborted if
+// ctx is cancelled before vCD accepts it.
+func (v *VM) PowerOnWithContext(ctx context.Context) (Task, error) {
 
 	s, _ := url.ParseRequestURI(v.VM.HREF)
 	s.Path += "/power/action/powerOn"
 
-	req := v.c.NewRequest(map[string]string{}, "POST", *s, nil)
+	req := v.c.NewRequestWithContext(ctx, map[string]string{}, "POST", *s, nil)
 
-	resp, err := checkResp(v.c.Http.Do(req))
+	resp, err := v.c.doRequest(req)
 	if err != nil {
-		return Task{}, fmt.Errorf("error powering on VM: %s", err)
+		return Task{}, fmt.Errorf("error powering on VM: %w", err)
 	}
 
 	task := NewTask(v.c)
 
 	if err = decodeBody(resp, task.Task); err != nil {
-		return Task{}, fmt.Errorf("error decoding Task response: %s", err)
+		return Task{}, fmt.Errorf("error decoding Task response: %w", err)
 	}
 
 	// The request was successful
@@ -140,21 +189,27 @@ func (v *VM) PowerOn() (Task, error) {
 }
 
 func (v *VM) PowerOff() (Task, error) {
+	return v.PowerOffWithContext(context.Background())
+}
+
+// PowerOffWithContext powers off the VM. The request is aborted if
+// ctx is cancelled before vCD accepts it.
+func (v *VM) PowerOffWithContext(ctx context.Context) (Task, error) {
 
 	s, _ := url.ParseRequestURI(v.VM.HREF)
 	s.Path += "/power/action/powerOff"
 
-	req := v.c.NewRequest(map[string]string{}, "POST", *s, nil)
+	req := v.c.NewRequestWithContext(ctx, map[string]string{}, "POST", *s, nil)
 
-	resp, err := checkResp(v.c.Http.Do(req))
+	resp, err := v.c.doRequest(req)
 	if err != nil {
-		return Task{}, fmt.Errorf("error powering off VM: %s", err)
+		return Task{}, fmt.Errorf("error powering off VM: %w", err)
 	}
 
 	task := NewTask(v.c)
 
 	if err = decodeBody(resp, task.Task); err != nil {
-		return Task{}, fmt.Errorf("error decoding Task response: %s", err)
+		return Task{}, fmt.Errorf("error decoding Task response: %w", err)
 	}
 
 	// The request was successful
@@ -163,10 +218,16 @@ func (v *VM) PowerOff() (Task, error) {
 }
 
 func (v *VM) ChangeCPUcount(size int) (Task, error) {
+	return v.ChangeCPUcountWithContext(context.Background(), size)
+}
+
+// ChangeCPUcountWithContext behaves like ChangeCPUcount. The request is
+// aborted if ctx is cancelled before vCD accepts it.
+func (v *VM) ChangeCPUcountWithContext(ctx context.Context, size int) (Task, error) {
 
-	err := v.Refresh()
+	err := v.RefreshWithContext(ctx)
 	if err != nil {
-		return Task{}, fmt.Errorf("error refreshing VM before running customization: %v", err)
+		return Task{}, fmt.Errorf("error refreshing VM before running customization: %w", err)
 	}
 
 	newcpu := &types.OVFItem{
@@ -192,13 +253,7 @@ func (v *VM) ChangeCPUcount(size int) (Task, error) {
 
 	output, err := xml.MarshalIndent(newcpu, "  ", "    ")
 	if err != nil {
-		fmt.Printf("error: %v\n", err)
-	}
-
-	debug := os.Getenv("GOVCLOUDAIR_DEBUG")
-
-	if debug == "true" {
-		fmt.Printf("\n\nXML DEBUG: %s\n\n", string(output))
+		v.c.log(LogError, "error marshaling request body", "error", err)
 	}
 
 	b := bytes.NewBufferString(xml.Header + string(output))
@@ -206,19 +261,19 @@ func (v *VM) ChangeCPUcount(size int) (Task, error) {
 	s, _ := url.ParseRequestURI(v.VM.HREF)
 	s.Path += "/virtualHardwareSection/cpu"
 
-	req := v.c.NewRequest(map[string]string{}, "PUT", *s, b)
+	req := v.c.NewRequestWithContext(ctx, map[string]string{}, "PUT", *s, b)
 
 	req.Header.Add("Content-Type", "application/vnd.vmware.vcloud.rasdItem+xml")
 
-	resp, err := checkResp(v.c.Http.Do(req))
+	resp, err := v.c.doRequest(req)
 	if err != nil {
-		return Task{}, fmt.Errorf("error customizing VM: %s", err)
+		return Task{}, fmt.Errorf("error customizing VM: %w", err)
 	}
 
 	task := NewTask(v.c)
 
 	if err = decodeBody(resp, task.Task); err != nil {
-		return Task{}, fmt.Errorf("error decoding Task response: %s", err)
+		return Task{}, fmt.Errorf("error decoding Task response: %w", err)
 	}
 
 	// The request was successful
@@ -227,12 +282,18 @@ func (v *VM) ChangeCPUcount(size int) (Task, error) {
 }
 
 func (v *VM) ChangeNetworkConfig(networks []map[string]interface{}, ip string) (Task, error) {
-	err := v.Refresh()
+	return v.ChangeNetworkConfigWithContext(context.Background(), networks, ip)
+}
+
+// ChangeNetworkConfigWithContext behaves like ChangeNetworkConfig. The
+// request is aborted if ctx is cancelled before vCD accepts it.
+func (v *VM) ChangeNetworkConfigWithContext(ctx context.Context, networks []map[string]interface{}, ip string) (Task, error) {
+	err := v.RefreshWithContext(ctx)
 	if err != nil {
-		return Task{}, fmt.Errorf("error refreshing VM before running customization: %v", err)
+		return Task{}, fmt.Errorf("error refreshing VM before running customization: %w", err)
 	}
 
-	networksection, err := v.GetNetworkConnectionSection()
+	networksection, err := v.GetNetworkConnectionSectionWithContext(ctx)
 
 	for index, network := range networks {
 		// Determine what type of address is requested for the vApp
@@ -258,7 +319,7 @@ func (v *VM) ChangeNetworkConfig(networks []map[string]interface{}, ip string) (
 			ipAllocationMode = "DHCP"
 		}
 
-		log.Printf("[DEBUG] Function ChangeNetworkConfig() for %s invoked", network["orgnetwork"])
+		v.c.log(LogDebug, "changing network configuration", "vm", v.VM.Name, "network", network["orgnetwork"])
 
 		networksection.Xmlns = "http://www.vmware.com/vcloud/v1.5"
 		networksection.Ovf = "http://schemas.dmtf.org/ovf/envelope/1"
@@ -277,29 +338,27 @@ func (v *VM) ChangeNetworkConfig(networks []map[string]interface{}, ip string) (
 
 	output, err := xml.MarshalIndent(networksection, "  ", "    ")
 	if err != nil {
-		fmt.Printf("error: %v\n", err)
+		v.c.log(LogError, "error marshaling request body", "error", err)
 	}
 
-	log.Printf("[DEBUG] NetworkXML: %s", output)
-
 	b := bytes.NewBufferString(xml.Header + string(output))
 
 	s, _ := url.ParseRequestURI(v.VM.HREF)
 	s.Path += "/networkConnectionSection/"
 
-	req := v.c.NewRequest(map[string]string{}, "PUT", *s, b)
+	req := v.c.NewRequestWithContext(ctx, map[string]string{}, "PUT", *s, b)
 
 	req.Header.Add("Content-Type", "application/vnd.vmware.vcloud.networkConnectionSection+xml")
 
-	resp, err := checkResp(v.c.Http.Do(req))
+	resp, err := v.c.doRequest(req)
 	if err != nil {
-		return Task{}, fmt.Errorf("error customizing VM Network: %s", err)
+		return Task{}, fmt.Errorf("error customizing VM Network: %w", err)
 	}
 
 	task := NewTask(v.c)
 
 	if err = decodeBody(resp, task.Task); err != nil {
-		return Task{}, fmt.Errorf("error decoding Task response: %s", err)
+		return Task{}, fmt.Errorf("error decoding Task response: %w", err)
 	}
 
 	// The request was successful
@@ -307,10 +366,16 @@ func (v *VM) ChangeNetworkConfig(networks []map[string]interface{}, ip string) (
 }
 
 func (v *VM) ChangeMemorySize(size int) (Task, error) {
+	return v.ChangeMemorySizeWithContext(context.Background(), size)
+}
 
-	err := v.Refresh()
+// ChangeMemorySizeWithContext behaves like ChangeMemorySize. The request is
+// aborted if ctx is cancelled before vCD accepts it.
+func (v *VM) ChangeMemorySizeWithContext(ctx context.Context, size int) (Task, error) {
+
+	err := v.RefreshWithContext(ctx)
 	if err != nil {
-		return Task{}, fmt.Errorf("error refreshing VM before running customization: %v", err)
+		return Task{}, fmt.Errorf("error refreshing VM before running customization: %w", err)
 	}
 
 	newmem := &types.OVFItem{
@@ -336,13 +401,7 @@ func (v *VM) ChangeMemorySize(size int) (Task, error) {
 
 	output, err := xml.MarshalIndent(newmem, "  ", "    ")
 	if err != nil {
-		fmt.Printf("error: %v\n", err)
-	}
-
-	debug := os.Getenv("GOVCLOUDAIR_DEBUG")
-
-	if debug == "true" {
-		fmt.Printf("\n\nXML DEBUG: %s\n\n", string(output))
+		v.c.log(LogError, "error marshaling request body", "error", err)
 	}
 
 	b := bytes.NewBufferString(xml.Header + string(output))
@@ -350,19 +409,19 @@ func (v *VM) ChangeMemorySize(size int) (Task, error) {
 	s, _ := url.ParseRequestURI(v.VM.HREF)
 	s.Path += "/virtualHardwareSection/memory"
 
-	req := v.c.NewRequest(map[string]string{}, "PUT", *s, b)
+	req := v.c.NewRequestWithContext(ctx, map[string]string{}, "PUT", *s, b)
 
 	req.Header.Add("Content-Type", "application/vnd.vmware.vcloud.rasdItem+xml")
 
-	resp, err := checkResp(v.c.Http.Do(req))
+	resp, err := v.c.doRequest(req)
 	if err != nil {
-		return Task{}, fmt.Errorf("error customizing VM: %s", err)
+		return Task{}, fmt.Errorf("error customizing VM: %w", err)
 	}
 
 	task := NewTask(v.c)
 
 	if err = decodeBody(resp, task.Task); err != nil {
-		return Task{}, fmt.Errorf("error decoding Task response: %s", err)
+		return Task{}, fmt.Errorf("error decoding Task response: %w", err)
 	}
 
 	// The request was successful
@@ -371,13 +430,25 @@ func (v *VM) ChangeMemorySize(size int) (Task, error) {
 }
 
 func (v *VM) RunCustomizationScript(computername, script string) (Task, error) {
-	return v.Customize(computername, script, false)
+	return v.RunCustomizationScriptWithContext(context.Background(), computername, script)
+}
+
+// RunCustomizationScriptWithContext behaves like RunCustomizationScript.
+// The request is aborted if ctx is cancelled before vCD accepts it.
+func (v *VM) RunCustomizationScriptWithContext(ctx context.Context, computername, script string) (Task, error) {
+	return v.CustomizeWithContext(ctx, computername, script, false)
 }
 
 func (v *VM) Customize(computername, script string, changeSid bool) (Task, error) {
-	err := v.Refresh()
+	return v.CustomizeWithContext(context.Background(), computername, script, changeSid)
+}
+
+// CustomizeWithContext behaves like Customize. The request is aborted if
+// ctx is cancelled before vCD accepts it.
+func (v *VM) CustomizeWithContext(ctx context.Context, computername, script string, changeSid bool) (Task, error) {
+	err := v.RefreshWithContext(ctx)
 	if err != nil {
-		return Task{}, fmt.Errorf("error refreshing VM before running customization: %v", err)
+		return Task{}, fmt.Errorf("error refreshing VM before running customization: %w", err)
 	}
 
 	vu := &types.GuestCustomizationSection{
@@ -396,15 +467,7 @@ func (v *VM) Customize(computername, script string, changeSid bool) (Task, error
 
 	output, err := xml.MarshalIndent(vu, "  ", "    ")
 	if err != nil {
-		fmt.Printf("error: %v\n", err)
-	}
-
-	log.Printf("[DEBUG] VCD Client configuration: %s", output)
-
-	debug := os.Getenv("GOVCLOUDAIR_DEBUG")
-
-	if debug == "true" {
-		fmt.Printf("\n\nXML DEBUG: %s\n\n", string(output))
+		v.c.log(LogError, "error marshaling request body", "error", err)
 	}
 
 	b := bytes.NewBufferString(xml.Header + string(output))
@@ -412,19 +475,19 @@ func (v *VM) Customize(computername, script string, changeSid bool) (Task, error
 	s, _ := url.ParseRequestURI(v.VM.HREF)
 	s.Path += "/guestCustomizationSection/"
 
-	req := v.c.NewRequest(map[string]string{}, "PUT", *s, b)
+	req := v.c.NewRequestWithContext(ctx, map[string]string{}, "PUT", *s, b)
 
 	req.Header.Add("Content-Type", "application/vnd.vmware.vcloud.guestCustomizationSection+xml")
 
-	resp, err := checkResp(v.c.Http.Do(req))
+	resp, err := v.c.doRequest(req)
 	if err != nil {
-		return Task{}, fmt.Errorf("error customizing VM: %s", err)
+		return Task{}, fmt.Errorf("error customizing VM: %w", err)
 	}
 
 	task := NewTask(v.c)
 
 	if err = decodeBody(resp, task.Task); err != nil {
-		return Task{}, fmt.Errorf("error decoding Task response: %s", err)
+		return Task{}, fmt.Errorf("error decoding Task response: %w", err)
 	}
 
 	// The request was successful
@@ -432,6 +495,12 @@ func (v *VM) Customize(computername, script string, changeSid bool) (Task, error
 }
 
 func (v *VM) Undeploy() (Task, error) {
+	return v.UndeployWithContext(context.Background())
+}
+
+// UndeployWithContext behaves like Undeploy. The request is aborted if ctx
+// is cancelled before vCD accepts it.
+func (v *VM) UndeployWithContext(ctx context.Context) (Task, error) {
 
 	vu := &types.UndeployVAppParams{
 		Xmlns:               "http://www.vmware.com/vcloud/v1.5",
@@ -440,13 +509,7 @@ func (v *VM) Undeploy() (Task, error) {
 
 	output, err := xml.MarshalIndent(vu, "  ", "    ")
 	if err != nil {
-		fmt.Printf("error: %v\n", err)
-	}
-
-	debug := os.Getenv("GOVCLOUDAIR_DEBUG")
-
-	if debug == "true" {
-		fmt.Printf("\n\nXML DEBUG: %s\n\n", string(output))
+		v.c.log(LogError, "error marshaling request body", "error", err)
 	}
 
 	b := bytes.NewBufferString(xml.Header + string(output))
@@ -454,22 +517,122 @@ func (v *VM) Undeploy() (Task, error) {
 	s, _ := url.ParseRequestURI(v.VM.HREF)
 	s.Path += "/action/undeploy"
 
-	req := v.c.NewRequest(map[string]string{}, "POST", *s, b)
+	req := v.c.NewRequestWithContext(ctx, map[string]string{}, "POST", *s, b)
 
 	req.Header.Add("Content-Type", "application/vnd.vmware.vcloud.undeployVAppParams+xml")
 
-	resp, err := checkResp(v.c.Http.Do(req))
+	resp, err := v.c.doRequest(req)
 	if err != nil {
-		return Task{}, fmt.Errorf("error undeploy vApp: %s", err)
+		return Task{}, fmt.Errorf("error undeploy vApp: %w", err)
 	}
 
 	task := NewTask(v.c)
 
 	if err = decodeBody(resp, task.Task); err != nil {
-		return Task{}, fmt.Errorf("error decoding Task response: %s", err)
+		return Task{}, fmt.Errorf("error decoding Task response: %w", err)
 	}
 
 	// The request was successful
 	return *task, nil
 
 }
+
+// GetDisks returns the disks of the VM, with the controllers they are
+// attached to. The disks have a ResourceType of 17.
+func (v *VM) GetDisks() (*types.RasdItemsList, error) {
+	disksHREF, err := url.ParseRequestURI(v.VM.HREF)
+	if err != nil {
+		return nil, fmt.Errorf("error getting VM HREF %s : %w", v.VM.HREF, err)
+	}
+	disksHREF.Path += "/virtualHardwareSection/disks"
+	req := v.c.NewRequest(map[string]string{}, "GET", *disksHREF, nil)
+	resp, err := v.c.doRequest(req)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving disks of VM %s: %w", v.VM.Name, err)
+	}
+	disks := new(types.RasdItemsList)
+	if err = decodeBody(resp, disks); err != nil {
+		return nil, fmt.Errorf("error decoding disks response: %w", err)
+	}
+	return disks, nil
+}
+
+// ChangeDiskStorageProfile moves the disk of the VM named diskName, e.g.
+// "Hard disk 1", to storageProfile, a storage profile of its VDC. The
+// other disks stay on their storage profile.
+func (v *VM) ChangeDiskStorageProfile(diskName string, storageProfile types.Reference) (Task, error) {
+	disks, err := v.GetDisks()
+	if err != nil {
+		return Task{}, err
+	}
+
+	update := &types.OVFRasdItemsList{
+		XmlnsRasd:   "http://schemas.dmtf.org/wbem/wscim/1/cim-schema/2/CIM_ResourceAllocationSettingData",
+		XmlnsVCloud: "http://www.vmware.com/vcloud/v1.5",
+		XmlnsXsi:    "http://www.w3.org/2001/XMLSchema-instance",
+		VCloudHREF:  disks.HREF,
+		VCloudType:  types.MimeRasdItemsList,
+	}
+	found := false
+	for _, item := range disks.Item {
+		diskItem := &types.OVFDiskItem{
+			Address:         item.Address,
+			Description:     item.Description,
+			ElementName:     item.ElementName,
+			InstanceID:      item.InstanceID,
+			Parent:          item.Parent,
+			ResourceSubType: item.ResourceSubType,
+			ResourceType:    item.ResourceType,
+		}
+		if item.ResourceType == 17 {
+			addressOnParent := item.AddressOnParent
+			diskItem.AddressOnParent = &addressOnParent
+		}
+		for _, hostResource := range item.HostResource {
+			ovfHostResource := &types.OVFHostResource{
+				BusSubType:        hostResource.BusSubType,
+				BusType:           hostResource.BusType,
+				Capacity:          hostResource.Capacity,
+				StorageProfile:    hostResource.StorageProfile,
+				OverrideVmDefault: hostResource.OverrideVmDefault,
+			}
+			if item.ResourceType == 17 && item.ElementName == diskName {
+				ovfHostResource.StorageProfile = storageProfile.HREF
+				ovfHostResource.OverrideVmDefault = true
+				found = true
+			}
+			diskItem.HostResource = append(diskItem.HostResource, ovfHostResource)
+		}
+		update.Item = append(update.Item, diskItem)
+	}
+	if !found {
+		return Task{}, fmt.Errorf("disk %s of VM %s: %w", diskName, v.VM.Name, ErrNotFound)
+	}
+
+	output, err := xml.MarshalIndent(update, "  ", "    ")
+	if err != nil {
+		return Task{}, fmt.Errorf("error encoding disks of VM %s: %w", v.VM.Name, err)
+	}
+	b := bytes.NewBufferString(xml.Header + string(output))
+
+	s, _ := url.ParseRequestURI(v.VM.HREF)
+	s.Path += "/virtualHardwareSection/disks"
+
+	req := v.c.NewRequest(map[string]string{}, "PUT", *s, b)
+
+	req.Header.Add("Content-Type", types.MimeRasdItemsList)
+
+	resp, err := v.c.doRequest(req)
+	if err != nil {
+		return Task{}, fmt.Errorf("error changing storage profile of disk %s: %w", diskName, err)
+	}
+
+	task := NewTask(v.c)
+
+	if err = decodeBody(resp, task.Task); err != nil {
+		return Task{}, fmt.Errorf("error decoding Task response: %w", err)
+	}
+
+	// The request was successful
+	return *task, nil
+}
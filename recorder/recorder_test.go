@@ -0,0 +1,97 @@
+/*
+ * Copyright 2014 VMware, Inc.  All rights reserved.  Licensed under the Apache v2 License.
+ */
+
+package recorder
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRecorder_RecordAndReplay(t *testing.T) {
+	dir, err := ioutil.TempDir("", "recorder")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "cassettes", "test.json")
+
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("x-vcloud-authorization", "secret-token")
+		body, _ := ioutil.ReadAll(r.Body)
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("<Task status=\"running\" call=\"" + string(rune('0'+calls)) + "\">" + string(body) + "</Task>"))
+	}))
+
+	send := func(client *http.Client, body string) (*http.Response, string) {
+		req, _ := http.NewRequest(http.MethodPost, server.URL+"/api/task", strings.NewReader(body))
+		req.SetBasicAuth("user@org", "secret-password")
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("error sending request: %s", err)
+		}
+		defer resp.Body.Close()
+		data, _ := ioutil.ReadAll(resp.Body)
+		return resp, string(data)
+	}
+
+	rec, err := New(path, ModeRecord, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &http.Client{Transport: rec}
+	password := "<AdminPassword>secret-password</AdminPassword>"
+	_, first := send(client, password)
+	_, second := send(client, password)
+	if err = rec.Stop(); err != nil {
+		t.Fatalf("error writing cassette: %s", err)
+	}
+	server.Close()
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("error reading cassette: %s", err)
+	}
+	if strings.Contains(string(data), "secret") {
+		t.Fatalf("cassette contains secrets:\n%s", data)
+	}
+
+	rec, err = New(path, ModeReplay, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client = &http.Client{Transport: rec}
+	// Repeated requests are answered in the recorded order
+	resp, replayed := send(client, password)
+	if resp.StatusCode != http.StatusOK || replayed != strings.Replace(first, "secret-password", Redacted, 1) {
+		t.Fatalf("unexpected first replay: %d %s", resp.StatusCode, replayed)
+	}
+	if resp.Header.Get("x-vcloud-authorization") != Redacted {
+		t.Fatalf("expected a redacted token, got %q", resp.Header.Get("x-vcloud-authorization"))
+	}
+	if _, replayed = send(client, password); replayed != strings.Replace(second, "secret-password", Redacted, 1) {
+		t.Fatalf("unexpected second replay: %s", replayed)
+	}
+	if rec.Unused() != 0 {
+		t.Fatalf("expected all interactions to be used, %d left", rec.Unused())
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/api/task", nil)
+	if _, err = client.Do(req); err == nil || !strings.Contains(err.Error(), "no recorded interaction") {
+		t.Fatalf("expected an error for an unrecorded request, got %v", err)
+	}
+}
+
+func TestRecorder_MissingCassette(t *testing.T) {
+	if _, err := New(filepath.Join(os.TempDir(), "no-such-cassette.json"), ModeReplay, nil); err == nil {
+		t.Fatalf("expected an error loading a missing cassette")
+	}
+}
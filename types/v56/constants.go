@@ -24,6 +24,8 @@ const (
 	NsXMLSchema = "http://www.w3.org/2001/XMLSchema-instance"
 	// NsVCloud vcloud xml namespace url
 	NsVCloud = "http://www.vmware.com/vcloud/v1.5"
+	// NsVCloudExtension vcloud extension xml namespace url
+	NsVCloudExtension = "http://www.vmware.com/vcloud/extension/v1.5"
 )
 
 const (
@@ -51,14 +53,104 @@ const (
 	MimeEntity = "application/vnd.vmware.vcloud.entity+xml"
 	// MimeQueryList mime for query list
 	MimeQueryList = "application/vnd.vmware.vcloud.query.queryList+xml"
+	// MimeQueryResultRecords mime for query results in records format
+	MimeQueryResultRecords = "application/vnd.vmware.vcloud.query.records+xml"
+	// MimeQueryResultReferences mime for query results in references format
+	MimeQueryResultReferences = "application/vnd.vmware.vcloud.query.references+xml"
+	// MimeQueryResultIdRecords mime for query results in idrecords format
+	MimeQueryResultIdRecords = "application/vnd.vmware.vcloud.query.idrecords+xml"
 	// MimeSession mime for a session
 	MimeSession = "application/vnd.vmware.vcloud.session+xml"
 	// MimeTask mime for task
 	MimeTask = "application/vnd.vmware.vcloud.task+xml"
+	// MimeTasksList mime for a list of tasks
+	MimeTasksList = "application/vnd.vmware.vcloud.tasksList+xml"
 	// MimeError mime for error
 	MimeError = "application/vnd.vmware.vcloud.error+xml"
 	// MimeNetwork mime for a network
 	MimeNetwork = "application/vnd.vmware.vcloud.network+xml"
+	// MimeAdminUser mime for a user
+	MimeAdminUser = "application/vnd.vmware.admin.user+xml"
+	// MimeAdminRole mime for a role
+	MimeAdminRole = "application/vnd.vmware.admin.role+xml"
+	// MimeAdminVCloud mime for the admin view of the cloud
+	MimeAdminVCloud = "application/vnd.vmware.admin.vcloud+xml"
+	// MimeAdminRight mime for a right
+	MimeAdminRight = "application/vnd.vmware.admin.right+xml"
+	// MimeControlAccess mime for the access control of a catalog or a vApp
+	MimeControlAccess = "application/vnd.vmware.vcloud.controlAccess+xml"
+	// MimeAdminGroup mime for a group
+	MimeAdminGroup = "application/vnd.vmware.admin.group+xml"
+	// MimeOrgLdapSettings mime for the LDAP settings of an org
+	MimeOrgLdapSettings = "application/vnd.vmware.admin.organizationLdapSettings+xml"
+	// MimeOrgSettings mime for all the settings of an org
+	MimeOrgSettings = "application/vnd.vmware.admin.orgSettings+xml"
+	// MimeOrgGeneralSettings mime for the general settings of an org
+	MimeOrgGeneralSettings = "application/vnd.vmware.admin.organizationGeneralSettings+xml"
+	// MimeVAppLeaseSettings mime for the vApp lease settings of an org
+	MimeVAppLeaseSettings = "application/vnd.vmware.admin.vAppLeaseSettings+xml"
+	// MimeVAppTemplateLeaseSettings mime for the vApp template lease settings of an org
+	MimeVAppTemplateLeaseSettings = "application/vnd.vmware.admin.vAppTemplateLeaseSettings+xml"
+	// MimeOrgEmailSettings mime for the email settings of an org
+	MimeOrgEmailSettings = "application/vnd.vmware.admin.organizationEmailSettings+xml"
+	// MimeOrgPasswordPolicySettings mime for the password policy of an org
+	MimeOrgPasswordPolicySettings = "application/vnd.vmware.admin.organizationPasswordPolicySettings+xml"
+	// MimeGuestPersonalizationSettings mime for the guest personalization settings of an org
+	MimeGuestPersonalizationSettings = "application/vnd.vmware.admin.guestPersonalizationSettings+xml"
+	// MimeOrgFederationSettings mime for the federation settings of an org
+	MimeOrgFederationSettings = "application/vnd.vmware.admin.organizationFederationSettings+xml"
+	// MimeProviderVdc mime for a provider vdc
+	MimeProviderVdc = "application/vnd.vmware.admin.providervdc+xml"
+	// MimeExternalNetwork mime for an external network
+	MimeExternalNetwork = "application/vnd.vmware.admin.vmwexternalnet+xml"
+	// MimeExternalNetworkReferences mime for the list of external networks
+	MimeExternalNetworkReferences = "application/vnd.vmware.admin.vmwExternalNetworkReferences+xml"
+	// MimeNetworkPool mime for a network pool
+	MimeNetworkPool = "application/vnd.vmware.admin.networkPool+xml"
+	// MimeNetworkPoolReferences mime for the list of network pools
+	MimeNetworkPoolReferences = "application/vnd.vmware.admin.vmwNetworkPoolReferences+xml"
+	// MimeAdminVdc mime for the admin view of a vdc
+	MimeAdminVdc = "application/vnd.vmware.admin.vdc+xml"
+	// MimeCreateVdcParams mime for the parameters of a vdc creation
+	MimeCreateVdcParams = "application/vnd.vmware.admin.createVdcParams+xml"
+	// MimeVdcStorageProfile mime for a storage profile of a vdc
+	MimeVdcStorageProfile = "application/vnd.vmware.vcloud.vdcStorageProfile+xml"
+	// MimeAdminVdcStorageProfile mime for the admin view of a storage profile of a vdc
+	MimeAdminVdcStorageProfile = "application/vnd.vmware.admin.vdcStorageProfile+xml"
+	// MimeUpdateVdcStorageProfiles mime for adding and removing storage profiles of a vdc
+	MimeUpdateVdcStorageProfiles = "application/vnd.vmware.admin.updateVdcStorageProfiles+xml"
+	// MimeRasdItemsList mime for a list of virtual hardware items, such as the disks of a vm
+	MimeRasdItemsList = "application/vnd.vmware.vcloud.rasdItemsList+xml"
+)
+
+const (
+	// AllocationVApp is the pay-as-you-go allocation model, the resources
+	// are committed as VMs are powered on
+	AllocationVApp = "AllocationVApp"
+	// AllocationPool commits a percentage of the allocated resources
+	AllocationPool = "AllocationPool"
+	// ReservationPool commits all the allocated resources
+	ReservationPool = "ReservationPool"
+)
+
+const (
+	// ControlAccessReadOnly allows viewing a catalog or a vApp
+	ControlAccessReadOnly = "ReadOnly"
+	// ControlAccessChange allows changing a catalog or a vApp
+	ControlAccessChange = "Change"
+	// ControlAccessFullControl allows changing, deleting and sharing a
+	// catalog or a vApp
+	ControlAccessFullControl = "FullControl"
+)
+
+const (
+	// OrgLdapModeNone the org has no LDAP users or groups
+	OrgLdapModeNone = "NONE"
+	// OrgLdapModeSystem the org uses the LDAP service of the system
+	OrgLdapModeSystem = "SYSTEM"
+	// OrgLdapModeCustom the org uses its own LDAP service, set in
+	// CustomOrgLdapSettings
+	OrgLdapModeCustom = "CUSTOM"
 )
 
 const (
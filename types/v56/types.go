@@ -402,6 +402,19 @@ type Task struct {
 	User             *Reference       `xml:"User,omitempty"`
 }
 
+// TasksList represents a list of tasks.
+// Type: TasksListType
+// Namespace: http://www.vmware.com/vcloud/v1.5
+// Description: Represents a list of tasks.
+// Since: 0.9
+type TasksList struct {
+	HREF string   `xml:"href,attr,omitempty"`
+	Type string   `xml:"type,attr,omitempty"`
+	Name string   `xml:"name,attr,omitempty"`
+	Link LinkList `xml:"Link,omitempty"`
+	Task []*Task  `xml:"Task"`
+}
+
 // CapacityWithUsage represents a capacity and usage of a given resource.
 // Type: CapacityWithUsageType
 // Namespace: http://www.vmware.com/vcloud/v1.5
@@ -539,9 +552,15 @@ type AdminOrg struct {
 	Link         LinkList         `xml:"Link,omitempty"`
 	Tasks        *TasksInProgress `xml:"Tasks,omitempty"`
 	OrgSettings  *OrgSettings     `xml:"Settings,omitempty"`
+	Users        *OrgUserList     `xml:"Users,omitempty"`
+	Groups       *OrgGroupList    `xml:"Groups,omitempty"`
 	Vdcs         *VDCList         `xml:"Vdcs,omitempty"`
 	Networks     *NetworksList    `xml:"Networks,omitempty"`
 	Catalogs     *CatalogsList    `xml:"Catalogs,omitemtpy"`
+	// RightReferences and RoleReferences list the rights available to the
+	// org and the roles local to it. Since: 27.0
+	RightReferences *RightReferences `xml:"RightReferences,omitempty"`
+	RoleReferences  *RoleReferences  `xml:"RoleReferences,omitempty"`
 }
 
 // OrgSettingsType represents the settings for a vCloud Director organization.
@@ -554,10 +573,14 @@ type OrgSettings struct {
 	HREF string `xml:"href,attr,omitempty"` // The URI of the entity.
 	Type string `xml:"type,attr,omitempty"` // The MIME type of the entity.
 	//elements
-	Link                    LinkList                   `xml:"Link,omitempty"`                      // A reference to an entity or operation associated with this object.
-	OrgGeneralSettings      *OrgGeneralSettings        `xml:"OrgGeneralSettings,omitempty"`        // General Settings for the org, not-required
-	OrgVAppTemplateSettings *VAppTemplateLeaseSettings `xml:"VAppTemplateLeaseSettings,omitempty"` // Vapp template lease settings, not required
-	OrgLdapSettings         *OrgLdapSettingsType       `xml:"OrgLdapSettings,omitempty"`           //LDAP settings, not-requried, defaults to none
+	Link                      LinkList                   `xml:"Link,omitempty"`                      // A reference to an entity or operation associated with this object.
+	OrgGeneralSettings        *OrgGeneralSettings        `xml:"OrgGeneralSettings,omitempty"`        // General Settings for the org, not-required
+	OrgVAppLeaseSettings      *VAppLeaseSettings         `xml:"VAppLeaseSettings,omitempty"`         // Vapp lease settings, not required
+	OrgVAppTemplateSettings   *VAppTemplateLeaseSettings `xml:"VAppTemplateLeaseSettings,omitempty"` // Vapp template lease settings, not required
+	OrgLdapSettings           *OrgLdapSettingsType       `xml:"OrgLdapSettings,omitempty"`           //LDAP settings, not-requried, defaults to none
+	OrgEmailSettings          *OrgEmailSettings          `xml:"OrgEmailSettings,omitempty"`          // Email settings, not required
+	OrgPasswordPolicySettings *OrgPasswordPolicySettings `xml:"OrgPasswordPolicySettings,omitempty"` // Password policy, not required
+	OrgFederationSettings     *OrgFederationSettings     `xml:"OrgFederationSettings,omitempty"`     // SAML federation settings, not required
 }
 
 // OrgGeneralSettingsType represents the general settings for a vCloud Director organization.
@@ -570,11 +593,11 @@ type OrgGeneralSettings struct {
 	Type string   `xml:"type,attr,omitempty"` // The MIME type of the entity.
 	Link LinkList `xml:"Link,omitempty"`      // A reference to an entity or operation associated with this object.
 
-	CanPublishCatalogs       bool `xml:"CanPublishCatalogs,omitempty"`
-	DeployedVMQuota          int  `xml:"DeployedVMQuota,omitempty"`
-	StoredVMQuota            int  `xml:"StoredVmQuota,omitempty"`
-	UseServerBootSequence    bool `xml:"UseServerBootSequence,omitempty"`
-	DelayAfterPowerOnSeconds int  `xml:"DelayAfterPowerOnSeconds,omitempty"`
+	CanPublishCatalogs       bool `xml:"CanPublishCatalogs"`
+	DeployedVMQuota          int  `xml:"DeployedVMQuota"` // Number of running VMs of a user, 0 means unlimited
+	StoredVMQuota            int  `xml:"StoredVmQuota"`   // Number of stored VMs of a user, 0 means unlimited
+	UseServerBootSequence    bool `xml:"UseServerBootSequence"`
+	DelayAfterPowerOnSeconds int  `xml:"DelayAfterPowerOnSeconds"`
 }
 
 // VAppTemplateLeaseSettings represents the vapp template lease settings for a vCloud Director organization.
@@ -587,8 +610,101 @@ type VAppTemplateLeaseSettings struct {
 	Type string   `xml:"type,attr,omitempty"` // The MIME type of the entity.
 	Link LinkList `xml:"Link,omitempty"`      // A reference to an entity or operation associated with this object.
 
-	DeleteOnStorageLeaseExpiration bool `xml:"DeleteOnStorageLeaseExpiration,omitempty"`
-	StorageLeaseSeconds            int  `xml:"StorageLeaseSeconds,omitempty"`
+	DeleteOnStorageLeaseExpiration bool `xml:"DeleteOnStorageLeaseExpiration"`
+	StorageLeaseSeconds            int  `xml:"StorageLeaseSeconds"` // Time an expired template is kept, 0 means forever
+}
+
+// VAppLeaseSettings represents the vapp lease settings for a vCloud Director organization.
+// Type: OrgLeaseSettingsType
+// Namespace: http://www.vmware.com/vcloud/v1.5
+// Description: Represents the vapp lease settings of a vCloud Director organization.
+// Since: 0.9
+type VAppLeaseSettings struct {
+	HREF string   `xml:"href,attr,omitempty"` // The URI of the entity.
+	Type string   `xml:"type,attr,omitempty"` // The MIME type of the entity.
+	Link LinkList `xml:"Link,omitempty"`      // A reference to an entity or operation associated with this object.
+
+	DeleteOnStorageLeaseExpiration bool `xml:"DeleteOnStorageLeaseExpiration"`
+	DeploymentLeaseSeconds         int  `xml:"DeploymentLeaseSeconds"` // Time a vapp can run before being undeployed, 0 means never
+	StorageLeaseSeconds            int  `xml:"StorageLeaseSeconds"`    // Time an undeployed vapp is kept, 0 means forever
+}
+
+// OrgEmailSettings represents the email settings for a vCloud Director organization.
+// Type: OrgEmailSettingsType
+// Namespace: http://www.vmware.com/vcloud/v1.5
+// Description: Represents the email settings of a vCloud Director organization.
+// Since: 0.9
+type OrgEmailSettings struct {
+	HREF string   `xml:"href,attr,omitempty"` // The URI of the entity.
+	Type string   `xml:"type,attr,omitempty"` // The MIME type of the entity.
+	Link LinkList `xml:"Link,omitempty"`      // A reference to an entity or operation associated with this object.
+
+	IsDefaultSmtpServer     bool                `xml:"IsDefaultSmtpServer"`          // Use the SMTP server of the system instead of SmtpServerSettings
+	IsDefaultOrgEmail       bool                `xml:"IsDefaultOrgEmail"`            // Use the sender and subject prefix of the system
+	FromEmailAddress        string              `xml:"FromEmailAddress"`             // Sender of the emails of the org
+	DefaultSubjectPrefix    string              `xml:"DefaultSubjectPrefix"`         // Prefix of the subject of the emails of the org
+	IsAlertEmailToAllAdmins bool                `xml:"IsAlertEmailToAllAdmins"`      // Send the alerts to all the org administrators
+	AlertEmailTo            string              `xml:"AlertEmailTo,omitempty"`       // Comma separated recipients of the alerts
+	SmtpServerSettings      *SmtpServerSettings `xml:"SmtpServerSettings,omitempty"` // Needs to be set if IsDefaultSmtpServer is false
+}
+
+// SmtpServerSettings represents the SMTP server used by an organization.
+// The password is never returned by vCD.
+// Type: SmtpServerSettingsType
+// Namespace: http://www.vmware.com/vcloud/v1.5
+// Description: Represents the SMTP server settings.
+// Since: 0.9
+type SmtpServerSettings struct {
+	IsUseAuthentication bool   `xml:"IsUseAuthentication"`
+	Host                string `xml:"Host"`
+	Port                int    `xml:"Port"`
+	Username            string `xml:"Username,omitempty"`
+	Password            string `xml:"Password,omitempty"`
+}
+
+// OrgPasswordPolicySettings represents the password policy for a vCloud Director organization.
+// Type: OrgPasswordPolicySettingsType
+// Namespace: http://www.vmware.com/vcloud/v1.5
+// Description: Represents the password policy of a vCloud Director organization.
+// Since: 0.9
+type OrgPasswordPolicySettings struct {
+	HREF string   `xml:"href,attr,omitempty"` // The URI of the entity.
+	Type string   `xml:"type,attr,omitempty"` // The MIME type of the entity.
+	Link LinkList `xml:"Link,omitempty"`      // A reference to an entity or operation associated with this object.
+
+	AccountLockoutEnabled         bool `xml:"AccountLockoutEnabled"`         // Lock the accounts after too many invalid logins
+	InvalidLoginsBeforeLockout    int  `xml:"InvalidLoginsBeforeLockout"`    // Number of invalid logins locking an account, from 1 to 15
+	AccountLockoutIntervalMinutes int  `xml:"AccountLockoutIntervalMinutes"` // Time an account stays locked
+}
+
+// OrgFederationSettings represents the SAML federation settings for a vCloud Director organization.
+// Type: OrgFederationSettingsType
+// Namespace: http://www.vmware.com/vcloud/v1.5
+// Description: Represents the federation settings of a vCloud Director organization.
+// Since: 1.5
+type OrgFederationSettings struct {
+	HREF string   `xml:"href,attr,omitempty"` // The URI of the entity.
+	Type string   `xml:"type,attr,omitempty"` // The MIME type of the entity.
+	Link LinkList `xml:"Link,omitempty"`      // A reference to an entity or operation associated with this object.
+
+	SAMLMetadata string `xml:"SAMLMetadata,omitempty"` // SAML metadata of the identity provider
+	Enabled      bool   `xml:"Enabled"`                // Allow users to log in with the identity provider
+}
+
+// GuestPersonalizationSettings represents the guest customization settings for a vCloud Director organization.
+// Type: OrgGuestPersonalizationSettingsType
+// Namespace: http://www.vmware.com/vcloud/v1.5
+// Description: Represents the guest personalization settings of a vCloud Director organization.
+// Since: 5.1
+type GuestPersonalizationSettings struct {
+	HREF string   `xml:"href,attr,omitempty"` // The URI of the entity.
+	Type string   `xml:"type,attr,omitempty"` // The MIME type of the entity.
+	Link LinkList `xml:"Link,omitempty"`      // A reference to an entity or operation associated with this object.
+
+	AllowDomainSettings bool   `xml:"AllowDomainSettings"`       // Allow the VMs of the org to join a domain
+	DomainName          string `xml:"DomainName,omitempty"`      // Domain the VMs join
+	AccountUsername     string `xml:"AccountUsername,omitempty"` // Account used to join the domain
+	AccountPassword     string `xml:"AccountPassword,omitempty"` // Password of the account, never returned by vCD
 }
 
 // OrgLdapSettingsType represents the ldap settings for a vCloud Director organization.
@@ -674,6 +790,125 @@ type VDCList struct {
 	Vdcs []*Reference `xml:"Vdc,omitempty"`
 }
 
+// AdminVdc represents the admin view of an organization vDC. It adds to
+// the user view the resource guarantees and the provider vDC and network
+// pool backing the vDC.
+// Type: AdminVdcType
+// Namespace: http://www.vmware.com/vcloud/v1.5
+// Description: Represents the admin view of an organization vDC.
+// Since: 0.9
+type AdminVdc struct {
+	XMLName      xml.Name `xml:"AdminVdc"`
+	Xmlns        string   `xml:"xmlns,attr"`
+	HREF         string   `xml:"href,attr,omitempty"`
+	Type         string   `xml:"type,attr,omitempty"`
+	ID           string   `xml:"id,attr,omitempty"`
+	OperationKey string   `xml:"operationKey,attr,omitempty"`
+	Name         string   `xml:"name,attr"`
+	Status       string   `xml:"status,attr,omitempty"`
+
+	Link               LinkList              `xml:"Link,omitempty"`
+	Description        string                `xml:"Description,omitempty"`
+	Tasks              *TasksInProgress      `xml:"Tasks,omitempty"`
+	AllocationModel    string                `xml:"AllocationModel"`
+	ComputeCapacity    []*ComputeCapacity    `xml:"ComputeCapacity"`
+	ResourceEntities   []*ResourceEntities   `xml:"ResourceEntities,omitempty"`
+	AvailableNetworks  []*AvailableNetworks  `xml:"AvailableNetworks,omitempty"`
+	Capabilities       []*Capabilities       `xml:"Capabilities,omitempty"`
+	NicQuota           int                   `xml:"NicQuota"`
+	NetworkQuota       int                   `xml:"NetworkQuota"`
+	UsedNetworkCount   int                   `xml:"UsedNetworkCount,omitempty"`
+	VMQuota            int                   `xml:"VmQuota"`
+	IsEnabled          bool                  `xml:"IsEnabled"`
+	VdcStorageProfiles []*VdcStorageProfiles `xml:"VdcStorageProfiles,omitempty"`
+
+	ResourceGuaranteedMemory float64    `xml:"ResourceGuaranteedMemory,omitempty"` // Fraction of the memory allocated to VMs that is guaranteed, from 0 to 1
+	ResourceGuaranteedCPU    float64    `xml:"ResourceGuaranteedCpu,omitempty"`    // Fraction of the CPU allocated to VMs that is guaranteed, from 0 to 1
+	VCPUInMhz                int64      `xml:"VCpuInMhz,omitempty"`                // Speed of a vCPU, used to compute the CPU allocated to VMs
+	IsThinProvision          bool       `xml:"IsThinProvision,omitempty"`          // True if the disks of the VMs are thin provisioned
+	NetworkPoolReference     *Reference `xml:"NetworkPoolReference,omitempty"`     // Network pool of the isolated and routed networks
+	ProviderVdcReference     *Reference `xml:"ProviderVdcReference,omitempty"`     // Provider vDC backing the vDC
+	UsesFastProvisioning     bool       `xml:"UsesFastProvisioning,omitempty"`     // True if the VMs are created as linked clones
+}
+
+// CreateVdcParams represents the parameters to create an organization
+// vDC. ComputeCapacity, VdcStorageProfile and ProviderVdcReference are
+// required.
+// Type: CreateVdcParamsType
+// Namespace: http://www.vmware.com/vcloud/v1.5
+// Description: Parameters for creating an organization vDC.
+// Since: 5.1
+type CreateVdcParams struct {
+	XMLName xml.Name `xml:"CreateVdcParams"`
+	Xmlns   string   `xml:"xmlns,attr"`
+	Name    string   `xml:"name,attr"`
+
+	Description              string                     `xml:"Description,omitempty"`
+	AllocationModel          string                     `xml:"AllocationModel"` // One of AllocationVApp, AllocationPool or ReservationPool
+	ComputeCapacity          []*ComputeCapacity         `xml:"ComputeCapacity"`
+	NicQuota                 int                        `xml:"NicQuota"`     // Maximum number of NICs, 0 means unlimited
+	NetworkQuota             int                        `xml:"NetworkQuota"` // Maximum number of networks, 0 means unlimited
+	VMQuota                  int                        `xml:"VmQuota"`      // Maximum number of VMs, 0 means unlimited
+	IsEnabled                bool                       `xml:"IsEnabled"`
+	VdcStorageProfile        []*VdcStorageProfileParams `xml:"VdcStorageProfile"`
+	ResourceGuaranteedMemory float64                    `xml:"ResourceGuaranteedMemory,omitempty"`
+	ResourceGuaranteedCPU    float64                    `xml:"ResourceGuaranteedCpu,omitempty"`
+	VCPUInMhz                int64                      `xml:"VCpuInMhz,omitempty"`
+	IsThinProvision          bool                       `xml:"IsThinProvision,omitempty"`
+	NetworkPoolReference     *Reference                 `xml:"NetworkPoolReference,omitempty"`
+	ProviderVdcReference     *Reference                 `xml:"ProviderVdcReference"`
+	UsesFastProvisioning     bool                       `xml:"UsesFastProvisioning,omitempty"`
+}
+
+// VdcStorageProfileParams represents a storage profile of the provider
+// vDC to give to an organization vDC, with its limit.
+// Type: VdcStorageProfileParamsType
+// Namespace: http://www.vmware.com/vcloud/v1.5
+// Description: Parameters of a storage profile of an organization vDC.
+// Since: 5.1
+type VdcStorageProfileParams struct {
+	Enabled                   bool       `xml:"Enabled"`
+	Units                     string     `xml:"Units"` // Unit of the limit, "MB"
+	Limit                     int64      `xml:"Limit"` // Maximum storage, 0 means unlimited
+	Default                   bool       `xml:"Default"`
+	ProviderVdcStorageProfile *Reference `xml:"ProviderVdcStorageProfile"`
+}
+
+// AdminVdcStorageProfile is the admin view of a storage profile of an
+// organization vDC, with its limit.
+// Type: AdminVdcStorageProfileType
+// Namespace: http://www.vmware.com/vcloud/v1.5
+// Description: Admin representation of a vDC storage profile.
+// Since: 5.1
+type AdminVdcStorageProfile struct {
+	Xmlns string `xml:"xmlns,attr,omitempty"`
+	// Attributes
+	HREF string `xml:"href,attr,omitempty"` // The URI of the entity.
+	Type string `xml:"type,attr,omitempty"` // The MIME type of the entity.
+	ID   string `xml:"id,attr,omitempty"`   // The entity identifier, expressed in URN format.
+	Name string `xml:"name,attr"`           // The name of the entity.
+	// Elements
+	Link                      LinkList   `xml:"Link,omitempty"`
+	Enabled                   bool       `xml:"Enabled"`
+	Units                     string     `xml:"Units"` // Unit of the limit, "MB"
+	Limit                     int64      `xml:"Limit"` // Maximum storage, 0 means unlimited
+	Default                   bool       `xml:"Default"`
+	ProviderVdcStorageProfile *Reference `xml:"ProviderVdcStorageProfile,omitempty"`
+}
+
+// UpdateVdcStorageProfiles adds storage profiles of the provider vDC to
+// an organization vDC, or removes them from it.
+// Type: UpdateVdcStorageProfilesType
+// Namespace: http://www.vmware.com/vcloud/v1.5
+// Description: Parameters to add and remove storage profiles of a vDC.
+// Since: 5.1
+type UpdateVdcStorageProfiles struct {
+	XMLName              xml.Name                   `xml:"UpdateVdcStorageProfiles"`
+	Xmlns                string                     `xml:"xmlns,attr,omitempty"`
+	AddStorageProfile    []*VdcStorageProfileParams `xml:"AddStorageProfile,omitempty"`
+	RemoveStorageProfile []*Reference               `xml:"RemoveStorageProfile,omitempty"`
+}
+
 // NetworksListType contains a list of references to Org Networks
 // Type: NetworksListType
 // Namespace: http://www.vmware.com/vcloud/v1.5
@@ -692,6 +927,376 @@ type CatalogsList struct {
 	Catalog []*Reference `xml:"CatalogReference,omitempty"`
 }
 
+// OrgUserList contains a list of references to the users of an organization.
+// Type: UsersListType
+// Namespace: http://www.vmware.com/vcloud/v1.5
+// Description: Container for references to users in the organization.
+// Since: 0.9
+type OrgUserList struct {
+	User []*Reference `xml:"UserReference,omitempty"`
+}
+
+// OrgGroupList contains a list of references to the groups of an organization.
+// Type: GroupsListType
+// Namespace: http://www.vmware.com/vcloud/v1.5
+// Description: Container for references to groups in the organization.
+// Since: 0.9
+type OrgGroupList struct {
+	Group []*Reference `xml:"GroupReference,omitempty"`
+}
+
+// User represents a user of an organization. Password is only sent when
+// creating the user or changing its password, vCD never returns it. A
+// quota of 0 means unlimited.
+// Type: UserType
+// Namespace: http://www.vmware.com/vcloud/v1.5
+// Description: Represents a user.
+// Since: 0.9
+type User struct {
+	XMLName          xml.Name         `xml:"User"`
+	Xmlns            string           `xml:"xmlns,attr"`
+	HREF             string           `xml:"href,attr,omitempty"`
+	Type             string           `xml:"type,attr,omitempty"`
+	ID               string           `xml:"id,attr,omitempty"`
+	OperationKey     string           `xml:"operationKey,attr,omitempty"`
+	Name             string           `xml:"name,attr"`
+	Link             LinkList         `xml:"Link,omitempty"`
+	Description      string           `xml:"Description,omitempty"`
+	FullName         string           `xml:"FullName,omitempty"`
+	EmailAddress     string           `xml:"EmailAddress,omitempty"`
+	Telephone        string           `xml:"Telephone,omitempty"`
+	IsEnabled        bool             `xml:"IsEnabled"`
+	IsLocked         bool             `xml:"IsLocked"`
+	IM               string           `xml:"IM,omitempty"`
+	NameInSource     string           `xml:"NameInSource,omitempty"`
+	IsAlertEnabled   bool             `xml:"IsAlertEnabled,omitempty"`
+	AlertEmailPrefix string           `xml:"AlertEmailPrefix,omitempty"`
+	AlertEmail       string           `xml:"AlertEmail,omitempty"`
+	IsExternal       bool             `xml:"IsExternal,omitempty"`
+	IsDefaultCached  bool             `xml:"IsDefaultCached,omitempty"`
+	IsGroupRole      bool             `xml:"IsGroupRole,omitempty"`
+	StoredVmQuota    int              `xml:"StoredVmQuota"`
+	DeployedVmQuota  int              `xml:"DeployedVmQuota"`
+	Role             *Reference       `xml:"Role,omitempty"`
+	Password         string           `xml:"Password,omitempty"`
+	GroupReferences  *GroupReferences `xml:"GroupReferences,omitempty"`
+}
+
+// GroupReferences contains a list of references to the groups of a user.
+// Type: GroupsListType
+// Namespace: http://www.vmware.com/vcloud/v1.5
+// Description: Container for references to groups.
+// Since: 0.9
+type GroupReferences struct {
+	GroupReference []*Reference `xml:"GroupReference,omitempty"`
+}
+
+// Group represents a group of an organization, imported from its LDAP
+// service. The users of the group get its role.
+// Type: GroupType
+// Namespace: http://www.vmware.com/vcloud/v1.5
+// Description: Represents a group in the organization.
+// Since: 0.9
+type Group struct {
+	XMLName      xml.Name   `xml:"Group"`
+	Xmlns        string     `xml:"xmlns,attr"`
+	HREF         string     `xml:"href,attr,omitempty"`
+	Type         string     `xml:"type,attr,omitempty"`
+	ID           string     `xml:"id,attr,omitempty"`
+	OperationKey string     `xml:"operationKey,attr,omitempty"`
+	Name         string     `xml:"name,attr"`
+	Link         LinkList   `xml:"Link,omitempty"`
+	Description  string     `xml:"Description,omitempty"`
+	NameInSource string     `xml:"NameInSource,omitempty"` // Name of the group in the LDAP service
+	UsersList    *UsersList `xml:"UsersList,omitempty"`    // Users of the group, read only
+	Role         *Reference `xml:"Role,omitempty"`
+	ProviderType string     `xml:"ProviderType,omitempty"` // Source of the group, INTEGRATED for LDAP
+}
+
+// UsersList contains a list of references to the users of a group.
+// Type: UsersListType
+// Namespace: http://www.vmware.com/vcloud/v1.5
+// Description: Container for references to users.
+// Since: 0.9
+type UsersList struct {
+	UserReference []*Reference `xml:"UserReference,omitempty"`
+}
+
+// VCloud represents the vCloud Director installation, as returned by the
+// admin API root, /api/admin. It references the top level entities
+// managed by system administrators.
+// Type: VCloudType
+// Namespace: http://www.vmware.com/vcloud/v1.5
+// Description: Represents the admin view of this cloud.
+// Since: 0.9
+type VCloud struct {
+	XMLName                xml.Name                `xml:"VCloud"`
+	Xmlns                  string                  `xml:"xmlns,attr"`
+	HREF                   string                  `xml:"href,attr,omitempty"`
+	Type                   string                  `xml:"type,attr,omitempty"`
+	Name                   string                  `xml:"name,attr"`
+	Link                   LinkList                `xml:"Link,omitempty"`
+	Description            string                  `xml:"Description,omitempty"`
+	OrganizationReferences *OrganizationReferences `xml:"OrganizationReferences,omitempty"`
+	ProviderVdcReferences  *ProviderVdcReferences  `xml:"ProviderVdcReferences,omitempty"`
+	RightReferences        *RightReferences        `xml:"RightReferences,omitempty"`
+	RoleReferences         *RoleReferences         `xml:"RoleReferences,omitempty"`
+	Networks               *Networks               `xml:"Networks,omitempty"`
+}
+
+// Networks contains a list of references to the external networks.
+// Type: NetworksType
+// Namespace: http://www.vmware.com/vcloud/v1.5
+// Description: Container for references to networks.
+// Since: 0.9
+type Networks struct {
+	Network []*Reference `xml:"Network,omitempty"`
+}
+
+// RoleReferences contains a list of references to roles.
+// Type: RoleReferencesType
+// Namespace: http://www.vmware.com/vcloud/v1.5
+// Description: Container for references to roles.
+// Since: 0.9
+type RoleReferences struct {
+	RoleReference []*Reference `xml:"RoleReference,omitempty"`
+}
+
+// RightReferences contains a list of references to rights.
+// Type: RightReferencesType
+// Namespace: http://www.vmware.com/vcloud/v1.5
+// Description: Container for references to rights.
+// Since: 0.9
+type RightReferences struct {
+	RightReference []*Reference `xml:"RightReference,omitempty"`
+}
+
+// Role represents a role, a named set of rights given to users and groups.
+// Roles are either global, defined by the system administrator, or local
+// to an org.
+// Type: RoleType
+// Namespace: http://www.vmware.com/vcloud/v1.5
+// Description: Represents a role.
+// Since: 0.9
+type Role struct {
+	XMLName         xml.Name         `xml:"Role"`
+	Xmlns           string           `xml:"xmlns,attr"`
+	HREF            string           `xml:"href,attr,omitempty"`
+	Type            string           `xml:"type,attr,omitempty"`
+	ID              string           `xml:"id,attr,omitempty"`
+	OperationKey    string           `xml:"operationKey,attr,omitempty"`
+	Name            string           `xml:"name,attr"`
+	Link            LinkList         `xml:"Link,omitempty"`
+	Description     string           `xml:"Description,omitempty"`
+	RightReferences *RightReferences `xml:"RightReferences,omitempty"`
+}
+
+// Right represents a right, the permission to perform an operation.
+// Rights are defined by the system and cannot be changed.
+// Type: RightType
+// Namespace: http://www.vmware.com/vcloud/v1.5
+// Description: Represents a right.
+// Since: 0.9
+type Right struct {
+	XMLName      xml.Name `xml:"Right"`
+	Xmlns        string   `xml:"xmlns,attr"`
+	HREF         string   `xml:"href,attr,omitempty"`
+	Type         string   `xml:"type,attr,omitempty"`
+	ID           string   `xml:"id,attr,omitempty"`
+	OperationKey string   `xml:"operationKey,attr,omitempty"`
+	Name         string   `xml:"name,attr"`
+	Link         LinkList `xml:"Link,omitempty"`
+	Description  string   `xml:"Description,omitempty"`
+	Category     string   `xml:"Category,omitempty"`  // Category of the right, e.g. "vApp"
+	BundleKey    string   `xml:"BundleKey,omitempty"` // Key of the localized name of the right
+}
+
+// OrganizationReferences contains a list of references to organizations.
+// Type: OrganizationReferencesType
+// Namespace: http://www.vmware.com/vcloud/v1.5
+// Description: Container for references to organizations.
+// Since: 0.9
+type OrganizationReferences struct {
+	OrganizationReference []*Reference `xml:"OrganizationReference,omitempty"`
+}
+
+// ProviderVdcReferences contains a list of references to provider vDCs.
+// Type: ProviderVdcReferencesType
+// Namespace: http://www.vmware.com/vcloud/v1.5
+// Description: Container for references to provider vDCs.
+// Since: 0.9
+type ProviderVdcReferences struct {
+	ProviderVdcReference []*Reference `xml:"ProviderVdcReference,omitempty"`
+}
+
+// ExternalNetworkReferences contains a list of references to external
+// networks, as returned by /api/admin/extension/externalNetworkReferences.
+// Type: VMWExternalNetworkReferencesType
+// Namespace: http://www.vmware.com/vcloud/extension/v1.5
+// Description: A list of references to external networks.
+// Since: 1.0
+type ExternalNetworkReferences struct {
+	ExternalNetworkReference []*Reference `xml:"ExternalNetworkReference,omitempty"`
+}
+
+// NetworkPoolReferences contains a list of references to network pools.
+// Type: VMWNetworkPoolReferencesType
+// Namespace: http://www.vmware.com/vcloud/extension/v1.5
+// Description: A list of references to network pools.
+// Since: 1.0
+type NetworkPoolReferences struct {
+	NetworkPoolReference []*Reference `xml:"NetworkPoolReference,omitempty"`
+}
+
+// ProviderVdc represents the admin view of a provider vDC, the compute,
+// memory and storage resources of a vCenter offered to org vDCs.
+// Type: ProviderVdcType
+// Namespace: http://www.vmware.com/vcloud/v1.5
+// Description: Represents a provider vDC.
+// Since: 0.9
+type ProviderVdc struct {
+	HREF         string `xml:"href,attr,omitempty"`
+	Type         string `xml:"type,attr,omitempty"`
+	ID           string `xml:"id,attr,omitempty"`
+	OperationKey string `xml:"operationKey,attr,omitempty"`
+	Name         string `xml:"name,attr"`
+
+	Link                  LinkList                 `xml:"Link,omitempty"`
+	Description           string                   `xml:"Description,omitempty"`
+	Tasks                 *TasksInProgress         `xml:"Tasks,omitempty"`
+	ComputeCapacity       *RootComputeCapacity     `xml:"ComputeCapacity,omitempty"`       // Compute capacity of the provider vDC
+	StorageCapacity       *ProviderVdcCapacity     `xml:"StorageCapacity,omitempty"`       // Storage capacity, before storage profiles
+	AvailableNetworks     *AvailableNetworks       `xml:"AvailableNetworks,omitempty"`     // External networks of the provider vDC
+	StorageProfiles       *ProviderStorageProfiles `xml:"StorageProfiles,omitempty"`       // Storage profiles of the provider vDC
+	Capabilities          *Capabilities            `xml:"Capabilities,omitempty"`          // Virtual hardware versions supported
+	Vdcs                  *VDCList                 `xml:"Vdcs,omitempty"`                  // Org vDCs using the provider vDC
+	IsEnabled             bool                     `xml:"IsEnabled"`                       // True if the provider vDC can be used
+	NetworkPoolReferences *NetworkPoolReferences   `xml:"NetworkPoolReferences,omitempty"` // Network pools of the provider vDC
+}
+
+// RootComputeCapacity represents the compute capacity of a provider vDC.
+// Type: RootComputeCapacityType
+// Namespace: http://www.vmware.com/vcloud/v1.5
+// Description: Represents compute capacity with units.
+// Since: 0.9
+type RootComputeCapacity struct {
+	CPU       *ProviderVdcCapacity `xml:"Cpu"`
+	Memory    *ProviderVdcCapacity `xml:"Memory"`
+	IsElastic bool                 `xml:"IsElastic,omitempty"`
+	IsHA      bool                 `xml:"IsHA,omitempty"`
+}
+
+// ProviderVdcCapacity represents the capacity of a provider vDC resource
+// and how much of it is allocated to org vDCs and used.
+// Type: ProviderVdcCapacityType
+// Namespace: http://www.vmware.com/vcloud/v1.5
+// Description: Represents resource capacity in a provider vDC.
+// Since: 0.9
+type ProviderVdcCapacity struct {
+	Units      string `xml:"Units"`
+	Allocation int64  `xml:"Allocation,omitempty"`
+	Reserved   int64  `xml:"Reserved,omitempty"`
+	Total      int64  `xml:"Total,omitempty"`
+	Used       int64  `xml:"Used,omitempty"`
+	Overhead   int64  `xml:"Overhead,omitempty"`
+}
+
+// ProviderStorageProfiles contains a list of references to the storage
+// profiles of a provider vDC.
+// Type: ProviderVdcStorageProfilesType
+// Namespace: http://www.vmware.com/vcloud/v1.5
+// Description: Container for references to storage profiles of a provider vDC.
+// Since: 5.1
+type ProviderStorageProfiles struct {
+	ProviderVdcStorageProfile []*Reference `xml:"ProviderVdcStorageProfile,omitempty"`
+}
+
+// ExternalNetwork represents an external network, backed by a vSphere
+// port group, that org vDC networks and edge gateways connect to. When
+// creating one, Name, Configuration with its IpScopes, and
+// VimPortGroupRef are required.
+// Type: VMWExternalNetworkType
+// Namespace: http://www.vmware.com/vcloud/extension/v1.5
+// Description: External network type.
+// Since: 1.0
+type ExternalNetwork struct {
+	HREF         string `xml:"href,attr,omitempty"`
+	Type         string `xml:"type,attr,omitempty"`
+	ID           string `xml:"id,attr,omitempty"`
+	OperationKey string `xml:"operationKey,attr,omitempty"`
+	Name         string `xml:"name,attr"`
+
+	Link            LinkList              `xml:"Link,omitempty"`
+	Description     string                `xml:"Description,omitempty"`
+	Tasks           *TasksInProgress      `xml:"Tasks,omitempty"`
+	Configuration   *NetworkConfiguration `xml:"Configuration,omitempty"`
+	VimPortGroupRef *VimObjectRef         `xml:"http://www.vmware.com/vcloud/extension/v1.5 VimPortGroupRef,omitempty"` // Port group backing the network
+}
+
+// VimObjectRef represents a reference to an object of a vCenter server.
+// Type: VimObjectRefType
+// Namespace: http://www.vmware.com/vcloud/extension/v1.5
+// Description: Represents the VIM object reference.
+// Since: 1.0
+type VimObjectRef struct {
+	VimServerRef  *Reference `xml:"VimServerRef"`  // The vCenter server
+	MoRef         string     `xml:"MoRef"`         // Managed object reference of the object, e.g. "dvportgroup-42"
+	VimObjectType string     `xml:"VimObjectType"` // Type of the object, e.g. "DV_PORTGROUP" or "NETWORK"
+}
+
+// NetworkPool represents a network pool, from which org vDCs get the
+// networks backing their isolated and routed networks. PoolType is the
+// schema type of the pool, e.g. "vmext:VlanPoolType".
+// Type: VMWNetworkPoolType
+// Namespace: http://www.vmware.com/vcloud/extension/v1.5
+// Description: Represents a network pool.
+// Since: 1.0
+type NetworkPool struct {
+	HREF         string `xml:"href,attr,omitempty"`
+	Type         string `xml:"type,attr,omitempty"`
+	ID           string `xml:"id,attr,omitempty"`
+	OperationKey string `xml:"operationKey,attr,omitempty"`
+	Name         string `xml:"name,attr"`
+	PoolType     string `xml:"http://www.w3.org/2001/XMLSchema-instance type,attr,omitempty"`
+
+	Link        LinkList         `xml:"Link,omitempty"`
+	Description string           `xml:"Description,omitempty"`
+	Tasks       *TasksInProgress `xml:"Tasks,omitempty"`
+}
+
+// ControlAccessParams specifies who can access a catalog or a vApp, and
+// how. EveryoneAccessLevel is required when IsSharedToEveryone is set.
+// Type: ControlAccessParamsType
+// Namespace: http://www.vmware.com/vcloud/v1.5
+// Description: Used to control access to resources.
+// Since: 0.9
+type ControlAccessParams struct {
+	XMLName             xml.Name           `xml:"ControlAccessParams"`
+	Xmlns               string             `xml:"xmlns,attr"`
+	IsSharedToEveryone  bool               `xml:"IsSharedToEveryone"`
+	EveryoneAccessLevel string             `xml:"EveryoneAccessLevel,omitempty"` // One of ReadOnly, Change and FullControl
+	AccessSettings      *AccessSettingList `xml:"AccessSettings,omitempty"`
+}
+
+// AccessSettingList contains a list of access settings.
+// Type: AccessSettingsType
+// Namespace: http://www.vmware.com/vcloud/v1.5
+// Description: A list of access settings for a resource.
+// Since: 0.9
+type AccessSettingList struct {
+	AccessSetting []*AccessSetting `xml:"AccessSetting,omitempty"`
+}
+
+// AccessSetting gives a user or a group an access level to a resource.
+// Type: AccessSettingType
+// Namespace: http://www.vmware.com/vcloud/v1.5
+// Description: Specifies who can access the resource.
+// Since: 0.9
+type AccessSetting struct {
+	Subject     *Reference `xml:"Subject"`     // Reference to the user or the group
+	AccessLevel string     `xml:"AccessLevel"` // One of ReadOnly, Change and FullControl
+}
+
 // CatalogItem contains a reference to a VappTemplate or Media object and related metadata.
 // Type: CatalogItemType
 // Namespace: http://www.vmware.com/vcloud/v1.5
@@ -1153,6 +1758,7 @@ type VirtualHardwareItem struct {
 	AutomaticAllocation bool                           `xml:"AutomaticAllocation,omitempty"`
 	Address             string                         `xml:"Address,omitempty"`
 	AddressOnParent     int                            `xml:"AddressOnParent,omitempty"`
+	Parent              int                            `xml:"Parent,omitempty"`
 	AllocationUnits     string                         `xml:"AllocationUnits,omitempty"`
 	Reservation         int                            `xml:"Reservation,omitempty"`
 	VirtualQuantity     int                            `xml:"VirtualQuantity,omitempty"`
@@ -1216,6 +1822,55 @@ type OVFItem struct {
 	Link            *Link    `xml:"vcloud:Link"`
 }
 
+// RasdItemsList is a list of items of the virtual hardware of a VM, such
+// as its disks and their controllers.
+// Type: RasdItemsListType
+// Namespace: http://www.vmware.com/vcloud/v1.5
+// Description: A list of RASD items.
+// Since: 0.9
+type RasdItemsList struct {
+	XMLName xml.Name               `xml:"RasdItemsList"`
+	HREF    string                 `xml:"href,attr,omitempty"`
+	Type    string                 `xml:"type,attr,omitempty"`
+	Item    []*VirtualHardwareItem `xml:"Item,omitempty"`
+}
+
+// OVFRasdItemsList is a RasdItemsList of disks, encoded with the prefixes
+// vCD expects the way OVFItem is.
+type OVFRasdItemsList struct {
+	XMLName     xml.Name       `xml:"vcloud:RasdItemsList"`
+	XmlnsRasd   string         `xml:"xmlns:rasd,attr"`
+	XmlnsVCloud string         `xml:"xmlns:vcloud,attr"`
+	XmlnsXsi    string         `xml:"xmlns:xsi,attr"`
+	VCloudHREF  string         `xml:"vcloud:href,attr,omitempty"`
+	VCloudType  string         `xml:"vcloud:type,attr"`
+	Item        []*OVFDiskItem `xml:"vcloud:Item"`
+}
+
+// OVFDiskItem is a disk or a disk controller of an OVFRasdItemsList.
+// AddressOnParent is only set on disks, where 0 is a valid address.
+type OVFDiskItem struct {
+	Address         string             `xml:"rasd:Address,omitempty"`
+	AddressOnParent *int               `xml:"rasd:AddressOnParent,omitempty"`
+	Description     string             `xml:"rasd:Description,omitempty"`
+	ElementName     string             `xml:"rasd:ElementName"`
+	HostResource    []*OVFHostResource `xml:"rasd:HostResource,omitempty"`
+	InstanceID      int                `xml:"rasd:InstanceID"`
+	Parent          int                `xml:"rasd:Parent,omitempty"`
+	ResourceSubType string             `xml:"rasd:ResourceSubType,omitempty"`
+	ResourceType    int                `xml:"rasd:ResourceType"`
+}
+
+// OVFHostResource is the backing of a disk of an OVFDiskItem. Capacity is
+// in MB.
+type OVFHostResource struct {
+	BusSubType        string `xml:"vcloud:busSubType,attr,omitempty"`
+	BusType           int    `xml:"vcloud:busType,attr,omitempty"`
+	Capacity          int    `xml:"vcloud:capacity,attr,omitempty"`
+	StorageProfile    string `xml:"vcloud:storageProfileHref,attr,omitempty"`
+	OverrideVmDefault bool   `xml:"vcloud:storageProfileOverrideVmDefault,attr,omitempty"`
+}
+
 // DeployVAppParams are the parameters to a deploy vApp request
 // Type: DeployVAppParamsType
 // Namespace: http://www.vmware.com/vcloud/v1.5
@@ -1823,6 +2478,49 @@ type QueryResultRecordsType struct {
 	VMRecord                   []*QueryResultVMRecordType                   `xml:"VMRecord"`                   // A record representing a VM result.
 	VAppRecord                 []*QueryResultVAppRecordType                 `xml:"VAppRecord"`                 // A record representing a VApp result.
 	OrgVdcStorageProfileRecord []*QueryResultOrgVdcStorageProfileRecordType `xml:"OrgVdcStorageProfileRecord"` // A record representing storage profiles
+	CatalogRecord              []*QueryResultCatalogRecordType              `xml:"CatalogRecord"`              // A record representing a catalog.
+	CatalogItemRecord          []*QueryResultCatalogItemRecordType          `xml:"CatalogItemRecord"`          // A record representing a catalog item.
+	VAppTemplateRecord         []*QueryResultVAppTemplateRecordType         `xml:"VAppTemplateRecord"`         // A record representing a vApp template.
+	OrgVdcNetworkRecord        []*QueryResultOrgVdcNetworkRecordType        `xml:"OrgVdcNetworkRecord"`        // A record representing an org VDC network.
+	AdminVdcRecord             []*QueryResultVdcRecordType                  `xml:"AdminVdcRecord"`             // A record representing a VDC, returned by adminOrgVdc queries.
+	OrgVdcRecord               []*QueryResultVdcRecordType                  `xml:"OrgVdcRecord"`               // A record representing a VDC, returned by orgVdc queries.
+	MediaRecord                []*QueryResultMediaRecordType                `xml:"MediaRecord"`                // A record representing a media.
+	DiskRecord                 []*QueryResultDiskRecordType                 `xml:"DiskRecord"`                 // A record representing an independent disk.
+	UserRecord                 []*QueryResultUserRecordType                 `xml:"UserRecord"`                 // A record representing a user.
+	GroupRecord                []*QueryResultGroupRecordType                `xml:"GroupRecord"`                // A record representing a group.
+	TaskRecord                 []*QueryResultTaskRecordType                 `xml:"TaskRecord"`                 // A record representing a task.
+	EventRecord                []*QueryResultEventRecordType                `xml:"EventRecord"`                // A record representing an event.
+	NetworkRecord              []*QueryResultNetworkRecordType              `xml:"NetworkRecord"`              // A record representing an external network, returned by externalNetwork queries.
+}
+
+// QueryResultReferencesType is a container for query results in references format.
+// Type: QueryResultReferencesType
+// Namespace: http://www.vmware.com/vcloud/v1.5
+// Description: Container for query results in references format.
+// Since: 1.5
+type QueryResultReferencesType struct {
+	// Attributes
+	HREF     string  `xml:"href,attr,omitempty"`     // The URI of the entity.
+	Type     string  `xml:"type,attr,omitempty"`     // The MIME type of the entity.
+	Name     string  `xml:"name,attr,omitempty"`     // The name of the entity.
+	Page     int     `xml:"page,attr,omitempty"`     // Page of the result set that this container holds. The first page is page number 1.
+	PageSize int     `xml:"pageSize,attr,omitempty"` // Page size, as a number of records or references.
+	Total    float64 `xml:"total,attr,omitempty"`    // Total number of records or references in the container.
+	// Elements
+	Link      []*Link      `xml:"Link,omitempty"` // A reference to an entity or operation associated with this object.
+	Reference []*Reference `xml:",any"`           // The references of the results, e.g. VAppReference elements for a vApp query.
+}
+
+// QueryList lists the queries available to the user. Every query type has
+// a link, named after the type, for each of the query formats.
+// Type: QueryListType
+// Namespace: http://www.vmware.com/vcloud/v1.5
+// Description: Container for the list of typed queries available to the requesting user.
+// Since: 1.5
+type QueryList struct {
+	HREF string  `xml:"href,attr,omitempty"` // The URI of the entity.
+	Type string  `xml:"type,attr,omitempty"` // The MIME type of the entity.
+	Link []*Link `xml:"Link,omitempty"`      // Links to the queries.
 }
 
 // QueryResultEdgeGatewayRecordType represents an edge gateway record as query result.
@@ -1918,3 +2616,241 @@ type QueryResultOrgVdcStorageProfileRecordType struct {
 	StorageUsedMB           int    `xml:"storageUsedMB,attr,omitempty"`
 	StorageLimitMB          int    `xml:"storageLimitMB,attr,omitempty"`
 }
+
+// QueryResultCatalogRecordType represents a catalog as query result.
+type QueryResultCatalogRecordType struct {
+	// Attributes
+	HREF                  string `xml:"href,attr,omitempty"`                  // The URI of the entity.
+	ID                    string `xml:"id,attr,omitempty"`                    // The entity identifier, in idrecords format.
+	Name                  string `xml:"name,attr,omitempty"`                  // Catalog name.
+	Description           string `xml:"description,attr,omitempty"`           // Catalog description.
+	IsPublished           bool   `xml:"isPublished,attr,omitempty"`           // True if the catalog is published.
+	IsShared              bool   `xml:"isShared,attr,omitempty"`              // True if the catalog is shared.
+	CreationDate          string `xml:"creationDate,attr,omitempty"`          // Creation date of the catalog.
+	OrgName               string `xml:"orgName,attr,omitempty"`               // Name of the organization owning the catalog.
+	OwnerName             string `xml:"ownerName,attr,omitempty"`             // Name of the catalog owner.
+	NumberOfVAppTemplates int    `xml:"numberOfVAppTemplates,attr,omitempty"` // Number of vApp templates in the catalog.
+	NumberOfMedia         int    `xml:"numberOfMedia,attr,omitempty"`         // Number of media in the catalog.
+	Owner                 string `xml:"owner,attr,omitempty"`                 // Reference to the catalog owner.
+}
+
+// QueryResultCatalogItemRecordType represents a catalog item as query result.
+type QueryResultCatalogItemRecordType struct {
+	// Attributes
+	HREF         string `xml:"href,attr,omitempty"`         // The URI of the entity.
+	ID           string `xml:"id,attr,omitempty"`           // The entity identifier, in idrecords format.
+	Name         string `xml:"name,attr,omitempty"`         // Catalog item name.
+	Entity       string `xml:"entity,attr,omitempty"`       // Reference to the vApp template or media of the item.
+	EntityName   string `xml:"entityName,attr,omitempty"`   // Name of the vApp template or media of the item.
+	EntityType   string `xml:"entityType,attr,omitempty"`   // Type of the entity, e.g. vapptemplate or media.
+	Catalog      string `xml:"catalog,attr,omitempty"`      // Reference to the catalog.
+	CatalogName  string `xml:"catalogName,attr,omitempty"`  // Name of the catalog.
+	Vdc          string `xml:"vdc,attr,omitempty"`          // Reference to the VDC storing the entity.
+	VdcName      string `xml:"vdcName,attr,omitempty"`      // Name of the VDC storing the entity.
+	Owner        string `xml:"owner,attr,omitempty"`        // Reference to the owner of the item.
+	OwnerName    string `xml:"ownerName,attr,omitempty"`    // Name of the owner of the item.
+	Status       string `xml:"status,attr,omitempty"`       // Status of the entity.
+	CreationDate string `xml:"creationDate,attr,omitempty"` // Creation date of the item.
+	IsPublished  bool   `xml:"isPublished,attr,omitempty"`  // True if the catalog is published.
+	IsVdcEnabled bool   `xml:"isVdcEnabled,attr,omitempty"` // True if the VDC storing the entity is enabled.
+}
+
+// QueryResultVAppTemplateRecordType represents a vApp template as query result.
+type QueryResultVAppTemplateRecordType struct {
+	// Attributes
+	HREF               string `xml:"href,attr,omitempty"`               // The URI of the entity.
+	ID                 string `xml:"id,attr,omitempty"`                 // The entity identifier, in idrecords format.
+	Name               string `xml:"name,attr,omitempty"`               // vApp template name.
+	Org                string `xml:"org,attr,omitempty"`                // Reference to the organization.
+	Vdc                string `xml:"vdc,attr,omitempty"`                // Reference to the VDC storing the template.
+	VdcName            string `xml:"vdcName,attr,omitempty"`            // Name of the VDC storing the template.
+	CatalogName        string `xml:"catalogName,attr,omitempty"`        // Name of the catalog holding the template.
+	OwnerName          string `xml:"ownerName,attr,omitempty"`          // Name of the template owner.
+	Status             string `xml:"status,attr,omitempty"`             // Status of the template.
+	StorageProfileName string `xml:"storageProfileName,attr,omitempty"` // Name of the storage profile of the template.
+	CreationDate       string `xml:"creationDate,attr,omitempty"`       // Creation date of the template.
+	IsBusy             bool   `xml:"isBusy,attr,omitempty"`             // True if the template is busy.
+	IsDeployed         bool   `xml:"isDeployed,attr,omitempty"`         // True if the template is deployed.
+	IsEnabled          bool   `xml:"isEnabled,attr,omitempty"`          // True if the template is enabled.
+	IsExpired          bool   `xml:"isExpired,attr,omitempty"`          // True if the template is expired.
+	IsGoldMaster       bool   `xml:"isGoldMaster,attr,omitempty"`       // True if the template is a gold master.
+	IsPublished        bool   `xml:"isPublished,attr,omitempty"`        // True if the catalog holding the template is published.
+}
+
+// QueryResultOrgVdcNetworkRecordType represents an org VDC network as query result.
+type QueryResultOrgVdcNetworkRecordType struct {
+	// Attributes
+	HREF               string `xml:"href,attr,omitempty"`               // The URI of the entity.
+	ID                 string `xml:"id,attr,omitempty"`                 // The entity identifier, in idrecords format.
+	Name               string `xml:"name,attr,omitempty"`               // Network name.
+	DefaultGateway     string `xml:"defaultGateway,attr,omitempty"`     // Default gateway of the network.
+	Netmask            string `xml:"netmask,attr,omitempty"`            // Netmask of the network.
+	Dns1               string `xml:"dns1,attr,omitempty"`               // Primary DNS server.
+	Dns2               string `xml:"dns2,attr,omitempty"`               // Secondary DNS server.
+	DnsSuffix          string `xml:"dnsSuffix,attr,omitempty"`          // DNS suffix.
+	LinkType           int    `xml:"linkType,attr,omitempty"`           // 0 for a direct network, 1 for a routed one, 2 for an isolated one.
+	ConnectedTo        string `xml:"connectedTo,attr,omitempty"`        // Name of the edge gateway or external network the network is connected to.
+	Vdc                string `xml:"vdc,attr,omitempty"`                // Reference to the VDC.
+	VdcName            string `xml:"vdcName,attr,omitempty"`            // Name of the VDC.
+	IsBusy             bool   `xml:"isBusy,attr,omitempty"`             // True if the network is busy.
+	IsShared           bool   `xml:"isShared,attr,omitempty"`           // True if the network is shared with the other VDCs of the organization.
+	IsIpScopeInherited bool   `xml:"isIpScopeInherited,attr,omitempty"` // True if the IP scope is inherited from the parent network.
+}
+
+// QueryResultVdcRecordType represents a VDC as query result, in both
+// adminOrgVdc and orgVdc queries. The provider VDC and network pool
+// attributes are only returned to system administrators.
+type QueryResultVdcRecordType struct {
+	// Attributes
+	HREF                  string `xml:"href,attr,omitempty"`                  // The URI of the entity.
+	ID                    string `xml:"id,attr,omitempty"`                    // The entity identifier, in idrecords format.
+	Name                  string `xml:"name,attr,omitempty"`                  // VDC name.
+	Description           string `xml:"description,attr,omitempty"`           // VDC description.
+	Org                   string `xml:"org,attr,omitempty"`                   // Reference to the organization.
+	OrgName               string `xml:"orgName,attr,omitempty"`               // Name of the organization.
+	ProviderVdc           string `xml:"providerVdc,attr,omitempty"`           // Reference to the provider VDC.
+	ProviderVdcName       string `xml:"providerVdcName,attr,omitempty"`       // Name of the provider VDC.
+	NetworkPool           string `xml:"networkPool,attr,omitempty"`           // Reference to the network pool.
+	AllocationModel       string `xml:"allocationModel,attr,omitempty"`       // AllocationVApp, AllocationPool or ReservationPool.
+	Status                string `xml:"status,attr,omitempty"`                // Status of the VDC.
+	IsEnabled             bool   `xml:"isEnabled,attr,omitempty"`             // True if the VDC is enabled.
+	IsBusy                bool   `xml:"isBusy,attr,omitempty"`                // True if the VDC is busy.
+	CpuAllocationMhz      int64  `xml:"cpuAllocationMhz,attr,omitempty"`      // CPU allocated to the VDC.
+	CpuLimitMhz           int64  `xml:"cpuLimitMhz,attr,omitempty"`           // CPU limit of the VDC.
+	CpuUsedMhz            int64  `xml:"cpuUsedMhz,attr,omitempty"`            // CPU used by the VDC.
+	MemoryAllocationMB    int64  `xml:"memoryAllocationMB,attr,omitempty"`    // Memory allocated to the VDC.
+	MemoryLimitMB         int64  `xml:"memoryLimitMB,attr,omitempty"`         // Memory limit of the VDC.
+	MemoryUsedMB          int64  `xml:"memoryUsedMB,attr,omitempty"`          // Memory used by the VDC.
+	StorageLimitMB        int64  `xml:"storageLimitMB,attr,omitempty"`        // Storage limit of the VDC.
+	StorageUsedMB         int64  `xml:"storageUsedMB,attr,omitempty"`         // Storage used by the VDC.
+	NumberOfVApps         int    `xml:"numberOfVApps,attr,omitempty"`         // Number of vApps in the VDC.
+	NumberOfVAppTemplates int    `xml:"numberOfVAppTemplates,attr,omitempty"` // Number of vApp templates in the VDC.
+	NumberOfMedia         int    `xml:"numberOfMedia,attr,omitempty"`         // Number of media in the VDC.
+	NumberOfDisks         int    `xml:"numberOfDisks,attr,omitempty"`         // Number of independent disks in the VDC.
+}
+
+// QueryResultMediaRecordType represents a media as query result.
+type QueryResultMediaRecordType struct {
+	// Attributes
+	HREF               string `xml:"href,attr,omitempty"`               // The URI of the entity.
+	ID                 string `xml:"id,attr,omitempty"`                 // The entity identifier, in idrecords format.
+	Name               string `xml:"name,attr,omitempty"`               // Media name.
+	Catalog            string `xml:"catalog,attr,omitempty"`            // Reference to the catalog holding the media.
+	CatalogName        string `xml:"catalogName,attr,omitempty"`        // Name of the catalog holding the media.
+	CatalogItem        string `xml:"catalogItem,attr,omitempty"`        // Reference to the catalog item of the media.
+	Org                string `xml:"org,attr,omitempty"`                // Reference to the organization.
+	Owner              string `xml:"owner,attr,omitempty"`              // Reference to the media owner.
+	OwnerName          string `xml:"ownerName,attr,omitempty"`          // Name of the media owner.
+	Vdc                string `xml:"vdc,attr,omitempty"`                // Reference to the VDC storing the media.
+	VdcName            string `xml:"vdcName,attr,omitempty"`            // Name of the VDC storing the media.
+	Status             string `xml:"status,attr,omitempty"`             // Status of the media.
+	StorageB           int64  `xml:"storageB,attr,omitempty"`           // Size of the media, in bytes.
+	StorageProfileName string `xml:"storageProfileName,attr,omitempty"` // Name of the storage profile of the media.
+	CreationDate       string `xml:"creationDate,attr,omitempty"`       // Creation date of the media.
+	IsBusy             bool   `xml:"isBusy,attr,omitempty"`             // True if the media is busy.
+	IsPublished        bool   `xml:"isPublished,attr,omitempty"`        // True if the catalog holding the media is published.
+}
+
+// QueryResultDiskRecordType represents an independent disk as query result.
+type QueryResultDiskRecordType struct {
+	// Attributes
+	HREF               string `xml:"href,attr,omitempty"`               // The URI of the entity.
+	ID                 string `xml:"id,attr,omitempty"`                 // The entity identifier, in idrecords format.
+	Name               string `xml:"name,attr,omitempty"`               // Disk name.
+	Description        string `xml:"description,attr,omitempty"`        // Disk description.
+	Vdc                string `xml:"vdc,attr,omitempty"`                // Reference to the VDC.
+	VdcName            string `xml:"vdcName,attr,omitempty"`            // Name of the VDC.
+	SizeB              int64  `xml:"sizeB,attr,omitempty"`              // Size of the disk, in bytes.
+	DataStore          string `xml:"dataStore,attr,omitempty"`          // Reference to the datastore.
+	DataStoreName      string `xml:"datastoreName,attr,omitempty"`      // Name of the datastore.
+	StorageProfile     string `xml:"storageProfile,attr,omitempty"`     // Reference to the storage profile.
+	StorageProfileName string `xml:"storageProfileName,attr,omitempty"` // Name of the storage profile.
+	OwnerName          string `xml:"ownerName,attr,omitempty"`          // Name of the disk owner.
+	Status             string `xml:"status,attr,omitempty"`             // Status of the disk.
+	BusType            string `xml:"busType,attr,omitempty"`            // Bus type of the disk.
+	BusSubType         string `xml:"busSubType,attr,omitempty"`         // Bus sub type of the disk.
+	IsAttached         bool   `xml:"isAttached,attr,omitempty"`         // True if the disk is attached to a VM.
+}
+
+// QueryResultUserRecordType represents a user as query result.
+type QueryResultUserRecordType struct {
+	// Attributes
+	HREF            string `xml:"href,attr,omitempty"`            // The URI of the entity.
+	ID              string `xml:"id,attr,omitempty"`              // The entity identifier, in idrecords format.
+	Name            string `xml:"name,attr,omitempty"`            // User name.
+	FullName        string `xml:"fullName,attr,omitempty"`        // Full name of the user.
+	Email           string `xml:"email,attr,omitempty"`           // Email address of the user.
+	Telephone       string `xml:"telephone,attr,omitempty"`       // Telephone number of the user.
+	LdapGUID        string `xml:"ldapGuid,attr,omitempty"`        // GUID of the user in LDAP.
+	IsEnabled       bool   `xml:"isEnabled,attr,omitempty"`       // True if the user is enabled.
+	IsLdapUser      bool   `xml:"isLdapUser,attr,omitempty"`      // True if the user was imported from LDAP.
+	DeployedVMQuota int    `xml:"deployedVMQuota,attr,omitempty"` // Quota of deployed VMs, 0 means unlimited.
+	StoredVMQuota   int    `xml:"storedVMQuota,attr,omitempty"`   // Quota of stored VMs, 0 means unlimited.
+	NumDeployedVMs  int    `xml:"numDeployedVMs,attr,omitempty"`  // Number of deployed VMs of the user.
+	NumStoredVMs    int    `xml:"numStoredVMs,attr,omitempty"`    // Number of stored VMs of the user.
+}
+
+// QueryResultGroupRecordType represents a group as query result.
+type QueryResultGroupRecordType struct {
+	// Attributes
+	HREF                 string `xml:"href,attr,omitempty"`                 // The URI of the entity.
+	ID                   string `xml:"id,attr,omitempty"`                   // The entity identifier, in idrecords format.
+	Name                 string `xml:"name,attr,omitempty"`                 // Group name.
+	RoleName             string `xml:"roleName,attr,omitempty"`             // Name of the role of the group.
+	IdentityProviderType string `xml:"identityProviderType,attr,omitempty"` // Source of the group, e.g. INTEGRATED for LDAP.
+	IsReadOnly           bool   `xml:"isReadOnly,attr,omitempty"`           // True if the group cannot be modified.
+}
+
+// QueryResultTaskRecordType represents a task as query result.
+type QueryResultTaskRecordType struct {
+	// Attributes
+	HREF             string `xml:"href,attr,omitempty"`             // The URI of the entity.
+	ID               string `xml:"id,attr,omitempty"`               // The entity identifier, in idrecords format.
+	Name             string `xml:"name,attr,omitempty"`             // Task name, i.e. the operation it runs, e.g. vappDeploy.
+	Status           string `xml:"status,attr,omitempty"`           // Status of the task.
+	StartDate        string `xml:"startDate,attr,omitempty"`        // Start date of the task.
+	EndDate          string `xml:"endDate,attr,omitempty"`          // End date of the task.
+	Object           string `xml:"object,attr,omitempty"`           // Reference to the object of the task.
+	ObjectName       string `xml:"objectName,attr,omitempty"`       // Name of the object of the task.
+	ObjectType       string `xml:"objectType,attr,omitempty"`       // Type of the object of the task.
+	Org              string `xml:"org,attr,omitempty"`              // Reference to the organization.
+	OrgName          string `xml:"orgName,attr,omitempty"`          // Name of the organization.
+	OwnerName        string `xml:"ownerName,attr,omitempty"`        // Name of the user who started the task.
+	ServiceNamespace string `xml:"serviceNamespace,attr,omitempty"` // Namespace of the service running the task.
+	Details          string `xml:"details,attr,omitempty"`          // Details of the task, e.g. its error message.
+}
+
+// QueryResultEventRecordType represents an event as query result.
+type QueryResultEventRecordType struct {
+	// Attributes
+	HREF             string `xml:"href,attr,omitempty"`             // The URI of the entity.
+	ID               string `xml:"id,attr,omitempty"`               // The entity identifier, in idrecords format.
+	EventType        string `xml:"eventType,attr,omitempty"`        // Type of the event, e.g. com/vmware/vcloud/event/vapp/deploy.
+	EventStatus      int    `xml:"eventStatus,attr,omitempty"`      // 0 for a successful event, 1 for a failed one.
+	Description      string `xml:"description,attr,omitempty"`      // Description of the event.
+	Details          string `xml:"details,attr,omitempty"`          // Details of the event.
+	Entity           string `xml:"entity,attr,omitempty"`           // Reference to the entity of the event.
+	EntityName       string `xml:"entityName,attr,omitempty"`       // Name of the entity of the event.
+	EntityType       string `xml:"entityType,attr,omitempty"`       // Type of the entity of the event.
+	Org              string `xml:"org,attr,omitempty"`              // Reference to the organization.
+	OrgName          string `xml:"orgName,attr,omitempty"`          // Name of the organization.
+	UserName         string `xml:"userName,attr,omitempty"`         // Name of the user who triggered the event.
+	ServiceNamespace string `xml:"serviceNamespace,attr,omitempty"` // Namespace of the service that triggered the event.
+	TimeStamp        string `xml:"timeStamp,attr,omitempty"`        // Date of the event.
+	ProductVersion   string `xml:"productVersion,attr,omitempty"`   // Version of vCD.
+}
+
+// QueryResultNetworkRecordType represents an external network as query result.
+type QueryResultNetworkRecordType struct {
+	// Attributes
+	HREF      string `xml:"href,attr,omitempty"`      // The URI of the entity.
+	ID        string `xml:"id,attr,omitempty"`        // The entity identifier, in idrecords format.
+	Name      string `xml:"name,attr,omitempty"`      // Network name.
+	Gateway   string `xml:"gateway,attr,omitempty"`   // Gateway of the network.
+	Netmask   string `xml:"netmask,attr,omitempty"`   // Netmask of the network.
+	Dns1      string `xml:"dns1,attr,omitempty"`      // Primary DNS server.
+	Dns2      string `xml:"dns2,attr,omitempty"`      // Secondary DNS server.
+	DnsSuffix string `xml:"dnsSuffix,attr,omitempty"` // DNS suffix.
+	VcName    string `xml:"vcName,attr,omitempty"`    // Name of the vCenter backing the network.
+	IsBusy    bool   `xml:"isBusy,attr,omitempty"`    // True if the network is busy.
+}
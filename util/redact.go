@@ -0,0 +1,45 @@
+package util
+
+import (
+	"net/http"
+	"regexp"
+)
+
+// Redacted replaces the secrets removed by RedactHeader and RedactXML.
+const Redacted = "[REDACTED]"
+
+// SecretHeaders lists the HTTP headers carrying session tokens or
+// credentials.
+var SecretHeaders = []string{
+	"Authorization",
+	"X-Vcloud-Authorization",
+	"X-Vmware-Vcloud-Access-Token",
+	"Cookie",
+	"Set-Cookie",
+}
+
+// passwordElement matches the content of the XML elements holding a
+// password, such as AdminPassword and DomainUserPassword in guest
+// customization sections, whether or not they have attributes.
+var passwordElement = regexp.MustCompile(`(<(?:\w+:)?\w*Password(?:\s[^>]*)?>)[^<]*(</)`)
+
+// RedactHeader returns a copy of header with the values of SecretHeaders
+// replaced by Redacted.
+func RedactHeader(header http.Header) http.Header {
+	redacted := make(http.Header, len(header))
+	for name, values := range header {
+		redacted[name] = append([]string(nil), values...)
+	}
+	for _, name := range SecretHeaders {
+		if _, ok := redacted[http.CanonicalHeaderKey(name)]; ok {
+			redacted.Set(name, Redacted)
+		}
+	}
+	return redacted
+}
+
+// RedactXML returns body with the content of password elements replaced
+// by Redacted.
+func RedactXML(body string) string {
+	return passwordElement.ReplaceAllString(body, "${1}"+Redacted+"${2}")
+}
@@ -0,0 +1,49 @@
+package util
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestRedactXML(t *testing.T) {
+	tests := []struct {
+		body     string
+		expected string
+	}{
+		{
+			"<AdminPassword>secret</AdminPassword>",
+			"<AdminPassword>" + Redacted + "</AdminPassword>",
+		},
+		{
+			`<vcloud:DomainUserPassword xsi:type="xs:string">secret</vcloud:DomainUserPassword>`,
+			`<vcloud:DomainUserPassword xsi:type="xs:string">` + Redacted + "</vcloud:DomainUserPassword>",
+		},
+		{
+			"<SmtpServerSettings><Password >secret</Password><Username>mailer</Username></SmtpServerSettings>",
+			"<SmtpServerSettings><Password >" + Redacted + "</Password><Username>mailer</Username></SmtpServerSettings>",
+		},
+		{
+			"<AdminPasswordEnabled>true</AdminPasswordEnabled><PasswordPolicy>strict</PasswordPolicy>",
+			"<AdminPasswordEnabled>true</AdminPasswordEnabled><PasswordPolicy>strict</PasswordPolicy>",
+		},
+	}
+	for _, test := range tests {
+		if redacted := RedactXML(test.body); redacted != test.expected {
+			t.Fatalf("expected %s, got %s", test.expected, redacted)
+		}
+	}
+}
+
+func TestRedactHeader(t *testing.T) {
+	header := http.Header{}
+	header.Set("X-Vcloud-Authorization", "token")
+	header.Set("Accept", "application/*+xml")
+
+	redacted := RedactHeader(header)
+	if redacted.Get("X-Vcloud-Authorization") != Redacted || redacted.Get("Accept") != "application/*+xml" {
+		t.Fatalf("unexpected redacted header: %v", redacted)
+	}
+	if header.Get("X-Vcloud-Authorization") != "token" {
+		t.Fatal("expected the original header to be kept")
+	}
+}